@@ -1,55 +1,119 @@
 package main
 
 import (
-	"github.com/gin-gonic/gin"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	_ "github.com/mattn/go-sqlite3"
 	"salaries/pkg/auth"
-	"salaries/pkg/controller"
+	"salaries/pkg/config"
+	dbClient "salaries/pkg/db"
+	"salaries/pkg/distribution"
 	"salaries/pkg/logger"
-	"salaries/pkg/middleware"
+	"salaries/pkg/metrics"
 	"salaries/pkg/repository"
+	"salaries/pkg/server"
 	"salaries/pkg/service"
+	"salaries/pkg/tracing"
 )
 
 const (
-	TrustedProxy = "192.168.1.2"
-	Port         = ":8080"
+	DatabaseName = "salaries"
+	ServiceName  = "salaries"
 )
 
 func main() {
-	logger := logger.NewLogger()
-	salaryRepository, err := repository.NewSalaryRepository(logger)
+	cfg := config.Load()
+	logger := logger.NewLoggerWithConfig(logger.Config{
+		Level:          cfg.LogLevel,
+		Format:         cfg.LogFormat,
+		RedactedFields: cfg.LogRedactedFields,
+	})
+	registry := metrics.NewRegistry()
+
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.Config{
+		ServiceName: ServiceName,
+		Exporter:    cfg.TracingExporter,
+		FilePath:    cfg.TracingFilePath,
+	})
 	if err != nil {
-		logger.Error("failed to create salary repository: ", err.Error())
+		logger.Error("failed to initialize tracing: %s", err.Error())
+	} else {
+		defer shutdownTracing(context.Background())
 	}
 
-	authService := auth.NewAuthService(logger)
-
-	salaryService := service.NewSalaryService(salaryRepository, logger)
+	db, err := sql.Open("sqlite3", cfg.DatabasePath)
+	if err != nil {
+		logger.Error("failed to open database: %s", err.Error())
+	}
+	if err := dbClient.Migrate(context.Background(), db); err != nil {
+		logger.Error("failed to migrate database: %s", err.Error())
+		return
+	}
+	registry.MustRegister(metrics.NewDBStatsCollector(db, DatabaseName))
 
-	serveApplication(authService, salaryService, logger)
-}
+	detector := distribution.Detector{Method: cfg.AnomalyMethod, Threshold: cfg.AnomalyThreshold}
+	if err := detector.Validate(); err != nil {
+		logger.Error("invalid anomaly detection: %s", err.Error())
+		return
+	}
 
-func serveApplication(authService auth.Service, salaryService service.SalaryService, logger logger.Logger) {
-	router := gin.Default()
-	router.SetTrustedProxies([]string{TrustedProxy})
+	salaryRepository := repository.NewSalaryRepositoryWithClient(dbClient.NewSqlite(db))
+	auditRepository := repository.NewAuditRepositoryWithClient(dbClient.NewSqliteAuditClient(db))
+	userRepository := repository.NewUserRepositoryWithClient(dbClient.NewSqliteUserClient(db))
+	payBandRepository := repository.NewPayBandRepositoryWithClient(dbClient.NewSqlitePayBandClient(db))
+	demographicsRepository := repository.NewDemographicsRepositoryWithClient(dbClient.NewSqliteDemographicsClient(db))
+	budgetRepository := repository.NewBudgetRepositoryWithClient(dbClient.NewSqliteBudgetClient(db))
+	snapshotRepository := repository.NewSnapshotRepositoryWithClient(dbClient.NewSqliteSnapshotClient(db))
+	idempotencyRepository := repository.NewIdempotencyRepositoryWithClient(dbClient.NewSqliteIdempotencyClient(db))
+	registry.MustRegister(service.NewSalaryMetricsCollector(salaryRepository, logger))
 
-	authController := auth.NewAuthController(authService)
+	signingKey := cfg.JWTSigningKey
+	if signingKey == "" {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			logger.Error("failed to generate the token signing key: %s", err.Error())
+			return
+		}
+		signingKey = hex.EncodeToString(key)
+		logger.Warn("JWT_SIGNING_KEY is not set, the access tokens are signed with a random key and won't survive a restart")
+	}
+	authService := auth.NewAuthService(userRepository, signingKey, cfg.JWTTokenTTL, logger, registry)
 
-	publicRoutes := router.Group("/auth")
-	publicRoutes.POST("/login", authController.Login)
+	statsPrivacy := service.StatsPrivacy{
+		MinGroupSize: cfg.StatsMinGroupSize,
+		Epsilon:      cfg.StatsNoiseEpsilon,
+		MinSalary:    cfg.StatsNoiseMinSalary,
+		MaxSalary:    cfg.StatsNoiseMaxSalary,
+	}
+	statsCache := service.NewStatsCache(salaryRepository, logger)
+	if cfg.StatsCacheCheckInterval > 0 {
+		go statsCache.Run(context.Background(), cfg.StatsCacheCheckInterval)
+	}
+	salaryService := service.NewSalaryService(salaryRepository, logger,
+		service.WithExchangeRates(cfg.ExchangeRates),
+		service.WithAnomalyWarnings(detector, cfg.AnomalyMinGroupSize),
+		service.WithStatsCache(statsCache),
+		service.WithRankMinGroupSize(cfg.RankMinGroupSize),
+		service.WithStatsPrivacy(statsPrivacy))
+	auditService := service.NewAuditService(auditRepository, logger)
+	payBandService := service.NewPayBandService(payBandRepository, salaryRepository, cfg.ExchangeRates, logger)
+	payEquityService := service.NewPayEquityService(demographicsRepository, cfg.ExchangeRates, cfg.PayEquityMinGroupSize, logger)
+	budgetService := service.NewBudgetService(budgetRepository, salaryRepository, cfg.ExchangeRates, cfg.FiscalYearStart, cfg.BudgetCurrency, statsPrivacy, logger)
+	snapshotService := service.NewSnapshotService(snapshotRepository, salaryRepository, statsPrivacy, logger)
+	if cfg.StatsSnapshotInterval > 0 {
+		go snapshotService.Schedule(context.Background(), cfg.StatsSnapshotInterval)
+	}
 
-	salaryController := controller.NewSalaryController(salaryService)
+	serveApplication(cfg, authService, salaryService, auditService, payBandService, payEquityService, budgetService, snapshotService, idempotencyRepository, registry, logger)
+}
 
-	protectedRoutes := router.Group("/api/salaries")
-	protectedRoutes.Use(middleware.NewAuthMiddleware(authService))
-	protectedRoutes.GET("", salaryController.GetAll)
-	protectedRoutes.POST("", salaryController.Create)
-	protectedRoutes.DELETE("/:id", salaryController.Delete)
-	protectedRoutes.GET("/stats", salaryController.GetStatisticsEntireDataset)
-	protectedRoutes.GET("/stats/contracts", salaryController.GetContractsStats)
-	protectedRoutes.GET("/stats/departments", salaryController.GetDepartmentsStats)
-	protectedRoutes.GET("/stats/sub-departments", salaryController.GetSubDepartmentsStats)
+func serveApplication(cfg config.Config, authService auth.Service, salaryService service.SalaryService, auditService service.AuditService, payBandService service.PayBandService, payEquityService service.PayEquityService, budgetService service.BudgetService, snapshotService service.SnapshotService, idempotencyRepository repository.IdempotencyRepository, registry *metrics.Registry, logger logger.Logger) {
+	router := server.NewRouter(cfg, authService, salaryService, auditService, payBandService, payEquityService, budgetService, snapshotService, idempotencyRepository, registry, logger)
 
-	router.Run(Port)
-	logger.Info("Server api-creator running on port %s", Port)
+	logger.Info("Server api-creator running on port %s", cfg.Port)
+	if err := router.Run(cfg.Port); err != nil {
+		logger.Error("server stopped: %s", err.Error())
+	}
 }
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"salaries/pkg/api"
+	"salaries/pkg/domain"
+	"strconv"
+	"strings"
+)
+
+func login(ctx context.Context, a *app, args []string) error {
+	flags := a.newFlagSet("login")
+	username := flags.String("username", a.config.Username, "name to log in with")
+	password := flags.String("password", "", "password, read from stdin when empty")
+	if err := a.parse(flags, args, false); err != nil {
+		return err
+	}
+	if *username == "" {
+		return usageError{message: "--username is required"}
+	}
+	if *password == "" {
+		fmt.Fprint(a.stderr, "password: ")
+		line, _ := bufio.NewReader(a.stdin).ReadString('\n')
+		if *password = strings.TrimRight(line, "\r\n"); *password == "" {
+			return fmt.Errorf("the password can not be empty")
+		}
+	}
+
+	token, err := a.client().Login(ctx, *username, *password)
+	if err != nil {
+		return err
+	}
+	a.config.Username = *username
+	a.config.Token = token
+	if err := saveConfig(a.configPath, a.config); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stdout, "logged in to %s as %s\n", a.config.URL, *username)
+	return nil
+}
+
+func logout(ctx context.Context, a *app, args []string) error {
+	if err := a.parse(a.newFlagSet("logout"), args, false); err != nil {
+		return err
+	}
+	a.config.Token = ""
+	return saveConfig(a.configPath, a.config)
+}
+
+func list(ctx context.Context, a *app, args []string) error {
+	flags := a.newFlagSet("list")
+	var filter api.SalaryFilter
+	flags.StringVar(&filter.Department, "department", "", "only salaries of the department")
+	flags.StringVar(&filter.SubDepartment, "sub-department", "", "only salaries of the sub-department")
+	flags.StringVar(&filter.Currency, "currency", "", "only salaries in the currency")
+	onContract := flags.String("on-contract", "", "true or false to only list salaries on contract or not")
+	output := flags.String("output", outputTable, "table, json or csv")
+	if err := a.parse(flags, args, false); err != nil {
+		return err
+	}
+	if *onContract != "" {
+		value, err := strconv.ParseBool(*onContract)
+		if err != nil {
+			return usageError{message: "--on-contract must be true or false"}
+		}
+		filter.OnContract = &value
+	}
+	if err := checkOutput(*output); err != nil {
+		return err
+	}
+
+	salaries, err := a.client().ListSalaries(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if salaries == nil {
+		salaries = []domain.Salary{}
+	}
+	return write(a.stdout, *output, salaries)
+}
+
+func add(ctx context.Context, a *app, args []string) error {
+	flags := a.newFlagSet("add")
+	var salary domain.Salary
+	flags.StringVar(&salary.Name, "name", "", "employee name")
+	flags.Float64Var(&salary.Salary, "salary", 0, "yearly salary")
+	flags.StringVar(&salary.Currency, "currency", "", "currency code")
+	flags.StringVar(&salary.Department, "department", "", "department")
+	flags.StringVar(&salary.SubDepartment, "sub-department", "", "sub-department")
+	flags.BoolVar(&salary.OnContract, "on-contract", false, "the employee is on contract")
+	if err := a.parse(flags, args, false); err != nil {
+		return err
+	}
+	var missing []string
+	for flag, value := range map[string]string{"name": salary.Name, "currency": salary.Currency, "department": salary.Department, "sub-department": salary.SubDepartment} {
+		if value == "" {
+			missing = append(missing, "--"+flag)
+		}
+	}
+	if salary.Salary <= 0 {
+		missing = append(missing, "--salary")
+	}
+	if len(missing) > 0 {
+		return usageError{message: "missing " + strings.Join(sortedStrings(missing), ", ")}
+	}
+
+	created, err := a.client().CreateSalary(ctx, &salary)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stdout, "salary %d added\n", created.ID)
+	return nil
+}
+
+func rm(ctx context.Context, a *app, args []string) error {
+	flags := a.newFlagSet("rm")
+	if err := a.parse(flags, args, true); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return usageError{message: "at least one id is required"}
+	}
+	ids := make([]int64, 0, flags.NArg())
+	for _, arg := range flags.Args() {
+		id, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return usageError{message: fmt.Sprintf("invalid id %q", arg)}
+		}
+		ids = append(ids, id)
+	}
+
+	salaries := a.client()
+	for _, id := range ids {
+		if err := salaries.DeleteSalary(ctx, id); err != nil {
+			return fmt.Errorf("removing salary %d: %w", id, err)
+		}
+		fmt.Fprintf(a.stdout, "salary %d removed\n", id)
+	}
+	return nil
+}
+
+func stats(ctx context.Context, a *app, args []string) error {
+	flags := a.newFlagSet("stats")
+	by := flags.String("by", "all", "all, contracts, department or sub-department")
+	output := flags.String("output", outputTable, "table, json or csv")
+	if err := a.parse(flags, args, false); err != nil {
+		return err
+	}
+	if err := checkOutput(*output); err != nil {
+		return err
+	}
+
+	salaries := a.client()
+	var value interface{}
+	var err error
+	switch strings.TrimSuffix(*by, "s") {
+	case "all":
+		value, err = salaries.Stats(ctx)
+	case "contract":
+		value, err = salaries.ContractsStats(ctx)
+	case "department":
+		value, err = salaries.DepartmentsStats(ctx)
+	case "sub-department":
+		value, err = salaries.SubDepartmentsStats(ctx)
+	default:
+		return usageError{message: fmt.Sprintf("unknown grouping %q", *by)}
+	}
+	if err != nil {
+		return err
+	}
+	return write(a.stdout, *output, value)
+}
@@ -0,0 +1,154 @@
+// Command salaries queries the salaries api from the terminal.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"salaries/pkg/client"
+	"sort"
+	"strings"
+)
+
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+type command struct {
+	usage       string
+	description string
+	run         func(ctx context.Context, a *app, args []string) error
+}
+
+var commands = map[string]command{
+	"login":  {usage: "login --username name [--password password]", description: "log in and store the access token", run: login},
+	"logout": {usage: "logout", description: "forget the stored access token", run: logout},
+	"list":   {usage: "list [--department name] [--sub-department name] [--currency code] [--on-contract true|false] [--output table|json|csv]", description: "list salaries", run: list},
+	"add":    {usage: "add --name name --salary amount --currency code --department name --sub-department name [--on-contract]", description: "add a salary", run: add},
+	"rm":     {usage: "rm id [id...]", description: "remove salaries", run: rm},
+	"stats":  {usage: "stats [--by all|contracts|department|sub-department] [--output table|json|csv]", description: "show salary statistics", run: stats},
+}
+
+type usageError struct {
+	message string
+}
+
+func (e usageError) Error() string {
+	return e.message
+}
+
+type app struct {
+	configPath string
+	config     Config
+	stdin      io.Reader
+	stdout     io.Writer
+	stderr     io.Writer
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("salaries", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	configPath := flags.String("config", defaultConfigPath(), "file storing the api url and access token, defaults to SALARIES_CONFIG")
+	url := flags.String("url", os.Getenv("SALARIES_URL"), "base url of the api, defaults to SALARIES_URL or the url of the last login")
+	flags.Usage = func() { printUsage(flags, stderr) }
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if flags.NArg() == 0 {
+		printUsage(flags, stderr)
+		return exitUsage
+	}
+	name := flags.Arg(0)
+	cmd, ok := commands[name]
+	if !ok {
+		printUsage(flags, stderr)
+		return exitUsage
+	}
+
+	config, err := loadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "salaries: %s\n", err.Error())
+		return exitError
+	}
+	if *url != "" {
+		config.URL = *url
+	}
+	if config.URL == "" {
+		config.URL = DefaultURL
+	}
+	a := &app{
+		configPath: *configPath,
+		config:     config,
+		stdin:      stdin,
+		stdout:     stdout,
+		stderr:     stderr,
+	}
+
+	err = cmd.run(context.Background(), a, flags.Args()[1:])
+	var usage usageError
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.As(err, &usage):
+		fmt.Fprintf(stderr, "salaries %s: %s\nusage: salaries %s\n", name, err.Error(), cmd.usage)
+		return exitUsage
+	case errors.Is(err, client.ErrUnauthorized):
+		fmt.Fprintf(stderr, "salaries %s: %s, run salaries login\n", name, err.Error())
+		return exitError
+	default:
+		fmt.Fprintf(stderr, "salaries %s: %s\n", name, err.Error())
+		return exitError
+	}
+}
+
+func printUsage(flags *flag.FlagSet, w io.Writer) {
+	fmt.Fprintln(w, "usage: salaries [--url url] [--config file] <command> [flags]")
+	fmt.Fprintln(w, "\ncommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-8s %s\n", name, commands[name].description)
+	}
+	fmt.Fprintln(w, "\nflags:")
+	flags.PrintDefaults()
+}
+
+func (a *app) newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(a.stderr)
+	return flags
+}
+
+// parse parses the flags of a command, positional arguments are only allowed when allowArgs is set
+func (a *app) parse(flags *flag.FlagSet, args []string, allowArgs bool) error {
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return usageError{message: "invalid flags"}
+	}
+	if !allowArgs && flags.NArg() > 0 {
+		return usageError{message: "unexpected arguments " + strings.Join(flags.Args(), " ")}
+	}
+	return nil
+}
+
+// client returns an api client authenticated with the stored token
+func (a *app) client() *client.Client {
+	return client.New(a.config.URL, client.WithToken(a.config.Token))
+}
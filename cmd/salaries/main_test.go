@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"salaries/pkg/server/servertest"
+	"strings"
+	"testing"
+)
+
+type result struct {
+	code   int
+	stdout string
+	stderr string
+}
+
+func runCommand(configPath, stdin string, args ...string) result {
+	var stdout, stderr bytes.Buffer
+	code := run(append([]string{"--config", configPath}, args...), strings.NewReader(stdin), &stdout, &stderr)
+	return result{code: code, stdout: stdout.String(), stderr: stderr.String()}
+}
+
+func TestSalaries(t *testing.T) {
+	testServer := servertest.NewServer(t)
+	configPath := filepath.Join(t.TempDir(), "salaries", "config.json")
+
+	tests := []struct {
+		name       string
+		stdin      string
+		args       []string
+		code       int
+		wantStdout string
+		wantStderr string
+	}{
+		{name: "unknown command", args: []string{"drop"}, code: exitUsage, wantStderr: "usage: salaries"},
+		{name: "list before login", args: []string{"--url", testServer.URL, "list"}, code: exitError, wantStderr: "run salaries login"},
+		{name: "login without username", args: []string{"--url", testServer.URL, "login"}, code: exitUsage, wantStderr: "--username is required"},
+		{name: "login with wrong password", args: []string{"--url", testServer.URL, "login", "--username", "pmagnaghi", "--password", "wrong"}, code: exitError, wantStderr: "401"},
+		{name: "login", stdin: "123456\n", args: []string{"--url", testServer.URL, "login", "--username", "pmagnaghi"}, code: exitOK, wantStdout: "logged in to " + testServer.URL + " as pmagnaghi"},
+		{name: "add with missing flags", args: []string{"add", "--name", "Anurag"}, code: exitUsage, wantStderr: "missing --currency, --department, --salary, --sub-department"},
+		{name: "add", args: []string{"add", "--name", "Anurag", "--salary", "90000", "--currency", "USD", "--department", "Banking", "--sub-department", "Loan", "--on-contract"}, code: exitOK, wantStdout: "salary 1 added"},
+		{name: "add another", args: []string{"add", "--name", "Himani", "--salary", "240000", "--currency", "USD", "--department", "Engineering", "--sub-department", "Platform"}, code: exitOK, wantStdout: "salary 2 added"},
+		{name: "list table", args: []string{"list"}, code: exitOK, wantStdout: "1   Anurag  90000.00   USD       true         Banking      Loan"},
+		{name: "list csv filtered", args: []string{"list", "--department", "Banking", "--output", "csv"}, code: exitOK, wantStdout: "id,name,salary,currency,on_contract,department,sub_department\n1,Anurag,90000,USD,true,Banking,Loan\n"},
+		{name: "list json", args: []string{"list", "--on-contract", "false", "--output", "json"}, code: exitOK, wantStdout: `"name": "Himani"`},
+		{name: "list with unknown output", args: []string{"list", "--output", "xml"}, code: exitUsage, wantStderr: `unknown output "xml"`},
+		{name: "stats", args: []string{"stats"}, code: exitOK, wantStdout: "MEAN"},
+		{name: "stats by department", args: []string{"stats", "--by", "department", "--output", "csv"}, code: exitOK, wantStdout: "Engineering,240000,240000,240000,1"},
+		{name: "stats by unknown grouping", args: []string{"stats", "--by", "country"}, code: exitUsage, wantStderr: `unknown grouping "country"`},
+		{name: "rm with invalid id", args: []string{"rm", "one"}, code: exitUsage, wantStderr: `invalid id "one"`},
+		{name: "rm", args: []string{"rm", "1"}, code: exitOK, wantStdout: "salary 1 removed"},
+		{name: "rm missing salary", args: []string{"rm", "1"}, code: exitError, wantStderr: "removing salary 1"},
+		{name: "logout", args: []string{"logout"}, code: exitOK},
+		{name: "list after logout", args: []string{"list"}, code: exitError, wantStderr: "run salaries login"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := runCommand(configPath, tt.stdin, tt.args...)
+
+			assert.Equal(t, tt.code, got.code, got.stderr)
+			assert.Contains(t, got.stdout, tt.wantStdout)
+			assert.Contains(t, got.stderr, tt.wantStderr)
+		})
+	}
+
+	info, err := os.Stat(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	config, err := loadConfig(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, testServer.URL, config.URL)
+	assert.Equal(t, "pmagnaghi", config.Username)
+	assert.Empty(t, config.Token)
+}
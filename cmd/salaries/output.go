@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"salaries/pkg/export"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputCSV   = "csv"
+)
+
+func checkOutput(output string) error {
+	switch output {
+	case outputTable, outputJSON, outputCSV:
+		return nil
+	}
+	return usageError{message: fmt.Sprintf("unknown output %q, use table, json or csv", output)}
+}
+
+// write prints salaries or stats, tables and csv share the columns of the api exports
+func write(w io.Writer, output string, value interface{}) error {
+	if output == outputJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	}
+	writer, err := export.NewWriter(export.CSV, w)
+	if err != nil {
+		return err
+	}
+	if output == outputTable {
+		writer = newTableWriter(w)
+	}
+	if err := export.WriteTable(writer, value); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// tableWriter aligns the columns for the terminal
+type tableWriter struct {
+	writer *tabwriter.Writer
+}
+
+func newTableWriter(w io.Writer) export.Writer {
+	return &tableWriter{writer: tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)}
+}
+
+func (t *tableWriter) WriteHeader(columns []string) error {
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = strings.ToUpper(strings.ReplaceAll(column, "_", " "))
+	}
+	_, err := fmt.Fprintln(t.writer, strings.Join(header, "\t"))
+	return err
+}
+
+func (t *tableWriter) WriteRow(values []interface{}) error {
+	cells := make([]string, len(values))
+	for i, value := range values {
+		switch v := value.(type) {
+		case float64:
+			cells[i] = strconv.FormatFloat(v, 'f', 2, 64)
+		default:
+			cells[i] = fmt.Sprint(v)
+		}
+	}
+	_, err := fmt.Fprintln(t.writer, strings.Join(cells, "\t"))
+	return err
+}
+
+func (t *tableWriter) Close() error {
+	return t.writer.Flush()
+}
+
+func sortedStrings(values []string) []string {
+	sort.Strings(values)
+	return values
+}
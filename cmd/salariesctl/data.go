@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"salaries/pkg/api"
+	"salaries/pkg/domain"
+	"salaries/pkg/export"
+	"salaries/pkg/importer"
+	"strconv"
+	"strings"
+)
+
+var importContentTypes = map[string]string{
+	"csv":    importer.CSVContentType,
+	"json":   importer.JSONContentType,
+	"ndjson": importer.NDJSONContentType,
+	"jsonl":  importer.NDJSONContentType,
+}
+
+func seed(ctx context.Context, a *app, args []string) error {
+	flags := a.newFlagSet("seed")
+	file := flags.String("file", "", "dataset file, csv, json or ndjson by extension")
+	if err := a.parse(flags, args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return usageError{message: "--file is required"}
+	}
+	db, err := a.open(ctx)
+	if err != nil {
+		return err
+	}
+	var count int64
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM salaries").Scan(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("database %s already has %d salaries, use salariesctl import to add more", a.databasePath, count)
+	}
+	return a.importFile(ctx, *file, "", false)
+}
+
+func importSalaries(ctx context.Context, a *app, args []string) error {
+	flags := a.newFlagSet("import")
+	file := flags.String("file", "", "file to import, - reads stdin")
+	format := flags.String("format", "", "csv, json or ndjson, detected from the file extension by default")
+	dryRun := flags.Bool("dry-run", false, "validate every row without writing")
+	if err := a.parse(flags, args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return usageError{message: "--file is required"}
+	}
+	if _, err := a.open(ctx); err != nil {
+		return err
+	}
+	return a.importFile(ctx, *file, *format, *dryRun)
+}
+
+// importFile imports the file in a single transaction and prints the report, any invalid row fails the command
+func (a *app) importFile(ctx context.Context, file, format string, dryRun bool) error {
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(file), ".")
+	}
+	contentType, ok := importContentTypes[strings.ToLower(format)]
+	if !ok {
+		return usageError{message: fmt.Sprintf("unknown format %q, use --format csv, json or ndjson", format)}
+	}
+	var input io.Reader = a.stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		input = f
+	}
+	decoder, err := importer.NewDecoder(contentType, input)
+	if err != nil {
+		return err
+	}
+
+	report, err := a.salaryService(a.db).Import(ctx, decoder, dryRun)
+	if err != nil {
+		return fmt.Errorf("importing %s: %w", file, err)
+	}
+	for _, rowError := range report.Errors {
+		fmt.Fprintf(a.stderr, "row %d: %s\n", rowError.Row, rowError.Error)
+	}
+	fmt.Fprintf(a.stdout, "rows: %d, valid: %d, imported: %d\n", report.Rows, report.Valid, report.Imported)
+	if len(report.Errors) > 0 {
+		return fmt.Errorf("%d invalid rows, nothing was imported", len(report.Errors))
+	}
+	return nil
+}
+
+func exportSalaries(ctx context.Context, a *app, args []string) error {
+	flags := a.newFlagSet("export")
+	format := flags.String("format", export.CSV, "csv, xlsx or ndjson")
+	output := flags.String("output", "-", "file to write, - writes stdout")
+	var filter api.SalaryFilter
+	flags.StringVar(&filter.Department, "department", "", "only salaries of the department")
+	flags.StringVar(&filter.SubDepartment, "sub-department", "", "only salaries of the sub-department")
+	flags.StringVar(&filter.Currency, "currency", "", "only salaries in the currency")
+	onContract := flags.String("on-contract", "", "true or false to only export salaries on contract or not")
+	if err := a.parse(flags, args); err != nil {
+		return err
+	}
+	if *onContract != "" {
+		value, err := strconv.ParseBool(*onContract)
+		if err != nil {
+			return usageError{message: "--on-contract must be true or false"}
+		}
+		filter.OnContract = &value
+	}
+	db, err := a.open(ctx)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = a.stdout
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	writer, err := export.NewWriter(*format, out)
+	if err != nil {
+		return usageError{message: err.Error()}
+	}
+	err = writer.WriteHeader(export.SalaryColumns)
+	if err == nil {
+		err = a.salaryService(db).Export(ctx, filter, func(salary *domain.Salary) error {
+			return writer.WriteRow(export.SalaryRow(salary))
+		})
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil && *output != "-" {
+		os.Remove(*output)
+	}
+	return err
+}
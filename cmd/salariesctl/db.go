@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	dbClient "salaries/pkg/db"
+)
+
+func dbInit(ctx context.Context, a *app, args []string) error {
+	if err := a.parse(a.newFlagSet("db init"), args); err != nil {
+		return err
+	}
+	if _, err := os.Stat(a.databasePath); err == nil {
+		return fmt.Errorf("database %s already exists, run salariesctl db migrate to update it", a.databasePath)
+	}
+	db, err := a.connect()
+	if err != nil {
+		return err
+	}
+	if err := dbClient.Migrate(ctx, db); err != nil {
+		return fmt.Errorf("creating schema: %w", err)
+	}
+	fmt.Fprintf(a.stdout, "database %s created at schema version %d\n", a.databasePath, dbClient.LatestSchemaVersion())
+	return nil
+}
+
+func dbMigrate(ctx context.Context, a *app, args []string) error {
+	if err := a.parse(a.newFlagSet("db migrate"), args); err != nil {
+		return err
+	}
+	db, err := a.openExisting()
+	if err != nil {
+		return err
+	}
+	before, err := dbClient.SchemaVersion(ctx, db)
+	if err != nil {
+		return fmt.Errorf("reading schema version of %s: %w", a.databasePath, err)
+	}
+	if err := dbClient.Migrate(ctx, db); err != nil {
+		return fmt.Errorf("migrating schema: %w", err)
+	}
+	after, err := dbClient.SchemaVersion(ctx, db)
+	if err != nil {
+		return err
+	}
+	if before == after {
+		fmt.Fprintf(a.stdout, "database %s is up to date at schema version %d\n", a.databasePath, after)
+		return nil
+	}
+	fmt.Fprintf(a.stdout, "database %s migrated from schema version %d to %d\n", a.databasePath, before, after)
+	return nil
+}
+
+func backup(ctx context.Context, a *app, args []string) error {
+	flags := a.newFlagSet("backup")
+	output := flags.String("output", "", "backup file, it must not exist")
+	if err := a.parse(flags, args); err != nil {
+		return err
+	}
+	if *output == "" {
+		return usageError{message: "--output is required"}
+	}
+	if _, err := os.Stat(*output); err == nil {
+		return fmt.Errorf("backup file %s already exists", *output)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	db, err := a.openExisting()
+	if err != nil {
+		return err
+	}
+	if err := dbClient.Backup(ctx, db, *output); err != nil {
+		return fmt.Errorf("writing backup: %w", err)
+	}
+	fmt.Fprintf(a.stdout, "database %s backed up to %s\n", a.databasePath, *output)
+	return nil
+}
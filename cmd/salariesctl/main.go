@@ -0,0 +1,211 @@
+// Command salariesctl administers the salaries database: schema, data, users and backups.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	_ "github.com/mattn/go-sqlite3"
+	"io"
+	"os"
+	"salaries/pkg/config"
+	dbClient "salaries/pkg/db"
+	"salaries/pkg/domain"
+	"salaries/pkg/logger"
+	"salaries/pkg/repository"
+	"salaries/pkg/requestctx"
+	"salaries/pkg/service"
+	"sort"
+	"strings"
+)
+
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+// actor is recorded in the audit log for every change made with salariesctl
+const actor = "salariesctl"
+
+type command struct {
+	usage       string
+	description string
+	run         func(ctx context.Context, a *app, args []string) error
+}
+
+var commands = map[string]command{
+	"db init":             {usage: "db init", description: "create the database and apply the schema", run: dbInit},
+	"db migrate":          {usage: "db migrate", description: "apply the pending schema migrations", run: dbMigrate},
+	"seed":                {usage: "seed --file dataset.json", description: "load a dataset into an empty database", run: seed},
+	"import":              {usage: "import --file salaries.csv [--format csv|json|ndjson] [--dry-run]", description: "validate and import salaries", run: importSalaries},
+	"export":              {usage: "export [--format csv|xlsx|ndjson] [--output file] [filters]", description: "export salaries", run: exportSalaries},
+	"user create":         {usage: "user create --username name [--role admin|user] [--password password]", description: "create a user, the password is read from stdin when not given", run: userCreate},
+	"user reset-password": {usage: "user reset-password --username name [--password password]", description: "replace the password of a user", run: userResetPassword},
+	"stats":               {usage: "stats [--by all|contracts|departments|sub-departments] [--format json|csv|ndjson|yaml|xml]", description: "print salary statistics", run: stats},
+	"backup":              {usage: "backup --output file", description: "write a consistent copy of the database", run: backup},
+}
+
+type usageError struct {
+	message string
+}
+
+func (e usageError) Error() string {
+	return e.message
+}
+
+type app struct {
+	databasePath string
+	logger       logger.Logger
+	stdin        io.Reader
+	stdout       io.Writer
+	stderr       io.Writer
+	db           *sql.DB
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("salariesctl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	databasePath := flags.String("database", config.Load().DatabasePath, "SQLite database file, defaults to DATABASE_PATH")
+	logLevel := flags.String("log-level", "warn", "debug, info, warn or error")
+	flags.Usage = func() { printUsage(flags, stderr) }
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	name, cmd, args, ok := findCommand(flags.Args())
+	if !ok {
+		printUsage(flags, stderr)
+		return exitUsage
+	}
+
+	a := &app{
+		databasePath: *databasePath,
+		logger:       logger.NewLoggerWithConfig(logger.Config{Level: *logLevel, Output: stderr}),
+		stdin:        stdin,
+		stdout:       stdout,
+		stderr:       stderr,
+	}
+	defer a.close()
+
+	ctx := requestctx.WithUser(context.Background(), &domain.User{Username: actor})
+	err := cmd.run(ctx, a, args)
+	var usage usageError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.As(err, &usage):
+		fmt.Fprintf(stderr, "salariesctl %s: %s\nusage: salariesctl %s\n", name, err.Error(), cmd.usage)
+		return exitUsage
+	default:
+		fmt.Fprintf(stderr, "salariesctl %s: %s\n", name, err.Error())
+		return exitError
+	}
+}
+
+// findCommand matches the longest command name, commands have one or two words
+func findCommand(args []string) (string, command, []string, bool) {
+	if len(args) >= 2 {
+		if cmd, ok := commands[args[0]+" "+args[1]]; ok {
+			return args[0] + " " + args[1], cmd, args[2:], true
+		}
+	}
+	if len(args) >= 1 {
+		if cmd, ok := commands[args[0]]; ok {
+			return args[0], cmd, args[1:], true
+		}
+	}
+	return "", command{}, nil, false
+}
+
+func printUsage(flags *flag.FlagSet, w io.Writer) {
+	fmt.Fprintln(w, "usage: salariesctl [--database file] [--log-level level] <command> [flags]")
+	fmt.Fprintln(w, "\ncommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-20s %s\n", name, commands[name].description)
+	}
+	fmt.Fprintln(w, "\nflags:")
+	flags.PrintDefaults()
+}
+
+// newFlagSet returns the flags of a command, parse errors are already reported by the flag set
+func (a *app) newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(a.stderr)
+	return flags
+}
+
+func (a *app) parse(flags *flag.FlagSet, args []string) error {
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return usageError{message: "invalid flags"}
+	}
+	if flags.NArg() > 0 {
+		return usageError{message: "unexpected arguments " + strings.Join(flags.Args(), " ")}
+	}
+	return nil
+}
+
+// open connects to an existing database with an up to date schema, only db init creates databases
+func (a *app) open(ctx context.Context) (*sql.DB, error) {
+	db, err := a.openExisting()
+	if err != nil {
+		return nil, err
+	}
+	version, err := dbClient.SchemaVersion(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("reading schema version of %s: %w", a.databasePath, err)
+	}
+	if version < dbClient.LatestSchemaVersion() {
+		return nil, fmt.Errorf("database %s is at schema version %d of %d, run salariesctl db migrate", a.databasePath, version, dbClient.LatestSchemaVersion())
+	}
+	return db, nil
+}
+
+func (a *app) openExisting() (*sql.DB, error) {
+	if _, err := os.Stat(a.databasePath); err != nil {
+		return nil, fmt.Errorf("database %s does not exist, run salariesctl db init: %w", a.databasePath, err)
+	}
+	return a.connect()
+}
+
+func (a *app) connect() (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", a.databasePath)
+	if err != nil {
+		return nil, fmt.Errorf("opening database %s: %w", a.databasePath, err)
+	}
+	a.db = db
+	return db, nil
+}
+
+func (a *app) close() {
+	if a.db != nil {
+		a.db.Close()
+	}
+}
+
+func (a *app) salaryService(db *sql.DB) service.SalaryService {
+	return service.NewSalaryService(repository.NewSalaryRepositoryWithClient(dbClient.NewSqlite(db)), a.logger)
+}
+
+func (a *app) userService(db *sql.DB) service.UserService {
+	return service.NewUserService(repository.NewUserRepositoryWithClient(dbClient.NewSqliteUserClient(db)), a.logger)
+}
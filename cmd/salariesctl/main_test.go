@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type result struct {
+	code   int
+	stdout string
+	stderr string
+}
+
+func runCommand(databasePath, stdin string, args ...string) result {
+	var stdout, stderr bytes.Buffer
+	code := run(append([]string{"--database", databasePath}, args...), strings.NewReader(stdin), &stdout, &stderr)
+	return result{code: code, stdout: stdout.String(), stderr: stderr.String()}
+}
+
+func TestSalariesctl(t *testing.T) {
+	dir := t.TempDir()
+	databasePath := filepath.Join(dir, "salaries.db")
+	dataset := filepath.Join(dir, "dataset.csv")
+	require.NoError(t, os.WriteFile(dataset, []byte("name,salary,currency,department,on_contract,sub_department\n"+
+		"Anurag,90000,USD,Banking,true,Loan\n"+
+		"Himani,240000,USD,Engineering,false,Platform\n"), 0o600))
+
+	tests := []struct {
+		name       string
+		stdin      string
+		args       []string
+		code       int
+		wantStdout string
+		wantStderr string
+	}{
+		{name: "unknown command", args: []string{"drop"}, code: exitUsage, wantStderr: "usage: salariesctl"},
+		{name: "database does not exist", args: []string{"stats"}, code: exitError, wantStderr: "run salariesctl db init"},
+		{name: "db init", args: []string{"db", "init"}, code: exitOK, wantStdout: "created at schema version"},
+		{name: "db init twice", args: []string{"db", "init"}, code: exitError, wantStderr: "already exists"},
+		{name: "db migrate", args: []string{"db", "migrate"}, code: exitOK, wantStdout: "up to date"},
+		{name: "seed without file", args: []string{"seed"}, code: exitUsage, wantStderr: "--file is required"},
+		{name: "seed", args: []string{"seed", "--file", dataset}, code: exitOK, wantStdout: "imported: 2"},
+		{name: "seed twice", args: []string{"seed", "--file", dataset}, code: exitError, wantStderr: "already has 2 salaries"},
+		{name: "import invalid rows", stdin: "name,salary\nRaghav,lots\n", args: []string{"import", "--file", "-", "--format", "csv"}, code: exitError, wantStderr: "row 1:"},
+		{name: "import dry run", stdin: `{"name": "Raghav", "salary": "70000", "currency": "USD", "department": "Banking", "sub_department": "Loan"}`, args: []string{"import", "--file", "-", "--format", "ndjson", "--dry-run"}, code: exitOK, wantStdout: "valid: 1, imported: 0"},
+		{name: "export", args: []string{"export", "--department", "Banking"}, code: exitOK, wantStdout: "1,Anurag,90000,USD,true,Banking,Loan\n"},
+		{name: "stats", args: []string{"stats", "--by", "departments", "--format", "csv"}, code: exitOK, wantStdout: "Engineering,240000,240000,240000,1"},
+		{name: "user create", stdin: "secret-password\n", args: []string{"user", "create", "--username", "analyst"}, code: exitOK, wantStdout: "user analyst created"},
+		{name: "user create twice", args: []string{"user", "create", "--username", "analyst", "--password", "secret-password"}, code: exitError, wantStderr: "record already exists"},
+		{name: "user create with short password", args: []string{"user", "create", "--username", "intern", "--password", "123"}, code: exitError, wantStderr: "at least 6 characters"},
+		{name: "user reset-password", args: []string{"user", "reset-password", "--username", "analyst", "--password", "new-password"}, code: exitOK},
+		{name: "user reset-password of unknown user", args: []string{"user", "reset-password", "--username", "nobody", "--password", "new-password"}, code: exitError, wantStderr: "record not found"},
+		{name: "backup", args: []string{"backup", "--output", filepath.Join(dir, "backup.db")}, code: exitOK},
+		{name: "backup twice", args: []string{"backup", "--output", filepath.Join(dir, "backup.db")}, code: exitError, wantStderr: "already exists"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := runCommand(databasePath, tt.stdin, tt.args...)
+
+			assert.Equal(t, tt.code, result.code, result.stderr)
+			assert.Contains(t, result.stdout, tt.wantStdout)
+			assert.Contains(t, result.stderr, tt.wantStderr)
+		})
+	}
+
+	backupStats := runCommand(filepath.Join(dir, "backup.db"), "", "stats", "--format", "csv")
+	assert.Equal(t, exitOK, backupStats.code)
+	assert.Contains(t, backupStats.stdout, "165000,240000,90000,2")
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"salaries/pkg/render"
+)
+
+var statsMediaTypes = map[string]string{
+	"json":   "application/json",
+	"csv":    "text/csv",
+	"ndjson": "application/x-ndjson",
+	"yaml":   "application/yaml",
+	"xml":    "application/xml",
+}
+
+func stats(ctx context.Context, a *app, args []string) error {
+	flags := a.newFlagSet("stats")
+	by := flags.String("by", "all", "all, contracts, departments or sub-departments")
+	format := flags.String("format", "json", "json, csv, ndjson, yaml or xml")
+	if err := a.parse(flags, args); err != nil {
+		return err
+	}
+	mediaType, ok := statsMediaTypes[*format]
+	if !ok {
+		return usageError{message: fmt.Sprintf("unknown format %q", *format)}
+	}
+	renderer, err := render.NewDefaultRegistry().Negotiate(mediaType)
+	if err != nil {
+		return err
+	}
+	db, err := a.open(ctx)
+	if err != nil {
+		return err
+	}
+
+	salaryService := a.salaryService(db)
+	var value interface{}
+	switch *by {
+	case "all":
+		value, err = salaryService.GetStatsForAllSalaries(ctx)
+	case "contracts":
+		value, err = salaryService.GetContractsStats(ctx)
+	case "departments":
+		value, err = salaryService.GetDepartmentsStats(ctx)
+	case "sub-departments":
+		value, err = salaryService.GetSubDepartmentsStats(ctx)
+	default:
+		return usageError{message: fmt.Sprintf("unknown grouping %q", *by)}
+	}
+	if err != nil {
+		return err
+	}
+	return renderer.Render(a.stdout, value)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"salaries/pkg/domain"
+	"strings"
+)
+
+func userCreate(ctx context.Context, a *app, args []string) error {
+	flags := a.newFlagSet("user create")
+	username := flags.String("username", "", "name to log in with")
+	role := flags.String("role", domain.RoleUser, "admin, hr or user")
+	password := flags.String("password", "", "password, read from stdin when empty")
+	if err := a.parse(flags, args); err != nil {
+		return err
+	}
+	if *username == "" {
+		return usageError{message: "--username is required"}
+	}
+	db, err := a.open(ctx)
+	if err != nil {
+		return err
+	}
+	if *password == "" {
+		if *password, err = a.readPassword(); err != nil {
+			return err
+		}
+	}
+
+	user, err := a.userService(db).Create(ctx, *username, *password, *role)
+	if err != nil {
+		return fmt.Errorf("creating user %s: %w", *username, err)
+	}
+	fmt.Fprintf(a.stdout, "user %s created with id %d and role %s\n", user.Username, user.ID, user.Role)
+	return nil
+}
+
+func userResetPassword(ctx context.Context, a *app, args []string) error {
+	flags := a.newFlagSet("user reset-password")
+	username := flags.String("username", "", "user to reset")
+	password := flags.String("password", "", "new password, read from stdin when empty")
+	if err := a.parse(flags, args); err != nil {
+		return err
+	}
+	if *username == "" {
+		return usageError{message: "--username is required"}
+	}
+	db, err := a.open(ctx)
+	if err != nil {
+		return err
+	}
+	if *password == "" {
+		if *password, err = a.readPassword(); err != nil {
+			return err
+		}
+	}
+
+	if err := a.userService(db).ResetPassword(ctx, *username, *password); err != nil {
+		return fmt.Errorf("resetting password of user %s: %w", *username, err)
+	}
+	fmt.Fprintf(a.stdout, "password of user %s reset\n", *username)
+	return nil
+}
+
+// readPassword reads the first line of stdin so passwords can be piped instead of showing up in the shell history
+func (a *app) readPassword() (string, error) {
+	fmt.Fprint(a.stderr, "password: ")
+	line, err := bufio.NewReader(a.stdin).ReadString('\n')
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		if err != nil {
+			return "", fmt.Errorf("reading password from stdin: %w", err)
+		}
+		return "", fmt.Errorf("the password can not be empty")
+	}
+	return line, nil
+}
@@ -1,14 +1,45 @@
 package api
 
+import (
+	"errors"
+	"fmt"
+	"salaries/pkg/domain"
+	"time"
+)
+
+var (
+	ErrNotFound = errors.New("record not found")
+	ErrConflict = errors.New("record already exists")
+	// ErrPreconditionFailed is returned when a change is made against a version of the record that is no longer current
+	ErrPreconditionFailed = errors.New("record was modified by another request")
+	// ErrBatchTooLarge is returned when a batch holds or matches more items than allowed
+	ErrBatchTooLarge = errors.New("too many items in the batch")
+	// ErrInvalidQuery is returned when the parameters of an analysis can not be used together or with the data
+	ErrInvalidQuery = errors.New("invalid query")
+	// ErrUnknownPayBand is returned when a salary is assigned a pay band that does not exist
+	ErrUnknownPayBand = errors.New("unknown pay band")
+	// ErrUnknownSalary is returned when a planned change refers to a salary that does not exist or was deleted
+	ErrUnknownSalary = errors.New("unknown salary")
+	// ErrGroupTooSmall is returned instead of figures that would be about too few people to keep them anonymous
+	ErrGroupTooSmall = errors.New("group too small to be reported")
+)
+
 type AppError struct {
 	Error error
 	Code  int
 }
 
+// OtherGroup names the departments and sub-departments merged together because they are too small to be reported on
+// their own
+const OtherGroup = "Other"
+
 type Stats struct {
-	Mean float64
-	Max  float64
-	Min  float64
+	Mean  float64
+	Max   float64
+	Min   float64
+	Count int64
+	// SumOfSquares is not reported, it lets the stats cache check its running sums against the database
+	SumOfSquares float64 `json:"-"`
 }
 
 type DepartmentStats struct {
@@ -20,3 +51,521 @@ type SubDepartmentStats struct {
 	SubDepartment   string
 	DepartmentStats DepartmentStats
 }
+
+type SalaryFilter struct {
+	Department    string `form:"department" json:"department,omitempty"`
+	SubDepartment string `form:"sub_department" json:"sub_department,omitempty"`
+	Currency      string `form:"currency" json:"currency,omitempty"`
+	OnContract    *bool  `form:"on_contract" json:"on_contract,omitempty"`
+}
+
+// Matches reports whether the salary is selected by the filter, the same way the database filters the salaries
+func (f SalaryFilter) Matches(salary *domain.Salary) bool {
+	return (f.Department == "" || f.Department == salary.Department) &&
+		(f.SubDepartment == "" || f.SubDepartment == salary.SubDepartment) &&
+		(f.Currency == "" || f.Currency == salary.Currency) &&
+		(f.OnContract == nil || *f.OnContract == salary.OnContract)
+}
+
+type ExportQuery struct {
+	Format string `form:"format"`
+}
+
+type AuditFilter struct {
+	ActorID   int64     `form:"actor_id"`
+	Action    string    `form:"action"`
+	Entity    string    `form:"entity"`
+	EntityID  int64     `form:"entity_id"`
+	RequestID string    `form:"request_id"`
+	From      time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To        time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit     int       `form:"limit"`
+	Offset    int       `form:"offset"`
+}
+
+type AuditPage struct {
+	Events []domain.AuditEvent `json:"events"`
+	Total  int64               `json:"total"`
+	Limit  int                 `json:"limit"`
+	Offset int                 `json:"offset"`
+}
+
+type ImportQuery struct {
+	DryRun bool `form:"dry_run"`
+}
+
+type ImportRowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+// ImportReport counts the rows read, the rows that passed validation and the rows written, rows are numbered from 1 without the csv header
+type ImportReport struct {
+	DryRun   bool             `json:"dry_run"`
+	Rows     int              `json:"rows"`
+	Valid    int              `json:"valid"`
+	Imported int              `json:"imported"`
+	Errors   []ImportRowError `json:"errors"`
+}
+
+// BatchQuery selects all-or-nothing batches by default, partial batches apply every item that can be applied
+type BatchQuery struct {
+	Partial bool `form:"partial"`
+}
+
+// BulkDeleteQuery selects the salaries to delete by comma separated ids, the filter is used when no ids are given
+type BulkDeleteQuery struct {
+	BatchQuery
+	IDs string `form:"ids"`
+}
+
+const (
+	BatchStatusCreated = "created"
+	BatchStatusDeleted = "deleted"
+	BatchStatusFailed  = "failed"
+	// BatchStatusSkipped marks the valid items of an all-or-nothing batch that was not applied
+	BatchStatusSkipped = "skipped"
+)
+
+// BatchItemResult reports the outcome of an item, items are indexed from 0 in the order they were sent
+type BatchItemResult struct {
+	Index  int    `json:"index"`
+	ID     int64  `json:"id,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type BatchResult struct {
+	Partial   bool              `json:"partial"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Items     []BatchItemResult `json:"items"`
+}
+
+// PurgeQuery overrides the trash retention, salaries deleted more than OlderThan ago are purged
+type PurgeQuery struct {
+	OlderThan *time.Duration `form:"older_than"`
+}
+
+type PurgeResult struct {
+	DeletedBefore time.Time `json:"deleted_before"`
+	Purged        int64     `json:"purged"`
+}
+
+const (
+	GroupByDepartment = "department"
+	GroupByContract   = "on_contract"
+)
+
+// HistogramQuery selects the salaries with the list filters and describes the buckets, Edges is a comma separated list
+// used by the edges strategy, amounts are converted to ConvertTo when it is set
+type HistogramQuery struct {
+	SalaryFilter
+	Strategy  string  `form:"strategy"`
+	Width     float64 `form:"width"`
+	Buckets   int     `form:"buckets"`
+	Edges     string  `form:"edges"`
+	GroupBy   string  `form:"group_by"`
+	ConvertTo string  `form:"convert_to"`
+}
+
+// Histogram counts the salaries of each group in buckets sharing the same edges, bucket i goes from Edges[i] up to
+// Edges[i+1] excluded and the last bucket includes its upper edge
+type Histogram struct {
+	Strategy string           `json:"strategy"`
+	Currency string           `json:"currency,omitempty"`
+	GroupBy  string           `json:"group_by,omitempty"`
+	Edges    []float64        `json:"edges"`
+	Groups   []HistogramGroup `json:"groups"`
+}
+
+// HistogramGroup has a count per bucket, salaries outside explicit edges are counted in Underflow and Overflow
+type HistogramGroup struct {
+	Group     string  `json:"group,omitempty"`
+	Count     int64   `json:"count"`
+	Counts    []int64 `json:"counts"`
+	Underflow int64   `json:"underflow,omitempty"`
+	Overflow  int64   `json:"overflow,omitempty"`
+}
+
+// RankQuery places Amount in Currency among the salaries matching the filters, every salary is converted to Currency
+type RankQuery struct {
+	Amount        float64 `form:"amount" binding:"required"`
+	Currency      string  `form:"currency" binding:"required"`
+	Department    string  `form:"department"`
+	SubDepartment string  `form:"sub_department"`
+	OnContract    *bool   `form:"on_contract"`
+}
+
+// PercentileBoundary is the value of the salaries at a percentile of the group
+type PercentileBoundary struct {
+	Percentile int     `json:"percentile"`
+	Value      float64 `json:"value"`
+}
+
+// PercentileRank is the percentage of the group earning less than Amount, the salaries equal to it count for half. Lower
+// and Upper are the nearest reported percentiles around Amount, they are left out when Amount is beyond them
+type PercentileRank struct {
+	Amount         float64             `json:"amount"`
+	Currency       string              `json:"currency"`
+	Department     string              `json:"department,omitempty"`
+	SubDepartment  string              `json:"sub_department,omitempty"`
+	OnContract     *bool               `json:"on_contract,omitempty"`
+	Count          int64               `json:"count"`
+	PercentileRank float64             `json:"percentile_rank"`
+	Lower          *PercentileBoundary `json:"lower,omitempty"`
+	Upper          *PercentileBoundary `json:"upper,omitempty"`
+}
+
+// PayBandFilter selects the bands with a name or currency, and the bands effective at AsOf when it is set
+type PayBandFilter struct {
+	Name     string    `form:"name"`
+	Currency string    `form:"currency"`
+	AsOf     time.Time `form:"as_of" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// CompaRatioQuery selects the salaries with the list filters and the bands effective at AsOf, now when it is not set
+type CompaRatioQuery struct {
+	SalaryFilter
+	AsOf time.Time `form:"as_of" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+const (
+	BandPositionBelow  = "below"
+	BandPositionWithin = "within"
+	BandPositionAbove  = "above"
+)
+
+// CompaRatio places a salary in its pay band, the salary is converted to the currency of the band. The compa-ratio is the
+// salary over the midpoint and the range penetration is how far the salary is from the minimum to the maximum
+type CompaRatio struct {
+	SalaryID         int64   `json:"salary_id"`
+	Name             string  `json:"name"`
+	Department       string  `json:"department"`
+	SubDepartment    string  `json:"sub_department"`
+	PayBand          string  `json:"pay_band"`
+	PayBandID        int64   `json:"pay_band_id"`
+	Currency         string  `json:"currency"`
+	Salary           float64 `json:"salary"`
+	CompaRatio       float64 `json:"compa_ratio"`
+	RangePenetration float64 `json:"range_penetration"`
+	Position         string  `json:"position"`
+}
+
+// CompaRatioReport has the salaries assigned to a band, Unmatched are the ids of the salaries whose band has no version
+// effective at AsOf in a currency the salary can be converted to
+type CompaRatioReport struct {
+	AsOf      time.Time    `json:"as_of"`
+	Salaries  []CompaRatio `json:"salaries"`
+	Unmatched []int64      `json:"unmatched"`
+}
+
+type OutOfRangeDepartment struct {
+	Department   string       `json:"department"`
+	BelowMinimum []CompaRatio `json:"below_minimum"`
+	AboveMaximum []CompaRatio `json:"above_maximum"`
+}
+
+// OutOfRangeReport lists, per department, the salaries below the minimum or above the maximum of their band
+type OutOfRangeReport struct {
+	AsOf        time.Time              `json:"as_of"`
+	Departments []OutOfRangeDepartment `json:"departments"`
+}
+
+// PayEquityQuery compares the pay of the groups of a demographic attribute, Reference is the group the others are
+// compared to, the largest one by default. MinGroupSize can only raise the configured minimum
+type PayEquityQuery struct {
+	SalaryFilter
+	Attribute    string `form:"attribute"`
+	Reference    string `form:"reference"`
+	MinGroupSize int    `form:"min_group_size"`
+	ConvertTo    string `form:"convert_to"`
+}
+
+// PayGroup has the pay of a group, groups smaller than the minimum size are suppressed without any figure. The gaps are
+// the difference with the reference group as a fraction of its pay, positive when the group is paid less
+type PayGroup struct {
+	Group      string   `json:"group"`
+	Suppressed bool     `json:"suppressed,omitempty"`
+	Count      int64    `json:"count,omitempty"`
+	Mean       float64  `json:"mean,omitempty"`
+	Median     float64  `json:"median,omitempty"`
+	MeanGap    *float64 `json:"mean_gap,omitempty"`
+	MedianGap  *float64 `json:"median_gap,omitempty"`
+}
+
+// PayEquityCell compares the groups of the people with the same department, sub-department and pay band
+type PayEquityCell struct {
+	Department    string     `json:"department"`
+	SubDepartment string     `json:"sub_department"`
+	PayBand       string     `json:"pay_band,omitempty"`
+	Groups        []PayGroup `json:"groups"`
+}
+
+// AdjustedPayGap averages the gaps of a group in the cells where both the group and the reference are reported,
+// weighted by the size of the group in each cell
+type AdjustedPayGap struct {
+	Group     string  `json:"group"`
+	Count     int64   `json:"count"`
+	Cells     int     `json:"cells"`
+	MeanGap   float64 `json:"mean_gap"`
+	MedianGap float64 `json:"median_gap"`
+}
+
+// PayEquityReport has the raw gaps over all the salaries and the gaps adjusted for department, sub-department and pay band
+type PayEquityReport struct {
+	Attribute    string           `json:"attribute"`
+	Reference    string           `json:"reference"`
+	Currency     string           `json:"currency,omitempty"`
+	MinGroupSize int              `json:"min_group_size"`
+	Raw          []PayGroup       `json:"raw"`
+	Adjusted     []AdjustedPayGap `json:"adjusted"`
+	Cells        []PayEquityCell  `json:"cells"`
+}
+
+// AnomalyQuery selects the salaries with the list filters, Method and Threshold override the configured detector.
+// Salaries are compared with the others of their department and sub-department and, unless ConvertTo is set, currency
+type AnomalyQuery struct {
+	SalaryFilter
+	Method    string  `form:"method"`
+	Threshold float64 `form:"threshold"`
+	ConvertTo string  `form:"convert_to"`
+}
+
+const (
+	AnomalyDirectionLow  = "low"
+	AnomalyDirectionHigh = "high"
+)
+
+// Anomaly is a salary outside the range of the salaries of its peers, Low and High are the ends of that range and
+// Peers is how many salaries it was computed from
+type Anomaly struct {
+	SalaryID      int64   `json:"salary_id"`
+	Name          string  `json:"name"`
+	Department    string  `json:"department"`
+	SubDepartment string  `json:"sub_department"`
+	Currency      string  `json:"currency"`
+	Salary        float64 `json:"salary"`
+	Direction     string  `json:"direction"`
+	Low           float64 `json:"low"`
+	High          float64 `json:"high"`
+	Median        float64 `json:"median"`
+	Peers         int     `json:"peers"`
+}
+
+// AnomalyReport lists the outliers of every group with at least MinGroupSize salaries, with the threshold in use
+type AnomalyReport struct {
+	Method       string    `json:"method"`
+	Threshold    float64   `json:"threshold"`
+	Currency     string    `json:"currency,omitempty"`
+	MinGroupSize int       `json:"min_group_size"`
+	Anomalies    []Anomaly `json:"anomalies"`
+}
+
+// CreatedSalary is the response to a create, Warnings flag a salary that is an outlier among its peers
+type CreatedSalary struct {
+	domain.Salary
+	Warnings []Anomaly `json:"warnings,omitempty"`
+}
+
+// SimulationRule raises the salaries matching the filter by Percentage or by Amount. Amount, MaxIncrease and MaxSalary
+// are in Currency when it is set and in the currency of each salary otherwise, MaxIncrease and MaxSalary cap the raise
+type SimulationRule struct {
+	Filter      SalaryFilter `json:"filter"`
+	Percentage  float64      `json:"percentage"`
+	Amount      float64      `json:"amount"`
+	Currency    string       `json:"currency"`
+	MaxIncrease float64      `json:"max_increase"`
+	MaxSalary   float64      `json:"max_salary"`
+}
+
+// SimulationRequest applies every matching rule to a salary in order, each on the salary raised by the previous ones.
+// The stats are computed in ConvertTo when it is set
+type SimulationRequest struct {
+	Rules     []SimulationRule `json:"rules"`
+	ConvertTo string           `json:"convert_to"`
+}
+
+// Validate checks that every rule raises salaries by either a percentage or an amount and that caps are not negative
+func (r SimulationRequest) Validate(maxRules int) error {
+	if len(r.Rules) == 0 || len(r.Rules) > maxRules {
+		return fmt.Errorf("%w: a simulation needs from 1 to %d rules", ErrInvalidQuery, maxRules)
+	}
+	for i, rule := range r.Rules {
+		if (rule.Percentage == 0) == (rule.Amount == 0) {
+			return fmt.Errorf("%w: rule %d needs either a percentage or an amount", ErrInvalidQuery, i)
+		}
+		if rule.Percentage < 0 || rule.Amount < 0 || rule.MaxIncrease < 0 || rule.MaxSalary < 0 {
+			return fmt.Errorf("%w: rule %d can not be negative", ErrInvalidQuery, i)
+		}
+	}
+	return nil
+}
+
+// SimulatedSalary is the projected salary of a record, Rules are the indexes of the rules that raised it
+type SimulatedSalary struct {
+	SalaryID      int64   `json:"salary_id"`
+	Name          string  `json:"name"`
+	Department    string  `json:"department"`
+	SubDepartment string  `json:"sub_department"`
+	OnContract    bool    `json:"on_contract"`
+	Currency      string  `json:"currency"`
+	Before        float64 `json:"before"`
+	After         float64 `json:"after"`
+	Increase      float64 `json:"increase"`
+	Rules         []int   `json:"rules"`
+}
+
+// SimulationStats has the same stats as the stats endpoints for the whole dataset, its departments and sub-departments
+type SimulationStats struct {
+	Overall        Stats                `json:"overall"`
+	Departments    []DepartmentStats    `json:"departments"`
+	SubDepartments []SubDepartmentStats `json:"sub_departments"`
+}
+
+// Simulation projects the raises of a SimulationRequest without changing any salary, Increase is the sum of the raises
+// in Currency, or in the currency of each salary when no conversion was requested
+type Simulation struct {
+	Currency string            `json:"currency,omitempty"`
+	Raised   int               `json:"raised"`
+	Increase float64           `json:"increase"`
+	Salaries []SimulatedSalary `json:"salaries"`
+	Before   SimulationStats   `json:"before"`
+	After    SimulationStats   `json:"after"`
+}
+
+type BudgetFilter struct {
+	Department string `form:"department"`
+	FiscalYear int    `form:"fiscal_year"`
+}
+
+// PayrollChangeFilter selects the changes of a kind or salary, effective from From included to To excluded when they are set
+type PayrollChangeFilter struct {
+	Kind     string    `form:"kind"`
+	SalaryID int64     `form:"salary_id"`
+	From     time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To       time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// PayrollQuery selects a fiscal year and the currency of the costs, the current fiscal year and the configured currency
+// by default, Department limits a projection to a department
+type PayrollQuery struct {
+	FiscalYear int    `form:"fiscal_year"`
+	Currency   string `form:"currency"`
+	Department string `form:"department"`
+}
+
+// MonthlyCost is the payroll cost of a month named like 2026-01, Headcount counts the people paid during the month
+type MonthlyCost struct {
+	Month     string  `json:"month"`
+	Cost      float64 `json:"cost"`
+	Headcount int     `json:"headcount"`
+}
+
+type DepartmentPayroll struct {
+	Department string        `json:"department"`
+	Annual     float64       `json:"annual"`
+	Monthly    []MonthlyCost `json:"monthly"`
+}
+
+// PayrollProjection is the cost of the fiscal year from From included to To excluded, from the current salaries and
+// the planned changes. Salaries are annual and paid by the day within each month
+type PayrollProjection struct {
+	FiscalYear  int                 `json:"fiscal_year"`
+	Currency    string              `json:"currency"`
+	From        time.Time           `json:"from"`
+	To          time.Time           `json:"to"`
+	Annual      float64             `json:"annual"`
+	Monthly     []MonthlyCost       `json:"monthly"`
+	Departments []DepartmentPayroll `json:"departments"`
+}
+
+const (
+	BudgetStatusUnder      = "under_budget"
+	BudgetStatusOver       = "over_budget"
+	BudgetStatusUnbudgeted = "unbudgeted"
+)
+
+// BudgetVariance compares the budget of a department with its projected cost, Variance is what is left of the budget
+// and is negative over budget, VariancePercent is relative to the budget and missing without one
+type BudgetVariance struct {
+	Department      string   `json:"department"`
+	Budget          float64  `json:"budget"`
+	Projected       float64  `json:"projected"`
+	Variance        float64  `json:"variance"`
+	VariancePercent *float64 `json:"variance_percent,omitempty"`
+	Status          string   `json:"status"`
+}
+
+type VarianceReport struct {
+	FiscalYear  int              `json:"fiscal_year"`
+	Currency    string           `json:"currency"`
+	Budget      float64          `json:"budget"`
+	Projected   float64          `json:"projected"`
+	Variance    float64          `json:"variance"`
+	Departments []BudgetVariance `json:"departments"`
+}
+
+// SnapshotFilter selects the snapshots taken from From included to To excluded when they are set
+type SnapshotFilter struct {
+	From time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To   time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+const (
+	StatsMetricMean  = "mean"
+	StatsMetricMax   = "max"
+	StatsMetricMin   = "min"
+	StatsMetricCount = "count"
+)
+
+// StatsHistoryQuery selects a metric of a group of the stats endpoints in the snapshots matching the SnapshotFilter,
+// the mean of the whole dataset by default. Department and SubDepartment limit the series of their groups
+type StatsHistoryQuery struct {
+	SnapshotFilter
+	Metric        string `form:"metric"`
+	Group         string `form:"group"`
+	Department    string `form:"department"`
+	SubDepartment string `form:"sub_department"`
+}
+
+type StatsPoint struct {
+	SnapshotID int64     `json:"snapshot_id"`
+	TakenAt    time.Time `json:"taken_at"`
+	Value      float64   `json:"value"`
+}
+
+// StatsSeries is the time series of a department or sub-department, or of the whole group when it has neither
+type StatsSeries struct {
+	Department    string       `json:"department,omitempty"`
+	SubDepartment string       `json:"sub_department,omitempty"`
+	Points        []StatsPoint `json:"points"`
+}
+
+type StatsHistory struct {
+	Metric string        `json:"metric"`
+	Group  string        `json:"group"`
+	Series []StatsSeries `json:"series"`
+}
+
+type SnapshotDiffQuery struct {
+	From int64 `form:"from" binding:"required"`
+	To   int64 `form:"to" binding:"required"`
+}
+
+// StatsDelta compares the stats of a group in two snapshots, Before or After is missing when the group is only in one
+// of them and Change, the difference of every stat, is only set when it is in both
+type StatsDelta struct {
+	Group         string `json:"group"`
+	Department    string `json:"department,omitempty"`
+	SubDepartment string `json:"sub_department,omitempty"`
+	Before        *Stats `json:"before,omitempty"`
+	After         *Stats `json:"after,omitempty"`
+	Change        *Stats `json:"change,omitempty"`
+}
+
+type SnapshotDiff struct {
+	From    domain.StatsSnapshot `json:"from"`
+	To      domain.StatsSnapshot `json:"to"`
+	Changes []StatsDelta         `json:"changes"`
+}
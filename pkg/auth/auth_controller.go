@@ -4,21 +4,15 @@ import (
 	"errors"
 	"github.com/gin-gonic/gin"
 	"net/http"
-	"salaries/pkg/domain"
-)
-
-const (
-	dummyUsername = "pmagnaghi"
-	dummyPassword = "123456"
 )
 
 type AuthenticationInput struct {
 	Username string `json:"username" binding:"required"`
-	Password string `json:"password" binding:"required"`
+	Password string `json:"password" binding:"required" log:"redact"`
 }
 
 type Token struct {
-	AccessToken string `json:"access_token"`
+	AccessToken string `json:"access_token" log:"redact"`
 }
 
 type Controller interface {
@@ -43,13 +37,15 @@ func (c authControllerImpl) Login(context *gin.Context) {
 		return
 	}
 
-	// TODO: Here is necessary find user in db and validate user and password carefully
-	// TODO: For the exercise only validate a dummy user
-	user, err := c.validate(input.Username, input.Password)
-	if err != nil {
+	user, err := c.authService.Authenticate(context.Request.Context(), input.Username, input.Password)
+	if errors.Is(err, ErrInvalidCredentials) {
 		context.JSON(http.StatusUnauthorized, err)
 		return
 	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, err)
+		return
+	}
 
 	jwt, err := c.authService.GenerateJWT(user)
 	if err != nil {
@@ -59,14 +55,3 @@ func (c authControllerImpl) Login(context *gin.Context) {
 
 	context.JSON(http.StatusOK, Token{jwt})
 }
-
-func (c authControllerImpl) validate(username, password string) (*domain.User, error) {
-	if username == dummyUsername && password == dummyPassword {
-		return &domain.User{
-			ID:       1,
-			Username: dummyUsername,
-			Password: dummyPassword,
-		}, nil
-	}
-	return nil, errors.New("invalid user")
-}
@@ -1,63 +1,119 @@
 package auth
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
+	"salaries/pkg/api"
 	"salaries/pkg/domain"
 	"salaries/pkg/logger"
-	"strconv"
+	"salaries/pkg/metrics"
+	"salaries/pkg/repository"
 	"strings"
 	"time"
 )
 
+const AuthorizationHeader = "Authorization"
+
 const (
-	AuthorizationHeader = "Authorization"
-	TokenTTL            = "3600"
-	JwtPrivateKey       = "DUMMY_KEY"
+	loginSuccess = "success"
+	loginFailure = "failure"
 )
 
-var privateKey = []byte(JwtPrivateKey)
-
 type Service interface {
+	Authenticate(ctx context.Context, username, password string) (*domain.User, error)
 	GenerateJWT(user *domain.User) (string, error)
-	VerifyToken(context *gin.Context) error
+	VerifyToken(context *gin.Context) (*domain.User, error)
 }
 
+var (
+	ErrInvalidCredentials = errors.New("invalid user")
+	ErrInvalidToken       = errors.New("invalid token provided")
+)
+
 type authServiceImpl struct {
-	logger logger.Logger
+	userRepository repository.UserRepository
+	signingKey     []byte
+	tokenTTL       time.Duration
+	logger         logger.Logger
+	loginAttempts  *metrics.CounterVec
 }
 
-func NewAuthService(logger logger.Logger) Service {
+// NewAuthService signs the tokens with signingKey, they expire tokenTTL after the login
+func NewAuthService(userRepository repository.UserRepository, signingKey string, tokenTTL time.Duration, logger logger.Logger, registry *metrics.Registry) Service {
+	loginAttempts := metrics.NewCounterVec("auth_login_attempts_total", "Total number of login attempts by result.", "result")
+	registry.MustRegister(loginAttempts)
 	return &authServiceImpl{
-		logger: logger,
+		userRepository: userRepository,
+		signingKey:     []byte(signingKey),
+		tokenTTL:       tokenTTL,
+		logger:         logger,
+		loginAttempts:  loginAttempts,
 	}
 }
 
+// Authenticate checks the password against the hash stored for the user, unknown users and wrong passwords get the same error
+func (s authServiceImpl) Authenticate(ctx context.Context, username, password string) (*domain.User, error) {
+	user, err := s.userRepository.ReadByUsername(ctx, username)
+	if err != nil && !errors.Is(err, api.ErrNotFound) {
+		s.logger.WithContext(ctx).Error("error getting user: %s", err.Error())
+		return nil, err
+	}
+	if user == nil {
+		CheckPassword(string(dummyHash), password)
+		s.loginAttempts.WithLabelValues(loginFailure).Inc()
+		return nil, ErrInvalidCredentials
+	}
+	if !CheckPassword(user.PasswordHash, password) {
+		s.loginAttempts.WithLabelValues(loginFailure).Inc()
+		return nil, ErrInvalidCredentials
+	}
+	s.loginAttempts.WithLabelValues(loginSuccess).Inc()
+	return user, nil
+}
+
+// GenerateJWT issues a token for the user, the role is not part of it since it is read again on every request
 func (s authServiceImpl) GenerateJWT(user *domain.User) (string, error) {
-	tokenTTL, _ := strconv.Atoi(TokenTTL)
+	now := time.Now()
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"id":  user.ID,
-		"iat": time.Now().Unix(),
-		"eat": time.Now().Add(time.Second * time.Duration(tokenTTL)).Unix(),
+		"id":       user.ID,
+		"username": user.Username,
+		"iat":      now.Unix(),
+		"exp":      now.Add(s.tokenTTL).Unix(),
 	})
-	return token.SignedString(privateKey)
+	return token.SignedString(s.signingKey)
 }
 
-func (s authServiceImpl) VerifyToken(context *gin.Context) error {
+// VerifyToken validates the bearer token of the request and returns the user it was issued for as stored in the
+// repository, so a changed role applies to the tokens already issued and a removed user is rejected
+func (s authServiceImpl) VerifyToken(context *gin.Context) (*domain.User, error) {
 	token, err := s.getToken(context)
-
 	if err != nil {
-		return err
+		return nil, err
 	}
-	_, ok := token.Claims.(jwt.MapClaims)
-
-	if ok && token.Valid {
-		return nil
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+	// jwt-go only checks exp when it is present, a token without it would never expire
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, fmt.Errorf("%w: missing or expired exp", ErrInvalidToken)
 	}
+	username, _ := claims["username"].(string)
+	// numeric claims are decoded as float64
+	id, _ := claims["id"].(float64)
 
-	return errors.New("invalid token provided")
+	user, err := s.userRepository.ReadByUsername(context.Request.Context(), username)
+	if errors.Is(err, api.ErrNotFound) || (err == nil && (user == nil || user.ID != int64(id))) {
+		return nil, fmt.Errorf("%w: unknown user %d", ErrInvalidToken, int64(id))
+	}
+	if err != nil {
+		s.logger.WithContext(context.Request.Context()).Error("error getting user: %s", err.Error())
+		return nil, err
+	}
+	return user, nil
 }
 
 func (s authServiceImpl) getToken(context *gin.Context) (*jwt.Token, error) {
@@ -67,7 +123,7 @@ func (s authServiceImpl) getToken(context *gin.Context) (*jwt.Token, error) {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
 
-		return privateKey, nil
+		return s.signingKey, nil
 	})
 	return token, err
 }
@@ -0,0 +1,128 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"salaries/pkg/api"
+	"salaries/pkg/auth"
+	"salaries/pkg/domain"
+	"salaries/pkg/logger"
+	"salaries/pkg/metrics"
+	"salaries/pkg/repository"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSigningKey = "test-signing-key"
+
+func TestService_Authenticate(t *testing.T) {
+	passwordHash, err := auth.HashPassword("123456")
+	require.NoError(t, err)
+	userRepository := &repository.UserRepositoryMock{
+		ReadByUsernameFunc: func(ctx context.Context, username string) (*domain.User, error) {
+			switch username {
+			case "pmagnaghi":
+				return &domain.User{ID: 1, Username: username, PasswordHash: passwordHash, Role: domain.RoleAdmin}, nil
+			case "broken":
+				return nil, errors.New("database is locked")
+			}
+			return nil, api.ErrNotFound
+		},
+	}
+	tests := []struct {
+		name      string
+		username  string
+		password  string
+		wantError error
+	}{
+		{name: "valid credentials", username: "pmagnaghi", password: "123456"},
+		{name: "wrong password", username: "pmagnaghi", password: "654321", wantError: auth.ErrInvalidCredentials},
+		{name: "unknown user", username: "nobody", password: "123456", wantError: auth.ErrInvalidCredentials},
+	}
+	registry := metrics.NewRegistry()
+	authService := auth.NewAuthService(userRepository, testSigningKey, time.Hour, logger.NewLogger(), registry)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := authService.Authenticate(context.Background(), tt.username, tt.password)
+			if tt.wantError != nil {
+				assert.ErrorIs(t, err, tt.wantError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), user.ID)
+			assert.Equal(t, domain.RoleAdmin, user.Role)
+		})
+	}
+
+	_, err = authService.Authenticate(context.Background(), "broken", "123456")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, auth.ErrInvalidCredentials)
+
+	var output strings.Builder
+	require.NoError(t, registry.WriteText(&output))
+	assert.Contains(t, output.String(), `auth_login_attempts_total{result="success"} 1`+"\n")
+	assert.Contains(t, output.String(), `auth_login_attempts_total{result="failure"} 2`+"\n", "repository errors are not login attempts")
+}
+
+func TestService_VerifyToken(t *testing.T) {
+	role := domain.RoleAdmin
+	userRepository := &repository.UserRepositoryMock{
+		ReadByUsernameFunc: func(ctx context.Context, username string) (*domain.User, error) {
+			if username == "pmagnaghi" {
+				return &domain.User{ID: 1, Username: username, Role: role}, nil
+			}
+			return nil, api.ErrNotFound
+		},
+	}
+	authService := auth.NewAuthService(userRepository, testSigningKey, time.Hour, logger.NewLogger(), metrics.NewRegistry())
+	signed := func(key string, claims jwt.MapClaims) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(key))
+		require.NoError(t, err)
+		return token
+	}
+	verify := func(token string) (*domain.User, error) {
+		request := httptest.NewRequest(http.MethodGet, "/api/salaries", nil)
+		request.Header.Set(auth.AuthorizationHeader, "Bearer "+token)
+		context, _ := gin.CreateTestContext(httptest.NewRecorder())
+		context.Request = request
+		return authService.VerifyToken(context)
+	}
+
+	token, err := authService.GenerateJWT(&domain.User{ID: 1, Username: "pmagnaghi", Role: domain.RoleAdmin})
+	require.NoError(t, err)
+	user, err := verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, domain.RoleAdmin, user.Role)
+
+	// the role is read from the repository, a demoted user loses it with the tokens already issued
+	role = domain.RoleUser
+	user, err = verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, domain.RoleUser, user.Role)
+
+	now := time.Now()
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "expired", token: signed(testSigningKey, jwt.MapClaims{"id": 1, "username": "pmagnaghi", "exp": now.Add(-time.Minute).Unix()})},
+		{name: "without exp", token: signed(testSigningKey, jwt.MapClaims{"id": 1, "username": "pmagnaghi", "eat": now.Add(time.Hour).Unix()})},
+		{name: "other key", token: signed("DUMMY_KEY", jwt.MapClaims{"id": 1, "username": "pmagnaghi", "exp": now.Add(time.Hour).Unix()})},
+		{name: "unknown user", token: signed(testSigningKey, jwt.MapClaims{"id": 2, "username": "nobody", "exp": now.Add(time.Hour).Unix()})},
+		{name: "other id", token: signed(testSigningKey, jwt.MapClaims{"id": 2, "username": "pmagnaghi", "exp": now.Add(time.Hour).Unix()})},
+		{name: "missing", token: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verify(tt.token)
+			assert.Error(t, err)
+		})
+	}
+}
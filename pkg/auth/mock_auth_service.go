@@ -4,6 +4,7 @@
 package auth
 
 import (
+	"context"
 	"github.com/gin-gonic/gin"
 	"salaries/pkg/domain"
 	"sync"
@@ -19,10 +20,13 @@ var _ Service = &ServiceMock{}
 //
 //		// make and configure a mocked Service
 //		mockedService := &ServiceMock{
+//			AuthenticateFunc: func(ctx context.Context, username string, password string) (*domain.User, error) {
+//				panic("mock out the Authenticate method")
+//			},
 //			GenerateJWTFunc: func(user *domain.User) (string, error) {
 //				panic("mock out the GenerateJWT method")
 //			},
-//			VerifyTokenFunc: func(context *gin.Context) error {
+//			VerifyTokenFunc: func(contextMoqParam *gin.Context) (*domain.User, error) {
 //				panic("mock out the VerifyToken method")
 //			},
 //		}
@@ -32,14 +36,26 @@ var _ Service = &ServiceMock{}
 //
 //	}
 type ServiceMock struct {
+	// AuthenticateFunc mocks the Authenticate method.
+	AuthenticateFunc func(ctx context.Context, username string, password string) (*domain.User, error)
+
 	// GenerateJWTFunc mocks the GenerateJWT method.
 	GenerateJWTFunc func(user *domain.User) (string, error)
 
 	// VerifyTokenFunc mocks the VerifyToken method.
-	VerifyTokenFunc func(context *gin.Context) error
+	VerifyTokenFunc func(contextMoqParam *gin.Context) (*domain.User, error)
 
 	// calls tracks calls to the methods.
 	calls struct {
+		// Authenticate holds details about calls to the Authenticate method.
+		Authenticate []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+			// Password is the password argument value.
+			Password string
+		}
 		// GenerateJWT holds details about calls to the GenerateJWT method.
 		GenerateJWT []struct {
 			// User is the user argument value.
@@ -47,12 +63,53 @@ type ServiceMock struct {
 		}
 		// VerifyToken holds details about calls to the VerifyToken method.
 		VerifyToken []struct {
-			// Context is the context argument value.
-			Context *gin.Context
+			// ContextMoqParam is the contextMoqParam argument value.
+			ContextMoqParam *gin.Context
 		}
 	}
-	lockGenerateJWT sync.RWMutex
-	lockVerifyToken sync.RWMutex
+	lockAuthenticate sync.RWMutex
+	lockGenerateJWT  sync.RWMutex
+	lockVerifyToken  sync.RWMutex
+}
+
+// Authenticate calls AuthenticateFunc.
+func (mock *ServiceMock) Authenticate(ctx context.Context, username string, password string) (*domain.User, error) {
+	if mock.AuthenticateFunc == nil {
+		panic("ServiceMock.AuthenticateFunc: method is nil but Service.Authenticate was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+		Password string
+	}{
+		Ctx:      ctx,
+		Username: username,
+		Password: password,
+	}
+	mock.lockAuthenticate.Lock()
+	mock.calls.Authenticate = append(mock.calls.Authenticate, callInfo)
+	mock.lockAuthenticate.Unlock()
+	return mock.AuthenticateFunc(ctx, username, password)
+}
+
+// AuthenticateCalls gets all the calls that were made to Authenticate.
+// Check the length with:
+//
+//	len(mockedService.AuthenticateCalls())
+func (mock *ServiceMock) AuthenticateCalls() []struct {
+	Ctx      context.Context
+	Username string
+	Password string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+		Password string
+	}
+	mock.lockAuthenticate.RLock()
+	calls = mock.calls.Authenticate
+	mock.lockAuthenticate.RUnlock()
+	return calls
 }
 
 // GenerateJWT calls GenerateJWTFunc.
@@ -88,19 +145,19 @@ func (mock *ServiceMock) GenerateJWTCalls() []struct {
 }
 
 // VerifyToken calls VerifyTokenFunc.
-func (mock *ServiceMock) VerifyToken(context *gin.Context) error {
+func (mock *ServiceMock) VerifyToken(contextMoqParam *gin.Context) (*domain.User, error) {
 	if mock.VerifyTokenFunc == nil {
 		panic("ServiceMock.VerifyTokenFunc: method is nil but Service.VerifyToken was just called")
 	}
 	callInfo := struct {
-		Context *gin.Context
+		ContextMoqParam *gin.Context
 	}{
-		Context: context,
+		ContextMoqParam: contextMoqParam,
 	}
 	mock.lockVerifyToken.Lock()
 	mock.calls.VerifyToken = append(mock.calls.VerifyToken, callInfo)
 	mock.lockVerifyToken.Unlock()
-	return mock.VerifyTokenFunc(context)
+	return mock.VerifyTokenFunc(contextMoqParam)
 }
 
 // VerifyTokenCalls gets all the calls that were made to VerifyToken.
@@ -108,10 +165,10 @@ func (mock *ServiceMock) VerifyToken(context *gin.Context) error {
 //
 //	len(mockedService.VerifyTokenCalls())
 func (mock *ServiceMock) VerifyTokenCalls() []struct {
-	Context *gin.Context
+	ContextMoqParam *gin.Context
 } {
 	var calls []struct {
-		Context *gin.Context
+		ContextMoqParam *gin.Context
 	}
 	mock.lockVerifyToken.RLock()
 	calls = mock.calls.VerifyToken
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+type loginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type loginResponse struct {
+	AccessToken string `json:"access_token"`
+}
+
+// Login gets an access token for the user, the credentials are kept to log in again when the token is rejected
+func (c *Client) Login(ctx context.Context, username, password string) (string, error) {
+	token, err := c.login(ctx, username, password)
+	if err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = token
+	c.username = username
+	c.password = password
+	return token, nil
+}
+
+func (c *Client) login(ctx context.Context, username, password string) (string, error) {
+	response, err := c.send(ctx, request{
+		method: http.MethodPost,
+		path:   "/auth/login",
+		body:   loginRequest{Username: username, Password: password},
+	})
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	var login loginResponse
+	if err := decodeResponse(response, &login); err != nil {
+		return "", err
+	}
+	return login.AccessToken, nil
+}
+
+func (c *Client) canRefresh() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.username != ""
+}
+
+func (c *Client) refresh(ctx context.Context) error {
+	c.mu.Lock()
+	username, password := c.username, c.password
+	c.mu.Unlock()
+	token, err := c.login(ctx, username, password)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = token
+	return nil
+}
@@ -0,0 +1,222 @@
+// Package client is a typed Go client for the salaries api.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"salaries/pkg/api"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultRetries = 3
+	DefaultBackoff = 100 * time.Millisecond
+)
+
+var ErrUnauthorized = errors.New("unauthorized")
+
+// Error is returned for responses with an error status code, it unwraps to api.ErrNotFound, api.ErrConflict,
+// api.ErrPreconditionFailed or ErrUnauthorized when it applies
+type Error struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("salaries api responded %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), strings.TrimSpace(e.Body))
+}
+
+func (e *Error) Unwrap() error {
+	switch e.StatusCode {
+	case http.StatusNotFound:
+		return api.ErrNotFound
+	case http.StatusConflict:
+		return api.ErrConflict
+	case http.StatusPreconditionFailed:
+		return api.ErrPreconditionFailed
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	}
+	return nil
+}
+
+type Option func(c *Client)
+
+// WithHTTPClient replaces http.DefaultClient
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithToken starts the client with a token from a previous login
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithRetries sets how many times idempotent calls are retried on network errors, 429 and 5xx responses
+func WithRetries(retries int) Option {
+	return func(c *Client) {
+		c.retries = retries
+	}
+}
+
+// WithBackoff sets the wait before the first retry, it doubles on every retry
+func WithBackoff(backoff time.Duration) Option {
+	return func(c *Client) {
+		c.backoff = backoff
+	}
+}
+
+// Client calls the salaries api, it is safe for concurrent use
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	retries    int
+	backoff    time.Duration
+
+	mu       sync.Mutex
+	token    string
+	username string
+	password string
+}
+
+// New returns a client for the api at baseURL, for example http://localhost:8080
+func New(baseURL string, options ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		retries:    DefaultRetries,
+		backoff:    DefaultBackoff,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// Token returns the access token of the last login
+func (c *Client) Token() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.token
+}
+
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	header http.Header
+	body   interface{}
+}
+
+func (r request) idempotent() bool {
+	return r.method != http.MethodPost
+}
+
+// do sends the request and decodes the response into out, expired tokens are refreshed once with the credentials of the last login
+func (c *Client) do(ctx context.Context, r request, out interface{}) error {
+	response, err := c.send(ctx, r)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode == http.StatusUnauthorized && c.canRefresh() {
+		response.Body.Close()
+		if err := c.refresh(ctx); err != nil {
+			return err
+		}
+		if response, err = c.send(ctx, r); err != nil {
+			return err
+		}
+	}
+	defer response.Body.Close()
+	return decodeResponse(response, out)
+}
+
+func decodeResponse(response *http.Response, out interface{}) error {
+	if response.StatusCode >= http.StatusBadRequest {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 4096))
+		return &Error{StatusCode: response.StatusCode, Body: string(body)}
+	}
+	if out == nil {
+		_, err := io.Copy(io.Discard, response.Body)
+		return err
+	}
+	return json.NewDecoder(response.Body).Decode(out)
+}
+
+// send retries idempotent requests with exponential backoff, the response of the last attempt is returned
+func (c *Client) send(ctx context.Context, r request) (*http.Response, error) {
+	var body []byte
+	if r.body != nil {
+		var err error
+		if body, err = json.Marshal(r.body); err != nil {
+			return nil, err
+		}
+	}
+	attempts := 1
+	if r.idempotent() {
+		attempts += c.retries
+	}
+	backoff := c.backoff
+	for attempt := 1; ; attempt++ {
+		response, err := c.attempt(ctx, r, body)
+		if attempt == attempts || !retryable(response, err) || ctx.Err() != nil {
+			return response, err
+		}
+		if response != nil {
+			response.Body.Close()
+		}
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+func (c *Client) attempt(ctx context.Context, r request, body []byte) (*http.Response, error) {
+	target := c.baseURL + r.path
+	if len(r.query) > 0 {
+		target += "?" + r.query.Encode()
+	}
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	httpRequest, err := http.NewRequestWithContext(ctx, r.method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range r.header {
+		httpRequest.Header[key] = values
+	}
+	httpRequest.Header.Set("Accept", "application/json")
+	if body != nil {
+		httpRequest.Header.Set("Content-Type", "application/json")
+	}
+	if token := c.Token(); token != "" {
+		httpRequest.Header.Set("Authorization", "Bearer "+token)
+	}
+	return c.httpClient.Do(httpRequest)
+}
+
+func retryable(response *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= http.StatusInternalServerError
+}
@@ -0,0 +1,185 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"salaries/pkg/api"
+	"salaries/pkg/client"
+	"salaries/pkg/domain"
+	"salaries/pkg/server/servertest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyTransport responds with the given statuses before forwarding the requests matching the method and path
+type flakyTransport struct {
+	mu       sync.Mutex
+	method   string
+	path     string
+	statuses []int
+	requests map[string]int
+}
+
+func (f *flakyTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	f.mu.Lock()
+	if f.requests == nil {
+		f.requests = map[string]int{}
+	}
+	f.requests[request.Method+" "+request.URL.Path]++
+	var status int
+	if request.Method == f.method && request.URL.Path == f.path && len(f.statuses) > 0 {
+		status, f.statuses = f.statuses[0], f.statuses[1:]
+	}
+	f.mu.Unlock()
+	if status != 0 {
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader("{}")), Header: http.Header{}, Request: request}, nil
+	}
+	return http.DefaultTransport.RoundTrip(request)
+}
+
+func (f *flakyTransport) count(method, path string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests[method+" "+path]
+}
+
+func TestClient_Salaries(t *testing.T) {
+	testServer := servertest.NewServer(t)
+	ctx := context.Background()
+	salaries := client.New(testServer.URL)
+
+	_, err := salaries.Login(ctx, "pmagnaghi", "wrong password")
+	assert.ErrorIs(t, err, client.ErrUnauthorized)
+	_, err = salaries.ListSalaries(ctx, api.SalaryFilter{})
+	assert.ErrorIs(t, err, client.ErrUnauthorized)
+
+	token, err := salaries.Login(ctx, "pmagnaghi", "123456")
+	require.NoError(t, err)
+	assert.NotEmpty(t, token)
+
+	anurag, err := salaries.CreateSalary(ctx, &domain.Salary{Name: "Anurag", Salary: 90000, Currency: "USD", OnContract: true, Department: "Banking", SubDepartment: "Loan"})
+	require.NoError(t, err)
+	assert.NotZero(t, anurag.ID)
+	_, err = salaries.CreateSalary(ctx, &domain.Salary{Name: "Himani", Salary: 240000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"})
+	require.NoError(t, err)
+
+	salary, err := salaries.GetSalary(ctx, anurag.ID)
+	require.NoError(t, err)
+	assert.Equal(t, anurag, salary)
+
+	onContract := true
+	list, err := salaries.ListSalaries(ctx, api.SalaryFilter{OnContract: &onContract})
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Salary{*anurag}, list)
+
+	stats, err := salaries.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, api.Stats{Mean: 165000, Max: 240000, Min: 90000, Count: 2}, *stats)
+	contractsStats, err := salaries.ContractsStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), contractsStats.Count)
+	departmentsStats, err := salaries.DepartmentsStats(ctx)
+	require.NoError(t, err)
+	assert.Len(t, departmentsStats, 2)
+	subDepartmentsStats, err := salaries.SubDepartmentsStats(ctx)
+	require.NoError(t, err)
+	assert.Len(t, subDepartmentsStats, 2)
+
+	raised := *salary
+	raised.Salary = 95000
+	updated, err := salaries.UpdateSalary(ctx, &raised)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), updated.Version)
+	_, err = salaries.UpdateSalary(ctx, salary)
+	assert.ErrorIs(t, err, api.ErrPreconditionFailed)
+	assert.ErrorIs(t, salaries.DeleteSalaryVersion(ctx, anurag.ID, salary.Version), api.ErrPreconditionFailed)
+
+	require.NoError(t, salaries.DeleteSalary(ctx, anurag.ID))
+	_, err = salaries.GetSalary(ctx, anurag.ID)
+	assert.ErrorIs(t, err, api.ErrNotFound)
+	assert.ErrorIs(t, salaries.DeleteSalary(ctx, anurag.ID), api.ErrNotFound)
+}
+
+func TestClient_RefreshesRejectedToken(t *testing.T) {
+	testServer := servertest.NewServer(t)
+	transport := &flakyTransport{method: http.MethodGet, path: "/api/salaries/stats", statuses: []int{http.StatusUnauthorized}}
+	salaries := client.New(testServer.URL, client.WithHTTPClient(&http.Client{Transport: transport}))
+
+	_, err := salaries.Login(context.Background(), "pmagnaghi", "123456")
+	require.NoError(t, err)
+	_, err = salaries.Stats(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, transport.count(http.MethodPost, "/auth/login"))
+	assert.Equal(t, 2, transport.count(http.MethodGet, "/api/salaries/stats"))
+}
+
+func TestClient_RetriesIdempotentCalls(t *testing.T) {
+	testServer := servertest.NewServer(t)
+	ctx := context.Background()
+	tests := []struct {
+		name      string
+		method    string
+		path      string
+		statuses  []int
+		call      func(salaries *client.Client) error
+		wantCalls int
+		wantError bool
+	}{
+		{
+			name:      "get recovers after server errors",
+			method:    http.MethodGet,
+			path:      "/api/salaries/stats",
+			statuses:  []int{http.StatusServiceUnavailable, http.StatusTooManyRequests},
+			call:      func(salaries *client.Client) error { _, err := salaries.Stats(ctx); return err },
+			wantCalls: 3,
+		},
+		{
+			name:      "get gives up after the retries",
+			method:    http.MethodGet,
+			path:      "/api/salaries/stats",
+			statuses:  []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway},
+			call:      func(salaries *client.Client) error { _, err := salaries.Stats(ctx); return err },
+			wantCalls: 3,
+			wantError: true,
+		},
+		{
+			name:     "create is not retried",
+			method:   http.MethodPost,
+			path:     "/api/salaries",
+			statuses: []int{http.StatusServiceUnavailable},
+			call: func(salaries *client.Client) error {
+				_, err := salaries.CreateSalary(ctx, &domain.Salary{Name: "Anurag", Salary: 90000, Currency: "USD", Department: "Banking", SubDepartment: "Loan"})
+				return err
+			},
+			wantCalls: 1,
+			wantError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &flakyTransport{method: tt.method, path: tt.path, statuses: tt.statuses}
+			salaries := client.New(testServer.URL,
+				client.WithHTTPClient(&http.Client{Transport: transport}),
+				client.WithRetries(2),
+				client.WithBackoff(time.Millisecond))
+			_, err := salaries.Login(ctx, "pmagnaghi", "123456")
+			require.NoError(t, err)
+
+			err = tt.call(salaries)
+
+			assert.Equal(t, tt.wantError, err != nil)
+			assert.Equal(t, tt.wantCalls, transport.count(tt.method, tt.path))
+			var apiError *client.Error
+			if tt.wantError {
+				assert.True(t, errors.As(err, &apiError))
+			}
+		})
+	}
+}
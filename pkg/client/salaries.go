@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"salaries/pkg/api"
+	"salaries/pkg/domain"
+	"strconv"
+)
+
+const salariesPath = "/api/salaries"
+
+func (c *Client) ListSalaries(ctx context.Context, filter api.SalaryFilter) ([]domain.Salary, error) {
+	var salaries []domain.Salary
+	err := c.do(ctx, request{method: http.MethodGet, path: salariesPath, query: filterQuery(filter)}, &salaries)
+	return salaries, err
+}
+
+func (c *Client) GetSalary(ctx context.Context, id int64) (*domain.Salary, error) {
+	var salary domain.Salary
+	if err := c.do(ctx, request{method: http.MethodGet, path: salaryPath(id)}, &salary); err != nil {
+		return nil, err
+	}
+	return &salary, nil
+}
+
+// CreateSalary returns the salary created with its id, creates are not retried
+func (c *Client) CreateSalary(ctx context.Context, salary *domain.Salary) (*domain.Salary, error) {
+	var created domain.Salary
+	if err := c.do(ctx, request{method: http.MethodPost, path: salariesPath, body: salary}, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// UpdateSalary replaces the salary, it fails with api.ErrPreconditionFailed when the salary changed since the version it was read at
+func (c *Client) UpdateSalary(ctx context.Context, salary *domain.Salary) (*domain.Salary, error) {
+	var updated domain.Salary
+	r := request{method: http.MethodPut, path: salaryPath(salary.ID), header: ifMatch(salary.Version), body: salary}
+	if err := c.do(ctx, r, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (c *Client) DeleteSalary(ctx context.Context, id int64) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: salaryPath(id)}, nil)
+}
+
+// DeleteSalaryVersion deletes the salary only if it is still at the version it was read at
+func (c *Client) DeleteSalaryVersion(ctx context.Context, id int64, version int64) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: salaryPath(id), header: ifMatch(version)}, nil)
+}
+
+func (c *Client) Stats(ctx context.Context) (*api.Stats, error) {
+	var stats api.Stats
+	if err := c.do(ctx, request{method: http.MethodGet, path: salariesPath + "/stats"}, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+func (c *Client) ContractsStats(ctx context.Context) (*api.Stats, error) {
+	var stats api.Stats
+	if err := c.do(ctx, request{method: http.MethodGet, path: salariesPath + "/stats/contracts"}, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+func (c *Client) DepartmentsStats(ctx context.Context) ([]api.DepartmentStats, error) {
+	var stats []api.DepartmentStats
+	err := c.do(ctx, request{method: http.MethodGet, path: salariesPath + "/stats/departments"}, &stats)
+	return stats, err
+}
+
+func (c *Client) SubDepartmentsStats(ctx context.Context) ([]api.SubDepartmentStats, error) {
+	var stats []api.SubDepartmentStats
+	err := c.do(ctx, request{method: http.MethodGet, path: salariesPath + "/stats/sub-departments"}, &stats)
+	return stats, err
+}
+
+// ifMatch makes the request conditional on the version, 0 sends no precondition
+func ifMatch(version int64) http.Header {
+	if version == 0 {
+		return nil
+	}
+	return http.Header{"If-Match": []string{`"` + strconv.FormatInt(version, 10) + `"`}}
+}
+
+func salaryPath(id int64) string {
+	return salariesPath + "/" + strconv.FormatInt(id, 10)
+}
+
+func filterQuery(filter api.SalaryFilter) url.Values {
+	query := url.Values{}
+	if filter.Department != "" {
+		query.Set("department", filter.Department)
+	}
+	if filter.SubDepartment != "" {
+		query.Set("sub_department", filter.SubDepartment)
+	}
+	if filter.Currency != "" {
+		query.Set("currency", filter.Currency)
+	}
+	if filter.OnContract != nil {
+		query.Set("on_contract", strconv.FormatBool(*filter.OnContract))
+	}
+	return query
+}
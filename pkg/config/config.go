@@ -0,0 +1,196 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultMaxBatchSize is used when MAX_BATCH_SIZE is not set
+	DefaultMaxBatchSize = 1000
+	// DefaultTrashRetention is used when TRASH_RETENTION is not set
+	DefaultTrashRetention = 30 * 24 * time.Hour
+	// DefaultIdempotencyWindow is used when IDEMPOTENCY_WINDOW is not set
+	DefaultIdempotencyWindow = 24 * time.Hour
+	// DefaultPayEquityMinGroupSize is used when PAY_EQUITY_MIN_GROUP_SIZE is not set
+	DefaultPayEquityMinGroupSize = 5
+	// DefaultAnomalyMethod is used when ANOMALY_METHOD is not set
+	DefaultAnomalyMethod = "iqr"
+	// DefaultAnomalyMinGroupSize is used when ANOMALY_MIN_GROUP_SIZE is not set
+	DefaultAnomalyMinGroupSize = 5
+	// DefaultBudgetCurrency is used when BUDGET_CURRENCY is not set
+	DefaultBudgetCurrency = "USD"
+	// DefaultStatsSnapshotInterval is used when STATS_SNAPSHOT_INTERVAL is not set
+	DefaultStatsSnapshotInterval = 24 * time.Hour
+	// DefaultStatsCacheCheckInterval is used when STATS_CACHE_CHECK_INTERVAL is not set
+	DefaultStatsCacheCheckInterval = 5 * time.Minute
+	// DefaultRankMinGroupSize is used when RANK_MIN_GROUP_SIZE is not set
+	DefaultRankMinGroupSize = 5
+	// DefaultStatsMinGroupSize is used when STATS_MIN_GROUP_SIZE is not set
+	DefaultStatsMinGroupSize = 5
+	// DefaultStatsNoiseMaxSalary is used when STATS_NOISE_MAX_SALARY is not set
+	DefaultStatsNoiseMaxSalary = 500000
+	// DefaultJWTTokenTTL is used when JWT_TOKEN_TTL is not set
+	DefaultJWTTokenTTL = time.Hour
+)
+
+type Config struct {
+	Port         string
+	TrustedProxy string
+	DatabasePath string
+	LogLevel     string
+	LogFormat    string
+	// LogRedactedFields are masked in the logs on top of the default salary, name, password and token fields
+	LogRedactedFields []string
+	// TracingExporter is none, stdout, file or otlp
+	TracingExporter string
+	TracingFilePath string
+	// MaxBatchSize limits the salaries created or deleted by a single bulk request
+	MaxBatchSize int
+	// TrashRetention is how long deleted salaries are kept before an admin purge removes them
+	TrashRetention time.Duration
+	// RequireIfMatch rejects updates and deletes of a salary without an If-Match header
+	RequireIfMatch bool
+	// IdempotencyWindow is how long the response of a POST is replayed for a retry with the same Idempotency-Key
+	IdempotencyWindow time.Duration
+	// ExchangeRates is the value of one unit of each currency in a common base, used to convert amounts in the stats
+	ExchangeRates map[string]float64
+	// PayEquityMinGroupSize is the smallest demographic group reported by the pay equity analysis
+	PayEquityMinGroupSize int
+	// AnomalyMethod is iqr, zscore or mad, AnomalyThreshold is the multiplier of the method, 0 for its default
+	AnomalyMethod    string
+	AnomalyThreshold float64
+	// AnomalyMinGroupSize is the fewest salaries of a department and sub-department that outliers are looked for in
+	AnomalyMinGroupSize int
+	// FiscalYearStart is the month fiscal years start in, a fiscal year is named after the calendar year it starts in
+	FiscalYearStart time.Month
+	// BudgetCurrency is the currency of the payroll projections when none is requested
+	BudgetCurrency string
+	// StatsSnapshotInterval is how often the output of the stats endpoints is persisted, 0 disables the scheduled snapshots
+	StatsSnapshotInterval time.Duration
+	// StatsCacheCheckInterval is how often the cached stats are compared with the database, 0 disables the checks
+	StatsCacheCheckInterval time.Duration
+	// RankMinGroupSize is the fewest salaries an amount is ranked among
+	RankMinGroupSize int
+	// StatsMinGroupSize is the fewest salaries of a group of the stats endpoints, smaller departments and sub-departments
+	// are merged into an Other group
+	StatsMinGroupSize int
+	// StatsNoiseEpsilon is the privacy budget of the noise added to the stats for the users that are neither HR nor
+	// admins, 0 disables the noise
+	StatsNoiseEpsilon float64
+	// StatsNoiseMinSalary and StatsNoiseMaxSalary bound the salaries the noise is scaled to, salaries outside them are
+	// clamped to them
+	StatsNoiseMinSalary float64
+	StatsNoiseMaxSalary float64
+	// JWTSigningKey signs the access tokens, a random key is used when it is empty so the tokens don't survive a restart
+	JWTSigningKey string
+	// JWTTokenTTL is how long an access token is accepted after the login
+	JWTTokenTTL time.Duration
+}
+
+// Load reads the configuration from environment variables, falling back to the defaults for local development
+func Load() Config {
+	return Config{
+		Port:                  getEnv("PORT", ":8080"),
+		TrustedProxy:          getEnv("TRUSTED_PROXY", "192.168.1.2"),
+		DatabasePath:          getEnv("DATABASE_PATH", "salaries.db"),
+		LogLevel:              getEnv("LOG_LEVEL", "info"),
+		LogFormat:             getEnv("LOG_FORMAT", "text"),
+		LogRedactedFields:     getEnvList("LOG_REDACTED_FIELDS"),
+		TracingExporter:       getEnv("TRACING_EXPORTER", "stdout"),
+		TracingFilePath:       getEnv("TRACING_FILE_PATH", "traces.json"),
+		MaxBatchSize:          getEnvInt("MAX_BATCH_SIZE", DefaultMaxBatchSize),
+		TrashRetention:        getEnvDuration("TRASH_RETENTION", DefaultTrashRetention),
+		RequireIfMatch:        getEnvBool("REQUIRE_IF_MATCH"),
+		IdempotencyWindow:     getEnvDuration("IDEMPOTENCY_WINDOW", DefaultIdempotencyWindow),
+		ExchangeRates:         getEnvRates("EXCHANGE_RATES"),
+		PayEquityMinGroupSize: getEnvInt("PAY_EQUITY_MIN_GROUP_SIZE", DefaultPayEquityMinGroupSize),
+		AnomalyMethod:         getEnv("ANOMALY_METHOD", DefaultAnomalyMethod),
+		AnomalyThreshold:      getEnvFloat("ANOMALY_THRESHOLD", 0),
+		AnomalyMinGroupSize:   getEnvInt("ANOMALY_MIN_GROUP_SIZE", DefaultAnomalyMinGroupSize),
+		FiscalYearStart:       getEnvMonth("FISCAL_YEAR_START_MONTH", time.January),
+		BudgetCurrency:        strings.ToUpper(getEnv("BUDGET_CURRENCY", DefaultBudgetCurrency)),
+		StatsSnapshotInterval: getEnvDuration("STATS_SNAPSHOT_INTERVAL", DefaultStatsSnapshotInterval),
+		RankMinGroupSize:      getEnvInt("RANK_MIN_GROUP_SIZE", DefaultRankMinGroupSize),
+		StatsMinGroupSize:     getEnvInt("STATS_MIN_GROUP_SIZE", DefaultStatsMinGroupSize),
+		StatsNoiseEpsilon:     getEnvFloat("STATS_NOISE_EPSILON", 0),
+		StatsNoiseMinSalary:   getEnvFloat("STATS_NOISE_MIN_SALARY", 0),
+		StatsNoiseMaxSalary:   getEnvFloat("STATS_NOISE_MAX_SALARY", DefaultStatsNoiseMaxSalary),
+
+		StatsCacheCheckInterval: getEnvDuration("STATS_CACHE_CHECK_INTERVAL", DefaultStatsCacheCheckInterval),
+		JWTSigningKey:           getEnv("JWT_SIGNING_KEY", ""),
+		JWTTokenTTL:             getEnvDuration("JWT_TOKEN_TTL", DefaultJWTTokenTTL),
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvMonth reads a month number from 1 to 12
+func getEnvMonth(key string, defaultValue time.Month) time.Month {
+	value := getEnvInt(key, int(defaultValue))
+	if value > 12 {
+		return defaultValue
+	}
+	return time.Month(value)
+}
+
+// getEnvFloat returns the default value when the variable is not a positive number
+func getEnvFloat(key string, defaultValue float64) float64 {
+	value, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvBool(key string) bool {
+	value, _ := strconv.ParseBool(os.Getenv(key))
+	return value
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value < 0 {
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getEnvRates reads comma separated CODE=RATE pairs, pairs without a positive rate are ignored
+func getEnvRates(key string) map[string]float64 {
+	rates := map[string]float64{}
+	for _, pair := range getEnvList(key) {
+		code, value, _ := strings.Cut(pair, "=")
+		rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || rate <= 0 {
+			continue
+		}
+		rates[strings.ToUpper(strings.TrimSpace(code))] = rate
+	}
+	return rates
+}
@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"salaries/pkg/api"
+	"salaries/pkg/service"
+)
+
+type AuditController interface {
+	GetAll(context *gin.Context)
+}
+
+type auditControllerImpl struct {
+	auditService service.AuditService
+}
+
+func NewAuditController(auditService service.AuditService) AuditController {
+	return &auditControllerImpl{
+		auditService: auditService,
+	}
+}
+
+func (c auditControllerImpl) GetAll(context *gin.Context) {
+	var filter api.AuditFilter
+	if err := context.ShouldBindQuery(&filter); err != nil {
+		context.JSON(http.StatusBadRequest, api.AppError{
+			Error: err,
+			Code:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	page, err := c.auditService.Find(context.Request.Context(), filter)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, api.AppError{
+			Error: err,
+			Code:  http.StatusInternalServerError,
+		})
+		return
+	}
+	context.JSON(http.StatusOK, page)
+}
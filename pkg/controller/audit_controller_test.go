@@ -0,0 +1,99 @@
+package controller_test
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"salaries/pkg/api"
+	"salaries/pkg/auth"
+	"salaries/pkg/controller"
+	"salaries/pkg/domain"
+	"salaries/pkg/logger"
+	"salaries/pkg/middleware"
+	"salaries/pkg/service"
+	"testing"
+)
+
+func TestHTTPHandler_GetAuditEvents(t *testing.T) {
+	type fields struct {
+		authService  auth.Service
+		auditService service.AuditService
+	}
+	tests := []struct {
+		name   string
+		query  string
+		fields fields
+		status int
+	}{
+		{
+			name:  "not an admin",
+			query: "",
+			fields: fields{
+				authService: &auth.ServiceMock{
+					VerifyTokenFunc: func(context *gin.Context) (*domain.User, error) {
+						return &domain.User{ID: 2, Username: "analyst", Role: domain.RoleUser}, nil
+					},
+				},
+			},
+			status: http.StatusForbidden,
+		},
+		{
+			name:  "getting audit events",
+			query: "?action=delete&entity_id=1&from=2022-12-01T00:00:00Z&limit=10",
+			fields: fields{
+				authService: adminAuthService,
+				auditService: &service.AuditServiceMock{
+					FindFunc: func(ctx context.Context, filter api.AuditFilter) (*api.AuditPage, error) {
+						assert.Equal(t, domain.AuditActionDelete, filter.Action)
+						assert.Equal(t, int64(1), filter.EntityID)
+						assert.Equal(t, 10, filter.Limit)
+						assert.False(t, filter.From.IsZero())
+						return &api.AuditPage{Limit: filter.Limit}, nil
+					},
+				},
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:  "invalid filter",
+			query: "?from=yesterday",
+			fields: fields{
+				authService: adminAuthService,
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:  "error getting audit events",
+			query: "",
+			fields: fields{
+				authService: adminAuthService,
+				auditService: &service.AuditServiceMock{
+					FindFunc: func(ctx context.Context, filter api.AuditFilter) (*api.AuditPage, error) {
+						return nil, errors.New("error getting audit events")
+					},
+				},
+			},
+			status: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auditController := controller.NewAuditController(tt.fields.auditService)
+
+			w := httptest.NewRecorder()
+			_, r := gin.CreateTestContext(w)
+
+			r.Use(middleware.NewAuthMiddleware(tt.fields.authService, logger.NewLogger()), middleware.NewRoleMiddleware(domain.RoleAdmin))
+
+			r.GET("/api/audit", auditController.GetAll)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/audit"+tt.query, nil)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package controller
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"salaries/pkg/api"
+	"salaries/pkg/currency"
+	"salaries/pkg/domain"
+	"salaries/pkg/service"
+	"strconv"
+)
+
+type BudgetController interface {
+	GetBudgets(context *gin.Context)
+	CreateBudget(context *gin.Context)
+	UpdateBudget(context *gin.Context)
+	DeleteBudget(context *gin.Context)
+	GetChanges(context *gin.Context)
+	CreateChange(context *gin.Context)
+	UpdateChange(context *gin.Context)
+	DeleteChange(context *gin.Context)
+	GetProjection(context *gin.Context)
+	GetVariance(context *gin.Context)
+}
+
+type budgetControllerImpl struct {
+	budgetService service.BudgetService
+}
+
+// NewBudgetController serves the department budgets, the planned payroll changes and the payroll reports
+func NewBudgetController(budgetService service.BudgetService) BudgetController {
+	return &budgetControllerImpl{
+		budgetService: budgetService,
+	}
+}
+
+func (c budgetControllerImpl) GetBudgets(context *gin.Context) {
+	var filter api.BudgetFilter
+	if err := context.ShouldBindQuery(&filter); err != nil {
+		c.error(context, http.StatusBadRequest, err)
+		return
+	}
+	budgets, err := c.budgetService.GetBudgets(context.Request.Context(), filter)
+	if err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, budgets)
+}
+
+// CreateBudget adds a budget, a department has a single budget per fiscal year and a second one gets a 409
+func (c budgetControllerImpl) CreateBudget(context *gin.Context) {
+	budget, ok := c.bindBudget(context)
+	if !ok {
+		return
+	}
+	if err := c.budgetService.CreateBudget(context.Request.Context(), budget); err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	context.JSON(http.StatusCreated, budget)
+}
+
+func (c budgetControllerImpl) UpdateBudget(context *gin.Context) {
+	budgetID, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		c.error(context, http.StatusBadRequest, err)
+		return
+	}
+	budget, ok := c.bindBudget(context)
+	if !ok {
+		return
+	}
+	budget.ID = budgetID
+	if err := c.budgetService.UpdateBudget(context.Request.Context(), budget); err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, budget)
+}
+
+func (c budgetControllerImpl) DeleteBudget(context *gin.Context) {
+	budgetID, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		c.error(context, http.StatusBadRequest, err)
+		return
+	}
+	if err := c.budgetService.DeleteBudgetByID(context.Request.Context(), budgetID); err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	context.Status(http.StatusNoContent)
+}
+
+func (c budgetControllerImpl) GetChanges(context *gin.Context) {
+	var filter api.PayrollChangeFilter
+	if err := context.ShouldBindQuery(&filter); err != nil {
+		c.error(context, http.StatusBadRequest, err)
+		return
+	}
+	changes, err := c.budgetService.GetChanges(context.Request.Context(), filter)
+	if err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, changes)
+}
+
+// CreateChange plans a hire, termination or raise, a change of a salary that is not in the list gets a 422
+func (c budgetControllerImpl) CreateChange(context *gin.Context) {
+	change, ok := c.bindChange(context)
+	if !ok {
+		return
+	}
+	if err := c.budgetService.CreateChange(context.Request.Context(), change); err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	context.JSON(http.StatusCreated, change)
+}
+
+func (c budgetControllerImpl) UpdateChange(context *gin.Context) {
+	changeID, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		c.error(context, http.StatusBadRequest, err)
+		return
+	}
+	change, ok := c.bindChange(context)
+	if !ok {
+		return
+	}
+	change.ID = changeID
+	if err := c.budgetService.UpdateChange(context.Request.Context(), change); err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, change)
+}
+
+func (c budgetControllerImpl) DeleteChange(context *gin.Context) {
+	changeID, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		c.error(context, http.StatusBadRequest, err)
+		return
+	}
+	if err := c.budgetService.DeleteChangeByID(context.Request.Context(), changeID); err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	context.Status(http.StatusNoContent)
+}
+
+func (c budgetControllerImpl) GetProjection(context *gin.Context) {
+	var query api.PayrollQuery
+	if err := context.ShouldBindQuery(&query); err != nil {
+		c.error(context, http.StatusBadRequest, err)
+		return
+	}
+	projection, err := c.budgetService.GetProjection(context.Request.Context(), query)
+	if err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, projection)
+}
+
+func (c budgetControllerImpl) GetVariance(context *gin.Context) {
+	var query api.PayrollQuery
+	if err := context.ShouldBindQuery(&query); err != nil {
+		c.error(context, http.StatusBadRequest, err)
+		return
+	}
+	report, err := c.budgetService.GetVariance(context.Request.Context(), query)
+	if err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, report)
+}
+
+func (c budgetControllerImpl) bindBudget(context *gin.Context) (*domain.Budget, bool) {
+	var budget domain.Budget
+	if err := context.ShouldBindJSON(&budget); err != nil {
+		c.error(context, http.StatusBadRequest, err)
+		return nil, false
+	}
+	if err := budget.Validate(); err != nil {
+		c.error(context, http.StatusBadRequest, err)
+		return nil, false
+	}
+	return &budget, true
+}
+
+func (c budgetControllerImpl) bindChange(context *gin.Context) (*domain.PayrollChange, bool) {
+	var change domain.PayrollChange
+	if err := context.ShouldBindJSON(&change); err != nil {
+		c.error(context, http.StatusBadRequest, err)
+		return nil, false
+	}
+	if err := change.Validate(); err != nil {
+		c.error(context, http.StatusBadRequest, err)
+		return nil, false
+	}
+	return &change, true
+}
+
+func (c budgetControllerImpl) serviceError(context *gin.Context, err error) {
+	switch {
+	case errors.Is(err, api.ErrNotFound):
+		c.error(context, http.StatusNotFound, err)
+	case errors.Is(err, api.ErrConflict):
+		c.error(context, http.StatusConflict, err)
+	case errors.Is(err, api.ErrInvalidQuery):
+		c.error(context, http.StatusBadRequest, err)
+	case errors.Is(err, api.ErrUnknownSalary), errors.Is(err, currency.ErrUnknownCurrency):
+		c.error(context, http.StatusUnprocessableEntity, err)
+	default:
+		c.error(context, http.StatusInternalServerError, err)
+	}
+}
+
+func (c budgetControllerImpl) error(context *gin.Context, code int, err error) {
+	context.JSON(code, api.AppError{
+		Error: err,
+		Code:  code,
+	})
+}
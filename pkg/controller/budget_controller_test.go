@@ -0,0 +1,153 @@
+package controller_test
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"salaries/pkg/api"
+	"salaries/pkg/auth"
+	"salaries/pkg/controller"
+	"salaries/pkg/currency"
+	"salaries/pkg/domain"
+	"salaries/pkg/logger"
+	"salaries/pkg/middleware"
+	"salaries/pkg/service"
+	"strings"
+	"testing"
+)
+
+func TestHTTPHandler_Budgets(t *testing.T) {
+	budgetService := &service.BudgetServiceMock{
+		CreateBudgetFunc: func(ctx context.Context, budget *domain.Budget) error {
+			if budget.Department == "Banking" {
+				return api.ErrConflict
+			}
+			return nil
+		},
+		CreateChangeFunc: func(ctx context.Context, change *domain.PayrollChange) error {
+			if change.SalaryID == 404 {
+				return api.ErrUnknownSalary
+			}
+			return nil
+		},
+		GetProjectionFunc: func(ctx context.Context, query api.PayrollQuery) (*api.PayrollProjection, error) {
+			if query.Currency == "XXX" {
+				return nil, currency.ErrUnknownCurrency
+			}
+			if query.FiscalYear == 26 {
+				return nil, api.ErrInvalidQuery
+			}
+			return &api.PayrollProjection{FiscalYear: query.FiscalYear}, nil
+		},
+		GetVarianceFunc: func(ctx context.Context, query api.PayrollQuery) (*api.VarianceReport, error) {
+			return &api.VarianceReport{FiscalYear: query.FiscalYear}, nil
+		},
+	}
+	tests := []struct {
+		name        string
+		authService auth.Service
+		method      string
+		path        string
+		body        string
+		status      int
+	}{
+		{
+			name:        "admin creates a budget",
+			authService: adminAuthService,
+			method:      http.MethodPost,
+			path:        "/api/payroll/budgets",
+			body:        `{"department": "Engineering", "fiscal_year": 2026, "currency": "USD", "amount": 1000000}`,
+			status:      http.StatusCreated,
+		},
+		{
+			name:        "users can not create budgets",
+			authService: authService,
+			method:      http.MethodPost,
+			path:        "/api/payroll/budgets",
+			body:        `{"department": "Engineering", "fiscal_year": 2026, "currency": "USD", "amount": 1000000}`,
+			status:      http.StatusForbidden,
+		},
+		{
+			name:        "negative budget",
+			authService: adminAuthService,
+			method:      http.MethodPost,
+			path:        "/api/payroll/budgets",
+			body:        `{"department": "Engineering", "fiscal_year": 2026, "currency": "USD", "amount": -1}`,
+			status:      http.StatusBadRequest,
+		},
+		{
+			name:        "second budget of a department",
+			authService: adminAuthService,
+			method:      http.MethodPost,
+			path:        "/api/payroll/budgets",
+			body:        `{"department": "Banking", "fiscal_year": 2026, "currency": "USD", "amount": 1000000}`,
+			status:      http.StatusConflict,
+		},
+		{
+			name:        "raise of an unknown salary",
+			authService: adminAuthService,
+			method:      http.MethodPost,
+			path:        "/api/payroll/changes",
+			body:        `{"kind": "raise", "salary_id": 404, "percentage": 5, "effective_date": "2026-07-01T00:00:00Z"}`,
+			status:      http.StatusUnprocessableEntity,
+		},
+		{
+			name:        "hire without a salary",
+			authService: adminAuthService,
+			method:      http.MethodPost,
+			path:        "/api/payroll/changes",
+			body:        `{"kind": "hire", "department": "Engineering", "effective_date": "2026-07-01T00:00:00Z"}`,
+			status:      http.StatusBadRequest,
+		},
+		{
+			name:        "projection",
+			authService: authService,
+			method:      http.MethodGet,
+			path:        "/api/payroll/projection?fiscal_year=2026",
+			status:      http.StatusOK,
+		},
+		{
+			name:        "projection of an invalid year",
+			authService: authService,
+			method:      http.MethodGet,
+			path:        "/api/payroll/projection?fiscal_year=26",
+			status:      http.StatusBadRequest,
+		},
+		{
+			name:        "projection in an unknown currency",
+			authService: authService,
+			method:      http.MethodGet,
+			path:        "/api/payroll/projection?currency=XXX",
+			status:      http.StatusUnprocessableEntity,
+		},
+		{
+			name:        "variance",
+			authService: authService,
+			method:      http.MethodGet,
+			path:        "/api/payroll/variance?fiscal_year=2026",
+			status:      http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budgetController := controller.NewBudgetController(budgetService)
+
+			w := httptest.NewRecorder()
+			_, r := gin.CreateTestContext(w)
+
+			routes := r.Group("/api/payroll", middleware.NewAuthMiddleware(tt.authService, logger.NewLogger()))
+			routes.GET("/projection", budgetController.GetProjection)
+			routes.GET("/variance", budgetController.GetVariance)
+			admin := r.Group("/api/payroll", middleware.NewAuthMiddleware(tt.authService, logger.NewLogger()), middleware.NewRoleMiddleware(domain.RoleAdmin))
+			admin.POST("/budgets", budgetController.CreateBudget)
+			admin.POST("/changes", budgetController.CreateChange)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
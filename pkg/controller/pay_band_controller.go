@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"salaries/pkg/api"
+	"salaries/pkg/domain"
+	"salaries/pkg/service"
+	"strconv"
+)
+
+type PayBandController interface {
+	GetAll(context *gin.Context)
+	GetByID(context *gin.Context)
+	Create(context *gin.Context)
+	Update(context *gin.Context)
+	Delete(context *gin.Context)
+	GetCompaRatios(context *gin.Context)
+	GetOutOfRange(context *gin.Context)
+}
+
+type payBandControllerImpl struct {
+	payBandService service.PayBandService
+}
+
+// NewPayBandController serves the pay bands and the reports placing the salaries in their band
+func NewPayBandController(payBandService service.PayBandService) PayBandController {
+	return &payBandControllerImpl{
+		payBandService: payBandService,
+	}
+}
+
+func (c payBandControllerImpl) GetAll(context *gin.Context) {
+	var filter api.PayBandFilter
+	if err := context.ShouldBindQuery(&filter); err != nil {
+		c.error(context, http.StatusBadRequest, err)
+		return
+	}
+	bands, err := c.payBandService.GetAll(context.Request.Context(), filter)
+	if err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, bands)
+}
+
+func (c payBandControllerImpl) GetByID(context *gin.Context) {
+	bandID, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		c.error(context, http.StatusBadRequest, err)
+		return
+	}
+	band, err := c.payBandService.GetByID(context.Request.Context(), bandID)
+	if err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, band)
+}
+
+// Create adds a band, a band with the same name and currency effective in the same period gets a 409
+func (c payBandControllerImpl) Create(context *gin.Context) {
+	band, ok := c.bind(context)
+	if !ok {
+		return
+	}
+	if err := c.payBandService.Create(context.Request.Context(), band); err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	context.JSON(http.StatusCreated, band)
+}
+
+func (c payBandControllerImpl) Update(context *gin.Context) {
+	bandID, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		c.error(context, http.StatusBadRequest, err)
+		return
+	}
+	band, ok := c.bind(context)
+	if !ok {
+		return
+	}
+	band.ID = bandID
+	if err := c.payBandService.Update(context.Request.Context(), band); err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, band)
+}
+
+// Delete removes a band, the last band of a name that is assigned to salaries can not be deleted and gets a 409
+func (c payBandControllerImpl) Delete(context *gin.Context) {
+	bandID, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		c.error(context, http.StatusBadRequest, err)
+		return
+	}
+	if err := c.payBandService.DeleteByID(context.Request.Context(), bandID); err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	context.Status(http.StatusNoContent)
+}
+
+func (c payBandControllerImpl) GetCompaRatios(context *gin.Context) {
+	var query api.CompaRatioQuery
+	if err := context.ShouldBindQuery(&query); err != nil {
+		c.error(context, http.StatusBadRequest, err)
+		return
+	}
+	report, err := c.payBandService.GetCompaRatios(context.Request.Context(), query)
+	if err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, report)
+}
+
+func (c payBandControllerImpl) GetOutOfRange(context *gin.Context) {
+	var query api.CompaRatioQuery
+	if err := context.ShouldBindQuery(&query); err != nil {
+		c.error(context, http.StatusBadRequest, err)
+		return
+	}
+	report, err := c.payBandService.GetOutOfRange(context.Request.Context(), query)
+	if err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, report)
+}
+
+func (c payBandControllerImpl) bind(context *gin.Context) (*domain.PayBand, bool) {
+	var band domain.PayBand
+	if err := context.ShouldBindJSON(&band); err != nil {
+		c.error(context, http.StatusBadRequest, err)
+		return nil, false
+	}
+	if err := band.Validate(); err != nil {
+		c.error(context, http.StatusBadRequest, err)
+		return nil, false
+	}
+	return &band, true
+}
+
+func (c payBandControllerImpl) serviceError(context *gin.Context, err error) {
+	switch {
+	case errors.Is(err, api.ErrNotFound):
+		c.error(context, http.StatusNotFound, err)
+	case errors.Is(err, api.ErrConflict):
+		c.error(context, http.StatusConflict, err)
+	default:
+		c.error(context, http.StatusInternalServerError, err)
+	}
+}
+
+func (c payBandControllerImpl) error(context *gin.Context, code int, err error) {
+	context.JSON(code, api.AppError{
+		Error: err,
+		Code:  code,
+	})
+}
@@ -0,0 +1,118 @@
+package controller_test
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"salaries/pkg/api"
+	"salaries/pkg/auth"
+	"salaries/pkg/controller"
+	"salaries/pkg/domain"
+	"salaries/pkg/logger"
+	"salaries/pkg/middleware"
+	"salaries/pkg/service"
+	"strings"
+	"testing"
+)
+
+func TestHTTPHandler_PayBands(t *testing.T) {
+	band := `{"name": "L3", "currency": "USD", "min": 80000, "mid": 100000, "max": 120000, "effective_from": "2025-01-01T00:00:00Z"}`
+	payBandService := &service.PayBandServiceMock{
+		CreateFunc: func(ctx context.Context, band *domain.PayBand) error {
+			if band.Currency == "EUR" {
+				return api.ErrConflict
+			}
+			band.ID = 1
+			return nil
+		},
+		DeleteByIDFunc: func(ctx context.Context, id int64) error {
+			return api.ErrConflict
+		},
+		GetCompaRatiosFunc: func(ctx context.Context, query api.CompaRatioQuery) (*api.CompaRatioReport, error) {
+			return &api.CompaRatioReport{AsOf: query.AsOf, Salaries: []api.CompaRatio{}, Unmatched: []int64{}}, nil
+		},
+	}
+	tests := []struct {
+		name        string
+		authService auth.Service
+		method      string
+		path        string
+		body        string
+		status      int
+	}{
+		{
+			name:        "create band",
+			authService: adminAuthService,
+			method:      http.MethodPost,
+			path:        "/api/pay-bands",
+			body:        band,
+			status:      http.StatusCreated,
+		},
+		{
+			name:        "only admins can create bands",
+			authService: authService,
+			method:      http.MethodPost,
+			path:        "/api/pay-bands",
+			body:        band,
+			status:      http.StatusForbidden,
+		},
+		{
+			name:        "mid outside the band",
+			authService: adminAuthService,
+			method:      http.MethodPost,
+			path:        "/api/pay-bands",
+			body:        strings.Replace(band, `"mid": 100000`, `"mid": 130000`, 1),
+			status:      http.StatusBadRequest,
+		},
+		{
+			name:        "overlapping band",
+			authService: adminAuthService,
+			method:      http.MethodPost,
+			path:        "/api/pay-bands",
+			body:        strings.Replace(band, "USD", "EUR", 1),
+			status:      http.StatusConflict,
+		},
+		{
+			name:        "delete an assigned band",
+			authService: adminAuthService,
+			method:      http.MethodDelete,
+			path:        "/api/pay-bands/1",
+			status:      http.StatusConflict,
+		},
+		{
+			name:        "compa-ratios",
+			authService: authService,
+			method:      http.MethodGet,
+			path:        "/api/pay-bands/compa-ratios?department=Engineering&as_of=2025-06-01T00:00:00Z",
+			status:      http.StatusOK,
+		},
+		{
+			name:        "invalid date",
+			authService: authService,
+			method:      http.MethodGet,
+			path:        "/api/pay-bands/compa-ratios?as_of=june",
+			status:      http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payBandController := controller.NewPayBandController(payBandService)
+
+			w := httptest.NewRecorder()
+			_, r := gin.CreateTestContext(w)
+
+			r.Use(middleware.NewAuthMiddleware(tt.authService, logger.NewLogger()))
+			r.GET("/api/pay-bands/compa-ratios", payBandController.GetCompaRatios)
+			admin := r.Group("/api/pay-bands", middleware.NewRoleMiddleware(domain.RoleAdmin))
+			admin.POST("", payBandController.Create)
+			admin.DELETE("/:id", payBandController.Delete)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
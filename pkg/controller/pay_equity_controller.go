@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"salaries/pkg/api"
+	"salaries/pkg/currency"
+	"salaries/pkg/domain"
+	"salaries/pkg/service"
+	"strconv"
+)
+
+type PayEquityController interface {
+	GetDemographics(context *gin.Context)
+	SaveDemographics(context *gin.Context)
+	DeleteDemographics(context *gin.Context)
+	GetPayEquity(context *gin.Context)
+}
+
+type payEquityControllerImpl struct {
+	payEquityService service.PayEquityService
+}
+
+// NewPayEquityController serves the demographics of the salaries and the pay equity analysis, its routes must be
+// restricted to the hr and admin roles
+func NewPayEquityController(payEquityService service.PayEquityService) PayEquityController {
+	return &payEquityControllerImpl{
+		payEquityService: payEquityService,
+	}
+}
+
+func (c payEquityControllerImpl) GetDemographics(context *gin.Context) {
+	salaryID, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		c.error(context, http.StatusBadRequest, err)
+		return
+	}
+	demographics, err := c.payEquityService.GetDemographics(context.Request.Context(), salaryID)
+	if err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, demographics)
+}
+
+// SaveDemographics replaces the demographics of a salary, empty attributes are unknown
+func (c payEquityControllerImpl) SaveDemographics(context *gin.Context) {
+	salaryID, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		c.error(context, http.StatusBadRequest, err)
+		return
+	}
+	var demographics domain.Demographics
+	if err := context.ShouldBindJSON(&demographics); err != nil {
+		c.error(context, http.StatusBadRequest, err)
+		return
+	}
+	demographics.SalaryID = salaryID
+	if err := c.payEquityService.SaveDemographics(context.Request.Context(), &demographics); err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, demographics)
+}
+
+func (c payEquityControllerImpl) DeleteDemographics(context *gin.Context) {
+	salaryID, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		c.error(context, http.StatusBadRequest, err)
+		return
+	}
+	if err := c.payEquityService.DeleteDemographics(context.Request.Context(), salaryID); err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	context.Status(http.StatusNoContent)
+}
+
+func (c payEquityControllerImpl) GetPayEquity(context *gin.Context) {
+	var query api.PayEquityQuery
+	if err := context.ShouldBindQuery(&query); err != nil {
+		c.error(context, http.StatusBadRequest, err)
+		return
+	}
+	report, err := c.payEquityService.GetPayEquity(context.Request.Context(), query)
+	if err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, report)
+}
+
+func (c payEquityControllerImpl) serviceError(context *gin.Context, err error) {
+	switch {
+	case errors.Is(err, api.ErrNotFound):
+		c.error(context, http.StatusNotFound, err)
+	case errors.Is(err, api.ErrInvalidQuery):
+		c.error(context, http.StatusBadRequest, err)
+	case errors.Is(err, currency.ErrUnknownCurrency):
+		c.error(context, http.StatusUnprocessableEntity, err)
+	default:
+		c.error(context, http.StatusInternalServerError, err)
+	}
+}
+
+func (c payEquityControllerImpl) error(context *gin.Context, code int, err error) {
+	context.JSON(code, api.AppError{
+		Error: err,
+		Code:  code,
+	})
+}
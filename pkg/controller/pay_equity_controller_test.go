@@ -0,0 +1,123 @@
+package controller_test
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"salaries/pkg/api"
+	"salaries/pkg/auth"
+	"salaries/pkg/controller"
+	"salaries/pkg/currency"
+	"salaries/pkg/domain"
+	"salaries/pkg/logger"
+	"salaries/pkg/middleware"
+	"salaries/pkg/service"
+	"strings"
+	"testing"
+)
+
+var hrAuthService = &auth.ServiceMock{
+	VerifyTokenFunc: func(context *gin.Context) (*domain.User, error) {
+		return &domain.User{ID: 3, Username: "recruiter", Role: domain.RoleHR}, nil
+	},
+}
+
+func TestHTTPHandler_PayEquity(t *testing.T) {
+	payEquityService := &service.PayEquityServiceMock{
+		SaveDemographicsFunc: func(ctx context.Context, demographics *domain.Demographics) error {
+			if demographics.SalaryID == 404 {
+				return api.ErrNotFound
+			}
+			return nil
+		},
+		GetPayEquityFunc: func(ctx context.Context, query api.PayEquityQuery) (*api.PayEquityReport, error) {
+			if query.ConvertTo == "XXX" {
+				return nil, currency.ErrUnknownCurrency
+			}
+			if query.Attribute != domain.AttributeGender {
+				return nil, api.ErrInvalidQuery
+			}
+			return &api.PayEquityReport{Attribute: query.Attribute, MinGroupSize: 5}, nil
+		},
+	}
+	tests := []struct {
+		name        string
+		authService auth.Service
+		method      string
+		path        string
+		body        string
+		status      int
+	}{
+		{
+			name:        "hr saves demographics",
+			authService: hrAuthService,
+			method:      http.MethodPut,
+			path:        "/api/salaries/1/demographics",
+			body:        `{"gender": "female"}`,
+			status:      http.StatusOK,
+		},
+		{
+			name:        "users can not see demographics",
+			authService: authService,
+			method:      http.MethodPut,
+			path:        "/api/salaries/1/demographics",
+			body:        `{"gender": "female"}`,
+			status:      http.StatusForbidden,
+		},
+		{
+			name:        "demographics of a missing salary",
+			authService: adminAuthService,
+			method:      http.MethodPut,
+			path:        "/api/salaries/404/demographics",
+			body:        `{"gender": "female"}`,
+			status:      http.StatusNotFound,
+		},
+		{
+			name:        "pay equity",
+			authService: hrAuthService,
+			method:      http.MethodGet,
+			path:        "/api/salaries/stats/pay-equity?attribute=gender&department=Engineering",
+			status:      http.StatusOK,
+		},
+		{
+			name:        "users can not see pay equity",
+			authService: authService,
+			method:      http.MethodGet,
+			path:        "/api/salaries/stats/pay-equity?attribute=gender",
+			status:      http.StatusForbidden,
+		},
+		{
+			name:        "unknown attribute",
+			authService: hrAuthService,
+			method:      http.MethodGet,
+			path:        "/api/salaries/stats/pay-equity?attribute=age",
+			status:      http.StatusBadRequest,
+		},
+		{
+			name:        "unknown currency",
+			authService: hrAuthService,
+			method:      http.MethodGet,
+			path:        "/api/salaries/stats/pay-equity?attribute=gender&convert_to=XXX",
+			status:      http.StatusUnprocessableEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payEquityController := controller.NewPayEquityController(payEquityService)
+
+			w := httptest.NewRecorder()
+			_, r := gin.CreateTestContext(w)
+
+			hr := r.Group("/api/salaries", middleware.NewAuthMiddleware(tt.authService, logger.NewLogger()), middleware.NewRoleMiddleware(domain.RoleHR, domain.RoleAdmin))
+			hr.PUT("/:id/demographics", payEquityController.SaveDemographics)
+			hr.GET("/stats/pay-equity", payEquityController.GetPayEquity)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
@@ -1,16 +1,22 @@
 package controller_test
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"net/http"
 	"net/http/httptest"
 	"salaries/pkg/api"
 	"salaries/pkg/auth"
 	"salaries/pkg/controller"
+	"salaries/pkg/currency"
 	"salaries/pkg/domain"
+	"salaries/pkg/importer"
+	"salaries/pkg/logger"
 	"salaries/pkg/middleware"
 	"salaries/pkg/service"
 	"strings"
@@ -18,8 +24,14 @@ import (
 )
 
 var authService = &auth.ServiceMock{
-	VerifyTokenFunc: func(context *gin.Context) error {
-		return nil
+	VerifyTokenFunc: func(context *gin.Context) (*domain.User, error) {
+		return &domain.User{ID: 2, Username: "analyst", Role: domain.RoleUser}, nil
+	},
+}
+
+var adminAuthService = &auth.ServiceMock{
+	VerifyTokenFunc: func(context *gin.Context) (*domain.User, error) {
+		return &domain.User{ID: 1, Username: "pmagnaghi", Role: domain.RoleAdmin}, nil
 	},
 }
 
@@ -42,8 +54,8 @@ func TestHTTPHandler_Create(t *testing.T) {
 			salary: salary,
 			fields: fields{
 				authService: &auth.ServiceMock{
-					VerifyTokenFunc: func(context *gin.Context) error {
-						return errors.New("invalid token")
+					VerifyTokenFunc: func(context *gin.Context) (*domain.User, error) {
+						return nil, errors.New("invalid token")
 					},
 				},
 			},
@@ -56,8 +68,8 @@ func TestHTTPHandler_Create(t *testing.T) {
 			fields: fields{
 				authService: authService,
 				salaryService: &service.SalaryServiceMock{
-					CreateFunc: func(salary *domain.Salary) error {
-						return nil
+					CreateFunc: func(ctx context.Context, salary *domain.Salary) ([]api.Anomaly, error) {
+						return nil, nil
 					},
 				},
 			},
@@ -70,8 +82,8 @@ func TestHTTPHandler_Create(t *testing.T) {
 			fields: fields{
 				authService: authService,
 				salaryService: &service.SalaryServiceMock{
-					CreateFunc: func(salary *domain.Salary) error {
-						return errors.New("error creating salary")
+					CreateFunc: func(ctx context.Context, salary *domain.Salary) ([]api.Anomaly, error) {
+						return nil, errors.New("error creating salary")
 					},
 				},
 			},
@@ -81,12 +93,12 @@ func TestHTTPHandler_Create(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			salaryController := controller.NewSalaryController(tt.fields.salaryService)
+			salaryController := controller.NewSalaryController(tt.fields.salaryService, 100)
 
 			w := httptest.NewRecorder()
 			_, r := gin.CreateTestContext(w)
 
-			r.Use(middleware.NewAuthMiddleware(tt.fields.authService))
+			r.Use(middleware.NewAuthMiddleware(tt.fields.authService, logger.NewLogger()))
 
 			r.POST("/api/salaries", salaryController.Create)
 
@@ -100,6 +112,166 @@ func TestHTTPHandler_Create(t *testing.T) {
 	}
 }
 
+func TestHTTPHandler_Import(t *testing.T) {
+	body := "name,salary,currency,department,on_contract,sub_department\nAnurag,90000,USD,Banking,true,Loan\n"
+	importFunc := func(report *api.ImportReport, err error) func(ctx context.Context, decoder importer.Decoder, dryRun bool) (*api.ImportReport, error) {
+		return func(ctx context.Context, decoder importer.Decoder, dryRun bool) (*api.ImportReport, error) {
+			if report != nil {
+				report.DryRun = dryRun
+			}
+			return report, err
+		}
+	}
+	tests := []struct {
+		name          string
+		query         string
+		contentType   string
+		salaryService service.SalaryService
+		status        int
+	}{
+		{
+			name:        "importing salaries",
+			contentType: "text/csv",
+			salaryService: &service.SalaryServiceMock{
+				ImportFunc: importFunc(&api.ImportReport{Rows: 1, Valid: 1, Imported: 1}, nil),
+			},
+			status: http.StatusCreated,
+		},
+		{
+			name:        "dry run",
+			query:       "?dry_run=true",
+			contentType: "text/csv",
+			salaryService: &service.SalaryServiceMock{
+				ImportFunc: importFunc(&api.ImportReport{Rows: 1, Valid: 1}, nil),
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:        "invalid rows",
+			contentType: "text/csv",
+			salaryService: &service.SalaryServiceMock{
+				ImportFunc: importFunc(&api.ImportReport{Rows: 1, Errors: []api.ImportRowError{{Row: 1, Error: "invalid row"}}}, nil),
+			},
+			status: http.StatusUnprocessableEntity,
+		},
+		{
+			name:        "unsupported format",
+			contentType: "application/xml",
+			status:      http.StatusUnsupportedMediaType,
+		},
+		{
+			name:        "invalid dry run",
+			query:       "?dry_run=maybe",
+			contentType: "text/csv",
+			status:      http.StatusBadRequest,
+		},
+		{
+			name:        "error importing salaries",
+			contentType: "text/csv",
+			salaryService: &service.SalaryServiceMock{
+				ImportFunc: importFunc(nil, errors.New("error importing salaries")),
+			},
+			status: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			salaryController := controller.NewSalaryController(tt.salaryService, 100)
+
+			w := httptest.NewRecorder()
+			_, r := gin.CreateTestContext(w)
+
+			r.Use(middleware.NewAuthMiddleware(authService, logger.NewLogger()))
+
+			r.POST("/api/salaries/import", salaryController.Import)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/salaries/import"+tt.query, strings.NewReader(body))
+			req.Header.Set("Content-Type", tt.contentType)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestHTTPHandler_CreateBatch(t *testing.T) {
+	salary := `{"name": "Anurag", "salary": "90000", "currency": "USD", "department": "Banking", "sub_department": "Loan"}`
+	createMany := func(succeeded, failed int) func(ctx context.Context, salaries []domain.Salary, partial bool) (*api.BatchResult, error) {
+		return func(ctx context.Context, salaries []domain.Salary, partial bool) (*api.BatchResult, error) {
+			return &api.BatchResult{Partial: partial, Succeeded: succeeded, Failed: failed}, nil
+		}
+	}
+	tests := []struct {
+		name          string
+		query         string
+		body          string
+		salaryService service.SalaryService
+		status        int
+	}{
+		{
+			name:          "creating salaries",
+			body:          "[" + salary + "," + salary + "]",
+			salaryService: &service.SalaryServiceMock{CreateManyFunc: createMany(2, 0)},
+			status:        http.StatusCreated,
+		},
+		{
+			name:          "some salaries created",
+			query:         "?partial=true",
+			body:          "[" + salary + "," + salary + "]",
+			salaryService: &service.SalaryServiceMock{CreateManyFunc: createMany(1, 1)},
+			status:        http.StatusMultiStatus,
+		},
+		{
+			name:          "no salary created",
+			body:          "[" + salary + "," + salary + "]",
+			salaryService: &service.SalaryServiceMock{CreateManyFunc: createMany(0, 1)},
+			status:        http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "empty batch",
+			body:   "[]",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "not an array",
+			body:   salary,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "batch too large",
+			body:   "[" + salary + strings.Repeat(","+salary, 100) + "]",
+			status: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name: "error creating salaries",
+			body: "[" + salary + "]",
+			salaryService: &service.SalaryServiceMock{
+				CreateManyFunc: func(ctx context.Context, salaries []domain.Salary, partial bool) (*api.BatchResult, error) {
+					return nil, errors.New("error creating salaries")
+				},
+			},
+			status: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			salaryController := controller.NewSalaryController(tt.salaryService, 100)
+
+			w := httptest.NewRecorder()
+			_, r := gin.CreateTestContext(w)
+
+			r.Use(middleware.NewAuthMiddleware(authService, logger.NewLogger()))
+
+			r.POST("/api/salaries/batch", salaryController.CreateBatch)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/salaries/batch"+tt.query, strings.NewReader(tt.body))
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
 func TestHTTPHandler_GetAll(t *testing.T) {
 	salaries := []domain.Salary{
 		{
@@ -125,8 +297,8 @@ func TestHTTPHandler_GetAll(t *testing.T) {
 			name: "invalid token",
 			fields: fields{
 				authService: &auth.ServiceMock{
-					VerifyTokenFunc: func(context *gin.Context) error {
-						return errors.New("invalid token")
+					VerifyTokenFunc: func(context *gin.Context) (*domain.User, error) {
+						return nil, errors.New("invalid token")
 					},
 				},
 			},
@@ -138,7 +310,7 @@ func TestHTTPHandler_GetAll(t *testing.T) {
 			fields: fields{
 				authService: authService,
 				salaryService: &service.SalaryServiceMock{
-					GetAllFunc: func() ([]domain.Salary, error) {
+					GetAllFunc: func(ctx context.Context, filter api.SalaryFilter) ([]domain.Salary, error) {
 						return salaries, nil
 					},
 				},
@@ -151,7 +323,7 @@ func TestHTTPHandler_GetAll(t *testing.T) {
 			fields: fields{
 				authService: authService,
 				salaryService: &service.SalaryServiceMock{
-					GetAllFunc: func() ([]domain.Salary, error) {
+					GetAllFunc: func(ctx context.Context, filter api.SalaryFilter) ([]domain.Salary, error) {
 						return nil, errors.New("error getting salaries")
 					},
 				},
@@ -162,16 +334,223 @@ func TestHTTPHandler_GetAll(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			salaryController := controller.NewSalaryController(tt.fields.salaryService)
+			salaryController := controller.NewSalaryController(tt.fields.salaryService, 100)
+
+			w := httptest.NewRecorder()
+			_, r := gin.CreateTestContext(w)
+
+			r.Use(middleware.NewAuthMiddleware(tt.fields.authService, logger.NewLogger()))
+
+			r.GET("/api/salaries", salaryController.GetAll)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/salaries", nil)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestHTTPHandler_GetAllNegotiatesContentType(t *testing.T) {
+	salaryService := &service.SalaryServiceMock{
+		GetAllFunc: func(ctx context.Context, filter api.SalaryFilter) ([]domain.Salary, error) {
+			return []domain.Salary{{ID: 1, Name: "Anurag", Salary: 90000, Currency: "USD", Department: "Banking", SubDepartment: "Loan"}}, nil
+		},
+	}
+	tests := []struct {
+		name        string
+		accept      string
+		status      int
+		contentType string
+	}{
+		{name: "json by default", accept: "", status: http.StatusOK, contentType: "application/json; charset=utf-8"},
+		{name: "csv", accept: "text/csv", status: http.StatusOK, contentType: "text/csv; charset=utf-8"},
+		{name: "ndjson", accept: "application/x-ndjson", status: http.StatusOK, contentType: "application/x-ndjson"},
+		{name: "yaml", accept: "application/yaml", status: http.StatusOK, contentType: "application/yaml; charset=utf-8"},
+		{name: "xml", accept: "application/xml", status: http.StatusOK, contentType: "application/xml; charset=utf-8"},
+		{name: "not acceptable", accept: "text/html", status: http.StatusNotAcceptable, contentType: "application/json; charset=utf-8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			salaryController := controller.NewSalaryController(salaryService, 100)
 
 			w := httptest.NewRecorder()
 			_, r := gin.CreateTestContext(w)
 
-			r.Use(middleware.NewAuthMiddleware(tt.fields.authService))
+			r.Use(middleware.NewAuthMiddleware(authService, logger.NewLogger()))
 
 			r.GET("/api/salaries", salaryController.GetAll)
 
 			req := httptest.NewRequest(http.MethodGet, "/api/salaries", nil)
+			req.Header.Set("Accept", tt.accept)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.contentType, w.Header().Get("Content-Type"))
+		})
+	}
+	assert.Len(t, salaryService.GetAllCalls(), 5)
+}
+
+func TestHTTPHandler_Export(t *testing.T) {
+	salary := &domain.Salary{ID: 1, Name: "Anurag", Salary: 90000, Currency: "USD", OnContract: true, Department: "Banking", SubDepartment: "Loan"}
+	tests := []struct {
+		name          string
+		query         string
+		salaryService service.SalaryService
+		status        int
+		contentType   string
+		body          string
+	}{
+		{
+			name:  "exporting salaries",
+			query: "?department=Banking&on_contract=true",
+			salaryService: &service.SalaryServiceMock{
+				ExportFunc: func(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary) error) error {
+					if filter.Department != "Banking" || filter.OnContract == nil || !*filter.OnContract {
+						return errors.New("filter not applied")
+					}
+					return each(salary)
+				},
+			},
+			status:      http.StatusOK,
+			contentType: "text/csv; charset=utf-8",
+			body:        "id,name,salary,currency,on_contract,department,sub_department\n1,Anurag,90000,USD,true,Banking,Loan\n",
+		},
+		{
+			name:   "unsupported format",
+			query:  "?format=pdf",
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "error exporting salaries",
+			salaryService: &service.SalaryServiceMock{
+				ExportFunc: func(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary) error) error {
+					return errors.New("error exporting salaries")
+				},
+			},
+			status:      http.StatusInternalServerError,
+			contentType: "application/json; charset=utf-8",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			salaryController := controller.NewSalaryController(tt.salaryService, 100)
+
+			w := httptest.NewRecorder()
+			_, r := gin.CreateTestContext(w)
+
+			r.Use(middleware.NewAuthMiddleware(authService, logger.NewLogger()))
+
+			r.GET("/api/salaries/export", salaryController.Export)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/salaries/export"+tt.query, nil)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.contentType != "" {
+				assert.Equal(t, tt.contentType, w.Header().Get("Content-Type"))
+			}
+			if tt.body != "" {
+				assert.Equal(t, tt.body, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHTTPHandler_ExportDepartmentsStats(t *testing.T) {
+	salaryController := controller.NewSalaryController(&service.SalaryServiceMock{
+		GetDepartmentsStatsFunc: func(ctx context.Context) ([]api.DepartmentStats, error) {
+			return []api.DepartmentStats{{Department: "Banking", Stats: api.Stats{Mean: 90000, Max: 90000, Min: 90000, Count: 1}}}, nil
+		},
+	}, 100)
+
+	w := httptest.NewRecorder()
+	_, r := gin.CreateTestContext(w)
+
+	r.Use(middleware.NewAuthMiddleware(authService, logger.NewLogger()))
+
+	r.GET("/api/salaries/stats/departments", salaryController.GetDepartmentsStats)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/salaries/stats/departments?format=csv", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `attachment; filename="departments_stats.csv"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, "department,mean,max,min,count\nBanking,90000,90000,90000,1\n", w.Body.String())
+}
+
+func TestHTTPHandler_GetByID(t *testing.T) {
+	type fields struct {
+		authService   auth.Service
+		salaryService service.SalaryService
+	}
+	tests := []struct {
+		name   string
+		ID     string
+		fields fields
+		status int
+	}{
+		{
+			name: "getting salary",
+			ID:   "1",
+			fields: fields{
+				authService: authService,
+				salaryService: &service.SalaryServiceMock{
+					GetByIDFunc: func(ctx context.Context, ID int64) (*domain.Salary, error) {
+						return &domain.Salary{ID: ID, Name: "Anurag", Salary: 90000, Currency: "USD", Department: "Banking", SubDepartment: "Loan"}, nil
+					},
+				},
+			},
+			status: http.StatusOK,
+		},
+		{
+			name: "invalid id",
+			ID:   "one",
+			fields: fields{
+				authService: authService,
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "salary not found",
+			ID:   "2",
+			fields: fields{
+				authService: authService,
+				salaryService: &service.SalaryServiceMock{
+					GetByIDFunc: func(ctx context.Context, ID int64) (*domain.Salary, error) {
+						return nil, api.ErrNotFound
+					},
+				},
+			},
+			status: http.StatusNotFound,
+		},
+		{
+			name: "error getting salary",
+			ID:   "3",
+			fields: fields{
+				authService: authService,
+				salaryService: &service.SalaryServiceMock{
+					GetByIDFunc: func(ctx context.Context, ID int64) (*domain.Salary, error) {
+						return nil, errors.New("error getting salary")
+					},
+				},
+			},
+			status: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			salaryController := controller.NewSalaryController(tt.fields.salaryService, 100)
+
+			w := httptest.NewRecorder()
+			_, r := gin.CreateTestContext(w)
+
+			r.Use(middleware.NewAuthMiddleware(tt.fields.authService, logger.NewLogger()))
+
+			r.GET("/api/salaries/:id", salaryController.GetByID)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/salaries/"+tt.ID, nil)
 			r.ServeHTTP(w, req)
 
 			assert.Equal(t, tt.status, w.Code)
@@ -196,8 +575,8 @@ func TestHTTPHandler_Delete(t *testing.T) {
 			ID:   1,
 			fields: fields{
 				authService: &auth.ServiceMock{
-					VerifyTokenFunc: func(context *gin.Context) error {
-						return errors.New("invalid token")
+					VerifyTokenFunc: func(context *gin.Context) (*domain.User, error) {
+						return nil, errors.New("invalid token")
 					},
 				},
 			},
@@ -210,7 +589,7 @@ func TestHTTPHandler_Delete(t *testing.T) {
 			fields: fields{
 				authService: authService,
 				salaryService: &service.SalaryServiceMock{
-					DeleteByIDFunc: func(ID int64) error {
+					DeleteByIDFunc: func(ctx context.Context, ID int64, version int64) error {
 						return nil
 					},
 				},
@@ -218,13 +597,27 @@ func TestHTTPHandler_Delete(t *testing.T) {
 			status:    http.StatusNoContent,
 			wantError: true,
 		},
+		{
+			name: "salary to delete not found",
+			ID:   3,
+			fields: fields{
+				authService: authService,
+				salaryService: &service.SalaryServiceMock{
+					DeleteByIDFunc: func(ctx context.Context, ID int64, version int64) error {
+						return api.ErrNotFound
+					},
+				},
+			},
+			status:    http.StatusNotFound,
+			wantError: true,
+		},
 		{
 			name: "error deleting salary",
 			ID:   2,
 			fields: fields{
 				authService: authService,
 				salaryService: &service.SalaryServiceMock{
-					DeleteByIDFunc: func(ID int64) error {
+					DeleteByIDFunc: func(ctx context.Context, ID int64, version int64) error {
 						return errors.New("error deleting salary")
 					},
 				},
@@ -235,12 +628,12 @@ func TestHTTPHandler_Delete(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			salaryController := controller.NewSalaryController(tt.fields.salaryService)
+			salaryController := controller.NewSalaryController(tt.fields.salaryService, 100)
 
 			w := httptest.NewRecorder()
 			_, r := gin.CreateTestContext(w)
 
-			r.Use(middleware.NewAuthMiddleware(tt.fields.authService))
+			r.Use(middleware.NewAuthMiddleware(tt.fields.authService, logger.NewLogger()))
 
 			r.DELETE("/api/salaries/:id", salaryController.Delete)
 
@@ -252,6 +645,235 @@ func TestHTTPHandler_Delete(t *testing.T) {
 	}
 }
 
+func TestHTTPHandler_DeleteBatch(t *testing.T) {
+	deleteMany := func(ctx context.Context, ids []int64, partial bool) (*api.BatchResult, error) {
+		result := &api.BatchResult{Partial: partial}
+		for _, id := range ids {
+			if id == 404 {
+				result.Failed++
+			} else {
+				result.Succeeded++
+			}
+		}
+		return result, nil
+	}
+	tests := []struct {
+		name          string
+		query         string
+		salaryService service.SalaryService
+		status        int
+		wantIDs       []int64
+		wantFilter    *api.SalaryFilter
+	}{
+		{
+			name:          "deleting salaries by id",
+			query:         "?ids=1,2",
+			salaryService: &service.SalaryServiceMock{DeleteManyFunc: deleteMany},
+			status:        http.StatusOK,
+			wantIDs:       []int64{1, 2},
+		},
+		{
+			name:          "some salaries deleted",
+			query:         "?ids=1,404&partial=true",
+			salaryService: &service.SalaryServiceMock{DeleteManyFunc: deleteMany},
+			status:        http.StatusMultiStatus,
+			wantIDs:       []int64{1, 404},
+		},
+		{
+			name:          "no salary deleted",
+			query:         "?ids=404",
+			salaryService: &service.SalaryServiceMock{DeleteManyFunc: deleteMany},
+			status:        http.StatusNotFound,
+			wantIDs:       []int64{404},
+		},
+		{
+			name:  "deleting salaries by filter",
+			query: "?department=Banking",
+			salaryService: &service.SalaryServiceMock{
+				DeleteMatchingFunc: func(ctx context.Context, filter api.SalaryFilter, limit int, partial bool) (*api.BatchResult, error) {
+					return &api.BatchResult{Succeeded: 3}, nil
+				},
+			},
+			status:     http.StatusOK,
+			wantFilter: &api.SalaryFilter{Department: "Banking"},
+		},
+		{
+			name:  "filter matching too many salaries",
+			query: "?department=Banking",
+			salaryService: &service.SalaryServiceMock{
+				DeleteMatchingFunc: func(ctx context.Context, filter api.SalaryFilter, limit int, partial bool) (*api.BatchResult, error) {
+					return nil, api.ErrBatchTooLarge
+				},
+			},
+			status:     http.StatusRequestEntityTooLarge,
+			wantFilter: &api.SalaryFilter{Department: "Banking"},
+		},
+		{
+			name:   "neither ids nor filter",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "ids and filter",
+			query:  "?ids=1&department=Banking",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid id",
+			query:  "?ids=1,one",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "too many ids",
+			query:  "?ids=1" + strings.Repeat(",1", 100),
+			status: http.StatusRequestEntityTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			salaryController := controller.NewSalaryController(tt.salaryService, 100)
+
+			w := httptest.NewRecorder()
+			_, r := gin.CreateTestContext(w)
+
+			r.Use(middleware.NewAuthMiddleware(authService, logger.NewLogger()))
+
+			r.DELETE("/api/salaries", salaryController.DeleteBatch)
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/salaries"+tt.query, nil)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			if mock, ok := tt.salaryService.(*service.SalaryServiceMock); ok && tt.wantIDs != nil {
+				require.Len(t, mock.DeleteManyCalls(), 1)
+				assert.Equal(t, tt.wantIDs, mock.DeleteManyCalls()[0].Ids)
+			}
+			if mock, ok := tt.salaryService.(*service.SalaryServiceMock); ok && tt.wantFilter != nil {
+				require.Len(t, mock.DeleteMatchingCalls(), 1)
+				assert.Equal(t, *tt.wantFilter, mock.DeleteMatchingCalls()[0].Filter)
+				assert.Equal(t, 100, mock.DeleteMatchingCalls()[0].Limit)
+			}
+		})
+	}
+}
+
+func TestHTTPHandler_ConditionalRequests(t *testing.T) {
+	stored := domain.Salary{ID: 1, Name: "Anurag", Salary: 90000, Currency: "USD", Department: "Banking", SubDepartment: "Loan", Version: 2}
+	body := `{"name": "Anurag", "salary": "95000", "currency": "USD", "department": "Banking", "sub_department": "Loan"}`
+	salaryService := &service.SalaryServiceMock{
+		GetByIDFunc: func(ctx context.Context, id int64) (*domain.Salary, error) {
+			salary := stored
+			return &salary, nil
+		},
+		GetAllFunc: func(ctx context.Context, filter api.SalaryFilter) ([]domain.Salary, error) {
+			return []domain.Salary{stored}, nil
+		},
+		UpdateFunc: func(ctx context.Context, salary *domain.Salary, version int64) error {
+			if version != 0 && version != stored.Version {
+				return api.ErrPreconditionFailed
+			}
+			salary.Version = stored.Version + 1
+			return nil
+		},
+		DeleteByIDFunc: func(ctx context.Context, id int64, version int64) error {
+			if version != 0 && version != stored.Version {
+				return api.ErrPreconditionFailed
+			}
+			return nil
+		},
+	}
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		header         map[string]string
+		body           string
+		requireIfMatch bool
+		status         int
+		wantETag       string
+	}{
+		{name: "get by id", method: http.MethodGet, path: "/api/salaries/1", status: http.StatusOK, wantETag: `"2"`},
+		{name: "get by id not modified", method: http.MethodGet, path: "/api/salaries/1", header: map[string]string{"If-None-Match": `"1", W/"2"`}, status: http.StatusNotModified, wantETag: `"2"`},
+		{name: "get by id as csv", method: http.MethodGet, path: "/api/salaries/1", header: map[string]string{"Accept": "text/csv"}, status: http.StatusOK, wantETag: `"2-csv"`},
+		{name: "get by id as csv modified", method: http.MethodGet, path: "/api/salaries/1", header: map[string]string{"Accept": "text/csv", "If-None-Match": `"2"`}, status: http.StatusOK, wantETag: `"2-csv"`},
+		{name: "get by id as yaml not modified", method: http.MethodGet, path: "/api/salaries/1", header: map[string]string{"Accept": "application/yaml", "If-None-Match": `"2-yaml"`}, status: http.StatusNotModified, wantETag: `"2-yaml"`},
+		{name: "get all modified", method: http.MethodGet, path: "/api/salaries", header: map[string]string{"If-None-Match": `"stale"`}, status: http.StatusOK},
+		{name: "update", method: http.MethodPut, path: "/api/salaries/1", header: map[string]string{"If-Match": `"2"`}, body: body, status: http.StatusOK, wantETag: `"3"`},
+		{name: "update with csv etag", method: http.MethodPut, path: "/api/salaries/1", header: map[string]string{"If-Match": `"2-csv"`}, body: body, status: http.StatusOK, wantETag: `"3"`},
+		{name: "update without precondition", method: http.MethodPut, path: "/api/salaries/1", body: body, status: http.StatusOK, wantETag: `"3"`},
+		{name: "update stale version", method: http.MethodPut, path: "/api/salaries/1", header: map[string]string{"If-Match": `"1"`}, body: body, status: http.StatusPreconditionFailed},
+		{name: "update with weak etag", method: http.MethodPut, path: "/api/salaries/1", header: map[string]string{"If-Match": `W/"2"`}, body: body, status: http.StatusPreconditionFailed},
+		{name: "update invalid salary", method: http.MethodPut, path: "/api/salaries/1", header: map[string]string{"If-Match": `"2"`}, body: `{"name": "Anurag"}`, status: http.StatusBadRequest},
+		{name: "update without required precondition", method: http.MethodPut, path: "/api/salaries/1", body: body, requireIfMatch: true, status: http.StatusPreconditionRequired},
+		{name: "delete any version", method: http.MethodDelete, path: "/api/salaries/1", header: map[string]string{"If-Match": "*"}, requireIfMatch: true, status: http.StatusNoContent},
+		{name: "delete stale version", method: http.MethodDelete, path: "/api/salaries/1", header: map[string]string{"If-Match": `"1"`}, status: http.StatusPreconditionFailed},
+		{name: "delete without required precondition", method: http.MethodDelete, path: "/api/salaries/1", requireIfMatch: true, status: http.StatusPreconditionRequired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			salaryController := controller.NewSalaryController(salaryService, 100)
+
+			w := httptest.NewRecorder()
+			_, r := gin.CreateTestContext(w)
+
+			r.Use(middleware.NewAuthMiddleware(authService, logger.NewLogger()))
+			var preconditions []gin.HandlerFunc
+			if tt.requireIfMatch {
+				preconditions = append(preconditions, middleware.NewRequireIfMatchMiddleware())
+			}
+
+			r.GET("/api/salaries", salaryController.GetAll)
+			r.GET("/api/salaries/:id", salaryController.GetByID)
+			r.PUT("/api/salaries/:id", append(preconditions, salaryController.Update)...)
+			r.DELETE("/api/salaries/:id", append(preconditions, salaryController.Delete)...)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			for key, value := range tt.header {
+				req.Header.Set(key, value)
+			}
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.wantETag != "" {
+				assert.Equal(t, tt.wantETag, w.Header().Get("ETag"))
+			}
+			if tt.status == http.StatusNotModified {
+				assert.Empty(t, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHTTPHandler_GetAllNotModified(t *testing.T) {
+	salaryController := controller.NewSalaryController(&service.SalaryServiceMock{
+		GetAllFunc: func(ctx context.Context, filter api.SalaryFilter) ([]domain.Salary, error) {
+			return []domain.Salary{{ID: 1, Name: "Anurag", Salary: 90000, Currency: "USD", Department: "Banking", SubDepartment: "Loan", Version: 1}}, nil
+		},
+	}, 100)
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	r.Use(middleware.NewAuthMiddleware(authService, logger.NewLogger()))
+	r.GET("/api/salaries", salaryController.GetAll)
+
+	first := httptest.NewRecorder()
+	r.ServeHTTP(first, httptest.NewRequest(http.MethodGet, "/api/salaries", nil))
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	second := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/salaries", nil)
+	req.Header.Set("If-None-Match", etag)
+	r.ServeHTTP(second, req)
+	assert.Equal(t, http.StatusNotModified, second.Code)
+	assert.Equal(t, etag, second.Header().Get("ETag"))
+
+	csv := httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/api/salaries", nil)
+	req.Header.Set("Accept", "text/csv")
+	req.Header.Set("If-None-Match", etag)
+	r.ServeHTTP(csv, req)
+	assert.Equal(t, http.StatusOK, csv.Code)
+	assert.NotEqual(t, etag, csv.Header().Get("ETag"))
+}
+
 func TestHTTPHandler_GetStatisticsEntireDataset(t *testing.T) {
 	stats := api.Stats{
 		Mean: 30.0,
@@ -272,8 +894,8 @@ func TestHTTPHandler_GetStatisticsEntireDataset(t *testing.T) {
 			name: "invalid token",
 			fields: fields{
 				authService: &auth.ServiceMock{
-					VerifyTokenFunc: func(context *gin.Context) error {
-						return errors.New("invalid token")
+					VerifyTokenFunc: func(context *gin.Context) (*domain.User, error) {
+						return nil, errors.New("invalid token")
 					},
 				},
 			},
@@ -285,7 +907,7 @@ func TestHTTPHandler_GetStatisticsEntireDataset(t *testing.T) {
 			fields: fields{
 				authService: authService,
 				salaryService: &service.SalaryServiceMock{
-					GetStatsForAllSalariesFunc: func() (*api.Stats, error) {
+					GetStatsForAllSalariesFunc: func(ctx context.Context) (*api.Stats, error) {
 						return &stats, nil
 					},
 				},
@@ -298,7 +920,7 @@ func TestHTTPHandler_GetStatisticsEntireDataset(t *testing.T) {
 			fields: fields{
 				authService: authService,
 				salaryService: &service.SalaryServiceMock{
-					GetStatsForAllSalariesFunc: func() (*api.Stats, error) {
+					GetStatsForAllSalariesFunc: func(ctx context.Context) (*api.Stats, error) {
 						return nil, errors.New("error getting stats")
 					},
 				},
@@ -306,15 +928,28 @@ func TestHTTPHandler_GetStatisticsEntireDataset(t *testing.T) {
 			status:    http.StatusInternalServerError,
 			wantError: true,
 		},
+		{
+			name: "too few salaries",
+			fields: fields{
+				authService: authService,
+				salaryService: &service.SalaryServiceMock{
+					GetStatsForAllSalariesFunc: func(ctx context.Context) (*api.Stats, error) {
+						return nil, fmt.Errorf("%w: fewer than 5 salaries", api.ErrGroupTooSmall)
+					},
+				},
+			},
+			status:    http.StatusUnprocessableEntity,
+			wantError: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			salaryController := controller.NewSalaryController(tt.fields.salaryService)
+			salaryController := controller.NewSalaryController(tt.fields.salaryService, 100)
 
 			w := httptest.NewRecorder()
 			_, r := gin.CreateTestContext(w)
 
-			r.Use(middleware.NewAuthMiddleware(tt.fields.authService))
+			r.Use(middleware.NewAuthMiddleware(tt.fields.authService, logger.NewLogger()))
 
 			r.GET("/api/salaries/stats", salaryController.GetStatisticsEntireDataset)
 
@@ -346,8 +981,8 @@ func TestHTTPHandler_GetContractsStats(t *testing.T) {
 			name: "invalid token",
 			fields: fields{
 				authService: &auth.ServiceMock{
-					VerifyTokenFunc: func(context *gin.Context) error {
-						return errors.New("invalid token")
+					VerifyTokenFunc: func(context *gin.Context) (*domain.User, error) {
+						return nil, errors.New("invalid token")
 					},
 				},
 			},
@@ -359,7 +994,7 @@ func TestHTTPHandler_GetContractsStats(t *testing.T) {
 			fields: fields{
 				authService: authService,
 				salaryService: &service.SalaryServiceMock{
-					GetContractsStatsFunc: func() (*api.Stats, error) {
+					GetContractsStatsFunc: func(ctx context.Context) (*api.Stats, error) {
 						return &stats, nil
 					},
 				},
@@ -372,7 +1007,7 @@ func TestHTTPHandler_GetContractsStats(t *testing.T) {
 			fields: fields{
 				authService: authService,
 				salaryService: &service.SalaryServiceMock{
-					GetContractsStatsFunc: func() (*api.Stats, error) {
+					GetContractsStatsFunc: func(ctx context.Context) (*api.Stats, error) {
 						return nil, errors.New("error getting contracts stats")
 					},
 				},
@@ -383,12 +1018,12 @@ func TestHTTPHandler_GetContractsStats(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			salaryController := controller.NewSalaryController(tt.fields.salaryService)
+			salaryController := controller.NewSalaryController(tt.fields.salaryService, 100)
 
 			w := httptest.NewRecorder()
 			_, r := gin.CreateTestContext(w)
 
-			r.Use(middleware.NewAuthMiddleware(tt.fields.authService))
+			r.Use(middleware.NewAuthMiddleware(tt.fields.authService, logger.NewLogger()))
 
 			r.GET("/api/salaries/stats/contracts", salaryController.GetContractsStats)
 
@@ -425,8 +1060,8 @@ func TestHTTPHandler_GetDepartmentsStats(t *testing.T) {
 			name: "invalid token",
 			fields: fields{
 				authService: &auth.ServiceMock{
-					VerifyTokenFunc: func(context *gin.Context) error {
-						return errors.New("invalid token")
+					VerifyTokenFunc: func(context *gin.Context) (*domain.User, error) {
+						return nil, errors.New("invalid token")
 					},
 				},
 			},
@@ -438,7 +1073,7 @@ func TestHTTPHandler_GetDepartmentsStats(t *testing.T) {
 			fields: fields{
 				authService: authService,
 				salaryService: &service.SalaryServiceMock{
-					GetDepartmentsStatsFunc: func() ([]api.DepartmentStats, error) {
+					GetDepartmentsStatsFunc: func(ctx context.Context) ([]api.DepartmentStats, error) {
 						return stats, nil
 					},
 				},
@@ -451,7 +1086,7 @@ func TestHTTPHandler_GetDepartmentsStats(t *testing.T) {
 			fields: fields{
 				authService: authService,
 				salaryService: &service.SalaryServiceMock{
-					GetDepartmentsStatsFunc: func() ([]api.DepartmentStats, error) {
+					GetDepartmentsStatsFunc: func(ctx context.Context) ([]api.DepartmentStats, error) {
 						return nil, errors.New("error getting departments stats")
 					},
 				},
@@ -462,12 +1097,12 @@ func TestHTTPHandler_GetDepartmentsStats(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			salaryController := controller.NewSalaryController(tt.fields.salaryService)
+			salaryController := controller.NewSalaryController(tt.fields.salaryService, 100)
 
 			w := httptest.NewRecorder()
 			_, r := gin.CreateTestContext(w)
 
-			r.Use(middleware.NewAuthMiddleware(tt.fields.authService))
+			r.Use(middleware.NewAuthMiddleware(tt.fields.authService, logger.NewLogger()))
 
 			r.GET("/api/salaries/stats/departments", salaryController.GetDepartmentsStats)
 
@@ -506,8 +1141,8 @@ func TestHTTPHandler_GetSubDepartmentsStats(t *testing.T) {
 			name: "invalid token",
 			fields: fields{
 				authService: &auth.ServiceMock{
-					VerifyTokenFunc: func(context *gin.Context) error {
-						return errors.New("invalid token")
+					VerifyTokenFunc: func(context *gin.Context) (*domain.User, error) {
+						return nil, errors.New("invalid token")
 					},
 				},
 			},
@@ -519,7 +1154,7 @@ func TestHTTPHandler_GetSubDepartmentsStats(t *testing.T) {
 			fields: fields{
 				authService: authService,
 				salaryService: &service.SalaryServiceMock{
-					GetSubDepartmentsStatsFunc: func() ([]api.SubDepartmentStats, error) {
+					GetSubDepartmentsStatsFunc: func(ctx context.Context) ([]api.SubDepartmentStats, error) {
 						return stats, nil
 					},
 				},
@@ -532,7 +1167,7 @@ func TestHTTPHandler_GetSubDepartmentsStats(t *testing.T) {
 			fields: fields{
 				authService: authService,
 				salaryService: &service.SalaryServiceMock{
-					GetSubDepartmentsStatsFunc: func() ([]api.SubDepartmentStats, error) {
+					GetSubDepartmentsStatsFunc: func(ctx context.Context) ([]api.SubDepartmentStats, error) {
 						return nil, errors.New("error getting subDepartments stats")
 					},
 				},
@@ -543,12 +1178,12 @@ func TestHTTPHandler_GetSubDepartmentsStats(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			salaryController := controller.NewSalaryController(tt.fields.salaryService)
+			salaryController := controller.NewSalaryController(tt.fields.salaryService, 100)
 
 			w := httptest.NewRecorder()
 			_, r := gin.CreateTestContext(w)
 
-			r.Use(middleware.NewAuthMiddleware(tt.fields.authService))
+			r.Use(middleware.NewAuthMiddleware(tt.fields.authService, logger.NewLogger()))
 
 			r.GET("/api/salaries/stats/sub-departments", salaryController.GetSubDepartmentsStats)
 
@@ -559,3 +1194,276 @@ func TestHTTPHandler_GetSubDepartmentsStats(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTPHandler_GetHistogram(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		err    error
+		status int
+	}{
+		{
+			name:   "histogram",
+			query:  "strategy=fixed_width&width=10000&group_by=department&department=Banking",
+			status: http.StatusOK,
+		},
+		{
+			name:   "invalid buckets",
+			query:  "strategy=log",
+			err:    fmt.Errorf("%w: log buckets need positive amounts", api.ErrInvalidQuery),
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "currency without exchange rate",
+			query:  "convert_to=USD",
+			err:    fmt.Errorf("%w GBP", currency.ErrUnknownCurrency),
+			status: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "invalid width",
+			query:  "width=wide",
+			status: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			salaryService := &service.SalaryServiceMock{
+				GetHistogramFunc: func(ctx context.Context, query api.HistogramQuery) (*api.Histogram, error) {
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &api.Histogram{Strategy: query.Strategy, Edges: []float64{0, 10000}, Groups: []api.HistogramGroup{{Group: query.Department, Count: 1, Counts: []int64{1}}}}, nil
+				},
+			}
+			salaryController := controller.NewSalaryController(salaryService, 100)
+
+			w := httptest.NewRecorder()
+			_, r := gin.CreateTestContext(w)
+
+			r.Use(middleware.NewAuthMiddleware(authService, logger.NewLogger()))
+
+			r.GET("/api/salaries/stats/histogram", salaryController.GetHistogram)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/salaries/stats/histogram?"+tt.query, nil)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status == http.StatusOK {
+				assert.JSONEq(t, `{"strategy":"fixed_width","edges":[0,10000],"groups":[{"group":"Banking","count":1,"counts":[1]}]}`, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHTTPHandler_GetRank(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		err    error
+		status int
+	}{
+		{
+			name:   "rank",
+			query:  "amount=60000&currency=USD&department=Engineering",
+			status: http.StatusOK,
+		},
+		{
+			name:   "group too small",
+			query:  "amount=60000&currency=USD&department=Banking",
+			err:    fmt.Errorf("%w: fewer than 5 salaries match", api.ErrGroupTooSmall),
+			status: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "missing currency",
+			query:  "amount=60000",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid amount",
+			query:  "amount=a lot&currency=USD",
+			status: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			salaryService := &service.SalaryServiceMock{
+				GetRankFunc: func(ctx context.Context, query api.RankQuery) (*api.PercentileRank, error) {
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &api.PercentileRank{
+						Amount:         query.Amount,
+						Currency:       query.Currency,
+						Department:     query.Department,
+						Count:          6,
+						PercentileRank: 50,
+						Lower:          &api.PercentileBoundary{Percentile: 50, Value: 60000},
+					}, nil
+				},
+			}
+			salaryController := controller.NewSalaryController(salaryService, 100)
+
+			w := httptest.NewRecorder()
+			_, r := gin.CreateTestContext(w)
+
+			r.Use(middleware.NewAuthMiddleware(authService, logger.NewLogger()))
+
+			r.GET("/api/salaries/stats/rank", salaryController.GetRank)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/salaries/stats/rank?"+strings.ReplaceAll(tt.query, " ", "%20"), nil)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status == http.StatusOK {
+				assert.JSONEq(t, `{"amount":60000,"currency":"USD","department":"Engineering","count":6,"percentile_rank":50,"lower":{"percentile":50,"value":60000}}`, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHTTPHandler_GetAnomalies(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		err    error
+		status int
+		json   string
+		csv    string
+	}{
+		{
+			name:   "anomalies",
+			query:  "method=mad&threshold=3&department=Engineering",
+			status: http.StatusOK,
+			json:   `{"method":"mad","threshold":3,"min_group_size":5,"anomalies":[{"salary_id":6,"name":"Anurag","department":"Engineering","sub_department":"Platform","currency":"USD","salary":1000000,"direction":"high","low":80000,"high":120000,"median":100000,"peers":6}]}`,
+		},
+		{
+			name:   "anomalies as csv",
+			query:  "format=csv",
+			status: http.StatusOK,
+			csv:    "id,name,department,sub_department,currency,salary,direction,low,high,median\n6,Anurag,Engineering,Platform,USD,1000000,high,80000,120000,100000\n",
+		},
+		{
+			name:   "invalid method",
+			query:  "method=grubbs",
+			err:    fmt.Errorf("%w: method must be iqr, zscore or mad", api.ErrInvalidQuery),
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid threshold",
+			query:  "threshold=high",
+			status: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			salaryService := &service.SalaryServiceMock{
+				GetAnomaliesFunc: func(ctx context.Context, query api.AnomalyQuery) (*api.AnomalyReport, error) {
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &api.AnomalyReport{Method: query.Method, Threshold: query.Threshold, MinGroupSize: 5, Anomalies: []api.Anomaly{{
+						SalaryID: 6, Name: "Anurag", Department: "Engineering", SubDepartment: "Platform", Currency: "USD", Salary: 1000000,
+						Direction: api.AnomalyDirectionHigh, Low: 80000, High: 120000, Median: 100000, Peers: 6,
+					}}}, nil
+				},
+			}
+			salaryController := controller.NewSalaryController(salaryService, 100)
+
+			w := httptest.NewRecorder()
+			_, r := gin.CreateTestContext(w)
+
+			r.Use(middleware.NewAuthMiddleware(authService, logger.NewLogger()))
+
+			r.GET("/api/salaries/anomalies", salaryController.GetAnomalies)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/salaries/anomalies?"+tt.query, nil)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.json != "" {
+				assert.JSONEq(t, tt.json, w.Body.String())
+			}
+			if tt.csv != "" {
+				assert.Equal(t, tt.csv, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHTTPHandler_CreateReturnsWarnings(t *testing.T) {
+	salaryService := &service.SalaryServiceMock{
+		CreateFunc: func(ctx context.Context, salary *domain.Salary) ([]api.Anomaly, error) {
+			salary.ID = 7
+			return []api.Anomaly{{SalaryID: 7, Salary: salary.Salary, Direction: api.AnomalyDirectionHigh, High: 120000, Peers: 5}}, nil
+		},
+	}
+	salaryController := controller.NewSalaryController(salaryService, 100)
+
+	w := httptest.NewRecorder()
+	_, r := gin.CreateTestContext(w)
+	r.Use(middleware.NewAuthMiddleware(authService, logger.NewLogger()))
+	r.POST("/api/salaries", salaryController.Create)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/salaries", strings.NewReader(`{"name": "Anurag", "salary": "1000000", "currency": "USD", "department": "Engineering", "on_contract": "false", "sub_department": "Platform"}`))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var created api.CreatedSalary
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, int64(7), created.ID)
+	assert.Equal(t, 1000000.0, created.Salary.Salary)
+	assert.Len(t, created.Warnings, 1)
+}
+
+func TestHTTPHandler_Simulate(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+	}{
+		{
+			name:   "simulation",
+			body:   `{"rules": [{"filter": {"department": "Engineering"}, "percentage": 5}, {"filter": {"on_contract": true}, "percentage": 3, "max_increase": 2000}]}`,
+			status: http.StatusOK,
+		},
+		{
+			name:   "invalid rules",
+			body:   `{"rules": []}`,
+			err:    fmt.Errorf("%w: a simulation needs from 1 to 100 rules", api.ErrInvalidQuery),
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid json",
+			body:   `{"rules": [{"percentage": "five"}]}`,
+			status: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			salaryService := &service.SalaryServiceMock{
+				SimulateFunc: func(ctx context.Context, request api.SimulationRequest) (*api.Simulation, error) {
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					assert.Equal(t, "Engineering", request.Rules[0].Filter.Department)
+					assert.True(t, *request.Rules[1].Filter.OnContract)
+					return &api.Simulation{Raised: 1, Salaries: []api.SimulatedSalary{}}, nil
+				},
+			}
+			salaryController := controller.NewSalaryController(salaryService, 100)
+
+			w := httptest.NewRecorder()
+			_, r := gin.CreateTestContext(w)
+
+			r.Use(middleware.NewAuthMiddleware(authService, logger.NewLogger()))
+
+			r.POST("/api/salaries/simulations", salaryController.Simulate)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/salaries/simulations", strings.NewReader(tt.body))
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
@@ -1,31 +1,57 @@
 package controller
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
+	"mime"
 	"net/http"
 	"salaries/pkg/api"
+	"salaries/pkg/currency"
 	"salaries/pkg/domain"
+	"salaries/pkg/export"
+	"salaries/pkg/importer"
+	"salaries/pkg/render"
 	"salaries/pkg/service"
 	"strconv"
+	"strings"
 )
 
 type SalaryController interface {
 	Create(context *gin.Context)
+	CreateBatch(context *gin.Context)
+	Import(context *gin.Context)
 	GetAll(context *gin.Context)
+	Export(context *gin.Context)
+	GetByID(context *gin.Context)
+	Update(context *gin.Context)
 	Delete(context *gin.Context)
+	DeleteBatch(context *gin.Context)
 	GetStatisticsEntireDataset(context *gin.Context)
 	GetContractsStats(context *gin.Context)
 	GetDepartmentsStats(context *gin.Context)
 	GetSubDepartmentsStats(context *gin.Context)
+	GetHistogram(context *gin.Context)
+	GetAnomalies(context *gin.Context)
+	Simulate(context *gin.Context)
+	GetRank(context *gin.Context)
 }
 
 type salaryControllerImpl struct {
 	salaryService service.SalaryService
+	renderers     *render.Registry
+	maxBatchSize  int
 }
 
-func NewSalaryController(salaryService service.SalaryService) SalaryController {
+// NewSalaryController serves the salaries api, bulk requests are limited to maxBatchSize salaries
+func NewSalaryController(salaryService service.SalaryService, maxBatchSize int) SalaryController {
 	return &salaryControllerImpl{
 		salaryService: salaryService,
+		renderers:     render.NewDefaultRegistry(),
+		maxBatchSize:  maxBatchSize,
 	}
 }
 
@@ -36,23 +62,173 @@ func (c salaryControllerImpl) Create(context *gin.Context) {
 		return
 	}
 
-	err := c.salaryService.Create(&salary)
+	warnings, err := c.salaryService.Create(context.Request.Context(), &salary)
+	if err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	context.Header("ETag", salaryETag(&salary))
+	context.JSON(http.StatusCreated, api.CreatedSalary{Salary: salary, Warnings: warnings})
+}
+
+// CreateBatch creates a json array of salaries, responding 201 when all were created, 207 when only some were and 422 when none were
+func (c salaryControllerImpl) CreateBatch(context *gin.Context) {
+	var query api.BatchQuery
+	if err := context.ShouldBindQuery(&query); err != nil {
+		c.badRequest(context, err)
+		return
+	}
+	var salaries []domain.Salary
+	if err := context.ShouldBindJSON(&salaries); err != nil {
+		c.badRequest(context, err)
+		return
+	}
+	if len(salaries) == 0 {
+		c.badRequest(context, errors.New("the batch is empty"))
+		return
+	}
+	if len(salaries) > c.maxBatchSize {
+		c.batchTooLarge(context)
+		return
+	}
+
+	result, err := c.salaryService.CreateMany(context.Request.Context(), salaries, query.Partial)
+	if err != nil {
+		c.internalServerError(context, err)
+		return
+	}
+	c.batchResult(context, result, http.StatusCreated, http.StatusUnprocessableEntity)
+}
+
+func (c salaryControllerImpl) Import(context *gin.Context) {
+	var query api.ImportQuery
+	if err := context.ShouldBindQuery(&query); err != nil {
+		c.badRequest(context, err)
+		return
+	}
+	decoder, err := importer.NewDecoder(context.ContentType(), context.Request.Body)
+	if err != nil {
+		context.JSON(http.StatusUnsupportedMediaType, api.AppError{
+			Error: err,
+			Code:  http.StatusUnsupportedMediaType,
+		})
+		return
+	}
+
+	report, err := c.salaryService.Import(context.Request.Context(), decoder, query.DryRun)
 	if err != nil {
 		c.internalServerError(context, err)
 		return
 	}
-	context.JSON(http.StatusCreated, salary)
+	switch {
+	case len(report.Errors) > 0:
+		context.JSON(http.StatusUnprocessableEntity, report)
+	case report.DryRun:
+		context.JSON(http.StatusOK, report)
+	default:
+		context.JSON(http.StatusCreated, report)
+	}
 }
 
 func (c salaryControllerImpl) GetAll(context *gin.Context) {
-	salaries, err := c.salaryService.GetAll()
+	renderer, ok := c.negotiate(context)
+	if !ok {
+		return
+	}
+	var filter api.SalaryFilter
+	if err := context.ShouldBindQuery(&filter); err != nil {
+		c.badRequest(context, err)
+		return
+	}
+
+	salaries, err := c.salaryService.GetAll(context.Request.Context(), filter)
 	if err != nil {
 		c.internalServerError(context, err)
 		return
 	}
-	context.JSON(http.StatusOK, salaries)
+	c.render(context, renderer, salaries)
+}
+
+// Export streams the salaries to the response as they are read from the database
+func (c salaryControllerImpl) Export(context *gin.Context) {
+	var filter api.SalaryFilter
+	var query api.ExportQuery
+	if err := context.ShouldBindQuery(&filter); err != nil {
+		c.badRequest(context, err)
+		return
+	}
+	if err := context.ShouldBindQuery(&query); err != nil {
+		c.badRequest(context, err)
+		return
+	}
+	writer, err := export.NewWriter(query.Format, context.Writer)
+	if err != nil {
+		c.badRequest(context, err)
+		return
+	}
+
+	c.exportHeaders(context, "salaries", query.Format)
+	err = writer.WriteHeader(export.SalaryColumns)
+	if err == nil {
+		err = c.salaryService.Export(context.Request.Context(), filter, func(salary *domain.Salary) error {
+			return writer.WriteRow(export.SalaryRow(salary))
+		})
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	c.exportError(context, err)
+}
+
+func (c salaryControllerImpl) GetByID(context *gin.Context) {
+	renderer, ok := c.negotiate(context)
+	if !ok {
+		return
+	}
+	ID := context.Param("id")
+	salaryID, err := strconv.ParseInt(ID, 10, 64)
+	if err != nil {
+		c.badRequest(context, err)
+		return
+	}
+
+	salary, err := c.salaryService.GetByID(context.Request.Context(), salaryID)
+	if err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	context.Header("ETag", salaryRepresentationETag(salary, renderer))
+	c.render(context, renderer, salary)
+}
+
+// Update replaces the salary, an If-Match header with the ETag of the salary makes the update fail with 412 if it changed since it was read
+func (c salaryControllerImpl) Update(context *gin.Context) {
+	salaryID, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		c.badRequest(context, err)
+		return
+	}
+	version, ok := c.ifMatchVersion(context)
+	if !ok {
+		return
+	}
+	var salary domain.Salary
+	if err := context.ShouldBindJSON(&salary); err != nil {
+		c.badRequest(context, err)
+		return
+	}
+	salary.ID = salaryID
+
+	err = c.salaryService.Update(context.Request.Context(), &salary, version)
+	if err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	context.Header("ETag", salaryETag(&salary))
+	context.JSON(http.StatusOK, salary)
 }
 
+// Delete deletes the salary, an If-Match header with the ETag of the salary makes the deletion fail with 412 if it changed since it was read
 func (c salaryControllerImpl) Delete(context *gin.Context) {
 	ID := context.Param("id")
 	salaryID, err := strconv.ParseInt(ID, 10, 64)
@@ -60,49 +236,344 @@ func (c salaryControllerImpl) Delete(context *gin.Context) {
 		c.badRequest(context, err)
 		return
 	}
+	version, ok := c.ifMatchVersion(context)
+	if !ok {
+		return
+	}
+
+	err = c.salaryService.DeleteByID(context.Request.Context(), salaryID, version)
+	if err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	context.Status(http.StatusNoContent)
+}
+
+// DeleteBatch deletes the salaries listed in ids or matching the filter, responding 200 when all were deleted, 207 when only some were and 404 when none were
+func (c salaryControllerImpl) DeleteBatch(context *gin.Context) {
+	var query api.BulkDeleteQuery
+	var filter api.SalaryFilter
+	if err := context.ShouldBindQuery(&query); err != nil {
+		c.badRequest(context, err)
+		return
+	}
+	if err := context.ShouldBindQuery(&filter); err != nil {
+		c.badRequest(context, err)
+		return
+	}
 
-	appError := c.salaryService.DeleteByID(salaryID)
-	if appError != nil {
+	var result *api.BatchResult
+	var err error
+	switch {
+	case query.IDs != "" && filter != (api.SalaryFilter{}):
+		c.badRequest(context, errors.New("ids and filters can not be combined"))
+		return
+	case query.IDs != "":
+		ids, parseErr := parseIDs(query.IDs)
+		if parseErr != nil {
+			c.badRequest(context, parseErr)
+			return
+		}
+		if len(ids) > c.maxBatchSize {
+			c.batchTooLarge(context)
+			return
+		}
+		result, err = c.salaryService.DeleteMany(context.Request.Context(), ids, query.Partial)
+	case filter != (api.SalaryFilter{}):
+		result, err = c.salaryService.DeleteMatching(context.Request.Context(), filter, c.maxBatchSize, query.Partial)
+	default:
+		c.badRequest(context, errors.New("ids or a filter are required to delete salaries"))
+		return
+	}
+	if errors.Is(err, api.ErrBatchTooLarge) {
+		c.batchTooLarge(context)
+		return
+	}
+	if err != nil {
 		c.internalServerError(context, err)
 		return
 	}
-	context.Status(http.StatusNoContent)
+	c.batchResult(context, result, http.StatusOK, http.StatusNotFound)
 }
 
 func (c salaryControllerImpl) GetStatisticsEntireDataset(context *gin.Context) {
-	stats, err := c.salaryService.GetStatsForAllSalaries()
+	renderer, ok := c.negotiate(context)
+	if !ok {
+		return
+	}
+	stats, err := c.salaryService.GetStatsForAllSalaries(context.Request.Context())
 	if err != nil {
-		c.internalServerError(context, err)
+		c.serviceError(context, err)
 		return
 	}
-	context.JSON(http.StatusOK, stats)
+	c.stats(context, renderer, "stats", stats)
 }
 
 func (c salaryControllerImpl) GetContractsStats(context *gin.Context) {
-	stats, err := c.salaryService.GetContractsStats()
+	renderer, ok := c.negotiate(context)
+	if !ok {
+		return
+	}
+	stats, err := c.salaryService.GetContractsStats(context.Request.Context())
 	if err != nil {
-		c.internalServerError(context, err)
+		c.serviceError(context, err)
 		return
 	}
-	context.JSON(http.StatusOK, stats)
+	c.stats(context, renderer, "contracts_stats", stats)
 }
 
 func (c salaryControllerImpl) GetDepartmentsStats(context *gin.Context) {
-	stats, err := c.salaryService.GetDepartmentsStats()
+	renderer, ok := c.negotiate(context)
+	if !ok {
+		return
+	}
+	stats, err := c.salaryService.GetDepartmentsStats(context.Request.Context())
 	if err != nil {
 		c.internalServerError(context, err)
 		return
 	}
-	context.JSON(http.StatusOK, stats)
+	c.stats(context, renderer, "departments_stats", stats)
 }
 
 func (c salaryControllerImpl) GetSubDepartmentsStats(context *gin.Context) {
-	stats, err := c.salaryService.GetSubDepartmentsStats()
+	renderer, ok := c.negotiate(context)
+	if !ok {
+		return
+	}
+	stats, err := c.salaryService.GetSubDepartmentsStats(context.Request.Context())
+	if err != nil {
+		c.internalServerError(context, err)
+		return
+	}
+	c.stats(context, renderer, "sub_departments_stats", stats)
+}
+
+func (c salaryControllerImpl) GetHistogram(context *gin.Context) {
+	renderer, ok := c.negotiate(context)
+	if !ok {
+		return
+	}
+	var query api.HistogramQuery
+	if err := context.ShouldBindQuery(&query); err != nil {
+		c.badRequest(context, err)
+		return
+	}
+	histogram, err := c.salaryService.GetHistogram(context.Request.Context(), query)
+	if err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	c.stats(context, renderer, "histogram", histogram)
+}
+
+// GetAnomalies lists the salaries that are outliers among the salaries of their department and sub-department
+func (c salaryControllerImpl) GetAnomalies(context *gin.Context) {
+	renderer, ok := c.negotiate(context)
+	if !ok {
+		return
+	}
+	var query api.AnomalyQuery
+	if err := context.ShouldBindQuery(&query); err != nil {
+		c.badRequest(context, err)
+		return
+	}
+	report, err := c.salaryService.GetAnomalies(context.Request.Context(), query)
+	if err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	c.stats(context, renderer, "anomalies", report)
+}
+
+// Simulate projects the raises of a list of rules on every salary, no salary is changed
+func (c salaryControllerImpl) Simulate(context *gin.Context) {
+	var request api.SimulationRequest
+	if err := context.ShouldBindJSON(&request); err != nil {
+		c.badRequest(context, err)
+		return
+	}
+	simulation, err := c.salaryService.Simulate(context.Request.Context(), request)
+	if err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, simulation)
+}
+
+// stats renders the negotiated media type unless a format is requested to download the stats as a file
+func (c salaryControllerImpl) stats(context *gin.Context, renderer render.Renderer, name string, stats interface{}) {
+	var query api.ExportQuery
+	if err := context.ShouldBindQuery(&query); err != nil {
+		c.badRequest(context, err)
+		return
+	}
+	if query.Format == "" {
+		c.render(context, renderer, stats)
+		return
+	}
+	writer, err := export.NewWriter(query.Format, context.Writer)
+	if err != nil {
+		c.badRequest(context, err)
+		return
+	}
+
+	c.exportHeaders(context, name, query.Format)
+	err = export.WriteTable(writer, stats)
+	if err == nil {
+		err = writer.Close()
+	}
+	c.exportError(context, err)
+}
+
+// negotiate picks the renderer for the Accept header and responds 406 when none can be used
+func (c salaryControllerImpl) negotiate(context *gin.Context) (render.Renderer, bool) {
+	renderer, err := c.renderers.Negotiate(context.GetHeader("Accept"))
 	if err != nil {
+		context.JSON(http.StatusNotAcceptable, api.AppError{
+			Error: err,
+			Code:  http.StatusNotAcceptable,
+		})
+		return nil, false
+	}
+	return renderer, true
+}
+
+// render responds with the value in the negotiated media type, responses without an ETag get one from their body so If-None-Match can be answered with 304
+func (c salaryControllerImpl) render(context *gin.Context, renderer render.Renderer, value interface{}) {
+	var body bytes.Buffer
+	if err := renderer.Render(&body, value); err != nil {
 		c.internalServerError(context, err)
 		return
 	}
-	context.JSON(http.StatusOK, stats)
+	etag := context.Writer.Header().Get("ETag")
+	if etag == "" {
+		sum := sha256.Sum256(body.Bytes())
+		etag = `"` + hex.EncodeToString(sum[:16]) + `"`
+		context.Header("ETag", etag)
+	}
+	context.Header("Vary", "Accept")
+	if noneMatch(context.GetHeader("If-None-Match"), etag) {
+		context.Status(http.StatusNotModified)
+		return
+	}
+	context.Data(http.StatusOK, renderer.ContentType(), body.Bytes())
+}
+
+// ifMatchVersion returns the salary version of the If-Match header, 0 when it is missing or * so any version matches,
+// it responds 412 when the header can not match a salary. The ETag of any representation of the salary matches
+func (c salaryControllerImpl) ifMatchVersion(context *gin.Context) (int64, bool) {
+	header := strings.TrimSpace(context.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, true
+	}
+	tag, _, _ := strings.Cut(strings.Trim(header, `"`), "-")
+	version, err := strconv.ParseInt(tag, 10, 64)
+	if err != nil || version <= 0 || !strings.HasPrefix(header, `"`) {
+		c.preconditionFailed(context, api.ErrPreconditionFailed)
+		return 0, false
+	}
+	return version, true
+}
+
+func salaryETag(salary *domain.Salary) string {
+	return `"` + strconv.FormatInt(salary.Version, 10) + `"`
+}
+
+// salaryRepresentationETag tags the version with the format of the representation, so caches keyed by the ETag keep
+// the formats of the same version apart. The json representation is the one of the create and update responses and
+// keeps their ETag
+func salaryRepresentationETag(salary *domain.Salary, renderer render.Renderer) string {
+	mediaType, _, err := mime.ParseMediaType(renderer.ContentType())
+	if err != nil {
+		mediaType = renderer.ContentType()
+	}
+	_, format, _ := strings.Cut(mediaType, "/")
+	format = strings.TrimPrefix(format, "x-")
+	if format == "json" {
+		return salaryETag(salary)
+	}
+	return `"` + strconv.FormatInt(salary.Version, 10) + "-" + format + `"`
+}
+
+// noneMatch compares If-None-Match with the ETag using the weak comparison
+func noneMatch(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+func (c salaryControllerImpl) exportHeaders(context *gin.Context, name, format string) {
+	context.Header("Content-Type", export.ContentType(format))
+	context.Header("Content-Disposition", `attachment; filename="`+export.FileName(name, format)+`"`)
+	context.Status(http.StatusOK)
+}
+
+// exportError responds with an error while nothing was sent, afterwards the stream can only be cut short
+func (c salaryControllerImpl) exportError(context *gin.Context, err error) {
+	if err == nil {
+		return
+	}
+	if !context.Writer.Written() {
+		context.Writer.Header().Del("Content-Type")
+		context.Writer.Header().Del("Content-Disposition")
+		c.internalServerError(context, err)
+		return
+	}
+	context.Error(err)
+	context.Abort()
+}
+
+func (c salaryControllerImpl) batchResult(context *gin.Context, result *api.BatchResult, success, failure int) {
+	switch {
+	case result.Failed == 0:
+		context.JSON(success, result)
+	case result.Succeeded == 0:
+		context.JSON(failure, result)
+	default:
+		context.JSON(http.StatusMultiStatus, result)
+	}
+}
+
+func (c salaryControllerImpl) batchTooLarge(context *gin.Context) {
+	context.JSON(http.StatusRequestEntityTooLarge, api.AppError{
+		Error: fmt.Errorf("%w, at most %d salaries can be sent", api.ErrBatchTooLarge, c.maxBatchSize),
+		Code:  http.StatusRequestEntityTooLarge,
+	})
+}
+
+// parseIDs reads a comma separated list of salary ids
+func parseIDs(value string) ([]int64, error) {
+	var ids []int64
+	for _, field := range strings.Split(value, ",") {
+		id, err := strconv.ParseInt(strings.TrimSpace(field), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid id %q", field)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// GetRank returns where an amount stands among the salaries of a group, groups too small to stay anonymous get a 422
+func (c salaryControllerImpl) GetRank(context *gin.Context) {
+	var query api.RankQuery
+	if err := context.ShouldBindQuery(&query); err != nil {
+		c.badRequest(context, err)
+		return
+	}
+	rank, err := c.salaryService.GetRank(context.Request.Context(), query)
+	if err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, rank)
 }
 
 func (c salaryControllerImpl) badRequest(context *gin.Context, err error) {
@@ -112,6 +583,39 @@ func (c salaryControllerImpl) badRequest(context *gin.Context, err error) {
 	})
 }
 
+func (c salaryControllerImpl) serviceError(context *gin.Context, err error) {
+	if errors.Is(err, api.ErrNotFound) {
+		context.JSON(http.StatusNotFound, api.AppError{
+			Error: err,
+			Code:  http.StatusNotFound,
+		})
+		return
+	}
+	if errors.Is(err, api.ErrPreconditionFailed) {
+		c.preconditionFailed(context, err)
+		return
+	}
+	if errors.Is(err, api.ErrInvalidQuery) {
+		c.badRequest(context, err)
+		return
+	}
+	if errors.Is(err, currency.ErrUnknownCurrency) || errors.Is(err, api.ErrUnknownPayBand) || errors.Is(err, api.ErrGroupTooSmall) {
+		context.JSON(http.StatusUnprocessableEntity, api.AppError{
+			Error: err,
+			Code:  http.StatusUnprocessableEntity,
+		})
+		return
+	}
+	c.internalServerError(context, err)
+}
+
+func (c salaryControllerImpl) preconditionFailed(context *gin.Context, err error) {
+	context.JSON(http.StatusPreconditionFailed, api.AppError{
+		Error: err,
+		Code:  http.StatusPreconditionFailed,
+	})
+}
+
 func (c salaryControllerImpl) internalServerError(context *gin.Context, err error) {
 	context.JSON(http.StatusInternalServerError, api.AppError{
 		Error: err,
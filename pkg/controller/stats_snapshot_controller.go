@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"salaries/pkg/api"
+	"salaries/pkg/domain"
+	"salaries/pkg/service"
+	"strconv"
+)
+
+type SnapshotController interface {
+	TakeSnapshot(context *gin.Context)
+	GetSnapshots(context *gin.Context)
+	GetSnapshot(context *gin.Context)
+	GetHistory(context *gin.Context)
+	GetDiff(context *gin.Context)
+}
+
+type snapshotControllerImpl struct {
+	snapshotService service.SnapshotService
+}
+
+// NewSnapshotController serves the snapshots of the stats endpoints and their history
+func NewSnapshotController(snapshotService service.SnapshotService) SnapshotController {
+	return &snapshotControllerImpl{
+		snapshotService: snapshotService,
+	}
+}
+
+// TakeSnapshot takes a snapshot on demand besides the scheduled ones
+func (c snapshotControllerImpl) TakeSnapshot(context *gin.Context) {
+	snapshot, err := c.snapshotService.TakeSnapshot(context.Request.Context(), domain.SnapshotSourceManual)
+	if err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	context.JSON(http.StatusCreated, snapshot)
+}
+
+func (c snapshotControllerImpl) GetSnapshots(context *gin.Context) {
+	var filter api.SnapshotFilter
+	if err := context.ShouldBindQuery(&filter); err != nil {
+		c.error(context, http.StatusBadRequest, err)
+		return
+	}
+	snapshots, err := c.snapshotService.GetSnapshots(context.Request.Context(), filter)
+	if err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, snapshots)
+}
+
+func (c snapshotControllerImpl) GetSnapshot(context *gin.Context) {
+	snapshotID, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		c.error(context, http.StatusBadRequest, err)
+		return
+	}
+	snapshot, err := c.snapshotService.GetSnapshot(context.Request.Context(), snapshotID)
+	if err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, snapshot)
+}
+
+func (c snapshotControllerImpl) GetHistory(context *gin.Context) {
+	var query api.StatsHistoryQuery
+	if err := context.ShouldBindQuery(&query); err != nil {
+		c.error(context, http.StatusBadRequest, err)
+		return
+	}
+	history, err := c.snapshotService.GetHistory(context.Request.Context(), query)
+	if err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, history)
+}
+
+// GetDiff compares the snapshots with the from and to ids, a missing snapshot gets a 404
+func (c snapshotControllerImpl) GetDiff(context *gin.Context) {
+	var query api.SnapshotDiffQuery
+	if err := context.ShouldBindQuery(&query); err != nil {
+		c.error(context, http.StatusBadRequest, err)
+		return
+	}
+	diff, err := c.snapshotService.GetDiff(context.Request.Context(), query)
+	if err != nil {
+		c.serviceError(context, err)
+		return
+	}
+	context.JSON(http.StatusOK, diff)
+}
+
+func (c snapshotControllerImpl) serviceError(context *gin.Context, err error) {
+	switch {
+	case errors.Is(err, api.ErrNotFound):
+		c.error(context, http.StatusNotFound, err)
+	case errors.Is(err, api.ErrInvalidQuery):
+		c.error(context, http.StatusBadRequest, err)
+	default:
+		c.error(context, http.StatusInternalServerError, err)
+	}
+}
+
+func (c snapshotControllerImpl) error(context *gin.Context, code int, err error) {
+	context.JSON(code, api.AppError{
+		Error: err,
+		Code:  code,
+	})
+}
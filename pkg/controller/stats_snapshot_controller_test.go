@@ -0,0 +1,131 @@
+package controller_test
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"salaries/pkg/api"
+	"salaries/pkg/auth"
+	"salaries/pkg/controller"
+	"salaries/pkg/domain"
+	"salaries/pkg/logger"
+	"salaries/pkg/middleware"
+	"salaries/pkg/service"
+	"testing"
+)
+
+func TestHTTPHandler_StatsSnapshots(t *testing.T) {
+	snapshotService := &service.SnapshotServiceMock{
+		TakeSnapshotFunc: func(ctx context.Context, source string) (*domain.StatsSnapshot, error) {
+			return &domain.StatsSnapshot{ID: 1, Source: source}, nil
+		},
+		GetSnapshotFunc: func(ctx context.Context, id int64) (*domain.StatsSnapshot, error) {
+			if id == 404 {
+				return nil, api.ErrNotFound
+			}
+			return &domain.StatsSnapshot{ID: id}, nil
+		},
+		GetHistoryFunc: func(ctx context.Context, query api.StatsHistoryQuery) (*api.StatsHistory, error) {
+			if query.Metric == "median" {
+				return nil, api.ErrInvalidQuery
+			}
+			return &api.StatsHistory{Metric: query.Metric, Group: query.Group}, nil
+		},
+		GetDiffFunc: func(ctx context.Context, query api.SnapshotDiffQuery) (*api.SnapshotDiff, error) {
+			return &api.SnapshotDiff{}, nil
+		},
+	}
+	tests := []struct {
+		name        string
+		authService auth.Service
+		method      string
+		path        string
+		status      int
+	}{
+		{
+			name:        "admin takes a snapshot",
+			authService: adminAuthService,
+			method:      http.MethodPost,
+			path:        "/api/salaries/stats/snapshots",
+			status:      http.StatusCreated,
+		},
+		{
+			name:        "users can not take snapshots",
+			authService: authService,
+			method:      http.MethodPost,
+			path:        "/api/salaries/stats/snapshots",
+			status:      http.StatusForbidden,
+		},
+		{
+			name:        "snapshot",
+			authService: authService,
+			method:      http.MethodGet,
+			path:        "/api/salaries/stats/snapshots/1",
+			status:      http.StatusOK,
+		},
+		{
+			name:        "missing snapshot",
+			authService: authService,
+			method:      http.MethodGet,
+			path:        "/api/salaries/stats/snapshots/404",
+			status:      http.StatusNotFound,
+		},
+		{
+			name:        "history",
+			authService: authService,
+			method:      http.MethodGet,
+			path:        "/api/salaries/stats/history?metric=mean&group=department&from=2026-01-01T00:00:00Z",
+			status:      http.StatusOK,
+		},
+		{
+			name:        "history of an unknown metric",
+			authService: authService,
+			method:      http.MethodGet,
+			path:        "/api/salaries/stats/history?metric=median",
+			status:      http.StatusBadRequest,
+		},
+		{
+			name:        "history with an invalid date",
+			authService: authService,
+			method:      http.MethodGet,
+			path:        "/api/salaries/stats/history?from=yesterday",
+			status:      http.StatusBadRequest,
+		},
+		{
+			name:        "diff",
+			authService: authService,
+			method:      http.MethodGet,
+			path:        "/api/salaries/stats/diff?from=1&to=2",
+			status:      http.StatusOK,
+		},
+		{
+			name:        "diff without the second snapshot",
+			authService: authService,
+			method:      http.MethodGet,
+			path:        "/api/salaries/stats/diff?from=1",
+			status:      http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshotController := controller.NewSnapshotController(snapshotService)
+
+			w := httptest.NewRecorder()
+			_, r := gin.CreateTestContext(w)
+
+			stats := r.Group("/api/salaries/stats", middleware.NewAuthMiddleware(tt.authService, logger.NewLogger()))
+			stats.GET("/history", snapshotController.GetHistory)
+			stats.GET("/diff", snapshotController.GetDiff)
+			stats.GET("/snapshots/:id", snapshotController.GetSnapshot)
+			admin := r.Group("/api/salaries/stats", middleware.NewAuthMiddleware(tt.authService, logger.NewLogger()), middleware.NewRoleMiddleware(domain.RoleAdmin))
+			admin.POST("/snapshots", snapshotController.TakeSnapshot)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
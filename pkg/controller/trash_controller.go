@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"salaries/pkg/api"
+	"salaries/pkg/service"
+	"strconv"
+	"time"
+)
+
+type TrashController interface {
+	GetAll(context *gin.Context)
+	Restore(context *gin.Context)
+	Purge(context *gin.Context)
+}
+
+type trashControllerImpl struct {
+	salaryService service.SalaryService
+	retention     time.Duration
+}
+
+// NewTrashController serves the deleted salaries, purges remove the salaries deleted longer than retention ago unless the request overrides it
+func NewTrashController(salaryService service.SalaryService, retention time.Duration) TrashController {
+	return &trashControllerImpl{
+		salaryService: salaryService,
+		retention:     retention,
+	}
+}
+
+func (c trashControllerImpl) GetAll(context *gin.Context) {
+	salaries, err := c.salaryService.GetTrash(context.Request.Context())
+	if err != nil {
+		c.error(context, http.StatusInternalServerError, err)
+		return
+	}
+	context.JSON(http.StatusOK, salaries)
+}
+
+func (c trashControllerImpl) Restore(context *gin.Context) {
+	salaryID, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		c.error(context, http.StatusBadRequest, err)
+		return
+	}
+
+	salary, err := c.salaryService.Restore(context.Request.Context(), salaryID)
+	if errors.Is(err, api.ErrNotFound) {
+		c.error(context, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		c.error(context, http.StatusInternalServerError, err)
+		return
+	}
+	context.Header("ETag", salaryETag(salary))
+	context.JSON(http.StatusOK, salary)
+}
+
+func (c trashControllerImpl) Purge(context *gin.Context) {
+	var query api.PurgeQuery
+	if err := context.ShouldBindQuery(&query); err != nil {
+		c.error(context, http.StatusBadRequest, err)
+		return
+	}
+	olderThan := c.retention
+	if query.OlderThan != nil {
+		olderThan = *query.OlderThan
+	}
+	if olderThan < 0 {
+		c.error(context, http.StatusBadRequest, errors.New("older_than can not be negative"))
+		return
+	}
+
+	result, err := c.salaryService.Purge(context.Request.Context(), olderThan)
+	if err != nil {
+		c.error(context, http.StatusInternalServerError, err)
+		return
+	}
+	context.JSON(http.StatusOK, result)
+}
+
+func (c trashControllerImpl) error(context *gin.Context, code int, err error) {
+	context.JSON(code, api.AppError{
+		Error: err,
+		Code:  code,
+	})
+}
@@ -0,0 +1,139 @@
+package controller_test
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"salaries/pkg/api"
+	"salaries/pkg/controller"
+	"salaries/pkg/domain"
+	"salaries/pkg/logger"
+	"salaries/pkg/middleware"
+	"salaries/pkg/service"
+	"testing"
+	"time"
+)
+
+func TestHTTPHandler_Trash(t *testing.T) {
+	salary := domain.Salary{ID: 1, Name: "Anurag", Salary: 90000, Currency: "USD", Department: "Banking", SubDepartment: "Loan"}
+	tests := []struct {
+		name          string
+		method        string
+		path          string
+		salaryService *service.SalaryServiceMock
+		status        int
+		wantOlderThan time.Duration
+	}{
+		{
+			name:   "getting the trash",
+			method: http.MethodGet,
+			path:   "/api/salaries/trash",
+			salaryService: &service.SalaryServiceMock{
+				GetTrashFunc: func(ctx context.Context) ([]domain.DeletedSalary, error) {
+					return []domain.DeletedSalary{{Salary: salary, DeletedAt: time.Now(), DeletedBy: "pmagnaghi"}}, nil
+				},
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:   "error getting the trash",
+			method: http.MethodGet,
+			path:   "/api/salaries/trash",
+			salaryService: &service.SalaryServiceMock{
+				GetTrashFunc: func(ctx context.Context) ([]domain.DeletedSalary, error) {
+					return nil, errors.New("error getting the trash")
+				},
+			},
+			status: http.StatusInternalServerError,
+		},
+		{
+			name:   "restoring a salary",
+			method: http.MethodPost,
+			path:   "/api/salaries/1/restore",
+			salaryService: &service.SalaryServiceMock{
+				RestoreFunc: func(ctx context.Context, id int64) (*domain.Salary, error) {
+					return &salary, nil
+				},
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:   "restoring a salary that is not in the trash",
+			method: http.MethodPost,
+			path:   "/api/salaries/2/restore",
+			salaryService: &service.SalaryServiceMock{
+				RestoreFunc: func(ctx context.Context, id int64) (*domain.Salary, error) {
+					return nil, api.ErrNotFound
+				},
+			},
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "restoring an invalid id",
+			method: http.MethodPost,
+			path:   "/api/salaries/one/restore",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "purging with the retention",
+			method: http.MethodDelete,
+			path:   "/api/salaries/trash",
+			salaryService: &service.SalaryServiceMock{
+				PurgeFunc: func(ctx context.Context, olderThan time.Duration) (*api.PurgeResult, error) {
+					return &api.PurgeResult{Purged: 2}, nil
+				},
+			},
+			status:        http.StatusOK,
+			wantOlderThan: 72 * time.Hour,
+		},
+		{
+			name:   "purging older than",
+			method: http.MethodDelete,
+			path:   "/api/salaries/trash?older_than=1h",
+			salaryService: &service.SalaryServiceMock{
+				PurgeFunc: func(ctx context.Context, olderThan time.Duration) (*api.PurgeResult, error) {
+					return &api.PurgeResult{Purged: 2}, nil
+				},
+			},
+			status:        http.StatusOK,
+			wantOlderThan: time.Hour,
+		},
+		{
+			name:   "purging with a negative age",
+			method: http.MethodDelete,
+			path:   "/api/salaries/trash?older_than=-1h",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "purging with an invalid age",
+			method: http.MethodDelete,
+			path:   "/api/salaries/trash?older_than=forever",
+			status: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trashController := controller.NewTrashController(tt.salaryService, 72*time.Hour)
+
+			w := httptest.NewRecorder()
+			_, r := gin.CreateTestContext(w)
+
+			r.Use(middleware.NewAuthMiddleware(adminAuthService, logger.NewLogger()))
+
+			r.GET("/api/salaries/trash", trashController.GetAll)
+			r.POST("/api/salaries/:id/restore", trashController.Restore)
+			r.DELETE("/api/salaries/trash", trashController.Purge)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.wantOlderThan != 0 {
+				assert.Equal(t, tt.wantOlderThan, tt.salaryService.PurgeCalls()[0].OlderThan)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+// Package currency converts salary amounts between currencies with configured exchange rates.
+package currency
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrUnknownCurrency = errors.New("no exchange rate for currency")
+
+// Rates holds the value of one unit of each currency in a common base currency, codes are upper case
+type Rates map[string]float64
+
+// Convert returns the amount in the to currency, amounts already in it are returned as they are
+func (r Rates) Convert(amount float64, from, to string) (float64, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	if from == to {
+		return amount, nil
+	}
+	fromRate, ok := r[from]
+	if !ok {
+		return 0, fmt.Errorf("%w %s", ErrUnknownCurrency, from)
+	}
+	toRate, ok := r[to]
+	if !ok {
+		return 0, fmt.Errorf("%w %s", ErrUnknownCurrency, to)
+	}
+	return amount * fromRate / toRate, nil
+}
@@ -0,0 +1,26 @@
+package currency_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"salaries/pkg/currency"
+	"testing"
+)
+
+func TestRates_Convert(t *testing.T) {
+	rates := currency.Rates{"USD": 1, "EUR": 1.1, "INR": 0.012}
+
+	amount, err := rates.Convert(1000, "eur", "USD")
+	assert.NoError(t, err)
+	assert.InDelta(t, 1100, amount, 1e-9)
+
+	amount, err = rates.Convert(1200, "INR", "EUR")
+	assert.NoError(t, err)
+	assert.InDelta(t, 13.0909, amount, 1e-4)
+
+	amount, err = rates.Convert(500, "GBP", "GBP")
+	assert.NoError(t, err)
+	assert.Equal(t, 500.0, amount)
+
+	_, err = rates.Convert(500, "GBP", "USD")
+	assert.ErrorIs(t, err, currency.ErrUnknownCurrency)
+}
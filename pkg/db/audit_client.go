@@ -0,0 +1,154 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"salaries/pkg/api"
+	"salaries/pkg/domain"
+	"salaries/pkg/requestctx"
+	"salaries/pkg/tracing"
+	"strings"
+	"time"
+)
+
+const (
+	auditTable   = "audit_events"
+	auditColumns = "id, occurred_at, actor_id, actor, action, entity, entity_id, request_id, before_snapshot, after_snapshot"
+)
+
+type DataBaseAuditClient interface {
+	Find(ctx context.Context, filter api.AuditFilter) ([]domain.AuditEvent, int64, error)
+}
+
+func NewSqliteAuditClient(client *sql.DB) DataBaseAuditClient {
+	return &dataBaseAuditClientImpl{
+		client: client,
+	}
+}
+
+type dataBaseAuditClientImpl struct {
+	client *sql.DB
+}
+
+// Find returns a page of audit events matching the filter, newest first, and the total of matching events
+func (d dataBaseAuditClientImpl) Find(ctx context.Context, filter api.AuditFilter) ([]domain.AuditEvent, int64, error) {
+	ctx, span := startSpan(ctx, auditTable, "select_audit_events", "SELECT")
+	defer span.End()
+
+	where, args := auditFilterClause(filter)
+
+	var total int64
+	if err := d.client.QueryRowContext(ctx, "SELECT COUNT(*) FROM audit_events"+where, args...).Scan(&total); err != nil {
+		return nil, 0, tracing.RecordError(span, err)
+	}
+
+	query := "SELECT " + auditColumns + " FROM audit_events" + where + " ORDER BY occurred_at DESC, id DESC LIMIT ? OFFSET ?"
+	rows, err := d.client.QueryContext(ctx, query, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, tracing.RecordError(span, err)
+	}
+	defer rows.Close()
+	events := []domain.AuditEvent{}
+	for rows.Next() {
+		var event domain.AuditEvent
+		var actorID, entityID sql.NullInt64
+		var actor, requestID, before, after sql.NullString
+		err := rows.Scan(&event.ID, &event.OccurredAt, &actorID, &actor, &event.Action, &event.Entity, &entityID, &requestID, &before, &after)
+		if err != nil {
+			return nil, 0, tracing.RecordError(span, err)
+		}
+		event.ActorID = actorID.Int64
+		event.Actor = actor.String
+		event.EntityID = entityID.Int64
+		event.RequestID = requestID.String
+		if before.Valid {
+			event.Before = json.RawMessage(before.String)
+		}
+		if after.Valid {
+			event.After = json.RawMessage(after.String)
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, tracing.RecordError(span, err)
+	}
+	return events, total, nil
+}
+
+func auditFilterClause(filter api.AuditFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	if filter.ActorID != 0 {
+		conditions = append(conditions, "actor_id = ?")
+		args = append(args, filter.ActorID)
+	}
+	if filter.Action != "" {
+		conditions = append(conditions, "action = ?")
+		args = append(args, filter.Action)
+	}
+	if filter.Entity != "" {
+		conditions = append(conditions, "entity = ?")
+		args = append(args, filter.Entity)
+	}
+	if filter.EntityID != 0 {
+		conditions = append(conditions, "entity_id = ?")
+		args = append(args, filter.EntityID)
+	}
+	if filter.RequestID != "" {
+		conditions = append(conditions, "request_id = ?")
+		args = append(args, filter.RequestID)
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "occurred_at >= ?")
+		args = append(args, filter.From.UTC())
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "occurred_at < ?")
+		args = append(args, filter.To.UTC())
+	}
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// insertAuditEvent appends an audit event inside the transaction of the change, the actor and request id come from the context
+func insertAuditEvent(ctx context.Context, tx *sql.Tx, action, entity string, entityID int64, before, after interface{}) error {
+	ctx, span := startSpan(ctx, auditTable, "insert_audit_event", "INSERT")
+	defer span.End()
+
+	beforeSnapshot, err := snapshot(before)
+	if err != nil {
+		return tracing.RecordError(span, err)
+	}
+	afterSnapshot, err := snapshot(after)
+	if err != nil {
+		return tracing.RecordError(span, err)
+	}
+	var actorID sql.NullInt64
+	var actor sql.NullString
+	if user := requestctx.User(ctx); user != nil {
+		// users without an id are the admin tools acting on the database directly
+		actorID = sql.NullInt64{Int64: user.ID, Valid: user.ID != 0}
+		actor = sql.NullString{String: user.Username, Valid: true}
+	}
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO audit_events (occurred_at, actor_id, actor, action, entity, entity_id, request_id, before_snapshot, after_snapshot) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		time.Now().UTC(), actorID, actor, action, entity, entityID, requestctx.RequestID(ctx), beforeSnapshot, afterSnapshot)
+	if err != nil {
+		return tracing.RecordError(span, err)
+	}
+	return nil
+}
+
+func snapshot(value interface{}) (sql.NullString, error) {
+	if value == nil {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
@@ -0,0 +1,12 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Backup writes a consistent copy of the database to path, which must not exist yet
+func Backup(ctx context.Context, client *sql.DB, path string) error {
+	_, err := client.ExecContext(ctx, "VACUUM INTO ?", path)
+	return err
+}
@@ -0,0 +1,349 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"salaries/pkg/api"
+	"salaries/pkg/domain"
+	"salaries/pkg/tracing"
+	"strings"
+)
+
+const (
+	budgetTable          = "budgets"
+	budgetColumns        = "id, department, fiscal_year, currency, amount"
+	payrollChangeTable   = "payroll_changes"
+	payrollChangeColumns = "id, kind, salary_id, department, sub_department, salary, currency, percentage, amount, effective_date"
+)
+
+type DataBaseBudgetClient interface {
+	CreateBudget(ctx context.Context, budget *domain.Budget) (*domain.Budget, error)
+	ReadBudgets(ctx context.Context, filter api.BudgetFilter) ([]domain.Budget, error)
+	UpdateBudget(ctx context.Context, budget *domain.Budget) (*domain.Budget, error)
+	DeleteBudgetByID(ctx context.Context, budgetID int64) error
+	CreateChange(ctx context.Context, change *domain.PayrollChange) (*domain.PayrollChange, error)
+	ReadChanges(ctx context.Context, filter api.PayrollChangeFilter) ([]domain.PayrollChange, error)
+	UpdateChange(ctx context.Context, change *domain.PayrollChange) (*domain.PayrollChange, error)
+	DeleteChangeByID(ctx context.Context, changeID int64) error
+}
+
+func NewSqliteBudgetClient(client *sql.DB) DataBaseBudgetClient {
+	return &dataBaseBudgetClientImpl{
+		client: client,
+	}
+}
+
+type dataBaseBudgetClientImpl struct {
+	client *sql.DB
+}
+
+// CreateBudget inserts the budget, api.ErrConflict is returned when the department already has a budget for the fiscal year
+func (d dataBaseBudgetClientImpl) CreateBudget(ctx context.Context, budget *domain.Budget) (*domain.Budget, error) {
+	ctx, span := startSpan(ctx, budgetTable, "insert_budget", "INSERT")
+	defer span.End()
+
+	tx, err := d.client.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, "INSERT INTO budgets (department, fiscal_year, currency, amount) VALUES (?, ?, ?, ?)",
+		budget.Department, budget.FiscalYear, budget.Currency, budget.Amount)
+	if isUniqueViolation(err) {
+		return nil, tracing.RecordError(span, api.ErrConflict)
+	}
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	if budget.ID, err = result.LastInsertId(); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	if err := insertAuditEvent(ctx, tx, domain.AuditActionCreate, domain.AuditEntityBudget, budget.ID, nil, budget); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	return budget, nil
+}
+
+// ReadBudgets returns the budgets matching the filter ordered by fiscal year and department
+func (d dataBaseBudgetClientImpl) ReadBudgets(ctx context.Context, filter api.BudgetFilter) ([]domain.Budget, error) {
+	ctx, span := startSpan(ctx, budgetTable, "select_budgets", "SELECT")
+	defer span.End()
+
+	var conditions []string
+	var args []interface{}
+	if filter.Department != "" {
+		conditions = append(conditions, "department = ?")
+		args = append(args, filter.Department)
+	}
+	if filter.FiscalYear != 0 {
+		conditions = append(conditions, "fiscal_year = ?")
+		args = append(args, filter.FiscalYear)
+	}
+	rows, err := d.client.QueryContext(ctx, "SELECT "+budgetColumns+" FROM budgets"+whereClause(conditions)+" ORDER BY fiscal_year, department", args...)
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	defer rows.Close()
+	budgets := []domain.Budget{}
+	for rows.Next() {
+		budget, err := scanBudget(rows)
+		if err != nil {
+			return nil, tracing.RecordError(span, err)
+		}
+		budgets = append(budgets, *budget)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	return budgets, nil
+}
+
+// UpdateBudget replaces the budget, api.ErrConflict is returned when it is moved to a department and year that has one
+func (d dataBaseBudgetClientImpl) UpdateBudget(ctx context.Context, budget *domain.Budget) (*domain.Budget, error) {
+	ctx, span := startSpan(ctx, budgetTable, "update_budget", "UPDATE")
+	defer span.End()
+
+	tx, err := d.client.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	defer tx.Rollback()
+
+	before, err := selectBudget(ctx, tx, budget.ID)
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	_, err = tx.ExecContext(ctx, "UPDATE budgets SET department = ?, fiscal_year = ?, currency = ?, amount = ? WHERE id = ?",
+		budget.Department, budget.FiscalYear, budget.Currency, budget.Amount, budget.ID)
+	if isUniqueViolation(err) {
+		return nil, tracing.RecordError(span, api.ErrConflict)
+	}
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	if err := insertAuditEvent(ctx, tx, domain.AuditActionUpdate, domain.AuditEntityBudget, budget.ID, before, budget); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	return budget, nil
+}
+
+func (d dataBaseBudgetClientImpl) DeleteBudgetByID(ctx context.Context, budgetID int64) error {
+	ctx, span := startSpan(ctx, budgetTable, "delete_budget", "DELETE")
+	defer span.End()
+
+	tx, err := d.client.BeginTx(ctx, nil)
+	if err != nil {
+		return tracing.RecordError(span, err)
+	}
+	defer tx.Rollback()
+
+	before, err := selectBudget(ctx, tx, budgetID)
+	if err != nil {
+		return tracing.RecordError(span, err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM budgets WHERE id = ?", budgetID); err != nil {
+		return tracing.RecordError(span, err)
+	}
+	if err := insertAuditEvent(ctx, tx, domain.AuditActionDelete, domain.AuditEntityBudget, budgetID, before, nil); err != nil {
+		return tracing.RecordError(span, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return tracing.RecordError(span, err)
+	}
+	return nil
+}
+
+// CreateChange inserts the change, api.ErrUnknownSalary is returned when a termination or raise refers to a salary that
+// does not exist or was deleted
+func (d dataBaseBudgetClientImpl) CreateChange(ctx context.Context, change *domain.PayrollChange) (*domain.PayrollChange, error) {
+	ctx, span := startSpan(ctx, payrollChangeTable, "insert_payroll_change", "INSERT")
+	defer span.End()
+
+	tx, err := d.client.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	defer tx.Rollback()
+
+	if err := checkChangeSalary(ctx, tx, change); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	result, err := tx.ExecContext(ctx, "INSERT INTO payroll_changes (kind, salary_id, department, sub_department, salary, currency, percentage, amount, effective_date) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		change.Kind, change.SalaryID, change.Department, change.SubDepartment, change.Salary, change.Currency, change.Percentage, change.Amount, change.EffectiveDate.UTC())
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	if change.ID, err = result.LastInsertId(); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	if err := insertAuditEvent(ctx, tx, domain.AuditActionCreate, domain.AuditEntityPayrollChange, change.ID, nil, change); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	return change, nil
+}
+
+// ReadChanges returns the changes matching the filter ordered by effective date
+func (d dataBaseBudgetClientImpl) ReadChanges(ctx context.Context, filter api.PayrollChangeFilter) ([]domain.PayrollChange, error) {
+	ctx, span := startSpan(ctx, payrollChangeTable, "select_payroll_changes", "SELECT")
+	defer span.End()
+
+	var conditions []string
+	var args []interface{}
+	if filter.Kind != "" {
+		conditions = append(conditions, "kind = ?")
+		args = append(args, filter.Kind)
+	}
+	if filter.SalaryID != 0 {
+		conditions = append(conditions, "salary_id = ?")
+		args = append(args, filter.SalaryID)
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "effective_date >= ?")
+		args = append(args, filter.From.UTC())
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "effective_date < ?")
+		args = append(args, filter.To.UTC())
+	}
+	rows, err := d.client.QueryContext(ctx, "SELECT "+payrollChangeColumns+" FROM payroll_changes"+whereClause(conditions)+" ORDER BY effective_date, id", args...)
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	defer rows.Close()
+	changes := []domain.PayrollChange{}
+	for rows.Next() {
+		change, err := scanPayrollChange(rows)
+		if err != nil {
+			return nil, tracing.RecordError(span, err)
+		}
+		changes = append(changes, *change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	return changes, nil
+}
+
+func (d dataBaseBudgetClientImpl) UpdateChange(ctx context.Context, change *domain.PayrollChange) (*domain.PayrollChange, error) {
+	ctx, span := startSpan(ctx, payrollChangeTable, "update_payroll_change", "UPDATE")
+	defer span.End()
+
+	tx, err := d.client.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	defer tx.Rollback()
+
+	before, err := selectPayrollChange(ctx, tx, change.ID)
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	if err := checkChangeSalary(ctx, tx, change); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	_, err = tx.ExecContext(ctx, "UPDATE payroll_changes SET kind = ?, salary_id = ?, department = ?, sub_department = ?, salary = ?, currency = ?, percentage = ?, amount = ?, effective_date = ? WHERE id = ?",
+		change.Kind, change.SalaryID, change.Department, change.SubDepartment, change.Salary, change.Currency, change.Percentage, change.Amount, change.EffectiveDate.UTC(), change.ID)
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	if err := insertAuditEvent(ctx, tx, domain.AuditActionUpdate, domain.AuditEntityPayrollChange, change.ID, before, change); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	return change, nil
+}
+
+func (d dataBaseBudgetClientImpl) DeleteChangeByID(ctx context.Context, changeID int64) error {
+	ctx, span := startSpan(ctx, payrollChangeTable, "delete_payroll_change", "DELETE")
+	defer span.End()
+
+	tx, err := d.client.BeginTx(ctx, nil)
+	if err != nil {
+		return tracing.RecordError(span, err)
+	}
+	defer tx.Rollback()
+
+	before, err := selectPayrollChange(ctx, tx, changeID)
+	if err != nil {
+		return tracing.RecordError(span, err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM payroll_changes WHERE id = ?", changeID); err != nil {
+		return tracing.RecordError(span, err)
+	}
+	if err := insertAuditEvent(ctx, tx, domain.AuditActionDelete, domain.AuditEntityPayrollChange, changeID, before, nil); err != nil {
+		return tracing.RecordError(span, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return tracing.RecordError(span, err)
+	}
+	return nil
+}
+
+// checkChangeSalary fails with api.ErrUnknownSalary when a termination or raise refers to a salary that is not in the list
+func checkChangeSalary(ctx context.Context, tx *sql.Tx, change *domain.PayrollChange) error {
+	if change.Kind == domain.PayrollChangeHire {
+		return nil
+	}
+	var salaries int
+	if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM salaries WHERE id = ? AND "+notDeleted, change.SalaryID).Scan(&salaries); err != nil {
+		return err
+	}
+	if salaries == 0 {
+		return api.ErrUnknownSalary
+	}
+	return nil
+}
+
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
+
+func selectBudget(ctx context.Context, tx *sql.Tx, budgetID int64) (*domain.Budget, error) {
+	budget, err := scanBudget(tx.QueryRowContext(ctx, "SELECT "+budgetColumns+" FROM budgets WHERE id = ?", budgetID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, api.ErrNotFound
+	}
+	return budget, err
+}
+
+func selectPayrollChange(ctx context.Context, tx *sql.Tx, changeID int64) (*domain.PayrollChange, error) {
+	change, err := scanPayrollChange(tx.QueryRowContext(ctx, "SELECT "+payrollChangeColumns+" FROM payroll_changes WHERE id = ?", changeID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, api.ErrNotFound
+	}
+	return change, err
+}
+
+func scanBudget(row rowScanner) (*domain.Budget, error) {
+	var budget domain.Budget
+	if err := row.Scan(&budget.ID, &budget.Department, &budget.FiscalYear, &budget.Currency, &budget.Amount); err != nil {
+		return nil, err
+	}
+	return &budget, nil
+}
+
+func scanPayrollChange(row rowScanner) (*domain.PayrollChange, error) {
+	var change domain.PayrollChange
+	err := row.Scan(&change.ID, &change.Kind, &change.SalaryID, &change.Department, &change.SubDepartment, &change.Salary, &change.Currency,
+		&change.Percentage, &change.Amount, &change.EffectiveDate)
+	if err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
@@ -1,19 +1,47 @@
 package db
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"github.com/mattn/go-sqlite3"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"salaries/pkg/api"
 	"salaries/pkg/domain"
+	"salaries/pkg/requestctx"
+	"salaries/pkg/tracing"
+	"strings"
+	"time"
 )
 
+const (
+	dbSystem      = "sqlite"
+	salaryTable   = "salaries"
+	salaryColumns = "id, name, salary, currency, on_contract, department, sub_department, pay_band, version"
+	insertSalary  = "INSERT INTO salaries (name, salary, currency, on_contract, department, sub_department, pay_band) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	// notDeleted excludes the salaries in the trash, every read and stats query applies it
+	notDeleted = "deleted_at IS NULL"
+)
+
+var tracer = otel.Tracer("salaries/pkg/db")
+
 type DataBaseSalaryClient interface {
-	Create(salary *domain.Salary) (*domain.Salary, error)
-	ReadAll() ([]domain.Salary, error)
-	DeleteByID(salaryID int64) error
-	GetStatsForAllSalaries() (*api.Stats, error)
-	GetContractsStats() (*api.Stats, error)
-	GetDepartmentsStats() ([]api.DepartmentStats, error)
-	GetSubDepartmentsStats() ([]api.SubDepartmentStats, error)
+	Create(ctx context.Context, salary *domain.Salary) (*domain.Salary, error)
+	Batch(ctx context.Context) (SalaryBatch, error)
+	ReadAll(ctx context.Context, filter api.SalaryFilter) ([]domain.Salary, error)
+	ReadEach(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary) error) error
+	ReadByID(ctx context.Context, salaryID int64) (*domain.Salary, error)
+	Update(ctx context.Context, salary *domain.Salary, version int64) (*domain.Salary, error)
+	DeleteByID(ctx context.Context, salaryID int64, version int64) (*domain.Salary, error)
+	ReadTrash(ctx context.Context) ([]domain.DeletedSalary, error)
+	Restore(ctx context.Context, salaryID int64) (*domain.Salary, error)
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
+	GetStatsForAllSalaries(ctx context.Context) (*api.Stats, error)
+	GetContractsStats(ctx context.Context) (*api.Stats, error)
+	GetDepartmentsStats(ctx context.Context) ([]api.DepartmentStats, error)
+	GetSubDepartmentsStats(ctx context.Context) ([]api.SubDepartmentStats, error)
 }
 
 func NewSqlite(client *sql.DB) DataBaseSalaryClient {
@@ -26,121 +54,411 @@ type dataBaseClientImpl struct {
 	client *sql.DB
 }
 
-func (d dataBaseClientImpl) Create(salary *domain.Salary) (*domain.Salary, error) {
-	statement, err := d.client.Prepare("INSERT INTO salaries (name, salary, currency, on_contract, department, sub_department) VALUES (?, ?, ?, ?, ?, ?)")
-	result, err := statement.Exec(salary.Name, salary.Salary, salary.Currency, salary.OnContract, salary.Department, salary.SubDepartment)
+// Create inserts the salary and its audit event in the same transaction
+func (d dataBaseClientImpl) Create(ctx context.Context, salary *domain.Salary) (*domain.Salary, error) {
+	ctx, span := startSpan(ctx, salaryTable, "insert_salary", "INSERT")
+	defer span.End()
+
+	tx, err := d.client.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, err
+		return nil, tracing.RecordError(span, err)
 	}
-	id, err := result.LastInsertId()
+	defer tx.Rollback()
+
+	if err := checkPayBand(ctx, tx, salary.PayBand); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	result, err := tx.ExecContext(ctx, insertSalary,
+		salary.Name, salary.Salary, salary.Currency, salary.OnContract, salary.Department, salary.SubDepartment, salary.PayBand)
 	if err != nil {
-		return nil, err
+		return nil, tracing.RecordError(span, err)
+	}
+	if err := auditCreatedSalary(ctx, tx, result, salary); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, tracing.RecordError(span, err)
 	}
-	salary.ID = id
 	return salary, nil
 }
 
-func (d dataBaseClientImpl) ReadAll() ([]domain.Salary, error) {
-	rows, _ := d.client.Query("SELECT id, name, salary, currency, on_contract, department, sub_department FROM salaries")
+func (d dataBaseClientImpl) ReadAll(ctx context.Context, filter api.SalaryFilter) ([]domain.Salary, error) {
 	var salaries []domain.Salary
-	var salary domain.Salary
-	for rows.Next() {
-		rows.Scan(&salary.ID, &salary.Name, &salary.Salary, &salary.Currency, &salary.OnContract, &salary.Department, &salary.SubDepartment)
-		salaries = append(salaries, salary)
+	err := d.ReadEach(ctx, filter, func(salary *domain.Salary) error {
+		salaries = append(salaries, *salary)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	return salaries, nil
 }
 
-func (d dataBaseClientImpl) DeleteByID(salaryID int64) error {
-	query := "delete from salaries where id=?"
-	statement, err := d.client.Prepare(query)
+// ReadEach calls each for every salary matching the filter while iterating the cursor, the rows are never held in memory
+func (d dataBaseClientImpl) ReadEach(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary) error) error {
+	ctx, span := startSpan(ctx, salaryTable, "select_salaries", "SELECT")
+	defer span.End()
+
+	where, args := salaryFilterClause(filter)
+	rows, err := d.client.QueryContext(ctx, "SELECT "+salaryColumns+" FROM salaries"+where+" ORDER BY id", args...)
 	if err != nil {
-		return err
+		return tracing.RecordError(span, err)
 	}
-	_, err = statement.Exec(salaryID)
-	if err != nil {
-		return err
+	defer rows.Close()
+	for rows.Next() {
+		salary, err := scanSalary(rows)
+		if err != nil {
+			return tracing.RecordError(span, err)
+		}
+		if err := each(salary); err != nil {
+			return tracing.RecordError(span, err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return tracing.RecordError(span, err)
 	}
 	return nil
 }
 
-func (d dataBaseClientImpl) GetStatsForAllSalaries() (*api.Stats, error) {
-	rows, err := d.client.Query("SELECT MIN(salary) as min, MAX(salary) as max, AVG(salary) as mean FROM salaries")
+// ReadByID returns a single salary, individual reads are sensitive so they are audited too
+func (d dataBaseClientImpl) ReadByID(ctx context.Context, salaryID int64) (*domain.Salary, error) {
+	ctx, span := startSpan(ctx, salaryTable, "select_salary", "SELECT")
+	defer span.End()
+
+	tx, err := d.client.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, err
+		return nil, tracing.RecordError(span, err)
+	}
+	defer tx.Rollback()
+
+	salary, err := selectSalary(ctx, tx, salaryID)
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	if err := insertAuditEvent(ctx, tx, domain.AuditActionRead, domain.AuditEntitySalary, salaryID, nil, nil); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	return salary, nil
+}
+
+// Update replaces the salary and increments its version, it returns the salary as it was stored before the update. A non
+// zero version must be the current one or api.ErrPreconditionFailed is returned
+func (d dataBaseClientImpl) Update(ctx context.Context, salary *domain.Salary, version int64) (*domain.Salary, error) {
+	ctx, span := startSpan(ctx, salaryTable, "update_salary", "UPDATE")
+	defer span.End()
+
+	tx, err := d.client.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	defer tx.Rollback()
+
+	before, err := selectSalaryVersion(ctx, tx, salary.ID, version)
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	if err := checkPayBand(ctx, tx, salary.PayBand); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	result, err := tx.ExecContext(ctx, "UPDATE salaries SET name = ?, salary = ?, currency = ?, on_contract = ?, department = ?, sub_department = ?, pay_band = ?, version = version + 1 WHERE id = ? AND version = ?",
+		salary.Name, salary.Salary, salary.Currency, salary.OnContract, salary.Department, salary.SubDepartment, salary.PayBand, before.ID, before.Version)
+	if err := checkVersionUpdated(result, err); err != nil {
+		return nil, tracing.RecordError(span, err)
 	}
-	var min, max, mean float64
+	salary.Version = before.Version + 1
+	if err := insertAuditEvent(ctx, tx, domain.AuditActionUpdate, domain.AuditEntitySalary, salary.ID, before, salary); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	return before, nil
+}
+
+// DeleteByID moves the salary to the trash and records its last state in the audit log in the same transaction, it
+// returns the deleted salary. A non zero version must be the current one or api.ErrPreconditionFailed is returned
+func (d dataBaseClientImpl) DeleteByID(ctx context.Context, salaryID int64, version int64) (*domain.Salary, error) {
+	ctx, span := startSpan(ctx, salaryTable, "delete_salary", "DELETE")
+	defer span.End()
+
+	tx, err := d.client.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	defer tx.Rollback()
+
+	before, err := selectSalaryVersion(ctx, tx, salaryID, version)
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	if err := deleteSalary(ctx, tx, before); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	return before, nil
+}
+
+func (d dataBaseClientImpl) GetStatsForAllSalaries(ctx context.Context) (*api.Stats, error) {
+	ctx, span := startSpan(ctx, salaryTable, "select_stats", "SELECT")
+	defer span.End()
+
+	rows, err := d.client.QueryContext(ctx, "SELECT MIN(salary) as min, MAX(salary) as max, AVG(salary) as mean, COUNT(*) as count, SUM(salary * salary) as sumOfSquares FROM salaries WHERE "+notDeleted)
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	defer rows.Close()
+	var min, max, mean, sumOfSquares sql.NullFloat64
+	var count int64
 	for rows.Next() {
-		rows.Scan(&min, &max, &mean)
+		if err := rows.Scan(&min, &max, &mean, &count, &sumOfSquares); err != nil {
+			return nil, tracing.RecordError(span, err)
+		}
 	}
 	return &api.Stats{
-		Mean: mean,
-		Max:  max,
-		Min:  min,
+		Mean:         mean.Float64,
+		Max:          max.Float64,
+		Min:          min.Float64,
+		Count:        count,
+		SumOfSquares: sumOfSquares.Float64,
 	}, nil
 }
 
-func (d dataBaseClientImpl) GetContractsStats() (*api.Stats, error) {
-	rows, err := d.client.Query("SELECT MIN(salary) as minPrice, MAX(salary) as maxPrice, AVG(salary) as mean FROM salaries WHERE on_contract = 1")
+func (d dataBaseClientImpl) GetContractsStats(ctx context.Context) (*api.Stats, error) {
+	ctx, span := startSpan(ctx, salaryTable, "select_contracts_stats", "SELECT")
+	defer span.End()
+
+	rows, err := d.client.QueryContext(ctx, "SELECT MIN(salary) as minPrice, MAX(salary) as maxPrice, AVG(salary) as mean, COUNT(*) as count, SUM(salary * salary) as sumOfSquares FROM salaries WHERE on_contract = 1 AND "+notDeleted)
 	if err != nil {
-		return nil, err
+		return nil, tracing.RecordError(span, err)
 	}
-	var min, max, mean float64
+	defer rows.Close()
+	var min, max, mean, sumOfSquares sql.NullFloat64
+	var count int64
 	for rows.Next() {
-		rows.Scan(&min, &max, &mean)
+		if err := rows.Scan(&min, &max, &mean, &count, &sumOfSquares); err != nil {
+			return nil, tracing.RecordError(span, err)
+		}
 	}
 	return &api.Stats{
-		Mean: mean,
-		Max:  max,
-		Min:  min,
+		Mean:         mean.Float64,
+		Max:          max.Float64,
+		Min:          min.Float64,
+		Count:        count,
+		SumOfSquares: sumOfSquares.Float64,
 	}, nil
 }
 
-func (d dataBaseClientImpl) GetDepartmentsStats() ([]api.DepartmentStats, error) {
-	rows, err := d.client.Query("SELECT department as subDepartment, MIN(salary) as minPrice, MAX(salary) as maxPrice, AVG(salary) as mean FROM salaries GROUP BY department")
+func (d dataBaseClientImpl) GetDepartmentsStats(ctx context.Context) ([]api.DepartmentStats, error) {
+	ctx, span := startSpan(ctx, salaryTable, "select_departments_stats", "SELECT")
+	defer span.End()
+
+	rows, err := d.client.QueryContext(ctx, "SELECT department as subDepartment, MIN(salary) as minPrice, MAX(salary) as maxPrice, AVG(salary) as mean, COUNT(*) as count, SUM(salary * salary) as sumOfSquares FROM salaries WHERE "+notDeleted+" GROUP BY department")
 	if err != nil {
-		return nil, err
+		return nil, tracing.RecordError(span, err)
 	}
+	defer rows.Close()
 	var departmentsStats []api.DepartmentStats
 	var department string
-	var min, max, mean float64
+	var min, max, mean, sumOfSquares float64
+	var count int64
 	for rows.Next() {
-		rows.Scan(&department, &min, &max, &mean)
+		if err := rows.Scan(&department, &min, &max, &mean, &count, &sumOfSquares); err != nil {
+			return nil, tracing.RecordError(span, err)
+		}
 		departmentStats := api.DepartmentStats{
 			Department: department,
 			Stats: api.Stats{
-				Mean: mean,
-				Max:  max,
-				Min:  min,
+				Mean:         mean,
+				Max:          max,
+				Min:          min,
+				Count:        count,
+				SumOfSquares: sumOfSquares,
 			},
 		}
 		departmentsStats = append(departmentsStats, departmentStats)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
 	return departmentsStats, nil
 }
 
-func (d dataBaseClientImpl) GetSubDepartmentsStats() ([]api.SubDepartmentStats, error) {
-	rows, err := d.client.Query("SELECT department, sub_department as subDepartment, MIN(salary) as minPrice, MAX(salary) as maxPrice, AVG(salary) as mean FROM salaries GROUP BY department, sub_department")
+func (d dataBaseClientImpl) GetSubDepartmentsStats(ctx context.Context) ([]api.SubDepartmentStats, error) {
+	ctx, span := startSpan(ctx, salaryTable, "select_sub_departments_stats", "SELECT")
+	defer span.End()
+
+	rows, err := d.client.QueryContext(ctx, "SELECT department, sub_department as subDepartment, MIN(salary) as minPrice, MAX(salary) as maxPrice, AVG(salary) as mean, COUNT(*) as count, SUM(salary * salary) as sumOfSquares FROM salaries WHERE "+notDeleted+" GROUP BY department, sub_department")
 	if err != nil {
-		return nil, err
+		return nil, tracing.RecordError(span, err)
 	}
+	defer rows.Close()
 	var subDepartmentsStats []api.SubDepartmentStats
 	var department, subDepartment string
-	var min, max, mean float64
+	var min, max, mean, sumOfSquares float64
+	var count int64
 	for rows.Next() {
-		rows.Scan(&department, &subDepartment, &min, &max, &mean)
+		if err := rows.Scan(&department, &subDepartment, &min, &max, &mean, &count, &sumOfSquares); err != nil {
+			return nil, tracing.RecordError(span, err)
+		}
 		departmentStats := api.SubDepartmentStats{
 			SubDepartment: subDepartment,
 			DepartmentStats: api.DepartmentStats{
 				Department: department,
 				Stats: api.Stats{
-					Mean: mean,
-					Max:  max,
-					Min:  min,
+					Mean:         mean,
+					Max:          max,
+					Min:          min,
+					Count:        count,
+					SumOfSquares: sumOfSquares,
 				},
 			},
 		}
 		subDepartmentsStats = append(subDepartmentsStats, departmentStats)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
 	return subDepartmentsStats, nil
 }
+
+// Batch opens a transaction to insert and delete many salaries, nothing is written until the batch is committed
+func (d dataBaseClientImpl) Batch(ctx context.Context) (SalaryBatch, error) {
+	ctx, span := startSpan(ctx, salaryTable, "batch_salaries", "BATCH")
+
+	tx, err := d.client.BeginTx(ctx, nil)
+	if err != nil {
+		err = tracing.RecordError(span, err)
+		span.End()
+		return nil, err
+	}
+	statement, err := tx.PrepareContext(ctx, insertSalary)
+	if err != nil {
+		tx.Rollback()
+		err = tracing.RecordError(span, err)
+		span.End()
+		return nil, err
+	}
+	return &salaryBatchImpl{
+		tx:        tx,
+		statement: statement,
+		span:      span,
+	}, nil
+}
+
+func auditCreatedSalary(ctx context.Context, tx *sql.Tx, result sql.Result, salary *domain.Salary) error {
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	salary.ID = id
+	salary.Version = 1
+	return insertAuditEvent(ctx, tx, domain.AuditActionCreate, domain.AuditEntitySalary, id, nil, salary)
+}
+
+// deleteSalary moves a salary read in the same transaction to the trash and audits its last state
+func deleteSalary(ctx context.Context, tx *sql.Tx, before *domain.Salary) error {
+	var deletedBy sql.NullString
+	if user := requestctx.User(ctx); user != nil {
+		deletedBy = sql.NullString{String: user.Username, Valid: true}
+	}
+	result, err := tx.ExecContext(ctx, "UPDATE salaries SET deleted_at = ?, deleted_by = ?, version = version + 1 WHERE id = ? AND version = ?",
+		time.Now().UTC(), deletedBy, before.ID, before.Version)
+	if err := checkVersionUpdated(result, err); err != nil {
+		return err
+	}
+	return insertAuditEvent(ctx, tx, domain.AuditActionDelete, domain.AuditEntitySalary, before.ID, before, nil)
+}
+
+func salaryFilterClause(filter api.SalaryFilter) (string, []interface{}) {
+	conditions := []string{notDeleted}
+	var args []interface{}
+	if filter.Department != "" {
+		conditions = append(conditions, "department = ?")
+		args = append(args, filter.Department)
+	}
+	if filter.SubDepartment != "" {
+		conditions = append(conditions, "sub_department = ?")
+		args = append(args, filter.SubDepartment)
+	}
+	if filter.Currency != "" {
+		conditions = append(conditions, "currency = ?")
+		args = append(args, filter.Currency)
+	}
+	if filter.OnContract != nil {
+		conditions = append(conditions, "on_contract = ?")
+		args = append(args, *filter.OnContract)
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// isUniqueViolation tells if the statement failed on a unique constraint
+func isUniqueViolation(err error) bool {
+	var sqliteError sqlite3.Error
+	return errors.As(err, &sqliteError) && sqliteError.ExtendedCode == sqlite3.ErrConstraintUnique
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSalary(row rowScanner) (*domain.Salary, error) {
+	var salary domain.Salary
+	err := row.Scan(&salary.ID, &salary.Name, &salary.Salary, &salary.Currency, &salary.OnContract, &salary.Department, &salary.SubDepartment, &salary.PayBand, &salary.Version)
+	if err != nil {
+		return nil, err
+	}
+	return &salary, nil
+}
+
+func selectSalary(ctx context.Context, tx *sql.Tx, salaryID int64) (*domain.Salary, error) {
+	salary, err := scanSalary(tx.QueryRowContext(ctx, "SELECT "+salaryColumns+" FROM salaries WHERE id = ? AND "+notDeleted, salaryID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, api.ErrNotFound
+	}
+	return salary, err
+}
+
+// selectSalaryVersion reads the salary to change, a non zero version must be the current one
+func selectSalaryVersion(ctx context.Context, tx *sql.Tx, salaryID int64, version int64) (*domain.Salary, error) {
+	salary, err := selectSalary(ctx, tx, salaryID)
+	if err != nil {
+		return nil, err
+	}
+	if version != 0 && salary.Version != version {
+		return nil, api.ErrPreconditionFailed
+	}
+	return salary, nil
+}
+
+// checkVersionUpdated fails when a versioned update matched no row because the salary changed since it was read
+func checkVersionUpdated(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return api.ErrPreconditionFailed
+	}
+	return nil
+}
+
+// startSpan starts a span for a single SQL statement, only the statement name is recorded and never its parameters
+func startSpan(ctx context.Context, table, statementName, operation string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "db."+statementName,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", dbSystem),
+			attribute.String("db.operation", operation),
+			attribute.String("db.sql.table", table),
+			attribute.String("db.statement.name", statementName),
+		),
+	)
+}
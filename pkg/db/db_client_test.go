@@ -0,0 +1,582 @@
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"salaries/pkg/api"
+	"salaries/pkg/db"
+	"salaries/pkg/domain"
+	"salaries/pkg/requestctx"
+	"testing"
+	"time"
+)
+
+func newTestDatabase(t *testing.T) *sql.DB {
+	database, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	// every connection to :memory: is a different database
+	database.SetMaxOpenConns(1)
+	t.Cleanup(func() { database.Close() })
+	require.NoError(t, db.Migrate(context.Background(), database))
+	return database
+}
+
+func newTestContext() context.Context {
+	ctx := requestctx.WithUser(context.Background(), &domain.User{ID: 1, Username: "pmagnaghi", Role: domain.RoleAdmin})
+	return requestctx.WithRequestID(ctx, "request-1")
+}
+
+func TestMigrate_IsIdempotent(t *testing.T) {
+	database := newTestDatabase(t)
+
+	assert.NoError(t, db.Migrate(context.Background(), database))
+	version, err := db.SchemaVersion(context.Background(), database)
+	assert.NoError(t, err)
+	assert.Greater(t, version, 0)
+}
+
+func TestDataBaseClient_AuditsMutations(t *testing.T) {
+	database := newTestDatabase(t)
+	salaryClient := db.NewSqlite(database)
+	auditClient := db.NewSqliteAuditClient(database)
+	ctx := newTestContext()
+
+	salary, err := salaryClient.Create(ctx, &domain.Salary{
+		Name:          "Anurag",
+		Salary:        90000,
+		Currency:      "USD",
+		Department:    "Banking",
+		SubDepartment: "Loan",
+	})
+	require.NoError(t, err)
+	_, err = salaryClient.ReadByID(ctx, salary.ID)
+	require.NoError(t, err)
+	_, err = salaryClient.DeleteByID(ctx, salary.ID, 0)
+	require.NoError(t, err)
+
+	events, total, err := auditClient.Find(ctx, api.AuditFilter{EntityID: salary.ID, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	require.Len(t, events, 3)
+
+	deleted, read, created := events[0], events[1], events[2]
+	assert.Equal(t, domain.AuditActionCreate, created.Action)
+	assert.Nil(t, created.Before)
+	assert.Contains(t, string(created.After), `"name":"Anurag"`)
+	assert.Equal(t, domain.AuditActionRead, read.Action)
+	assert.Equal(t, domain.AuditActionDelete, deleted.Action)
+	assert.Contains(t, string(deleted.Before), `"name":"Anurag"`)
+	assert.Nil(t, deleted.After)
+	for _, event := range events {
+		assert.Equal(t, int64(1), event.ActorID)
+		assert.Equal(t, "pmagnaghi", event.Actor)
+		assert.Equal(t, "request-1", event.RequestID)
+		assert.Equal(t, domain.AuditEntitySalary, event.Entity)
+	}
+}
+
+func TestDataBaseClient_DeleteNotFound(t *testing.T) {
+	database := newTestDatabase(t)
+	salaryClient := db.NewSqlite(database)
+	auditClient := db.NewSqliteAuditClient(database)
+
+	_, err := salaryClient.DeleteByID(newTestContext(), 42, 0)
+
+	assert.ErrorIs(t, err, api.ErrNotFound)
+	_, total, err := auditClient.Find(context.Background(), api.AuditFilter{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+}
+
+func TestDataBaseClient_UpdateChecksVersion(t *testing.T) {
+	database := newTestDatabase(t)
+	salaryClient := db.NewSqlite(database)
+	ctx := newTestContext()
+	created, err := salaryClient.Create(ctx, &domain.Salary{Name: "Anurag", Salary: 90000, Currency: "USD", Department: "Banking", SubDepartment: "Loan"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), created.Version)
+
+	raise := *created
+	raise.Salary = 95000
+	before, err := salaryClient.Update(ctx, &raise, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), raise.Version)
+	assert.Equal(t, int64(1), before.Version, "the stored salary before the update is returned")
+	assert.Equal(t, 90000.0, before.Salary)
+
+	stale := *created
+	stale.Salary = 100000
+	_, err = salaryClient.Update(ctx, &stale, 1)
+	assert.ErrorIs(t, err, api.ErrPreconditionFailed)
+	_, err = salaryClient.DeleteByID(ctx, created.ID, 1)
+	assert.ErrorIs(t, err, api.ErrPreconditionFailed)
+	_, err = salaryClient.Update(ctx, &domain.Salary{ID: 42, Name: "Nobody"}, 0)
+	assert.ErrorIs(t, err, api.ErrNotFound)
+
+	read, err := salaryClient.ReadByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 95000.0, read.Salary)
+	assert.Equal(t, int64(2), read.Version)
+
+	_, err = salaryClient.DeleteByID(ctx, created.ID, 2)
+	require.NoError(t, err)
+	restored, err := salaryClient.Restore(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), restored.Version)
+
+	_, total, err := db.NewSqliteAuditClient(database).Find(ctx, api.AuditFilter{Action: domain.AuditActionUpdate, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+}
+
+func TestDataBaseClient_SoftDeleteRestoreAndPurge(t *testing.T) {
+	database := newTestDatabase(t)
+	salaryClient := db.NewSqlite(database)
+	ctx := newTestContext()
+	var ids []int64
+	for _, salary := range []domain.Salary{
+		{Name: "Anurag", Salary: 90000, Currency: "USD", OnContract: true, Department: "Banking", SubDepartment: "Loan"},
+		{Name: "Himani", Salary: 240000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"},
+	} {
+		salary := salary
+		created, err := salaryClient.Create(ctx, &salary)
+		require.NoError(t, err)
+		ids = append(ids, created.ID)
+	}
+
+	deleted, err := salaryClient.DeleteByID(ctx, ids[0], 0)
+	require.NoError(t, err)
+	assert.Equal(t, "Anurag", deleted.Name)
+
+	_, err = salaryClient.ReadByID(ctx, ids[0])
+	assert.ErrorIs(t, err, api.ErrNotFound)
+	_, err = salaryClient.DeleteByID(ctx, ids[0], 0)
+	assert.ErrorIs(t, err, api.ErrNotFound)
+	salaries, err := salaryClient.ReadAll(ctx, api.SalaryFilter{})
+	require.NoError(t, err)
+	require.Len(t, salaries, 1)
+	stats, err := salaryClient.GetStatsForAllSalaries(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.Count)
+	contracts, err := salaryClient.GetContractsStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), contracts.Count)
+	departments, err := salaryClient.GetDepartmentsStats(ctx)
+	require.NoError(t, err)
+	require.Len(t, departments, 1)
+	assert.Equal(t, "Engineering", departments[0].Department)
+	subDepartments, err := salaryClient.GetSubDepartmentsStats(ctx)
+	require.NoError(t, err)
+	assert.Len(t, subDepartments, 1)
+
+	trash, err := salaryClient.ReadTrash(ctx)
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+	assert.Equal(t, "Anurag", trash[0].Name)
+	assert.Equal(t, "pmagnaghi", trash[0].DeletedBy)
+	assert.False(t, trash[0].DeletedAt.IsZero())
+
+	restored, err := salaryClient.Restore(ctx, ids[0])
+	require.NoError(t, err)
+	assert.Equal(t, "Anurag", restored.Name)
+	_, err = salaryClient.Restore(ctx, ids[0])
+	assert.ErrorIs(t, err, api.ErrNotFound)
+	_, err = salaryClient.ReadByID(ctx, ids[0])
+	assert.NoError(t, err)
+
+	_, err = salaryClient.DeleteByID(ctx, ids[1], 0)
+	require.NoError(t, err)
+	purged, err := salaryClient.Purge(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), purged)
+	purged, err = salaryClient.Purge(ctx, time.Now().Add(time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+	trash, err = salaryClient.ReadTrash(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, trash)
+	_, err = salaryClient.Restore(ctx, ids[1])
+	assert.ErrorIs(t, err, api.ErrNotFound)
+
+	auditClient := db.NewSqliteAuditClient(database)
+	for action, want := range map[string]int64{domain.AuditActionRestore: 1, domain.AuditActionPurge: 1} {
+		_, total, err := auditClient.Find(ctx, api.AuditFilter{Action: action, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, want, total, action)
+	}
+}
+
+func TestAuditEvents_AreAppendOnly(t *testing.T) {
+	database := newTestDatabase(t)
+	salaryClient := db.NewSqlite(database)
+	_, err := salaryClient.Create(newTestContext(), &domain.Salary{Name: "Anurag", Salary: 90000, Currency: "USD", Department: "Banking", SubDepartment: "Loan"})
+	require.NoError(t, err)
+
+	_, err = database.Exec("UPDATE audit_events SET actor = 'someone else'")
+	assert.Error(t, err)
+	_, err = database.Exec("DELETE FROM audit_events")
+	assert.Error(t, err)
+}
+
+func TestAuditClient_FindPaginates(t *testing.T) {
+	database := newTestDatabase(t)
+	salaryClient := db.NewSqlite(database)
+	auditClient := db.NewSqliteAuditClient(database)
+	ctx := newTestContext()
+	for i := 0; i < 5; i++ {
+		_, err := salaryClient.Create(ctx, &domain.Salary{Name: "Anurag", Salary: 90000, Currency: "USD", Department: "Banking", SubDepartment: "Loan"})
+		require.NoError(t, err)
+	}
+
+	events, total, err := auditClient.Find(ctx, api.AuditFilter{Action: domain.AuditActionCreate, Limit: 2, Offset: 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), total)
+	assert.Len(t, events, 2)
+	assert.Equal(t, int64(3), events[0].EntityID)
+}
+
+func TestDataBaseClient_Batch(t *testing.T) {
+	database := newTestDatabase(t)
+	salaryClient := db.NewSqlite(database)
+	ctx := newTestContext()
+
+	rolledBack, err := salaryClient.Batch(ctx)
+	require.NoError(t, err)
+	require.NoError(t, rolledBack.Add(ctx, &domain.Salary{Name: "Anurag", Salary: 90000, Currency: "USD", Department: "Banking", SubDepartment: "Loan"}))
+	require.NoError(t, rolledBack.Rollback())
+
+	batch, err := salaryClient.Batch(ctx)
+	require.NoError(t, err)
+	require.NoError(t, batch.Add(ctx, &domain.Salary{Name: "Himani", Salary: 240000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"}))
+	require.NoError(t, batch.Add(ctx, &domain.Salary{Name: "Yatendra", Salary: 30, Currency: "USD", Department: "Operations", SubDepartment: "CustomerOnboarding"}))
+	require.NoError(t, batch.Commit())
+	assert.NoError(t, batch.Rollback())
+
+	salaries, err := salaryClient.ReadAll(ctx, api.SalaryFilter{})
+	require.NoError(t, err)
+	require.Len(t, salaries, 2)
+	assert.Equal(t, "Himani", salaries[0].Name)
+
+	_, total, err := db.NewSqliteAuditClient(database).Find(ctx, api.AuditFilter{Action: domain.AuditActionCreate, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+
+	deletes, err := salaryClient.Batch(ctx)
+	require.NoError(t, err)
+	require.NoError(t, deletes.Delete(ctx, salaries[0].ID))
+	assert.ErrorIs(t, deletes.Delete(ctx, 404), api.ErrNotFound)
+	require.NoError(t, deletes.Commit())
+
+	salaries, err = salaryClient.ReadAll(ctx, api.SalaryFilter{})
+	require.NoError(t, err)
+	require.Len(t, salaries, 1)
+	assert.Equal(t, "Yatendra", salaries[0].Name)
+
+	_, total, err = db.NewSqliteAuditClient(database).Find(ctx, api.AuditFilter{Action: domain.AuditActionDelete, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+}
+
+func TestDataBaseClient_ReadEachFiltersSalaries(t *testing.T) {
+	database := newTestDatabase(t)
+	salaryClient := db.NewSqlite(database)
+	ctx := newTestContext()
+	for _, salary := range []domain.Salary{
+		{Name: "Anurag", Salary: 90000, Currency: "USD", OnContract: true, Department: "Banking", SubDepartment: "Loan"},
+		{Name: "Himani", Salary: 240000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"},
+		{Name: "Raghav", Salary: 70000, Currency: "USD", Department: "Banking", SubDepartment: "Loan"},
+	} {
+		salary := salary
+		_, err := salaryClient.Create(ctx, &salary)
+		require.NoError(t, err)
+	}
+	onContract := false
+
+	var names []string
+	err := salaryClient.ReadEach(ctx, api.SalaryFilter{Department: "Banking", OnContract: &onContract}, func(salary *domain.Salary) error {
+		names = append(names, salary.Name)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Raghav"}, names)
+
+	stop := errors.New("stop")
+	err = salaryClient.ReadEach(ctx, api.SalaryFilter{}, func(salary *domain.Salary) error {
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+}
+
+func TestDataBaseUserClient(t *testing.T) {
+	database := newTestDatabase(t)
+	userClient := db.NewSqliteUserClient(database)
+	ctx := newTestContext()
+
+	admin, err := userClient.ReadByUsername(ctx, "pmagnaghi")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), admin.ID)
+	assert.Equal(t, domain.RoleAdmin, admin.Role)
+
+	user, err := userClient.Create(ctx, &domain.User{Username: "analyst", PasswordHash: "hash", Role: domain.RoleUser})
+	require.NoError(t, err)
+	_, err = userClient.Create(ctx, &domain.User{Username: "analyst", PasswordHash: "hash", Role: domain.RoleUser})
+	assert.ErrorIs(t, err, api.ErrConflict)
+
+	require.NoError(t, userClient.UpdatePasswordHash(ctx, "analyst", "new-hash"))
+	updated, err := userClient.ReadByUsername(ctx, "analyst")
+	require.NoError(t, err)
+	assert.Equal(t, "new-hash", updated.PasswordHash)
+	assert.ErrorIs(t, userClient.UpdatePasswordHash(ctx, "nobody", "hash"), api.ErrNotFound)
+
+	events, _, err := db.NewSqliteAuditClient(database).Find(ctx, api.AuditFilter{Entity: domain.AuditEntityUser, EntityID: user.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	for _, event := range events {
+		assert.NotContains(t, string(event.After), "hash")
+	}
+}
+
+func TestDataBaseIdempotencyClient(t *testing.T) {
+	database := newTestDatabase(t)
+	idempotencyClient := db.NewSqliteIdempotencyClient(database)
+	ctx := newTestContext()
+	expiredBefore := time.Now().Add(-time.Hour)
+
+	stored, err := idempotencyClient.Reserve(ctx, &domain.IdempotencyRecord{Scope: "pmagnaghi", Key: "key-1", RequestHash: "hash"}, expiredBefore)
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+
+	stored, err = idempotencyClient.Reserve(ctx, &domain.IdempotencyRecord{Scope: "pmagnaghi", Key: "key-1", RequestHash: "hash"}, expiredBefore)
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.False(t, stored.Completed())
+
+	other, err := idempotencyClient.Reserve(ctx, &domain.IdempotencyRecord{Scope: "analyst", Key: "key-1", RequestHash: "hash"}, expiredBefore)
+	require.NoError(t, err)
+	assert.Nil(t, other, "keys are scoped by user")
+
+	require.NoError(t, idempotencyClient.Complete(ctx, &domain.IdempotencyRecord{
+		Scope: "pmagnaghi", Key: "key-1", StatusCode: 201,
+		Header: map[string][]string{"Content-Type": {"application/json"}}, Body: []byte(`{"id":1}`),
+	}))
+	stored, err = idempotencyClient.Reserve(ctx, &domain.IdempotencyRecord{Scope: "pmagnaghi", Key: "key-1", RequestHash: "other"}, expiredBefore)
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.True(t, stored.Completed())
+	assert.Equal(t, "hash", stored.RequestHash)
+	assert.Equal(t, 201, stored.StatusCode)
+	assert.Equal(t, "application/json", stored.Header.Get("Content-Type"))
+	assert.Equal(t, `{"id":1}`, string(stored.Body))
+
+	require.NoError(t, idempotencyClient.Release(ctx, "analyst", "key-1"))
+	other, err = idempotencyClient.Reserve(ctx, &domain.IdempotencyRecord{Scope: "analyst", Key: "key-1", RequestHash: "hash"}, expiredBefore)
+	require.NoError(t, err)
+	assert.Nil(t, other, "released keys can be used again")
+
+	require.NoError(t, idempotencyClient.Complete(ctx, &domain.IdempotencyRecord{Scope: "analyst", Key: "key-1", RequestHash: "streamed", StatusCode: 201}))
+	other, err = idempotencyClient.Reserve(ctx, &domain.IdempotencyRecord{Scope: "analyst", Key: "key-1"}, expiredBefore)
+	require.NoError(t, err)
+	require.NotNil(t, other)
+	assert.Equal(t, "streamed", other.RequestHash, "the hash of a streamed body is stored with the response")
+
+	stored, err = idempotencyClient.Reserve(ctx, &domain.IdempotencyRecord{Scope: "pmagnaghi", Key: "key-1", RequestHash: "other"}, time.Now().Add(time.Second))
+	require.NoError(t, err)
+	assert.Nil(t, stored, "expired keys can be used again")
+}
+
+func TestDataBasePayBandClient(t *testing.T) {
+	database := newTestDatabase(t)
+	payBandClient := db.NewSqlitePayBandClient(database)
+	salaryClient := db.NewSqlite(database)
+	ctx := newTestContext()
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	band, err := payBandClient.Create(ctx, &domain.PayBand{Name: "L3", Currency: "USD", Min: 80000, Mid: 100000, Max: 120000, EffectiveFrom: from, EffectiveTo: &to})
+	require.NoError(t, err)
+	_, err = payBandClient.Create(ctx, &domain.PayBand{Name: "L3", Currency: "USD", Min: 85000, Mid: 105000, Max: 125000, EffectiveFrom: from.AddDate(0, 6, 0)})
+	assert.ErrorIs(t, err, api.ErrConflict, "overlapping bands")
+	next, err := payBandClient.Create(ctx, &domain.PayBand{Name: "L3", Currency: "USD", Min: 85000, Mid: 105000, Max: 125000, EffectiveFrom: to})
+	require.NoError(t, err)
+
+	effective, err := payBandClient.ReadAll(ctx, api.PayBandFilter{Name: "L3", AsOf: from.AddDate(0, 6, 0)})
+	require.NoError(t, err)
+	require.Len(t, effective, 1)
+	assert.Equal(t, band.ID, effective[0].ID)
+	assert.True(t, effective[0].EffectiveTo.Equal(to))
+
+	_, err = salaryClient.Create(ctx, &domain.Salary{Name: "Anurag", Salary: 90000, Currency: "USD", PayBand: "L4"})
+	assert.ErrorIs(t, err, api.ErrUnknownPayBand)
+	batch, err := salaryClient.Batch(ctx)
+	require.NoError(t, err)
+	assert.NoError(t, batch.CheckPayBand(ctx, "L3"))
+	assert.NoError(t, batch.CheckPayBand(ctx, ""))
+	assert.ErrorIs(t, batch.CheckPayBand(ctx, "L4"), api.ErrUnknownPayBand)
+	require.NoError(t, batch.Rollback())
+	salary, err := salaryClient.Create(ctx, &domain.Salary{Name: "Anurag", Salary: 90000, Currency: "USD", PayBand: "L3"})
+	require.NoError(t, err)
+	read, err := salaryClient.ReadByID(ctx, salary.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "L3", read.PayBand)
+
+	require.NoError(t, payBandClient.DeleteByID(ctx, band.ID))
+	assert.ErrorIs(t, payBandClient.DeleteByID(ctx, next.ID), api.ErrConflict, "the last L3 band is assigned")
+	next.Name = "L4"
+	_, err = payBandClient.Update(ctx, next)
+	assert.ErrorIs(t, err, api.ErrConflict, "the last L3 band is assigned")
+	assert.ErrorIs(t, payBandClient.DeleteByID(ctx, band.ID), api.ErrNotFound)
+
+	events, _, err := db.NewSqliteAuditClient(database).Find(ctx, api.AuditFilter{Entity: domain.AuditEntityPayBand, Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, events, 3)
+}
+
+func TestDataBaseDemographicsClient(t *testing.T) {
+	database := newTestDatabase(t)
+	demographicsClient := db.NewSqliteDemographicsClient(database)
+	salaryClient := db.NewSqlite(database)
+	ctx := newTestContext()
+
+	assert.ErrorIs(t, demographicsClient.Save(ctx, &domain.Demographics{SalaryID: 404, Gender: "female"}), api.ErrNotFound)
+	first, err := salaryClient.Create(ctx, &domain.Salary{Name: "Anurag", Salary: 90000, Currency: "USD", Department: "Banking"})
+	require.NoError(t, err)
+	second, err := salaryClient.Create(ctx, &domain.Salary{Name: "Himani", Salary: 240000, Currency: "USD", Department: "Engineering"})
+	require.NoError(t, err)
+
+	require.NoError(t, demographicsClient.Save(ctx, &domain.Demographics{SalaryID: first.ID, Gender: "male"}))
+	require.NoError(t, demographicsClient.Save(ctx, &domain.Demographics{SalaryID: first.ID, Gender: "female", Ethnicity: "asian"}))
+	require.NoError(t, demographicsClient.Save(ctx, &domain.Demographics{SalaryID: second.ID, Gender: "female"}))
+	read, err := demographicsClient.Read(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.Demographics{SalaryID: first.ID, Gender: "female", Ethnicity: "asian"}, *read)
+
+	var ids []int64
+	err = demographicsClient.ReadEach(ctx, api.SalaryFilter{Department: "Banking"}, func(salary *domain.Salary, demographics *domain.Demographics) error {
+		assert.Equal(t, salary.ID, demographics.SalaryID)
+		ids = append(ids, salary.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{first.ID}, ids)
+
+	require.NoError(t, demographicsClient.Delete(ctx, second.ID))
+	assert.ErrorIs(t, demographicsClient.Delete(ctx, second.ID), api.ErrNotFound)
+	_, err = demographicsClient.Read(ctx, second.ID)
+	assert.ErrorIs(t, err, api.ErrNotFound)
+
+	_, err = salaryClient.DeleteByID(ctx, first.ID, 0)
+	require.NoError(t, err)
+	_, err = salaryClient.Purge(ctx, time.Now().Add(time.Second))
+	require.NoError(t, err)
+	_, err = demographicsClient.Read(ctx, first.ID)
+	assert.ErrorIs(t, err, api.ErrNotFound, "purged with the salary")
+
+	events, _, err := db.NewSqliteAuditClient(database).Find(ctx, api.AuditFilter{Entity: domain.AuditEntityDemographics, Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, events, 5, "three saves, a read and a delete")
+}
+
+func TestDataBaseBudgetClient(t *testing.T) {
+	database := newTestDatabase(t)
+	budgetClient := db.NewSqliteBudgetClient(database)
+	salaryClient := db.NewSqlite(database)
+	ctx := newTestContext()
+
+	budget, err := budgetClient.CreateBudget(ctx, &domain.Budget{Department: "Engineering", FiscalYear: 2026, Currency: "USD", Amount: 1000000})
+	require.NoError(t, err)
+	_, err = budgetClient.CreateBudget(ctx, &domain.Budget{Department: "Engineering", FiscalYear: 2026, Currency: "EUR", Amount: 900000})
+	assert.ErrorIs(t, err, api.ErrConflict)
+	_, err = budgetClient.CreateBudget(ctx, &domain.Budget{Department: "Engineering", FiscalYear: 2027, Currency: "USD", Amount: 1100000})
+	require.NoError(t, err)
+	budget.Amount = 1200000
+	_, err = budgetClient.UpdateBudget(ctx, budget)
+	require.NoError(t, err)
+	budgets, err := budgetClient.ReadBudgets(ctx, api.BudgetFilter{FiscalYear: 2026})
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Budget{*budget}, budgets)
+
+	salary, err := salaryClient.Create(ctx, &domain.Salary{Name: "Anurag", Salary: 90000, Currency: "USD", Department: "Banking"})
+	require.NoError(t, err)
+	effective := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	_, err = budgetClient.CreateChange(ctx, &domain.PayrollChange{Kind: domain.PayrollChangeRaise, SalaryID: 404, Percentage: 5, EffectiveDate: effective})
+	assert.ErrorIs(t, err, api.ErrUnknownSalary)
+	raise, err := budgetClient.CreateChange(ctx, &domain.PayrollChange{Kind: domain.PayrollChangeRaise, SalaryID: salary.ID, Percentage: 5, EffectiveDate: effective})
+	require.NoError(t, err)
+	_, err = budgetClient.CreateChange(ctx, &domain.PayrollChange{Kind: domain.PayrollChangeHire, Department: "Banking", Salary: 80000, Currency: "USD", EffectiveDate: effective.AddDate(0, -3, 0)})
+	require.NoError(t, err)
+	changes, err := budgetClient.ReadChanges(ctx, api.PayrollChangeFilter{From: effective.AddDate(0, -1, 0)})
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, raise.ID, changes[0].ID)
+	assert.True(t, changes[0].EffectiveDate.Equal(effective))
+
+	assert.ErrorIs(t, budgetClient.DeleteChangeByID(ctx, 404), api.ErrNotFound)
+	_, err = salaryClient.DeleteByID(ctx, salary.ID, 0)
+	require.NoError(t, err)
+	_, err = salaryClient.Purge(ctx, time.Now().Add(time.Second))
+	require.NoError(t, err)
+	changes, err = budgetClient.ReadChanges(ctx, api.PayrollChangeFilter{Kind: domain.PayrollChangeRaise})
+	require.NoError(t, err)
+	assert.Empty(t, changes, "purged with the salary")
+
+	events, _, err := db.NewSqliteAuditClient(database).Find(ctx, api.AuditFilter{Entity: domain.AuditEntityBudget, Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, events, 3)
+}
+
+func TestDataBaseSnapshotClient(t *testing.T) {
+	snapshotClient := db.NewSqliteSnapshotClient(newTestDatabase(t))
+	ctx := newTestContext()
+
+	_, err := snapshotClient.ReadLatestSnapshot(ctx)
+	assert.ErrorIs(t, err, api.ErrNotFound)
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, mean := range []float64{100, 110, 120} {
+		_, err := snapshotClient.CreateSnapshot(ctx, &domain.StatsSnapshot{
+			TakenAt: start.AddDate(0, i, 0),
+			Source:  domain.SnapshotSourceScheduled,
+			Stats: []domain.SnapshotStats{
+				{Group: domain.StatsGroupAll, Mean: mean, Max: mean * 2, Min: mean / 2, Count: 4},
+				{Group: domain.StatsGroupDepartment, Department: "Banking", Mean: mean - 10, Count: 2},
+				{Group: domain.StatsGroupDepartment, Department: "Engineering", Mean: mean + 10, Count: 2},
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	latest, err := snapshotClient.ReadLatestSnapshot(ctx)
+	require.NoError(t, err)
+	assert.True(t, latest.TakenAt.Equal(start.AddDate(0, 2, 0)))
+	assert.Empty(t, latest.Stats)
+
+	snapshots, err := snapshotClient.ReadSnapshots(ctx, api.SnapshotFilter{From: start.AddDate(0, 1, 0)})
+	require.NoError(t, err)
+	assert.Len(t, snapshots, 2)
+
+	snapshot, err := snapshotClient.ReadSnapshot(ctx, latest.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.SnapshotSourceScheduled, snapshot.Source)
+	require.Len(t, snapshot.Stats, 3)
+	assert.Equal(t, domain.SnapshotStats{Group: domain.StatsGroupAll, Mean: 120, Max: 240, Min: 60, Count: 4}, snapshot.Stats[0])
+	_, err = snapshotClient.ReadSnapshot(ctx, 404)
+	assert.ErrorIs(t, err, api.ErrNotFound)
+
+	history, err := snapshotClient.ReadHistory(ctx, api.StatsHistoryQuery{
+		SnapshotFilter: api.SnapshotFilter{To: start.AddDate(0, 2, 0)},
+		Group:          domain.StatsGroupDepartment,
+		Department:     "Engineering",
+	})
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, []domain.SnapshotStats{{Group: domain.StatsGroupDepartment, Department: "Engineering", Mean: 120, Count: 2}}, history[1].Stats)
+
+	history, err = snapshotClient.ReadHistory(ctx, api.StatsHistoryQuery{Group: domain.StatsGroupSubDepartment})
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
@@ -0,0 +1,146 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"salaries/pkg/api"
+	"salaries/pkg/domain"
+	"salaries/pkg/tracing"
+)
+
+const demographicsTable = "salary_demographics"
+
+type DataBaseDemographicsClient interface {
+	Read(ctx context.Context, salaryID int64) (*domain.Demographics, error)
+	Save(ctx context.Context, demographics *domain.Demographics) error
+	Delete(ctx context.Context, salaryID int64) error
+	ReadEach(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary, demographics *domain.Demographics) error) error
+}
+
+func NewSqliteDemographicsClient(client *sql.DB) DataBaseDemographicsClient {
+	return &dataBaseDemographicsClientImpl{
+		client: client,
+	}
+}
+
+type dataBaseDemographicsClientImpl struct {
+	client *sql.DB
+}
+
+// Read returns the demographics of the salary, every read is audited
+func (d dataBaseDemographicsClientImpl) Read(ctx context.Context, salaryID int64) (*domain.Demographics, error) {
+	ctx, span := startSpan(ctx, demographicsTable, "select_salary_demographics", "SELECT")
+	defer span.End()
+
+	tx, err := d.client.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	defer tx.Rollback()
+
+	demographics := domain.Demographics{SalaryID: salaryID}
+	err = tx.QueryRowContext(ctx, "SELECT gender, ethnicity FROM salary_demographics JOIN salaries ON salaries.id = salary_demographics.salary_id WHERE salary_id = ? AND "+notDeleted, salaryID).
+		Scan(&demographics.Gender, &demographics.Ethnicity)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, tracing.RecordError(span, api.ErrNotFound)
+	}
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	if err := insertAuditEvent(ctx, tx, domain.AuditActionRead, domain.AuditEntityDemographics, salaryID, nil, nil); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	return &demographics, nil
+}
+
+// Save creates or replaces the demographics of a salary that is not deleted, the audit event has no snapshots
+func (d dataBaseDemographicsClientImpl) Save(ctx context.Context, demographics *domain.Demographics) error {
+	ctx, span := startSpan(ctx, demographicsTable, "upsert_salary_demographics", "INSERT")
+	defer span.End()
+
+	tx, err := d.client.BeginTx(ctx, nil)
+	if err != nil {
+		return tracing.RecordError(span, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := selectSalary(ctx, tx, demographics.SalaryID); err != nil {
+		return tracing.RecordError(span, err)
+	}
+	_, err = tx.ExecContext(ctx, "INSERT INTO salary_demographics (salary_id, gender, ethnicity) VALUES (?, ?, ?) ON CONFLICT (salary_id) DO UPDATE SET gender = excluded.gender, ethnicity = excluded.ethnicity",
+		demographics.SalaryID, demographics.Gender, demographics.Ethnicity)
+	if err != nil {
+		return tracing.RecordError(span, err)
+	}
+	if err := insertAuditEvent(ctx, tx, domain.AuditActionUpdate, domain.AuditEntityDemographics, demographics.SalaryID, nil, nil); err != nil {
+		return tracing.RecordError(span, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return tracing.RecordError(span, err)
+	}
+	return nil
+}
+
+func (d dataBaseDemographicsClientImpl) Delete(ctx context.Context, salaryID int64) error {
+	ctx, span := startSpan(ctx, demographicsTable, "delete_salary_demographics", "DELETE")
+	defer span.End()
+
+	tx, err := d.client.BeginTx(ctx, nil)
+	if err != nil {
+		return tracing.RecordError(span, err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM salary_demographics WHERE salary_id = ?", salaryID)
+	if err != nil {
+		return tracing.RecordError(span, err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return tracing.RecordError(span, err)
+	}
+	if deleted == 0 {
+		return tracing.RecordError(span, api.ErrNotFound)
+	}
+	if err := insertAuditEvent(ctx, tx, domain.AuditActionDelete, domain.AuditEntityDemographics, salaryID, nil, nil); err != nil {
+		return tracing.RecordError(span, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return tracing.RecordError(span, err)
+	}
+	return nil
+}
+
+// ReadEach calls each for every salary matching the filter that has demographics
+func (d dataBaseDemographicsClientImpl) ReadEach(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary, demographics *domain.Demographics) error) error {
+	ctx, span := startSpan(ctx, demographicsTable, "select_salaries_demographics", "SELECT")
+	defer span.End()
+
+	where, args := salaryFilterClause(filter)
+	rows, err := d.client.QueryContext(ctx, "SELECT "+salaryColumns+", gender, ethnicity FROM salaries JOIN salary_demographics ON salary_demographics.salary_id = salaries.id"+where+" ORDER BY id", args...)
+	if err != nil {
+		return tracing.RecordError(span, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var salary domain.Salary
+		var demographics domain.Demographics
+		err := rows.Scan(&salary.ID, &salary.Name, &salary.Salary, &salary.Currency, &salary.OnContract, &salary.Department, &salary.SubDepartment, &salary.PayBand, &salary.Version,
+			&demographics.Gender, &demographics.Ethnicity)
+		if err != nil {
+			return tracing.RecordError(span, err)
+		}
+		demographics.SalaryID = salary.ID
+		if err := each(&salary, &demographics); err != nil {
+			return tracing.RecordError(span, err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return tracing.RecordError(span, err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"salaries/pkg/domain"
+	"salaries/pkg/tracing"
+	"time"
+)
+
+const idempotencyTable = "idempotency_keys"
+
+type DataBaseIdempotencyClient interface {
+	Reserve(ctx context.Context, record *domain.IdempotencyRecord, expiredBefore time.Time) (*domain.IdempotencyRecord, error)
+	Complete(ctx context.Context, record *domain.IdempotencyRecord) error
+	Release(ctx context.Context, scope, key string) error
+}
+
+func NewSqliteIdempotencyClient(client *sql.DB) DataBaseIdempotencyClient {
+	return &dataBaseIdempotencyClientImpl{
+		client: client,
+	}
+}
+
+type dataBaseIdempotencyClientImpl struct {
+	client *sql.DB
+}
+
+// Reserve stores the key as in progress and returns nil, or returns the record already stored for the key,
+// records created before expiredBefore are removed first so their keys can be used again
+func (d dataBaseIdempotencyClientImpl) Reserve(ctx context.Context, record *domain.IdempotencyRecord, expiredBefore time.Time) (*domain.IdempotencyRecord, error) {
+	ctx, span := startSpan(ctx, idempotencyTable, "reserve_idempotency_key", "INSERT")
+	defer span.End()
+
+	tx, err := d.client.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE created_at < ?", expiredBefore.UTC()); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	record.CreatedAt = time.Now().UTC()
+	result, err := tx.ExecContext(ctx, "INSERT OR IGNORE INTO idempotency_keys (scope, idempotency_key, request_hash, created_at) VALUES (?, ?, ?, ?)",
+		record.Scope, record.Key, record.RequestHash, record.CreatedAt)
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+
+	var stored *domain.IdempotencyRecord
+	if inserted == 0 {
+		if stored, err = selectIdempotencyRecord(ctx, tx, record.Scope, record.Key); err != nil {
+			return nil, tracing.RecordError(span, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	return stored, nil
+}
+
+// Complete stores the response of a reserved key, a request hash set on the record replaces the reserved one for the
+// requests hashed while their body is streamed
+func (d dataBaseIdempotencyClientImpl) Complete(ctx context.Context, record *domain.IdempotencyRecord) error {
+	ctx, span := startSpan(ctx, idempotencyTable, "complete_idempotency_key", "UPDATE")
+	defer span.End()
+
+	headers, err := json.Marshal(record.Header)
+	if err != nil {
+		return tracing.RecordError(span, err)
+	}
+	_, err = d.client.ExecContext(ctx, "UPDATE idempotency_keys SET request_hash = COALESCE(NULLIF(?, ''), request_hash), status_code = ?, headers = ?, body = ? WHERE scope = ? AND idempotency_key = ?",
+		record.RequestHash, record.StatusCode, string(headers), record.Body, record.Scope, record.Key)
+	if err != nil {
+		return tracing.RecordError(span, err)
+	}
+	return nil
+}
+
+// Release forgets a reserved key so the request can be retried, it is used when the request failed
+func (d dataBaseIdempotencyClientImpl) Release(ctx context.Context, scope, key string) error {
+	ctx, span := startSpan(ctx, idempotencyTable, "release_idempotency_key", "DELETE")
+	defer span.End()
+
+	if _, err := d.client.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE scope = ? AND idempotency_key = ?", scope, key); err != nil {
+		return tracing.RecordError(span, err)
+	}
+	return nil
+}
+
+func selectIdempotencyRecord(ctx context.Context, tx *sql.Tx, scope, key string) (*domain.IdempotencyRecord, error) {
+	record := domain.IdempotencyRecord{Scope: scope, Key: key}
+	var headers sql.NullString
+	err := tx.QueryRowContext(ctx, "SELECT request_hash, status_code, headers, body, created_at FROM idempotency_keys WHERE scope = ? AND idempotency_key = ?", scope, key).
+		Scan(&record.RequestHash, &record.StatusCode, &headers, &record.Body, &record.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if headers.Valid {
+		if err := json.Unmarshal([]byte(headers.String), &record.Header); err != nil {
+			return nil, err
+		}
+	}
+	return &record, nil
+}
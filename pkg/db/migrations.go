@@ -0,0 +1,163 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type Migration struct {
+	Version    int
+	Name       string
+	Statements []string
+}
+
+// migrations are applied in order and never edited once released, new schema changes are appended
+var migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "create_salaries",
+		Statements: []string{
+			"CREATE TABLE IF NOT EXISTS salaries (id INTEGER PRIMARY KEY, name VARCHAR(256), salary REAL, currency VARCHAR(64), on_contract INTEGER NULL, department VARCHAR(256), sub_department VARCHAR(256))",
+		},
+	},
+	{
+		Version: 2,
+		Name:    "create_audit_events",
+		Statements: []string{
+			"CREATE TABLE IF NOT EXISTS audit_events (id INTEGER PRIMARY KEY, occurred_at TIMESTAMP NOT NULL, actor_id INTEGER, actor VARCHAR(256), action VARCHAR(64) NOT NULL, entity VARCHAR(64) NOT NULL, entity_id INTEGER, request_id VARCHAR(128), before_snapshot TEXT, after_snapshot TEXT)",
+			"CREATE INDEX IF NOT EXISTS audit_events_entity ON audit_events (entity, entity_id)",
+			"CREATE INDEX IF NOT EXISTS audit_events_occurred_at ON audit_events (occurred_at)",
+			"CREATE TRIGGER IF NOT EXISTS audit_events_no_update BEFORE UPDATE ON audit_events BEGIN SELECT RAISE(ABORT, 'audit events are append-only'); END",
+			"CREATE TRIGGER IF NOT EXISTS audit_events_no_delete BEFORE DELETE ON audit_events BEGIN SELECT RAISE(ABORT, 'audit events are append-only'); END",
+		},
+	},
+	{
+		Version: 3,
+		Name:    "create_users",
+		Statements: []string{
+			"CREATE TABLE IF NOT EXISTS users (id INTEGER PRIMARY KEY, username VARCHAR(256) NOT NULL UNIQUE, password_hash VARCHAR(256) NOT NULL, role VARCHAR(64) NOT NULL, created_at TIMESTAMP NOT NULL, updated_at TIMESTAMP NOT NULL)",
+			// the documented demo admin (pmagnaghi / 123456), change its password with salariesctl user reset-password
+			"INSERT OR IGNORE INTO users (id, username, password_hash, role, created_at, updated_at) VALUES (1, 'pmagnaghi', '$2a$10$oR4H944k58h2rAzvU6rY/.1h9V24E1Vt9SgEyce6qgXZn0WWJvkp.', 'admin', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)",
+		},
+	},
+	{
+		Version: 4,
+		Name:    "soft_delete_salaries",
+		Statements: []string{
+			"ALTER TABLE salaries ADD COLUMN deleted_at TIMESTAMP NULL",
+			"ALTER TABLE salaries ADD COLUMN deleted_by VARCHAR(256) NULL",
+			"CREATE INDEX IF NOT EXISTS salaries_deleted_at ON salaries (deleted_at)",
+		},
+	},
+	{
+		Version: 5,
+		Name:    "version_salaries",
+		Statements: []string{
+			"ALTER TABLE salaries ADD COLUMN version INTEGER NOT NULL DEFAULT 1",
+		},
+	},
+	{
+		Version: 6,
+		Name:    "create_idempotency_keys",
+		Statements: []string{
+			"CREATE TABLE IF NOT EXISTS idempotency_keys (scope VARCHAR(256) NOT NULL, idempotency_key VARCHAR(256) NOT NULL, request_hash VARCHAR(64) NOT NULL, status_code INTEGER NOT NULL DEFAULT 0, headers TEXT, body BLOB, created_at TIMESTAMP NOT NULL, PRIMARY KEY (scope, idempotency_key))",
+			"CREATE INDEX IF NOT EXISTS idempotency_keys_created_at ON idempotency_keys (created_at)",
+		},
+	},
+	{
+		Version: 7,
+		Name:    "create_pay_bands",
+		Statements: []string{
+			"CREATE TABLE IF NOT EXISTS pay_bands (id INTEGER PRIMARY KEY, name VARCHAR(256) NOT NULL, currency VARCHAR(64) NOT NULL, min REAL NOT NULL, mid REAL NOT NULL, max REAL NOT NULL, effective_from TIMESTAMP NOT NULL, effective_to TIMESTAMP NULL)",
+			"CREATE INDEX IF NOT EXISTS pay_bands_name ON pay_bands (name)",
+			"ALTER TABLE salaries ADD COLUMN pay_band VARCHAR(256) NOT NULL DEFAULT ''",
+		},
+	},
+	{
+		Version: 8,
+		Name:    "create_salary_demographics",
+		Statements: []string{
+			"CREATE TABLE IF NOT EXISTS salary_demographics (salary_id INTEGER PRIMARY KEY, gender VARCHAR(64) NOT NULL DEFAULT '', ethnicity VARCHAR(64) NOT NULL DEFAULT '')",
+		},
+	},
+	{
+		Version: 9,
+		Name:    "create_budgets",
+		Statements: []string{
+			"CREATE TABLE IF NOT EXISTS budgets (id INTEGER PRIMARY KEY, department VARCHAR(256) NOT NULL, fiscal_year INTEGER NOT NULL, currency VARCHAR(64) NOT NULL, amount REAL NOT NULL, UNIQUE (department, fiscal_year))",
+			"CREATE TABLE IF NOT EXISTS payroll_changes (id INTEGER PRIMARY KEY, kind VARCHAR(64) NOT NULL, salary_id INTEGER NOT NULL DEFAULT 0, department VARCHAR(256) NOT NULL DEFAULT '', sub_department VARCHAR(256) NOT NULL DEFAULT '', salary REAL NOT NULL DEFAULT 0, currency VARCHAR(64) NOT NULL DEFAULT '', percentage REAL NOT NULL DEFAULT 0, amount REAL NOT NULL DEFAULT 0, effective_date TIMESTAMP NOT NULL)",
+			"CREATE INDEX IF NOT EXISTS payroll_changes_effective_date ON payroll_changes (effective_date)",
+		},
+	},
+	{
+		Version: 10,
+		Name:    "create_stats_snapshots",
+		Statements: []string{
+			"CREATE TABLE IF NOT EXISTS stats_snapshots (id INTEGER PRIMARY KEY, taken_at TIMESTAMP NOT NULL, source VARCHAR(64) NOT NULL)",
+			"CREATE INDEX IF NOT EXISTS stats_snapshots_taken_at ON stats_snapshots (taken_at)",
+			"CREATE TABLE IF NOT EXISTS snapshot_stats (snapshot_id INTEGER NOT NULL REFERENCES stats_snapshots (id) ON DELETE CASCADE, stats_group VARCHAR(64) NOT NULL, department VARCHAR(256) NOT NULL DEFAULT '', sub_department VARCHAR(256) NOT NULL DEFAULT '', mean REAL NOT NULL, max REAL NOT NULL, min REAL NOT NULL, count INTEGER NOT NULL)",
+			"CREATE INDEX IF NOT EXISTS snapshot_stats_group ON snapshot_stats (stats_group, snapshot_id)",
+		},
+	},
+}
+
+// Migrate applies the pending migrations, each one in its own transaction
+func Migrate(ctx context.Context, client *sql.DB) error {
+	_, err := client.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY, name VARCHAR(256) NOT NULL, applied_at TIMESTAMP NOT NULL)")
+	if err != nil {
+		return err
+	}
+	current, err := SchemaVersion(ctx, client)
+	if err != nil {
+		return err
+	}
+	for _, migration := range migrations {
+		if migration.Version <= current {
+			continue
+		}
+		if err := applyMigration(ctx, client, migration); err != nil {
+			return fmt.Errorf("migration %d %s: %w", migration.Version, migration.Name, err)
+		}
+	}
+	return nil
+}
+
+// LatestSchemaVersion returns the version a fully migrated database is at
+func LatestSchemaVersion() int {
+	return migrations[len(migrations)-1].Version
+}
+
+// SchemaVersion returns the last applied migration, 0 for an empty database or one created before versioned migrations
+func SchemaVersion(ctx context.Context, client *sql.DB) (int, error) {
+	var tables int
+	err := client.QueryRowContext(ctx, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'").Scan(&tables)
+	if err != nil || tables == 0 {
+		return 0, err
+	}
+	var version sql.NullInt64
+	err = client.QueryRowContext(ctx, "SELECT MAX(version) FROM schema_migrations").Scan(&version)
+	if err != nil {
+		return 0, err
+	}
+	return int(version.Int64), nil
+}
+
+func applyMigration(ctx context.Context, client *sql.DB, migration Migration) error {
+	tx, err := client.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, statement := range migration.Statements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	_, err = tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)", migration.Version, migration.Name, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
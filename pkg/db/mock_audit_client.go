@@ -0,0 +1,83 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package db
+
+import (
+	"context"
+	"salaries/pkg/api"
+	"salaries/pkg/domain"
+	"sync"
+)
+
+// Ensure, that DataBaseAuditClientMock does implement DataBaseAuditClient.
+// If this is not the case, regenerate this file with moq.
+var _ DataBaseAuditClient = &DataBaseAuditClientMock{}
+
+// DataBaseAuditClientMock is a mock implementation of DataBaseAuditClient.
+//
+//	func TestSomethingThatUsesDataBaseAuditClient(t *testing.T) {
+//
+//		// make and configure a mocked DataBaseAuditClient
+//		mockedDataBaseAuditClient := &DataBaseAuditClientMock{
+//			FindFunc: func(ctx context.Context, filter api.AuditFilter) ([]domain.AuditEvent, int64, error) {
+//				panic("mock out the Find method")
+//			},
+//		}
+//
+//		// use mockedDataBaseAuditClient in code that requires DataBaseAuditClient
+//		// and then make assertions.
+//
+//	}
+type DataBaseAuditClientMock struct {
+	// FindFunc mocks the Find method.
+	FindFunc func(ctx context.Context, filter api.AuditFilter) ([]domain.AuditEvent, int64, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Find holds details about calls to the Find method.
+		Find []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Filter is the filter argument value.
+			Filter api.AuditFilter
+		}
+	}
+	lockFind sync.RWMutex
+}
+
+// Find calls FindFunc.
+func (mock *DataBaseAuditClientMock) Find(ctx context.Context, filter api.AuditFilter) ([]domain.AuditEvent, int64, error) {
+	if mock.FindFunc == nil {
+		panic("DataBaseAuditClientMock.FindFunc: method is nil but DataBaseAuditClient.Find was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Filter api.AuditFilter
+	}{
+		Ctx:    ctx,
+		Filter: filter,
+	}
+	mock.lockFind.Lock()
+	mock.calls.Find = append(mock.calls.Find, callInfo)
+	mock.lockFind.Unlock()
+	return mock.FindFunc(ctx, filter)
+}
+
+// FindCalls gets all the calls that were made to Find.
+// Check the length with:
+//
+//	len(mockedDataBaseAuditClient.FindCalls())
+func (mock *DataBaseAuditClientMock) FindCalls() []struct {
+	Ctx    context.Context
+	Filter api.AuditFilter
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Filter api.AuditFilter
+	}
+	mock.lockFind.RLock()
+	calls = mock.calls.Find
+	mock.lockFind.RUnlock()
+	return calls
+}
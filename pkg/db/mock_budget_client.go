@@ -0,0 +1,433 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package db
+
+import (
+	"context"
+	"salaries/pkg/api"
+	"salaries/pkg/domain"
+	"sync"
+)
+
+// Ensure, that DataBaseBudgetClientMock does implement DataBaseBudgetClient.
+// If this is not the case, regenerate this file with moq.
+var _ DataBaseBudgetClient = &DataBaseBudgetClientMock{}
+
+// DataBaseBudgetClientMock is a mock implementation of DataBaseBudgetClient.
+//
+//	func TestSomethingThatUsesDataBaseBudgetClient(t *testing.T) {
+//
+//		// make and configure a mocked DataBaseBudgetClient
+//		mockedDataBaseBudgetClient := &DataBaseBudgetClientMock{
+//			CreateBudgetFunc: func(ctx context.Context, budget *domain.Budget) (*domain.Budget, error) {
+//				panic("mock out the CreateBudget method")
+//			},
+//			CreateChangeFunc: func(ctx context.Context, change *domain.PayrollChange) (*domain.PayrollChange, error) {
+//				panic("mock out the CreateChange method")
+//			},
+//			DeleteBudgetByIDFunc: func(ctx context.Context, budgetID int64) error {
+//				panic("mock out the DeleteBudgetByID method")
+//			},
+//			DeleteChangeByIDFunc: func(ctx context.Context, changeID int64) error {
+//				panic("mock out the DeleteChangeByID method")
+//			},
+//			ReadBudgetsFunc: func(ctx context.Context, filter api.BudgetFilter) ([]domain.Budget, error) {
+//				panic("mock out the ReadBudgets method")
+//			},
+//			ReadChangesFunc: func(ctx context.Context, filter api.PayrollChangeFilter) ([]domain.PayrollChange, error) {
+//				panic("mock out the ReadChanges method")
+//			},
+//			UpdateBudgetFunc: func(ctx context.Context, budget *domain.Budget) (*domain.Budget, error) {
+//				panic("mock out the UpdateBudget method")
+//			},
+//			UpdateChangeFunc: func(ctx context.Context, change *domain.PayrollChange) (*domain.PayrollChange, error) {
+//				panic("mock out the UpdateChange method")
+//			},
+//		}
+//
+//		// use mockedDataBaseBudgetClient in code that requires DataBaseBudgetClient
+//		// and then make assertions.
+//
+//	}
+type DataBaseBudgetClientMock struct {
+	// CreateBudgetFunc mocks the CreateBudget method.
+	CreateBudgetFunc func(ctx context.Context, budget *domain.Budget) (*domain.Budget, error)
+
+	// CreateChangeFunc mocks the CreateChange method.
+	CreateChangeFunc func(ctx context.Context, change *domain.PayrollChange) (*domain.PayrollChange, error)
+
+	// DeleteBudgetByIDFunc mocks the DeleteBudgetByID method.
+	DeleteBudgetByIDFunc func(ctx context.Context, budgetID int64) error
+
+	// DeleteChangeByIDFunc mocks the DeleteChangeByID method.
+	DeleteChangeByIDFunc func(ctx context.Context, changeID int64) error
+
+	// ReadBudgetsFunc mocks the ReadBudgets method.
+	ReadBudgetsFunc func(ctx context.Context, filter api.BudgetFilter) ([]domain.Budget, error)
+
+	// ReadChangesFunc mocks the ReadChanges method.
+	ReadChangesFunc func(ctx context.Context, filter api.PayrollChangeFilter) ([]domain.PayrollChange, error)
+
+	// UpdateBudgetFunc mocks the UpdateBudget method.
+	UpdateBudgetFunc func(ctx context.Context, budget *domain.Budget) (*domain.Budget, error)
+
+	// UpdateChangeFunc mocks the UpdateChange method.
+	UpdateChangeFunc func(ctx context.Context, change *domain.PayrollChange) (*domain.PayrollChange, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// CreateBudget holds details about calls to the CreateBudget method.
+		CreateBudget []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Budget is the budget argument value.
+			Budget *domain.Budget
+		}
+		// CreateChange holds details about calls to the CreateChange method.
+		CreateChange []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Change is the change argument value.
+			Change *domain.PayrollChange
+		}
+		// DeleteBudgetByID holds details about calls to the DeleteBudgetByID method.
+		DeleteBudgetByID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// BudgetID is the budgetID argument value.
+			BudgetID int64
+		}
+		// DeleteChangeByID holds details about calls to the DeleteChangeByID method.
+		DeleteChangeByID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ChangeID is the changeID argument value.
+			ChangeID int64
+		}
+		// ReadBudgets holds details about calls to the ReadBudgets method.
+		ReadBudgets []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Filter is the filter argument value.
+			Filter api.BudgetFilter
+		}
+		// ReadChanges holds details about calls to the ReadChanges method.
+		ReadChanges []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Filter is the filter argument value.
+			Filter api.PayrollChangeFilter
+		}
+		// UpdateBudget holds details about calls to the UpdateBudget method.
+		UpdateBudget []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Budget is the budget argument value.
+			Budget *domain.Budget
+		}
+		// UpdateChange holds details about calls to the UpdateChange method.
+		UpdateChange []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Change is the change argument value.
+			Change *domain.PayrollChange
+		}
+	}
+	lockCreateBudget     sync.RWMutex
+	lockCreateChange     sync.RWMutex
+	lockDeleteBudgetByID sync.RWMutex
+	lockDeleteChangeByID sync.RWMutex
+	lockReadBudgets      sync.RWMutex
+	lockReadChanges      sync.RWMutex
+	lockUpdateBudget     sync.RWMutex
+	lockUpdateChange     sync.RWMutex
+}
+
+// CreateBudget calls CreateBudgetFunc.
+func (mock *DataBaseBudgetClientMock) CreateBudget(ctx context.Context, budget *domain.Budget) (*domain.Budget, error) {
+	if mock.CreateBudgetFunc == nil {
+		panic("DataBaseBudgetClientMock.CreateBudgetFunc: method is nil but DataBaseBudgetClient.CreateBudget was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Budget *domain.Budget
+	}{
+		Ctx:    ctx,
+		Budget: budget,
+	}
+	mock.lockCreateBudget.Lock()
+	mock.calls.CreateBudget = append(mock.calls.CreateBudget, callInfo)
+	mock.lockCreateBudget.Unlock()
+	return mock.CreateBudgetFunc(ctx, budget)
+}
+
+// CreateBudgetCalls gets all the calls that were made to CreateBudget.
+// Check the length with:
+//
+//	len(mockedDataBaseBudgetClient.CreateBudgetCalls())
+func (mock *DataBaseBudgetClientMock) CreateBudgetCalls() []struct {
+	Ctx    context.Context
+	Budget *domain.Budget
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Budget *domain.Budget
+	}
+	mock.lockCreateBudget.RLock()
+	calls = mock.calls.CreateBudget
+	mock.lockCreateBudget.RUnlock()
+	return calls
+}
+
+// CreateChange calls CreateChangeFunc.
+func (mock *DataBaseBudgetClientMock) CreateChange(ctx context.Context, change *domain.PayrollChange) (*domain.PayrollChange, error) {
+	if mock.CreateChangeFunc == nil {
+		panic("DataBaseBudgetClientMock.CreateChangeFunc: method is nil but DataBaseBudgetClient.CreateChange was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Change *domain.PayrollChange
+	}{
+		Ctx:    ctx,
+		Change: change,
+	}
+	mock.lockCreateChange.Lock()
+	mock.calls.CreateChange = append(mock.calls.CreateChange, callInfo)
+	mock.lockCreateChange.Unlock()
+	return mock.CreateChangeFunc(ctx, change)
+}
+
+// CreateChangeCalls gets all the calls that were made to CreateChange.
+// Check the length with:
+//
+//	len(mockedDataBaseBudgetClient.CreateChangeCalls())
+func (mock *DataBaseBudgetClientMock) CreateChangeCalls() []struct {
+	Ctx    context.Context
+	Change *domain.PayrollChange
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Change *domain.PayrollChange
+	}
+	mock.lockCreateChange.RLock()
+	calls = mock.calls.CreateChange
+	mock.lockCreateChange.RUnlock()
+	return calls
+}
+
+// DeleteBudgetByID calls DeleteBudgetByIDFunc.
+func (mock *DataBaseBudgetClientMock) DeleteBudgetByID(ctx context.Context, budgetID int64) error {
+	if mock.DeleteBudgetByIDFunc == nil {
+		panic("DataBaseBudgetClientMock.DeleteBudgetByIDFunc: method is nil but DataBaseBudgetClient.DeleteBudgetByID was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		BudgetID int64
+	}{
+		Ctx:      ctx,
+		BudgetID: budgetID,
+	}
+	mock.lockDeleteBudgetByID.Lock()
+	mock.calls.DeleteBudgetByID = append(mock.calls.DeleteBudgetByID, callInfo)
+	mock.lockDeleteBudgetByID.Unlock()
+	return mock.DeleteBudgetByIDFunc(ctx, budgetID)
+}
+
+// DeleteBudgetByIDCalls gets all the calls that were made to DeleteBudgetByID.
+// Check the length with:
+//
+//	len(mockedDataBaseBudgetClient.DeleteBudgetByIDCalls())
+func (mock *DataBaseBudgetClientMock) DeleteBudgetByIDCalls() []struct {
+	Ctx      context.Context
+	BudgetID int64
+} {
+	var calls []struct {
+		Ctx      context.Context
+		BudgetID int64
+	}
+	mock.lockDeleteBudgetByID.RLock()
+	calls = mock.calls.DeleteBudgetByID
+	mock.lockDeleteBudgetByID.RUnlock()
+	return calls
+}
+
+// DeleteChangeByID calls DeleteChangeByIDFunc.
+func (mock *DataBaseBudgetClientMock) DeleteChangeByID(ctx context.Context, changeID int64) error {
+	if mock.DeleteChangeByIDFunc == nil {
+		panic("DataBaseBudgetClientMock.DeleteChangeByIDFunc: method is nil but DataBaseBudgetClient.DeleteChangeByID was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		ChangeID int64
+	}{
+		Ctx:      ctx,
+		ChangeID: changeID,
+	}
+	mock.lockDeleteChangeByID.Lock()
+	mock.calls.DeleteChangeByID = append(mock.calls.DeleteChangeByID, callInfo)
+	mock.lockDeleteChangeByID.Unlock()
+	return mock.DeleteChangeByIDFunc(ctx, changeID)
+}
+
+// DeleteChangeByIDCalls gets all the calls that were made to DeleteChangeByID.
+// Check the length with:
+//
+//	len(mockedDataBaseBudgetClient.DeleteChangeByIDCalls())
+func (mock *DataBaseBudgetClientMock) DeleteChangeByIDCalls() []struct {
+	Ctx      context.Context
+	ChangeID int64
+} {
+	var calls []struct {
+		Ctx      context.Context
+		ChangeID int64
+	}
+	mock.lockDeleteChangeByID.RLock()
+	calls = mock.calls.DeleteChangeByID
+	mock.lockDeleteChangeByID.RUnlock()
+	return calls
+}
+
+// ReadBudgets calls ReadBudgetsFunc.
+func (mock *DataBaseBudgetClientMock) ReadBudgets(ctx context.Context, filter api.BudgetFilter) ([]domain.Budget, error) {
+	if mock.ReadBudgetsFunc == nil {
+		panic("DataBaseBudgetClientMock.ReadBudgetsFunc: method is nil but DataBaseBudgetClient.ReadBudgets was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Filter api.BudgetFilter
+	}{
+		Ctx:    ctx,
+		Filter: filter,
+	}
+	mock.lockReadBudgets.Lock()
+	mock.calls.ReadBudgets = append(mock.calls.ReadBudgets, callInfo)
+	mock.lockReadBudgets.Unlock()
+	return mock.ReadBudgetsFunc(ctx, filter)
+}
+
+// ReadBudgetsCalls gets all the calls that were made to ReadBudgets.
+// Check the length with:
+//
+//	len(mockedDataBaseBudgetClient.ReadBudgetsCalls())
+func (mock *DataBaseBudgetClientMock) ReadBudgetsCalls() []struct {
+	Ctx    context.Context
+	Filter api.BudgetFilter
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Filter api.BudgetFilter
+	}
+	mock.lockReadBudgets.RLock()
+	calls = mock.calls.ReadBudgets
+	mock.lockReadBudgets.RUnlock()
+	return calls
+}
+
+// ReadChanges calls ReadChangesFunc.
+func (mock *DataBaseBudgetClientMock) ReadChanges(ctx context.Context, filter api.PayrollChangeFilter) ([]domain.PayrollChange, error) {
+	if mock.ReadChangesFunc == nil {
+		panic("DataBaseBudgetClientMock.ReadChangesFunc: method is nil but DataBaseBudgetClient.ReadChanges was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Filter api.PayrollChangeFilter
+	}{
+		Ctx:    ctx,
+		Filter: filter,
+	}
+	mock.lockReadChanges.Lock()
+	mock.calls.ReadChanges = append(mock.calls.ReadChanges, callInfo)
+	mock.lockReadChanges.Unlock()
+	return mock.ReadChangesFunc(ctx, filter)
+}
+
+// ReadChangesCalls gets all the calls that were made to ReadChanges.
+// Check the length with:
+//
+//	len(mockedDataBaseBudgetClient.ReadChangesCalls())
+func (mock *DataBaseBudgetClientMock) ReadChangesCalls() []struct {
+	Ctx    context.Context
+	Filter api.PayrollChangeFilter
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Filter api.PayrollChangeFilter
+	}
+	mock.lockReadChanges.RLock()
+	calls = mock.calls.ReadChanges
+	mock.lockReadChanges.RUnlock()
+	return calls
+}
+
+// UpdateBudget calls UpdateBudgetFunc.
+func (mock *DataBaseBudgetClientMock) UpdateBudget(ctx context.Context, budget *domain.Budget) (*domain.Budget, error) {
+	if mock.UpdateBudgetFunc == nil {
+		panic("DataBaseBudgetClientMock.UpdateBudgetFunc: method is nil but DataBaseBudgetClient.UpdateBudget was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Budget *domain.Budget
+	}{
+		Ctx:    ctx,
+		Budget: budget,
+	}
+	mock.lockUpdateBudget.Lock()
+	mock.calls.UpdateBudget = append(mock.calls.UpdateBudget, callInfo)
+	mock.lockUpdateBudget.Unlock()
+	return mock.UpdateBudgetFunc(ctx, budget)
+}
+
+// UpdateBudgetCalls gets all the calls that were made to UpdateBudget.
+// Check the length with:
+//
+//	len(mockedDataBaseBudgetClient.UpdateBudgetCalls())
+func (mock *DataBaseBudgetClientMock) UpdateBudgetCalls() []struct {
+	Ctx    context.Context
+	Budget *domain.Budget
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Budget *domain.Budget
+	}
+	mock.lockUpdateBudget.RLock()
+	calls = mock.calls.UpdateBudget
+	mock.lockUpdateBudget.RUnlock()
+	return calls
+}
+
+// UpdateChange calls UpdateChangeFunc.
+func (mock *DataBaseBudgetClientMock) UpdateChange(ctx context.Context, change *domain.PayrollChange) (*domain.PayrollChange, error) {
+	if mock.UpdateChangeFunc == nil {
+		panic("DataBaseBudgetClientMock.UpdateChangeFunc: method is nil but DataBaseBudgetClient.UpdateChange was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Change *domain.PayrollChange
+	}{
+		Ctx:    ctx,
+		Change: change,
+	}
+	mock.lockUpdateChange.Lock()
+	mock.calls.UpdateChange = append(mock.calls.UpdateChange, callInfo)
+	mock.lockUpdateChange.Unlock()
+	return mock.UpdateChangeFunc(ctx, change)
+}
+
+// UpdateChangeCalls gets all the calls that were made to UpdateChange.
+// Check the length with:
+//
+//	len(mockedDataBaseBudgetClient.UpdateChangeCalls())
+func (mock *DataBaseBudgetClientMock) UpdateChangeCalls() []struct {
+	Ctx    context.Context
+	Change *domain.PayrollChange
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Change *domain.PayrollChange
+	}
+	mock.lockUpdateChange.RLock()
+	calls = mock.calls.UpdateChange
+	mock.lockUpdateChange.RUnlock()
+	return calls
+}
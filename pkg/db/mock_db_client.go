@@ -4,9 +4,11 @@
 package db
 
 import (
+	"context"
 	"salaries/pkg/api"
 	"salaries/pkg/domain"
 	"sync"
+	"time"
 )
 
 // Ensure, that DataBaseSalaryClientMock does implement DataBaseSalaryClient.
@@ -19,27 +21,48 @@ var _ DataBaseSalaryClient = &DataBaseSalaryClientMock{}
 //
 //		// make and configure a mocked DataBaseSalaryClient
 //		mockedDataBaseSalaryClient := &DataBaseSalaryClientMock{
-//			CreateFunc: func(salary *domain.Salary) (*domain.Salary, error) {
+//			BatchFunc: func(ctx context.Context) (SalaryBatch, error) {
+//				panic("mock out the Batch method")
+//			},
+//			CreateFunc: func(ctx context.Context, salary *domain.Salary) (*domain.Salary, error) {
 //				panic("mock out the Create method")
 //			},
-//			DeleteByIDFunc: func(salaryID int64) error {
+//			DeleteByIDFunc: func(ctx context.Context, salaryID int64, version int64) (*domain.Salary, error) {
 //				panic("mock out the DeleteByID method")
 //			},
-//			GetContractsStatsFunc: func() (*api.Stats, error) {
+//			GetContractsStatsFunc: func(ctx context.Context) (*api.Stats, error) {
 //				panic("mock out the GetContractsStats method")
 //			},
-//			GetDepartmentsStatsFunc: func() ([]api.DepartmentStats, error) {
+//			GetDepartmentsStatsFunc: func(ctx context.Context) ([]api.DepartmentStats, error) {
 //				panic("mock out the GetDepartmentsStats method")
 //			},
-//			GetStatsForAllSalariesFunc: func() (*api.Stats, error) {
+//			GetStatsForAllSalariesFunc: func(ctx context.Context) (*api.Stats, error) {
 //				panic("mock out the GetStatsForAllSalaries method")
 //			},
-//			GetSubDepartmentsStatsFunc: func() ([]api.SubDepartmentStats, error) {
+//			GetSubDepartmentsStatsFunc: func(ctx context.Context) ([]api.SubDepartmentStats, error) {
 //				panic("mock out the GetSubDepartmentsStats method")
 //			},
-//			ReadAllFunc: func() ([]domain.Salary, error) {
+//			PurgeFunc: func(ctx context.Context, deletedBefore time.Time) (int64, error) {
+//				panic("mock out the Purge method")
+//			},
+//			ReadAllFunc: func(ctx context.Context, filter api.SalaryFilter) ([]domain.Salary, error) {
 //				panic("mock out the ReadAll method")
 //			},
+//			ReadByIDFunc: func(ctx context.Context, salaryID int64) (*domain.Salary, error) {
+//				panic("mock out the ReadByID method")
+//			},
+//			ReadEachFunc: func(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary) error) error {
+//				panic("mock out the ReadEach method")
+//			},
+//			ReadTrashFunc: func(ctx context.Context) ([]domain.DeletedSalary, error) {
+//				panic("mock out the ReadTrash method")
+//			},
+//			RestoreFunc: func(ctx context.Context, salaryID int64) (*domain.Salary, error) {
+//				panic("mock out the Restore method")
+//			},
+//			UpdateFunc: func(ctx context.Context, salary *domain.Salary, version int64) (*domain.Salary, error) {
+//				panic("mock out the Update method")
+//			},
 //		}
 //
 //		// use mockedDataBaseSalaryClient in code that requires DataBaseSalaryClient
@@ -47,78 +70,207 @@ var _ DataBaseSalaryClient = &DataBaseSalaryClientMock{}
 //
 //	}
 type DataBaseSalaryClientMock struct {
+	// BatchFunc mocks the Batch method.
+	BatchFunc func(ctx context.Context) (SalaryBatch, error)
+
 	// CreateFunc mocks the Create method.
-	CreateFunc func(salary *domain.Salary) (*domain.Salary, error)
+	CreateFunc func(ctx context.Context, salary *domain.Salary) (*domain.Salary, error)
 
 	// DeleteByIDFunc mocks the DeleteByID method.
-	DeleteByIDFunc func(salaryID int64) error
+	DeleteByIDFunc func(ctx context.Context, salaryID int64, version int64) (*domain.Salary, error)
 
 	// GetContractsStatsFunc mocks the GetContractsStats method.
-	GetContractsStatsFunc func() (*api.Stats, error)
+	GetContractsStatsFunc func(ctx context.Context) (*api.Stats, error)
 
 	// GetDepartmentsStatsFunc mocks the GetDepartmentsStats method.
-	GetDepartmentsStatsFunc func() ([]api.DepartmentStats, error)
+	GetDepartmentsStatsFunc func(ctx context.Context) ([]api.DepartmentStats, error)
 
 	// GetStatsForAllSalariesFunc mocks the GetStatsForAllSalaries method.
-	GetStatsForAllSalariesFunc func() (*api.Stats, error)
+	GetStatsForAllSalariesFunc func(ctx context.Context) (*api.Stats, error)
 
 	// GetSubDepartmentsStatsFunc mocks the GetSubDepartmentsStats method.
-	GetSubDepartmentsStatsFunc func() ([]api.SubDepartmentStats, error)
+	GetSubDepartmentsStatsFunc func(ctx context.Context) ([]api.SubDepartmentStats, error)
+
+	// PurgeFunc mocks the Purge method.
+	PurgeFunc func(ctx context.Context, deletedBefore time.Time) (int64, error)
 
 	// ReadAllFunc mocks the ReadAll method.
-	ReadAllFunc func() ([]domain.Salary, error)
+	ReadAllFunc func(ctx context.Context, filter api.SalaryFilter) ([]domain.Salary, error)
+
+	// ReadByIDFunc mocks the ReadByID method.
+	ReadByIDFunc func(ctx context.Context, salaryID int64) (*domain.Salary, error)
+
+	// ReadEachFunc mocks the ReadEach method.
+	ReadEachFunc func(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary) error) error
+
+	// ReadTrashFunc mocks the ReadTrash method.
+	ReadTrashFunc func(ctx context.Context) ([]domain.DeletedSalary, error)
+
+	// RestoreFunc mocks the Restore method.
+	RestoreFunc func(ctx context.Context, salaryID int64) (*domain.Salary, error)
+
+	// UpdateFunc mocks the Update method.
+	UpdateFunc func(ctx context.Context, salary *domain.Salary, version int64) (*domain.Salary, error)
 
 	// calls tracks calls to the methods.
 	calls struct {
+		// Batch holds details about calls to the Batch method.
+		Batch []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
 		// Create holds details about calls to the Create method.
 		Create []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// Salary is the salary argument value.
 			Salary *domain.Salary
 		}
 		// DeleteByID holds details about calls to the DeleteByID method.
 		DeleteByID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// SalaryID is the salaryID argument value.
 			SalaryID int64
+			// Version is the version argument value.
+			Version int64
 		}
 		// GetContractsStats holds details about calls to the GetContractsStats method.
 		GetContractsStats []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 		}
 		// GetDepartmentsStats holds details about calls to the GetDepartmentsStats method.
 		GetDepartmentsStats []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 		}
 		// GetStatsForAllSalaries holds details about calls to the GetStatsForAllSalaries method.
 		GetStatsForAllSalaries []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 		}
 		// GetSubDepartmentsStats holds details about calls to the GetSubDepartmentsStats method.
 		GetSubDepartmentsStats []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// Purge holds details about calls to the Purge method.
+		Purge []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// DeletedBefore is the deletedBefore argument value.
+			DeletedBefore time.Time
 		}
 		// ReadAll holds details about calls to the ReadAll method.
 		ReadAll []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Filter is the filter argument value.
+			Filter api.SalaryFilter
+		}
+		// ReadByID holds details about calls to the ReadByID method.
+		ReadByID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// SalaryID is the salaryID argument value.
+			SalaryID int64
+		}
+		// ReadEach holds details about calls to the ReadEach method.
+		ReadEach []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Filter is the filter argument value.
+			Filter api.SalaryFilter
+			// Each is the each argument value.
+			Each func(salary *domain.Salary) error
+		}
+		// ReadTrash holds details about calls to the ReadTrash method.
+		ReadTrash []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// Restore holds details about calls to the Restore method.
+		Restore []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// SalaryID is the salaryID argument value.
+			SalaryID int64
+		}
+		// Update holds details about calls to the Update method.
+		Update []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Salary is the salary argument value.
+			Salary *domain.Salary
+			// Version is the version argument value.
+			Version int64
 		}
 	}
+	lockBatch                  sync.RWMutex
 	lockCreate                 sync.RWMutex
 	lockDeleteByID             sync.RWMutex
 	lockGetContractsStats      sync.RWMutex
 	lockGetDepartmentsStats    sync.RWMutex
 	lockGetStatsForAllSalaries sync.RWMutex
 	lockGetSubDepartmentsStats sync.RWMutex
+	lockPurge                  sync.RWMutex
 	lockReadAll                sync.RWMutex
+	lockReadByID               sync.RWMutex
+	lockReadEach               sync.RWMutex
+	lockReadTrash              sync.RWMutex
+	lockRestore                sync.RWMutex
+	lockUpdate                 sync.RWMutex
+}
+
+// Batch calls BatchFunc.
+func (mock *DataBaseSalaryClientMock) Batch(ctx context.Context) (SalaryBatch, error) {
+	if mock.BatchFunc == nil {
+		panic("DataBaseSalaryClientMock.BatchFunc: method is nil but DataBaseSalaryClient.Batch was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockBatch.Lock()
+	mock.calls.Batch = append(mock.calls.Batch, callInfo)
+	mock.lockBatch.Unlock()
+	return mock.BatchFunc(ctx)
+}
+
+// BatchCalls gets all the calls that were made to Batch.
+// Check the length with:
+//
+//	len(mockedDataBaseSalaryClient.BatchCalls())
+func (mock *DataBaseSalaryClientMock) BatchCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockBatch.RLock()
+	calls = mock.calls.Batch
+	mock.lockBatch.RUnlock()
+	return calls
 }
 
 // Create calls CreateFunc.
-func (mock *DataBaseSalaryClientMock) Create(salary *domain.Salary) (*domain.Salary, error) {
+func (mock *DataBaseSalaryClientMock) Create(ctx context.Context, salary *domain.Salary) (*domain.Salary, error) {
 	if mock.CreateFunc == nil {
 		panic("DataBaseSalaryClientMock.CreateFunc: method is nil but DataBaseSalaryClient.Create was just called")
 	}
 	callInfo := struct {
+		Ctx    context.Context
 		Salary *domain.Salary
 	}{
+		Ctx:    ctx,
 		Salary: salary,
 	}
 	mock.lockCreate.Lock()
 	mock.calls.Create = append(mock.calls.Create, callInfo)
 	mock.lockCreate.Unlock()
-	return mock.CreateFunc(salary)
+	return mock.CreateFunc(ctx, salary)
 }
 
 // CreateCalls gets all the calls that were made to Create.
@@ -126,9 +278,11 @@ func (mock *DataBaseSalaryClientMock) Create(salary *domain.Salary) (*domain.Sal
 //
 //	len(mockedDataBaseSalaryClient.CreateCalls())
 func (mock *DataBaseSalaryClientMock) CreateCalls() []struct {
+	Ctx    context.Context
 	Salary *domain.Salary
 } {
 	var calls []struct {
+		Ctx    context.Context
 		Salary *domain.Salary
 	}
 	mock.lockCreate.RLock()
@@ -138,19 +292,23 @@ func (mock *DataBaseSalaryClientMock) CreateCalls() []struct {
 }
 
 // DeleteByID calls DeleteByIDFunc.
-func (mock *DataBaseSalaryClientMock) DeleteByID(salaryID int64) error {
+func (mock *DataBaseSalaryClientMock) DeleteByID(ctx context.Context, salaryID int64, version int64) (*domain.Salary, error) {
 	if mock.DeleteByIDFunc == nil {
 		panic("DataBaseSalaryClientMock.DeleteByIDFunc: method is nil but DataBaseSalaryClient.DeleteByID was just called")
 	}
 	callInfo := struct {
+		Ctx      context.Context
 		SalaryID int64
+		Version  int64
 	}{
+		Ctx:      ctx,
 		SalaryID: salaryID,
+		Version:  version,
 	}
 	mock.lockDeleteByID.Lock()
 	mock.calls.DeleteByID = append(mock.calls.DeleteByID, callInfo)
 	mock.lockDeleteByID.Unlock()
-	return mock.DeleteByIDFunc(salaryID)
+	return mock.DeleteByIDFunc(ctx, salaryID, version)
 }
 
 // DeleteByIDCalls gets all the calls that were made to DeleteByID.
@@ -158,10 +316,14 @@ func (mock *DataBaseSalaryClientMock) DeleteByID(salaryID int64) error {
 //
 //	len(mockedDataBaseSalaryClient.DeleteByIDCalls())
 func (mock *DataBaseSalaryClientMock) DeleteByIDCalls() []struct {
+	Ctx      context.Context
 	SalaryID int64
+	Version  int64
 } {
 	var calls []struct {
+		Ctx      context.Context
 		SalaryID int64
+		Version  int64
 	}
 	mock.lockDeleteByID.RLock()
 	calls = mock.calls.DeleteByID
@@ -170,16 +332,19 @@ func (mock *DataBaseSalaryClientMock) DeleteByIDCalls() []struct {
 }
 
 // GetContractsStats calls GetContractsStatsFunc.
-func (mock *DataBaseSalaryClientMock) GetContractsStats() (*api.Stats, error) {
+func (mock *DataBaseSalaryClientMock) GetContractsStats(ctx context.Context) (*api.Stats, error) {
 	if mock.GetContractsStatsFunc == nil {
 		panic("DataBaseSalaryClientMock.GetContractsStatsFunc: method is nil but DataBaseSalaryClient.GetContractsStats was just called")
 	}
 	callInfo := struct {
-	}{}
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
 	mock.lockGetContractsStats.Lock()
 	mock.calls.GetContractsStats = append(mock.calls.GetContractsStats, callInfo)
 	mock.lockGetContractsStats.Unlock()
-	return mock.GetContractsStatsFunc()
+	return mock.GetContractsStatsFunc(ctx)
 }
 
 // GetContractsStatsCalls gets all the calls that were made to GetContractsStats.
@@ -187,8 +352,10 @@ func (mock *DataBaseSalaryClientMock) GetContractsStats() (*api.Stats, error) {
 //
 //	len(mockedDataBaseSalaryClient.GetContractsStatsCalls())
 func (mock *DataBaseSalaryClientMock) GetContractsStatsCalls() []struct {
+	Ctx context.Context
 } {
 	var calls []struct {
+		Ctx context.Context
 	}
 	mock.lockGetContractsStats.RLock()
 	calls = mock.calls.GetContractsStats
@@ -197,16 +364,19 @@ func (mock *DataBaseSalaryClientMock) GetContractsStatsCalls() []struct {
 }
 
 // GetDepartmentsStats calls GetDepartmentsStatsFunc.
-func (mock *DataBaseSalaryClientMock) GetDepartmentsStats() ([]api.DepartmentStats, error) {
+func (mock *DataBaseSalaryClientMock) GetDepartmentsStats(ctx context.Context) ([]api.DepartmentStats, error) {
 	if mock.GetDepartmentsStatsFunc == nil {
 		panic("DataBaseSalaryClientMock.GetDepartmentsStatsFunc: method is nil but DataBaseSalaryClient.GetDepartmentsStats was just called")
 	}
 	callInfo := struct {
-	}{}
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
 	mock.lockGetDepartmentsStats.Lock()
 	mock.calls.GetDepartmentsStats = append(mock.calls.GetDepartmentsStats, callInfo)
 	mock.lockGetDepartmentsStats.Unlock()
-	return mock.GetDepartmentsStatsFunc()
+	return mock.GetDepartmentsStatsFunc(ctx)
 }
 
 // GetDepartmentsStatsCalls gets all the calls that were made to GetDepartmentsStats.
@@ -214,8 +384,10 @@ func (mock *DataBaseSalaryClientMock) GetDepartmentsStats() ([]api.DepartmentSta
 //
 //	len(mockedDataBaseSalaryClient.GetDepartmentsStatsCalls())
 func (mock *DataBaseSalaryClientMock) GetDepartmentsStatsCalls() []struct {
+	Ctx context.Context
 } {
 	var calls []struct {
+		Ctx context.Context
 	}
 	mock.lockGetDepartmentsStats.RLock()
 	calls = mock.calls.GetDepartmentsStats
@@ -224,16 +396,19 @@ func (mock *DataBaseSalaryClientMock) GetDepartmentsStatsCalls() []struct {
 }
 
 // GetStatsForAllSalaries calls GetStatsForAllSalariesFunc.
-func (mock *DataBaseSalaryClientMock) GetStatsForAllSalaries() (*api.Stats, error) {
+func (mock *DataBaseSalaryClientMock) GetStatsForAllSalaries(ctx context.Context) (*api.Stats, error) {
 	if mock.GetStatsForAllSalariesFunc == nil {
 		panic("DataBaseSalaryClientMock.GetStatsForAllSalariesFunc: method is nil but DataBaseSalaryClient.GetStatsForAllSalaries was just called")
 	}
 	callInfo := struct {
-	}{}
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
 	mock.lockGetStatsForAllSalaries.Lock()
 	mock.calls.GetStatsForAllSalaries = append(mock.calls.GetStatsForAllSalaries, callInfo)
 	mock.lockGetStatsForAllSalaries.Unlock()
-	return mock.GetStatsForAllSalariesFunc()
+	return mock.GetStatsForAllSalariesFunc(ctx)
 }
 
 // GetStatsForAllSalariesCalls gets all the calls that were made to GetStatsForAllSalaries.
@@ -241,8 +416,10 @@ func (mock *DataBaseSalaryClientMock) GetStatsForAllSalaries() (*api.Stats, erro
 //
 //	len(mockedDataBaseSalaryClient.GetStatsForAllSalariesCalls())
 func (mock *DataBaseSalaryClientMock) GetStatsForAllSalariesCalls() []struct {
+	Ctx context.Context
 } {
 	var calls []struct {
+		Ctx context.Context
 	}
 	mock.lockGetStatsForAllSalaries.RLock()
 	calls = mock.calls.GetStatsForAllSalaries
@@ -251,16 +428,19 @@ func (mock *DataBaseSalaryClientMock) GetStatsForAllSalariesCalls() []struct {
 }
 
 // GetSubDepartmentsStats calls GetSubDepartmentsStatsFunc.
-func (mock *DataBaseSalaryClientMock) GetSubDepartmentsStats() ([]api.SubDepartmentStats, error) {
+func (mock *DataBaseSalaryClientMock) GetSubDepartmentsStats(ctx context.Context) ([]api.SubDepartmentStats, error) {
 	if mock.GetSubDepartmentsStatsFunc == nil {
 		panic("DataBaseSalaryClientMock.GetSubDepartmentsStatsFunc: method is nil but DataBaseSalaryClient.GetSubDepartmentsStats was just called")
 	}
 	callInfo := struct {
-	}{}
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
 	mock.lockGetSubDepartmentsStats.Lock()
 	mock.calls.GetSubDepartmentsStats = append(mock.calls.GetSubDepartmentsStats, callInfo)
 	mock.lockGetSubDepartmentsStats.Unlock()
-	return mock.GetSubDepartmentsStatsFunc()
+	return mock.GetSubDepartmentsStatsFunc(ctx)
 }
 
 // GetSubDepartmentsStatsCalls gets all the calls that were made to GetSubDepartmentsStats.
@@ -268,8 +448,10 @@ func (mock *DataBaseSalaryClientMock) GetSubDepartmentsStats() ([]api.SubDepartm
 //
 //	len(mockedDataBaseSalaryClient.GetSubDepartmentsStatsCalls())
 func (mock *DataBaseSalaryClientMock) GetSubDepartmentsStatsCalls() []struct {
+	Ctx context.Context
 } {
 	var calls []struct {
+		Ctx context.Context
 	}
 	mock.lockGetSubDepartmentsStats.RLock()
 	calls = mock.calls.GetSubDepartmentsStats
@@ -277,17 +459,58 @@ func (mock *DataBaseSalaryClientMock) GetSubDepartmentsStatsCalls() []struct {
 	return calls
 }
 
+// Purge calls PurgeFunc.
+func (mock *DataBaseSalaryClientMock) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	if mock.PurgeFunc == nil {
+		panic("DataBaseSalaryClientMock.PurgeFunc: method is nil but DataBaseSalaryClient.Purge was just called")
+	}
+	callInfo := struct {
+		Ctx           context.Context
+		DeletedBefore time.Time
+	}{
+		Ctx:           ctx,
+		DeletedBefore: deletedBefore,
+	}
+	mock.lockPurge.Lock()
+	mock.calls.Purge = append(mock.calls.Purge, callInfo)
+	mock.lockPurge.Unlock()
+	return mock.PurgeFunc(ctx, deletedBefore)
+}
+
+// PurgeCalls gets all the calls that were made to Purge.
+// Check the length with:
+//
+//	len(mockedDataBaseSalaryClient.PurgeCalls())
+func (mock *DataBaseSalaryClientMock) PurgeCalls() []struct {
+	Ctx           context.Context
+	DeletedBefore time.Time
+} {
+	var calls []struct {
+		Ctx           context.Context
+		DeletedBefore time.Time
+	}
+	mock.lockPurge.RLock()
+	calls = mock.calls.Purge
+	mock.lockPurge.RUnlock()
+	return calls
+}
+
 // ReadAll calls ReadAllFunc.
-func (mock *DataBaseSalaryClientMock) ReadAll() ([]domain.Salary, error) {
+func (mock *DataBaseSalaryClientMock) ReadAll(ctx context.Context, filter api.SalaryFilter) ([]domain.Salary, error) {
 	if mock.ReadAllFunc == nil {
 		panic("DataBaseSalaryClientMock.ReadAllFunc: method is nil but DataBaseSalaryClient.ReadAll was just called")
 	}
 	callInfo := struct {
-	}{}
+		Ctx    context.Context
+		Filter api.SalaryFilter
+	}{
+		Ctx:    ctx,
+		Filter: filter,
+	}
 	mock.lockReadAll.Lock()
 	mock.calls.ReadAll = append(mock.calls.ReadAll, callInfo)
 	mock.lockReadAll.Unlock()
-	return mock.ReadAllFunc()
+	return mock.ReadAllFunc(ctx, filter)
 }
 
 // ReadAllCalls gets all the calls that were made to ReadAll.
@@ -295,11 +518,199 @@ func (mock *DataBaseSalaryClientMock) ReadAll() ([]domain.Salary, error) {
 //
 //	len(mockedDataBaseSalaryClient.ReadAllCalls())
 func (mock *DataBaseSalaryClientMock) ReadAllCalls() []struct {
+	Ctx    context.Context
+	Filter api.SalaryFilter
 } {
 	var calls []struct {
+		Ctx    context.Context
+		Filter api.SalaryFilter
 	}
 	mock.lockReadAll.RLock()
 	calls = mock.calls.ReadAll
 	mock.lockReadAll.RUnlock()
 	return calls
 }
+
+// ReadByID calls ReadByIDFunc.
+func (mock *DataBaseSalaryClientMock) ReadByID(ctx context.Context, salaryID int64) (*domain.Salary, error) {
+	if mock.ReadByIDFunc == nil {
+		panic("DataBaseSalaryClientMock.ReadByIDFunc: method is nil but DataBaseSalaryClient.ReadByID was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		SalaryID int64
+	}{
+		Ctx:      ctx,
+		SalaryID: salaryID,
+	}
+	mock.lockReadByID.Lock()
+	mock.calls.ReadByID = append(mock.calls.ReadByID, callInfo)
+	mock.lockReadByID.Unlock()
+	return mock.ReadByIDFunc(ctx, salaryID)
+}
+
+// ReadByIDCalls gets all the calls that were made to ReadByID.
+// Check the length with:
+//
+//	len(mockedDataBaseSalaryClient.ReadByIDCalls())
+func (mock *DataBaseSalaryClientMock) ReadByIDCalls() []struct {
+	Ctx      context.Context
+	SalaryID int64
+} {
+	var calls []struct {
+		Ctx      context.Context
+		SalaryID int64
+	}
+	mock.lockReadByID.RLock()
+	calls = mock.calls.ReadByID
+	mock.lockReadByID.RUnlock()
+	return calls
+}
+
+// ReadEach calls ReadEachFunc.
+func (mock *DataBaseSalaryClientMock) ReadEach(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary) error) error {
+	if mock.ReadEachFunc == nil {
+		panic("DataBaseSalaryClientMock.ReadEachFunc: method is nil but DataBaseSalaryClient.ReadEach was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Filter api.SalaryFilter
+		Each   func(salary *domain.Salary) error
+	}{
+		Ctx:    ctx,
+		Filter: filter,
+		Each:   each,
+	}
+	mock.lockReadEach.Lock()
+	mock.calls.ReadEach = append(mock.calls.ReadEach, callInfo)
+	mock.lockReadEach.Unlock()
+	return mock.ReadEachFunc(ctx, filter, each)
+}
+
+// ReadEachCalls gets all the calls that were made to ReadEach.
+// Check the length with:
+//
+//	len(mockedDataBaseSalaryClient.ReadEachCalls())
+func (mock *DataBaseSalaryClientMock) ReadEachCalls() []struct {
+	Ctx    context.Context
+	Filter api.SalaryFilter
+	Each   func(salary *domain.Salary) error
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Filter api.SalaryFilter
+		Each   func(salary *domain.Salary) error
+	}
+	mock.lockReadEach.RLock()
+	calls = mock.calls.ReadEach
+	mock.lockReadEach.RUnlock()
+	return calls
+}
+
+// ReadTrash calls ReadTrashFunc.
+func (mock *DataBaseSalaryClientMock) ReadTrash(ctx context.Context) ([]domain.DeletedSalary, error) {
+	if mock.ReadTrashFunc == nil {
+		panic("DataBaseSalaryClientMock.ReadTrashFunc: method is nil but DataBaseSalaryClient.ReadTrash was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockReadTrash.Lock()
+	mock.calls.ReadTrash = append(mock.calls.ReadTrash, callInfo)
+	mock.lockReadTrash.Unlock()
+	return mock.ReadTrashFunc(ctx)
+}
+
+// ReadTrashCalls gets all the calls that were made to ReadTrash.
+// Check the length with:
+//
+//	len(mockedDataBaseSalaryClient.ReadTrashCalls())
+func (mock *DataBaseSalaryClientMock) ReadTrashCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockReadTrash.RLock()
+	calls = mock.calls.ReadTrash
+	mock.lockReadTrash.RUnlock()
+	return calls
+}
+
+// Restore calls RestoreFunc.
+func (mock *DataBaseSalaryClientMock) Restore(ctx context.Context, salaryID int64) (*domain.Salary, error) {
+	if mock.RestoreFunc == nil {
+		panic("DataBaseSalaryClientMock.RestoreFunc: method is nil but DataBaseSalaryClient.Restore was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		SalaryID int64
+	}{
+		Ctx:      ctx,
+		SalaryID: salaryID,
+	}
+	mock.lockRestore.Lock()
+	mock.calls.Restore = append(mock.calls.Restore, callInfo)
+	mock.lockRestore.Unlock()
+	return mock.RestoreFunc(ctx, salaryID)
+}
+
+// RestoreCalls gets all the calls that were made to Restore.
+// Check the length with:
+//
+//	len(mockedDataBaseSalaryClient.RestoreCalls())
+func (mock *DataBaseSalaryClientMock) RestoreCalls() []struct {
+	Ctx      context.Context
+	SalaryID int64
+} {
+	var calls []struct {
+		Ctx      context.Context
+		SalaryID int64
+	}
+	mock.lockRestore.RLock()
+	calls = mock.calls.Restore
+	mock.lockRestore.RUnlock()
+	return calls
+}
+
+// Update calls UpdateFunc.
+func (mock *DataBaseSalaryClientMock) Update(ctx context.Context, salary *domain.Salary, version int64) (*domain.Salary, error) {
+	if mock.UpdateFunc == nil {
+		panic("DataBaseSalaryClientMock.UpdateFunc: method is nil but DataBaseSalaryClient.Update was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Salary  *domain.Salary
+		Version int64
+	}{
+		Ctx:     ctx,
+		Salary:  salary,
+		Version: version,
+	}
+	mock.lockUpdate.Lock()
+	mock.calls.Update = append(mock.calls.Update, callInfo)
+	mock.lockUpdate.Unlock()
+	return mock.UpdateFunc(ctx, salary, version)
+}
+
+// UpdateCalls gets all the calls that were made to Update.
+// Check the length with:
+//
+//	len(mockedDataBaseSalaryClient.UpdateCalls())
+func (mock *DataBaseSalaryClientMock) UpdateCalls() []struct {
+	Ctx     context.Context
+	Salary  *domain.Salary
+	Version int64
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Salary  *domain.Salary
+		Version int64
+	}
+	mock.lockUpdate.RLock()
+	calls = mock.calls.Update
+	mock.lockUpdate.RUnlock()
+	return calls
+}
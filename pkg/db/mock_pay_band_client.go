@@ -0,0 +1,283 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package db
+
+import (
+	"context"
+	"salaries/pkg/api"
+	"salaries/pkg/domain"
+	"sync"
+)
+
+// Ensure, that DataBasePayBandClientMock does implement DataBasePayBandClient.
+// If this is not the case, regenerate this file with moq.
+var _ DataBasePayBandClient = &DataBasePayBandClientMock{}
+
+// DataBasePayBandClientMock is a mock implementation of DataBasePayBandClient.
+//
+//	func TestSomethingThatUsesDataBasePayBandClient(t *testing.T) {
+//
+//		// make and configure a mocked DataBasePayBandClient
+//		mockedDataBasePayBandClient := &DataBasePayBandClientMock{
+//			CreateFunc: func(ctx context.Context, band *domain.PayBand) (*domain.PayBand, error) {
+//				panic("mock out the Create method")
+//			},
+//			DeleteByIDFunc: func(ctx context.Context, bandID int64) error {
+//				panic("mock out the DeleteByID method")
+//			},
+//			ReadAllFunc: func(ctx context.Context, filter api.PayBandFilter) ([]domain.PayBand, error) {
+//				panic("mock out the ReadAll method")
+//			},
+//			ReadByIDFunc: func(ctx context.Context, bandID int64) (*domain.PayBand, error) {
+//				panic("mock out the ReadByID method")
+//			},
+//			UpdateFunc: func(ctx context.Context, band *domain.PayBand) (*domain.PayBand, error) {
+//				panic("mock out the Update method")
+//			},
+//		}
+//
+//		// use mockedDataBasePayBandClient in code that requires DataBasePayBandClient
+//		// and then make assertions.
+//
+//	}
+type DataBasePayBandClientMock struct {
+	// CreateFunc mocks the Create method.
+	CreateFunc func(ctx context.Context, band *domain.PayBand) (*domain.PayBand, error)
+
+	// DeleteByIDFunc mocks the DeleteByID method.
+	DeleteByIDFunc func(ctx context.Context, bandID int64) error
+
+	// ReadAllFunc mocks the ReadAll method.
+	ReadAllFunc func(ctx context.Context, filter api.PayBandFilter) ([]domain.PayBand, error)
+
+	// ReadByIDFunc mocks the ReadByID method.
+	ReadByIDFunc func(ctx context.Context, bandID int64) (*domain.PayBand, error)
+
+	// UpdateFunc mocks the Update method.
+	UpdateFunc func(ctx context.Context, band *domain.PayBand) (*domain.PayBand, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Create holds details about calls to the Create method.
+		Create []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Band is the band argument value.
+			Band *domain.PayBand
+		}
+		// DeleteByID holds details about calls to the DeleteByID method.
+		DeleteByID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// BandID is the bandID argument value.
+			BandID int64
+		}
+		// ReadAll holds details about calls to the ReadAll method.
+		ReadAll []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Filter is the filter argument value.
+			Filter api.PayBandFilter
+		}
+		// ReadByID holds details about calls to the ReadByID method.
+		ReadByID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// BandID is the bandID argument value.
+			BandID int64
+		}
+		// Update holds details about calls to the Update method.
+		Update []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Band is the band argument value.
+			Band *domain.PayBand
+		}
+	}
+	lockCreate     sync.RWMutex
+	lockDeleteByID sync.RWMutex
+	lockReadAll    sync.RWMutex
+	lockReadByID   sync.RWMutex
+	lockUpdate     sync.RWMutex
+}
+
+// Create calls CreateFunc.
+func (mock *DataBasePayBandClientMock) Create(ctx context.Context, band *domain.PayBand) (*domain.PayBand, error) {
+	if mock.CreateFunc == nil {
+		panic("DataBasePayBandClientMock.CreateFunc: method is nil but DataBasePayBandClient.Create was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Band *domain.PayBand
+	}{
+		Ctx:  ctx,
+		Band: band,
+	}
+	mock.lockCreate.Lock()
+	mock.calls.Create = append(mock.calls.Create, callInfo)
+	mock.lockCreate.Unlock()
+	return mock.CreateFunc(ctx, band)
+}
+
+// CreateCalls gets all the calls that were made to Create.
+// Check the length with:
+//
+//	len(mockedDataBasePayBandClient.CreateCalls())
+func (mock *DataBasePayBandClientMock) CreateCalls() []struct {
+	Ctx  context.Context
+	Band *domain.PayBand
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Band *domain.PayBand
+	}
+	mock.lockCreate.RLock()
+	calls = mock.calls.Create
+	mock.lockCreate.RUnlock()
+	return calls
+}
+
+// DeleteByID calls DeleteByIDFunc.
+func (mock *DataBasePayBandClientMock) DeleteByID(ctx context.Context, bandID int64) error {
+	if mock.DeleteByIDFunc == nil {
+		panic("DataBasePayBandClientMock.DeleteByIDFunc: method is nil but DataBasePayBandClient.DeleteByID was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		BandID int64
+	}{
+		Ctx:    ctx,
+		BandID: bandID,
+	}
+	mock.lockDeleteByID.Lock()
+	mock.calls.DeleteByID = append(mock.calls.DeleteByID, callInfo)
+	mock.lockDeleteByID.Unlock()
+	return mock.DeleteByIDFunc(ctx, bandID)
+}
+
+// DeleteByIDCalls gets all the calls that were made to DeleteByID.
+// Check the length with:
+//
+//	len(mockedDataBasePayBandClient.DeleteByIDCalls())
+func (mock *DataBasePayBandClientMock) DeleteByIDCalls() []struct {
+	Ctx    context.Context
+	BandID int64
+} {
+	var calls []struct {
+		Ctx    context.Context
+		BandID int64
+	}
+	mock.lockDeleteByID.RLock()
+	calls = mock.calls.DeleteByID
+	mock.lockDeleteByID.RUnlock()
+	return calls
+}
+
+// ReadAll calls ReadAllFunc.
+func (mock *DataBasePayBandClientMock) ReadAll(ctx context.Context, filter api.PayBandFilter) ([]domain.PayBand, error) {
+	if mock.ReadAllFunc == nil {
+		panic("DataBasePayBandClientMock.ReadAllFunc: method is nil but DataBasePayBandClient.ReadAll was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Filter api.PayBandFilter
+	}{
+		Ctx:    ctx,
+		Filter: filter,
+	}
+	mock.lockReadAll.Lock()
+	mock.calls.ReadAll = append(mock.calls.ReadAll, callInfo)
+	mock.lockReadAll.Unlock()
+	return mock.ReadAllFunc(ctx, filter)
+}
+
+// ReadAllCalls gets all the calls that were made to ReadAll.
+// Check the length with:
+//
+//	len(mockedDataBasePayBandClient.ReadAllCalls())
+func (mock *DataBasePayBandClientMock) ReadAllCalls() []struct {
+	Ctx    context.Context
+	Filter api.PayBandFilter
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Filter api.PayBandFilter
+	}
+	mock.lockReadAll.RLock()
+	calls = mock.calls.ReadAll
+	mock.lockReadAll.RUnlock()
+	return calls
+}
+
+// ReadByID calls ReadByIDFunc.
+func (mock *DataBasePayBandClientMock) ReadByID(ctx context.Context, bandID int64) (*domain.PayBand, error) {
+	if mock.ReadByIDFunc == nil {
+		panic("DataBasePayBandClientMock.ReadByIDFunc: method is nil but DataBasePayBandClient.ReadByID was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		BandID int64
+	}{
+		Ctx:    ctx,
+		BandID: bandID,
+	}
+	mock.lockReadByID.Lock()
+	mock.calls.ReadByID = append(mock.calls.ReadByID, callInfo)
+	mock.lockReadByID.Unlock()
+	return mock.ReadByIDFunc(ctx, bandID)
+}
+
+// ReadByIDCalls gets all the calls that were made to ReadByID.
+// Check the length with:
+//
+//	len(mockedDataBasePayBandClient.ReadByIDCalls())
+func (mock *DataBasePayBandClientMock) ReadByIDCalls() []struct {
+	Ctx    context.Context
+	BandID int64
+} {
+	var calls []struct {
+		Ctx    context.Context
+		BandID int64
+	}
+	mock.lockReadByID.RLock()
+	calls = mock.calls.ReadByID
+	mock.lockReadByID.RUnlock()
+	return calls
+}
+
+// Update calls UpdateFunc.
+func (mock *DataBasePayBandClientMock) Update(ctx context.Context, band *domain.PayBand) (*domain.PayBand, error) {
+	if mock.UpdateFunc == nil {
+		panic("DataBasePayBandClientMock.UpdateFunc: method is nil but DataBasePayBandClient.Update was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Band *domain.PayBand
+	}{
+		Ctx:  ctx,
+		Band: band,
+	}
+	mock.lockUpdate.Lock()
+	mock.calls.Update = append(mock.calls.Update, callInfo)
+	mock.lockUpdate.Unlock()
+	return mock.UpdateFunc(ctx, band)
+}
+
+// UpdateCalls gets all the calls that were made to Update.
+// Check the length with:
+//
+//	len(mockedDataBasePayBandClient.UpdateCalls())
+func (mock *DataBasePayBandClientMock) UpdateCalls() []struct {
+	Ctx  context.Context
+	Band *domain.PayBand
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Band *domain.PayBand
+	}
+	mock.lockUpdate.RLock()
+	calls = mock.calls.Update
+	mock.lockUpdate.RUnlock()
+	return calls
+}
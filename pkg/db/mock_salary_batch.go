@@ -0,0 +1,256 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package db
+
+import (
+	"context"
+	"salaries/pkg/domain"
+	"sync"
+)
+
+// Ensure, that SalaryBatchMock does implement SalaryBatch.
+// If this is not the case, regenerate this file with moq.
+var _ SalaryBatch = &SalaryBatchMock{}
+
+// SalaryBatchMock is a mock implementation of SalaryBatch.
+//
+//	func TestSomethingThatUsesSalaryBatch(t *testing.T) {
+//
+//		// make and configure a mocked SalaryBatch
+//		mockedSalaryBatch := &SalaryBatchMock{
+//			AddFunc: func(ctx context.Context, salary *domain.Salary) error {
+//				panic("mock out the Add method")
+//			},
+//			CheckPayBandFunc: func(ctx context.Context, name string) error {
+//				panic("mock out the CheckPayBand method")
+//			},
+//			CommitFunc: func() error {
+//				panic("mock out the Commit method")
+//			},
+//			DeleteFunc: func(ctx context.Context, salaryID int64) error {
+//				panic("mock out the Delete method")
+//			},
+//			RollbackFunc: func() error {
+//				panic("mock out the Rollback method")
+//			},
+//		}
+//
+//		// use mockedSalaryBatch in code that requires SalaryBatch
+//		// and then make assertions.
+//
+//	}
+type SalaryBatchMock struct {
+	// AddFunc mocks the Add method.
+	AddFunc func(ctx context.Context, salary *domain.Salary) error
+
+	// CheckPayBandFunc mocks the CheckPayBand method.
+	CheckPayBandFunc func(ctx context.Context, name string) error
+
+	// CommitFunc mocks the Commit method.
+	CommitFunc func() error
+
+	// DeleteFunc mocks the Delete method.
+	DeleteFunc func(ctx context.Context, salaryID int64) error
+
+	// RollbackFunc mocks the Rollback method.
+	RollbackFunc func() error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Add holds details about calls to the Add method.
+		Add []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Salary is the salary argument value.
+			Salary *domain.Salary
+		}
+		// CheckPayBand holds details about calls to the CheckPayBand method.
+		CheckPayBand []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Name is the name argument value.
+			Name string
+		}
+		// Commit holds details about calls to the Commit method.
+		Commit []struct {
+		}
+		// Delete holds details about calls to the Delete method.
+		Delete []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// SalaryID is the salaryID argument value.
+			SalaryID int64
+		}
+		// Rollback holds details about calls to the Rollback method.
+		Rollback []struct {
+		}
+	}
+	lockAdd          sync.RWMutex
+	lockCheckPayBand sync.RWMutex
+	lockCommit       sync.RWMutex
+	lockDelete       sync.RWMutex
+	lockRollback     sync.RWMutex
+}
+
+// Add calls AddFunc.
+func (mock *SalaryBatchMock) Add(ctx context.Context, salary *domain.Salary) error {
+	if mock.AddFunc == nil {
+		panic("SalaryBatchMock.AddFunc: method is nil but SalaryBatch.Add was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Salary *domain.Salary
+	}{
+		Ctx:    ctx,
+		Salary: salary,
+	}
+	mock.lockAdd.Lock()
+	mock.calls.Add = append(mock.calls.Add, callInfo)
+	mock.lockAdd.Unlock()
+	return mock.AddFunc(ctx, salary)
+}
+
+// AddCalls gets all the calls that were made to Add.
+// Check the length with:
+//
+//	len(mockedSalaryBatch.AddCalls())
+func (mock *SalaryBatchMock) AddCalls() []struct {
+	Ctx    context.Context
+	Salary *domain.Salary
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Salary *domain.Salary
+	}
+	mock.lockAdd.RLock()
+	calls = mock.calls.Add
+	mock.lockAdd.RUnlock()
+	return calls
+}
+
+// CheckPayBand calls CheckPayBandFunc.
+func (mock *SalaryBatchMock) CheckPayBand(ctx context.Context, name string) error {
+	if mock.CheckPayBandFunc == nil {
+		panic("SalaryBatchMock.CheckPayBandFunc: method is nil but SalaryBatch.CheckPayBand was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Name string
+	}{
+		Ctx:  ctx,
+		Name: name,
+	}
+	mock.lockCheckPayBand.Lock()
+	mock.calls.CheckPayBand = append(mock.calls.CheckPayBand, callInfo)
+	mock.lockCheckPayBand.Unlock()
+	return mock.CheckPayBandFunc(ctx, name)
+}
+
+// CheckPayBandCalls gets all the calls that were made to CheckPayBand.
+// Check the length with:
+//
+//	len(mockedSalaryBatch.CheckPayBandCalls())
+func (mock *SalaryBatchMock) CheckPayBandCalls() []struct {
+	Ctx  context.Context
+	Name string
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Name string
+	}
+	mock.lockCheckPayBand.RLock()
+	calls = mock.calls.CheckPayBand
+	mock.lockCheckPayBand.RUnlock()
+	return calls
+}
+
+// Commit calls CommitFunc.
+func (mock *SalaryBatchMock) Commit() error {
+	if mock.CommitFunc == nil {
+		panic("SalaryBatchMock.CommitFunc: method is nil but SalaryBatch.Commit was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockCommit.Lock()
+	mock.calls.Commit = append(mock.calls.Commit, callInfo)
+	mock.lockCommit.Unlock()
+	return mock.CommitFunc()
+}
+
+// CommitCalls gets all the calls that were made to Commit.
+// Check the length with:
+//
+//	len(mockedSalaryBatch.CommitCalls())
+func (mock *SalaryBatchMock) CommitCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockCommit.RLock()
+	calls = mock.calls.Commit
+	mock.lockCommit.RUnlock()
+	return calls
+}
+
+// Delete calls DeleteFunc.
+func (mock *SalaryBatchMock) Delete(ctx context.Context, salaryID int64) error {
+	if mock.DeleteFunc == nil {
+		panic("SalaryBatchMock.DeleteFunc: method is nil but SalaryBatch.Delete was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		SalaryID int64
+	}{
+		Ctx:      ctx,
+		SalaryID: salaryID,
+	}
+	mock.lockDelete.Lock()
+	mock.calls.Delete = append(mock.calls.Delete, callInfo)
+	mock.lockDelete.Unlock()
+	return mock.DeleteFunc(ctx, salaryID)
+}
+
+// DeleteCalls gets all the calls that were made to Delete.
+// Check the length with:
+//
+//	len(mockedSalaryBatch.DeleteCalls())
+func (mock *SalaryBatchMock) DeleteCalls() []struct {
+	Ctx      context.Context
+	SalaryID int64
+} {
+	var calls []struct {
+		Ctx      context.Context
+		SalaryID int64
+	}
+	mock.lockDelete.RLock()
+	calls = mock.calls.Delete
+	mock.lockDelete.RUnlock()
+	return calls
+}
+
+// Rollback calls RollbackFunc.
+func (mock *SalaryBatchMock) Rollback() error {
+	if mock.RollbackFunc == nil {
+		panic("SalaryBatchMock.RollbackFunc: method is nil but SalaryBatch.Rollback was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockRollback.Lock()
+	mock.calls.Rollback = append(mock.calls.Rollback, callInfo)
+	mock.lockRollback.Unlock()
+	return mock.RollbackFunc()
+}
+
+// RollbackCalls gets all the calls that were made to Rollback.
+// Check the length with:
+//
+//	len(mockedSalaryBatch.RollbackCalls())
+func (mock *SalaryBatchMock) RollbackCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockRollback.RLock()
+	calls = mock.calls.Rollback
+	mock.lockRollback.RUnlock()
+	return calls
+}
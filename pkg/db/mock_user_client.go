@@ -0,0 +1,188 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package db
+
+import (
+	"context"
+	"salaries/pkg/domain"
+	"sync"
+)
+
+// Ensure, that DataBaseUserClientMock does implement DataBaseUserClient.
+// If this is not the case, regenerate this file with moq.
+var _ DataBaseUserClient = &DataBaseUserClientMock{}
+
+// DataBaseUserClientMock is a mock implementation of DataBaseUserClient.
+//
+//	func TestSomethingThatUsesDataBaseUserClient(t *testing.T) {
+//
+//		// make and configure a mocked DataBaseUserClient
+//		mockedDataBaseUserClient := &DataBaseUserClientMock{
+//			CreateFunc: func(ctx context.Context, user *domain.User) (*domain.User, error) {
+//				panic("mock out the Create method")
+//			},
+//			ReadByUsernameFunc: func(ctx context.Context, username string) (*domain.User, error) {
+//				panic("mock out the ReadByUsername method")
+//			},
+//			UpdatePasswordHashFunc: func(ctx context.Context, username string, passwordHash string) error {
+//				panic("mock out the UpdatePasswordHash method")
+//			},
+//		}
+//
+//		// use mockedDataBaseUserClient in code that requires DataBaseUserClient
+//		// and then make assertions.
+//
+//	}
+type DataBaseUserClientMock struct {
+	// CreateFunc mocks the Create method.
+	CreateFunc func(ctx context.Context, user *domain.User) (*domain.User, error)
+
+	// ReadByUsernameFunc mocks the ReadByUsername method.
+	ReadByUsernameFunc func(ctx context.Context, username string) (*domain.User, error)
+
+	// UpdatePasswordHashFunc mocks the UpdatePasswordHash method.
+	UpdatePasswordHashFunc func(ctx context.Context, username string, passwordHash string) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Create holds details about calls to the Create method.
+		Create []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// User is the user argument value.
+			User *domain.User
+		}
+		// ReadByUsername holds details about calls to the ReadByUsername method.
+		ReadByUsername []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+		}
+		// UpdatePasswordHash holds details about calls to the UpdatePasswordHash method.
+		UpdatePasswordHash []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+			// PasswordHash is the passwordHash argument value.
+			PasswordHash string
+		}
+	}
+	lockCreate             sync.RWMutex
+	lockReadByUsername     sync.RWMutex
+	lockUpdatePasswordHash sync.RWMutex
+}
+
+// Create calls CreateFunc.
+func (mock *DataBaseUserClientMock) Create(ctx context.Context, user *domain.User) (*domain.User, error) {
+	if mock.CreateFunc == nil {
+		panic("DataBaseUserClientMock.CreateFunc: method is nil but DataBaseUserClient.Create was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		User *domain.User
+	}{
+		Ctx:  ctx,
+		User: user,
+	}
+	mock.lockCreate.Lock()
+	mock.calls.Create = append(mock.calls.Create, callInfo)
+	mock.lockCreate.Unlock()
+	return mock.CreateFunc(ctx, user)
+}
+
+// CreateCalls gets all the calls that were made to Create.
+// Check the length with:
+//
+//	len(mockedDataBaseUserClient.CreateCalls())
+func (mock *DataBaseUserClientMock) CreateCalls() []struct {
+	Ctx  context.Context
+	User *domain.User
+} {
+	var calls []struct {
+		Ctx  context.Context
+		User *domain.User
+	}
+	mock.lockCreate.RLock()
+	calls = mock.calls.Create
+	mock.lockCreate.RUnlock()
+	return calls
+}
+
+// ReadByUsername calls ReadByUsernameFunc.
+func (mock *DataBaseUserClientMock) ReadByUsername(ctx context.Context, username string) (*domain.User, error) {
+	if mock.ReadByUsernameFunc == nil {
+		panic("DataBaseUserClientMock.ReadByUsernameFunc: method is nil but DataBaseUserClient.ReadByUsername was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+	}{
+		Ctx:      ctx,
+		Username: username,
+	}
+	mock.lockReadByUsername.Lock()
+	mock.calls.ReadByUsername = append(mock.calls.ReadByUsername, callInfo)
+	mock.lockReadByUsername.Unlock()
+	return mock.ReadByUsernameFunc(ctx, username)
+}
+
+// ReadByUsernameCalls gets all the calls that were made to ReadByUsername.
+// Check the length with:
+//
+//	len(mockedDataBaseUserClient.ReadByUsernameCalls())
+func (mock *DataBaseUserClientMock) ReadByUsernameCalls() []struct {
+	Ctx      context.Context
+	Username string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+	}
+	mock.lockReadByUsername.RLock()
+	calls = mock.calls.ReadByUsername
+	mock.lockReadByUsername.RUnlock()
+	return calls
+}
+
+// UpdatePasswordHash calls UpdatePasswordHashFunc.
+func (mock *DataBaseUserClientMock) UpdatePasswordHash(ctx context.Context, username string, passwordHash string) error {
+	if mock.UpdatePasswordHashFunc == nil {
+		panic("DataBaseUserClientMock.UpdatePasswordHashFunc: method is nil but DataBaseUserClient.UpdatePasswordHash was just called")
+	}
+	callInfo := struct {
+		Ctx          context.Context
+		Username     string
+		PasswordHash string
+	}{
+		Ctx:          ctx,
+		Username:     username,
+		PasswordHash: passwordHash,
+	}
+	mock.lockUpdatePasswordHash.Lock()
+	mock.calls.UpdatePasswordHash = append(mock.calls.UpdatePasswordHash, callInfo)
+	mock.lockUpdatePasswordHash.Unlock()
+	return mock.UpdatePasswordHashFunc(ctx, username, passwordHash)
+}
+
+// UpdatePasswordHashCalls gets all the calls that were made to UpdatePasswordHash.
+// Check the length with:
+//
+//	len(mockedDataBaseUserClient.UpdatePasswordHashCalls())
+func (mock *DataBaseUserClientMock) UpdatePasswordHashCalls() []struct {
+	Ctx          context.Context
+	Username     string
+	PasswordHash string
+} {
+	var calls []struct {
+		Ctx          context.Context
+		Username     string
+		PasswordHash string
+	}
+	mock.lockUpdatePasswordHash.RLock()
+	calls = mock.calls.UpdatePasswordHash
+	mock.lockUpdatePasswordHash.RUnlock()
+	return calls
+}
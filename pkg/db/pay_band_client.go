@@ -0,0 +1,271 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"salaries/pkg/api"
+	"salaries/pkg/domain"
+	"salaries/pkg/tracing"
+	"strings"
+)
+
+const (
+	payBandTable   = "pay_bands"
+	payBandColumns = "id, name, currency, min, mid, max, effective_from, effective_to"
+)
+
+type DataBasePayBandClient interface {
+	Create(ctx context.Context, band *domain.PayBand) (*domain.PayBand, error)
+	ReadAll(ctx context.Context, filter api.PayBandFilter) ([]domain.PayBand, error)
+	ReadByID(ctx context.Context, bandID int64) (*domain.PayBand, error)
+	Update(ctx context.Context, band *domain.PayBand) (*domain.PayBand, error)
+	DeleteByID(ctx context.Context, bandID int64) error
+}
+
+func NewSqlitePayBandClient(client *sql.DB) DataBasePayBandClient {
+	return &dataBasePayBandClientImpl{
+		client: client,
+	}
+}
+
+type dataBasePayBandClientImpl struct {
+	client *sql.DB
+}
+
+// Create inserts the band, api.ErrConflict is returned when a band with the same name and currency is effective in the same period
+func (d dataBasePayBandClientImpl) Create(ctx context.Context, band *domain.PayBand) (*domain.PayBand, error) {
+	ctx, span := startSpan(ctx, payBandTable, "insert_pay_band", "INSERT")
+	defer span.End()
+
+	tx, err := d.client.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	defer tx.Rollback()
+
+	if err := checkPayBandOverlap(ctx, tx, band); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	result, err := tx.ExecContext(ctx, "INSERT INTO pay_bands (name, currency, min, mid, max, effective_from, effective_to) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		band.Name, band.Currency, band.Min, band.Mid, band.Max, band.EffectiveFrom.UTC(), effectiveTo(band))
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	if band.ID, err = result.LastInsertId(); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	if err := insertAuditEvent(ctx, tx, domain.AuditActionCreate, domain.AuditEntityPayBand, band.ID, nil, band); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	return band, nil
+}
+
+// ReadAll returns the bands matching the filter ordered by name, currency and effective date
+func (d dataBasePayBandClientImpl) ReadAll(ctx context.Context, filter api.PayBandFilter) ([]domain.PayBand, error) {
+	ctx, span := startSpan(ctx, payBandTable, "select_pay_bands", "SELECT")
+	defer span.End()
+
+	where, args := payBandFilterClause(filter)
+	rows, err := d.client.QueryContext(ctx, "SELECT "+payBandColumns+" FROM pay_bands"+where+" ORDER BY name, currency, effective_from, id", args...)
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	defer rows.Close()
+	bands := []domain.PayBand{}
+	for rows.Next() {
+		band, err := scanPayBand(rows)
+		if err != nil {
+			return nil, tracing.RecordError(span, err)
+		}
+		bands = append(bands, *band)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	return bands, nil
+}
+
+func (d dataBasePayBandClientImpl) ReadByID(ctx context.Context, bandID int64) (*domain.PayBand, error) {
+	ctx, span := startSpan(ctx, payBandTable, "select_pay_band", "SELECT")
+	defer span.End()
+
+	band, err := scanPayBand(d.client.QueryRowContext(ctx, "SELECT "+payBandColumns+" FROM pay_bands WHERE id = ?", bandID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, tracing.RecordError(span, api.ErrNotFound)
+	}
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	return band, nil
+}
+
+// Update replaces the band, renaming the last band of a name still assigned to salaries returns api.ErrConflict
+func (d dataBasePayBandClientImpl) Update(ctx context.Context, band *domain.PayBand) (*domain.PayBand, error) {
+	ctx, span := startSpan(ctx, payBandTable, "update_pay_band", "UPDATE")
+	defer span.End()
+
+	tx, err := d.client.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	defer tx.Rollback()
+
+	before, err := selectPayBand(ctx, tx, band.ID)
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	if before.Name != band.Name {
+		if err := checkPayBandUnassigned(ctx, tx, before); err != nil {
+			return nil, tracing.RecordError(span, err)
+		}
+	}
+	if err := checkPayBandOverlap(ctx, tx, band); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	_, err = tx.ExecContext(ctx, "UPDATE pay_bands SET name = ?, currency = ?, min = ?, mid = ?, max = ?, effective_from = ?, effective_to = ? WHERE id = ?",
+		band.Name, band.Currency, band.Min, band.Mid, band.Max, band.EffectiveFrom.UTC(), effectiveTo(band), band.ID)
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	if err := insertAuditEvent(ctx, tx, domain.AuditActionUpdate, domain.AuditEntityPayBand, band.ID, before, band); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	return band, nil
+}
+
+// DeleteByID deletes the band, deleting the last band of a name still assigned to salaries returns api.ErrConflict
+func (d dataBasePayBandClientImpl) DeleteByID(ctx context.Context, bandID int64) error {
+	ctx, span := startSpan(ctx, payBandTable, "delete_pay_band", "DELETE")
+	defer span.End()
+
+	tx, err := d.client.BeginTx(ctx, nil)
+	if err != nil {
+		return tracing.RecordError(span, err)
+	}
+	defer tx.Rollback()
+
+	before, err := selectPayBand(ctx, tx, bandID)
+	if err != nil {
+		return tracing.RecordError(span, err)
+	}
+	if err := checkPayBandUnassigned(ctx, tx, before); err != nil {
+		return tracing.RecordError(span, err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM pay_bands WHERE id = ?", bandID); err != nil {
+		return tracing.RecordError(span, err)
+	}
+	if err := insertAuditEvent(ctx, tx, domain.AuditActionDelete, domain.AuditEntityPayBand, bandID, before, nil); err != nil {
+		return tracing.RecordError(span, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return tracing.RecordError(span, err)
+	}
+	return nil
+}
+
+// checkPayBand fails with api.ErrUnknownPayBand when a salary is assigned a band name that does not exist
+func checkPayBand(ctx context.Context, tx *sql.Tx, name string) error {
+	if name == "" {
+		return nil
+	}
+	var bands int
+	if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM pay_bands WHERE name = ?", name).Scan(&bands); err != nil {
+		return err
+	}
+	if bands == 0 {
+		return api.ErrUnknownPayBand
+	}
+	return nil
+}
+
+// checkPayBandOverlap fails with api.ErrConflict when another band with the name and currency is effective during the band
+func checkPayBandOverlap(ctx context.Context, tx *sql.Tx, band *domain.PayBand) error {
+	query := "SELECT COUNT(*) FROM pay_bands WHERE name = ? AND currency = ? AND id != ? AND (effective_to IS NULL OR effective_to > ?)"
+	args := []interface{}{band.Name, band.Currency, band.ID, band.EffectiveFrom.UTC()}
+	if band.EffectiveTo != nil {
+		query += " AND effective_from < ?"
+		args = append(args, band.EffectiveTo.UTC())
+	}
+	var overlapping int
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&overlapping); err != nil {
+		return err
+	}
+	if overlapping > 0 {
+		return api.ErrConflict
+	}
+	return nil
+}
+
+// checkPayBandUnassigned fails with api.ErrConflict when the band is the last one of its name and salaries, deleted ones included, are assigned to it
+func checkPayBandUnassigned(ctx context.Context, tx *sql.Tx, band *domain.PayBand) error {
+	var others, salaries int
+	if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM pay_bands WHERE name = ? AND id != ?", band.Name, band.ID).Scan(&others); err != nil {
+		return err
+	}
+	if others > 0 {
+		return nil
+	}
+	if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM salaries WHERE pay_band = ?", band.Name).Scan(&salaries); err != nil {
+		return err
+	}
+	if salaries > 0 {
+		return api.ErrConflict
+	}
+	return nil
+}
+
+func payBandFilterClause(filter api.PayBandFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	if filter.Name != "" {
+		conditions = append(conditions, "name = ?")
+		args = append(args, filter.Name)
+	}
+	if filter.Currency != "" {
+		conditions = append(conditions, "currency = ?")
+		args = append(args, filter.Currency)
+	}
+	if !filter.AsOf.IsZero() {
+		conditions = append(conditions, "effective_from <= ? AND (effective_to IS NULL OR effective_to > ?)")
+		args = append(args, filter.AsOf.UTC(), filter.AsOf.UTC())
+	}
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func selectPayBand(ctx context.Context, tx *sql.Tx, bandID int64) (*domain.PayBand, error) {
+	band, err := scanPayBand(tx.QueryRowContext(ctx, "SELECT "+payBandColumns+" FROM pay_bands WHERE id = ?", bandID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, api.ErrNotFound
+	}
+	return band, err
+}
+
+func scanPayBand(row rowScanner) (*domain.PayBand, error) {
+	var band domain.PayBand
+	var to sql.NullTime
+	err := row.Scan(&band.ID, &band.Name, &band.Currency, &band.Min, &band.Mid, &band.Max, &band.EffectiveFrom, &to)
+	if err != nil {
+		return nil, err
+	}
+	if to.Valid {
+		band.EffectiveTo = &to.Time
+	}
+	return &band, nil
+}
+
+func effectiveTo(band *domain.PayBand) interface{} {
+	if band.EffectiveTo == nil {
+		return nil
+	}
+	return band.EffectiveTo.UTC()
+}
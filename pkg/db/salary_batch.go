@@ -0,0 +1,93 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"salaries/pkg/domain"
+	"salaries/pkg/tracing"
+)
+
+// SalaryBatch inserts and deletes salaries in a single transaction, every change is audited like Create and DeleteByID
+type SalaryBatch interface {
+	Add(ctx context.Context, salary *domain.Salary) error
+	CheckPayBand(ctx context.Context, name string) error
+	Delete(ctx context.Context, salaryID int64) error
+	Commit() error
+	Rollback() error
+}
+
+type salaryBatchImpl struct {
+	tx        *sql.Tx
+	statement *sql.Stmt
+	span      trace.Span
+	rows      int
+	done      bool
+}
+
+// Add returns api.ErrUnknownPayBand without writing anything when the pay band does not exist, so the batch can go on
+func (b *salaryBatchImpl) Add(ctx context.Context, salary *domain.Salary) error {
+	if err := checkPayBand(ctx, b.tx, salary.PayBand); err != nil {
+		return tracing.RecordError(b.span, err)
+	}
+	result, err := b.statement.ExecContext(ctx,
+		salary.Name, salary.Salary, salary.Currency, salary.OnContract, salary.Department, salary.SubDepartment, salary.PayBand)
+	if err != nil {
+		return tracing.RecordError(b.span, err)
+	}
+	if err := auditCreatedSalary(ctx, b.tx, result, salary); err != nil {
+		return tracing.RecordError(b.span, err)
+	}
+	b.rows++
+	return nil
+}
+
+// CheckPayBand returns api.ErrUnknownPayBand when no pay band has the name, like Add without writing the salary
+func (b *salaryBatchImpl) CheckPayBand(ctx context.Context, name string) error {
+	if err := checkPayBand(ctx, b.tx, name); err != nil {
+		return tracing.RecordError(b.span, err)
+	}
+	return nil
+}
+
+// Delete returns api.ErrNotFound without writing anything when the salary does not exist, so the batch can go on
+func (b *salaryBatchImpl) Delete(ctx context.Context, salaryID int64) error {
+	before, err := selectSalary(ctx, b.tx, salaryID)
+	if err != nil {
+		return tracing.RecordError(b.span, err)
+	}
+	if err := deleteSalary(ctx, b.tx, before); err != nil {
+		return tracing.RecordError(b.span, err)
+	}
+	b.rows++
+	return nil
+}
+
+func (b *salaryBatchImpl) Commit() error {
+	if b.done {
+		return sql.ErrTxDone
+	}
+	defer b.end()
+	b.statement.Close()
+	if err := b.tx.Commit(); err != nil {
+		return tracing.RecordError(b.span, err)
+	}
+	return nil
+}
+
+// Rollback discards the batch, it is a no-op once the batch was committed so it can be deferred
+func (b *salaryBatchImpl) Rollback() error {
+	if b.done {
+		return nil
+	}
+	defer b.end()
+	b.statement.Close()
+	return b.tx.Rollback()
+}
+
+func (b *salaryBatchImpl) end() {
+	b.done = true
+	b.span.SetAttributes(attribute.Int("db.rows", b.rows))
+	b.span.End()
+}
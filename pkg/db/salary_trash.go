@@ -0,0 +1,133 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"go.opentelemetry.io/otel/attribute"
+	"salaries/pkg/api"
+	"salaries/pkg/domain"
+	"salaries/pkg/tracing"
+	"time"
+)
+
+const deletedSalaryColumns = salaryColumns + ", deleted_at, deleted_by"
+
+// ReadTrash returns the soft deleted salaries, the most recently deleted first
+func (d dataBaseClientImpl) ReadTrash(ctx context.Context) ([]domain.DeletedSalary, error) {
+	ctx, span := startSpan(ctx, salaryTable, "select_deleted_salaries", "SELECT")
+	defer span.End()
+
+	rows, err := d.client.QueryContext(ctx, "SELECT "+deletedSalaryColumns+" FROM salaries WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC, id DESC")
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	defer rows.Close()
+	salaries := []domain.DeletedSalary{}
+	for rows.Next() {
+		salary, err := scanDeletedSalary(rows)
+		if err != nil {
+			return nil, tracing.RecordError(span, err)
+		}
+		salaries = append(salaries, *salary)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	return salaries, nil
+}
+
+// Restore takes the salary out of the trash, api.ErrNotFound is returned when it is not in the trash
+func (d dataBaseClientImpl) Restore(ctx context.Context, salaryID int64) (*domain.Salary, error) {
+	ctx, span := startSpan(ctx, salaryTable, "restore_salary", "UPDATE")
+	defer span.End()
+
+	tx, err := d.client.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	defer tx.Rollback()
+
+	deleted, err := scanDeletedSalary(tx.QueryRowContext(ctx, "SELECT "+deletedSalaryColumns+" FROM salaries WHERE id = ? AND deleted_at IS NOT NULL", salaryID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, tracing.RecordError(span, api.ErrNotFound)
+	}
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	result, err := tx.ExecContext(ctx, "UPDATE salaries SET deleted_at = NULL, deleted_by = NULL, version = version + 1 WHERE id = ? AND version = ?", salaryID, deleted.Version)
+	if err := checkVersionUpdated(result, err); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	restored := deleted.Salary
+	restored.Version++
+	if err := insertAuditEvent(ctx, tx, domain.AuditActionRestore, domain.AuditEntitySalary, salaryID, deleted, &restored); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	return &restored, nil
+}
+
+// Purge permanently removes the salaries deleted before the given time with their demographics and planned changes, each removal is audited with the last state of the salary
+func (d dataBaseClientImpl) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	ctx, span := startSpan(ctx, salaryTable, "purge_salaries", "DELETE")
+	defer span.End()
+
+	tx, err := d.client.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, tracing.RecordError(span, err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, "SELECT "+deletedSalaryColumns+" FROM salaries WHERE deleted_at < ? ORDER BY id", deletedBefore.UTC())
+	if err != nil {
+		return 0, tracing.RecordError(span, err)
+	}
+	var purged []domain.DeletedSalary
+	for rows.Next() {
+		salary, err := scanDeletedSalary(rows)
+		if err != nil {
+			rows.Close()
+			return 0, tracing.RecordError(span, err)
+		}
+		purged = append(purged, *salary)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, tracing.RecordError(span, err)
+	}
+
+	for i := range purged {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM salaries WHERE id = ?", purged[i].ID); err != nil {
+			return 0, tracing.RecordError(span, err)
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM salary_demographics WHERE salary_id = ?", purged[i].ID); err != nil {
+			return 0, tracing.RecordError(span, err)
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM payroll_changes WHERE salary_id = ?", purged[i].ID); err != nil {
+			return 0, tracing.RecordError(span, err)
+		}
+		if err := insertAuditEvent(ctx, tx, domain.AuditActionPurge, domain.AuditEntitySalary, purged[i].ID, &purged[i], nil); err != nil {
+			return 0, tracing.RecordError(span, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, tracing.RecordError(span, err)
+	}
+	span.SetAttributes(attribute.Int("db.rows", len(purged)))
+	return int64(len(purged)), nil
+}
+
+func scanDeletedSalary(row rowScanner) (*domain.DeletedSalary, error) {
+	var deleted domain.DeletedSalary
+	var deletedBy sql.NullString
+	salary := &deleted.Salary
+	err := row.Scan(&salary.ID, &salary.Name, &salary.Salary, &salary.Currency, &salary.OnContract, &salary.Department, &salary.SubDepartment, &salary.PayBand, &salary.Version, &deleted.DeletedAt, &deletedBy)
+	if err != nil {
+		return nil, err
+	}
+	deleted.DeletedBy = deletedBy.String
+	return &deleted, nil
+}
@@ -0,0 +1,213 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"salaries/pkg/api"
+	"salaries/pkg/domain"
+	"salaries/pkg/tracing"
+)
+
+const (
+	statsSnapshotTable   = "stats_snapshots"
+	statsSnapshotColumns = "id, taken_at, source"
+	snapshotStatsColumns = "stats_group, department, sub_department, mean, max, min, count"
+)
+
+type DataBaseSnapshotClient interface {
+	CreateSnapshot(ctx context.Context, snapshot *domain.StatsSnapshot) (*domain.StatsSnapshot, error)
+	ReadSnapshots(ctx context.Context, filter api.SnapshotFilter) ([]domain.StatsSnapshot, error)
+	ReadSnapshot(ctx context.Context, snapshotID int64) (*domain.StatsSnapshot, error)
+	ReadLatestSnapshot(ctx context.Context) (*domain.StatsSnapshot, error)
+	ReadHistory(ctx context.Context, query api.StatsHistoryQuery) ([]domain.StatsSnapshot, error)
+}
+
+func NewSqliteSnapshotClient(client *sql.DB) DataBaseSnapshotClient {
+	return &dataBaseSnapshotClientImpl{
+		client: client,
+	}
+}
+
+type dataBaseSnapshotClientImpl struct {
+	client *sql.DB
+}
+
+// CreateSnapshot inserts the snapshot and the stats of all its groups in a single transaction
+func (d dataBaseSnapshotClientImpl) CreateSnapshot(ctx context.Context, snapshot *domain.StatsSnapshot) (*domain.StatsSnapshot, error) {
+	ctx, span := startSpan(ctx, statsSnapshotTable, "insert_stats_snapshot", "INSERT")
+	defer span.End()
+
+	tx, err := d.client.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, "INSERT INTO stats_snapshots (taken_at, source) VALUES (?, ?)", snapshot.TakenAt.UTC(), snapshot.Source)
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	if snapshot.ID, err = result.LastInsertId(); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	statement, err := tx.PrepareContext(ctx, "INSERT INTO snapshot_stats (snapshot_id, "+snapshotStatsColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	defer statement.Close()
+	for _, stats := range snapshot.Stats {
+		_, err := statement.ExecContext(ctx, snapshot.ID, stats.Group, stats.Department, stats.SubDepartment, stats.Mean, stats.Max, stats.Min, stats.Count)
+		if err != nil {
+			return nil, tracing.RecordError(span, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	return snapshot, nil
+}
+
+// ReadSnapshots returns the snapshots matching the filter without their stats, oldest first
+func (d dataBaseSnapshotClientImpl) ReadSnapshots(ctx context.Context, filter api.SnapshotFilter) ([]domain.StatsSnapshot, error) {
+	ctx, span := startSpan(ctx, statsSnapshotTable, "select_stats_snapshots", "SELECT")
+	defer span.End()
+
+	conditions, args := snapshotConditions(filter)
+	rows, err := d.client.QueryContext(ctx, "SELECT "+statsSnapshotColumns+" FROM stats_snapshots"+whereClause(conditions)+" ORDER BY taken_at, id", args...)
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	defer rows.Close()
+	snapshots := []domain.StatsSnapshot{}
+	for rows.Next() {
+		snapshot, err := scanSnapshot(rows)
+		if err != nil {
+			return nil, tracing.RecordError(span, err)
+		}
+		snapshots = append(snapshots, *snapshot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	return snapshots, nil
+}
+
+// ReadSnapshot returns the snapshot with the stats of all its groups
+func (d dataBaseSnapshotClientImpl) ReadSnapshot(ctx context.Context, snapshotID int64) (*domain.StatsSnapshot, error) {
+	ctx, span := startSpan(ctx, statsSnapshotTable, "select_stats_snapshot", "SELECT")
+	defer span.End()
+
+	snapshot, err := scanSnapshot(d.client.QueryRowContext(ctx, "SELECT "+statsSnapshotColumns+" FROM stats_snapshots WHERE id = ?", snapshotID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, tracing.RecordError(span, api.ErrNotFound)
+	}
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	rows, err := d.client.QueryContext(ctx, "SELECT "+snapshotStatsColumns+" FROM snapshot_stats WHERE snapshot_id = ? ORDER BY rowid", snapshotID)
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		stats, err := scanSnapshotStats(rows)
+		if err != nil {
+			return nil, tracing.RecordError(span, err)
+		}
+		snapshot.Stats = append(snapshot.Stats, *stats)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	return snapshot, nil
+}
+
+// ReadLatestSnapshot returns the last snapshot taken without its stats, api.ErrNotFound when none was taken
+func (d dataBaseSnapshotClientImpl) ReadLatestSnapshot(ctx context.Context) (*domain.StatsSnapshot, error) {
+	ctx, span := startSpan(ctx, statsSnapshotTable, "select_latest_stats_snapshot", "SELECT")
+	defer span.End()
+
+	snapshot, err := scanSnapshot(d.client.QueryRowContext(ctx, "SELECT "+statsSnapshotColumns+" FROM stats_snapshots ORDER BY taken_at DESC, id DESC LIMIT 1"))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, tracing.RecordError(span, api.ErrNotFound)
+	}
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	return snapshot, nil
+}
+
+// ReadHistory returns the snapshots matching the query oldest first, each with only the stats of the group of the query.
+// Snapshots without any stats of the group are left out
+func (d dataBaseSnapshotClientImpl) ReadHistory(ctx context.Context, query api.StatsHistoryQuery) ([]domain.StatsSnapshot, error) {
+	ctx, span := startSpan(ctx, statsSnapshotTable, "select_stats_history", "SELECT")
+	defer span.End()
+
+	conditions, args := snapshotConditions(query.SnapshotFilter)
+	conditions = append(conditions, "stats_group = ?")
+	args = append(args, query.Group)
+	if query.Department != "" {
+		conditions = append(conditions, "department = ?")
+		args = append(args, query.Department)
+	}
+	if query.SubDepartment != "" {
+		conditions = append(conditions, "sub_department = ?")
+		args = append(args, query.SubDepartment)
+	}
+	rows, err := d.client.QueryContext(ctx, "SELECT "+statsSnapshotColumns+", "+snapshotStatsColumns+" FROM stats_snapshots JOIN snapshot_stats ON snapshot_stats.snapshot_id = stats_snapshots.id"+
+		whereClause(conditions)+" ORDER BY taken_at, id, snapshot_stats.rowid", args...)
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	defer rows.Close()
+	snapshots := []domain.StatsSnapshot{}
+	for rows.Next() {
+		var snapshot domain.StatsSnapshot
+		var stats domain.SnapshotStats
+		err := rows.Scan(&snapshot.ID, &snapshot.TakenAt, &snapshot.Source,
+			&stats.Group, &stats.Department, &stats.SubDepartment, &stats.Mean, &stats.Max, &stats.Min, &stats.Count)
+		if err != nil {
+			return nil, tracing.RecordError(span, err)
+		}
+		if last := len(snapshots) - 1; last < 0 || snapshots[last].ID != snapshot.ID {
+			snapshots = append(snapshots, snapshot)
+		}
+		last := &snapshots[len(snapshots)-1]
+		last.Stats = append(last.Stats, stats)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	return snapshots, nil
+}
+
+func snapshotConditions(filter api.SnapshotFilter) ([]string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "taken_at >= ?")
+		args = append(args, filter.From.UTC())
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "taken_at < ?")
+		args = append(args, filter.To.UTC())
+	}
+	return conditions, args
+}
+
+func scanSnapshot(row rowScanner) (*domain.StatsSnapshot, error) {
+	var snapshot domain.StatsSnapshot
+	if err := row.Scan(&snapshot.ID, &snapshot.TakenAt, &snapshot.Source); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+func scanSnapshotStats(row rowScanner) (*domain.SnapshotStats, error) {
+	var stats domain.SnapshotStats
+	if err := row.Scan(&stats.Group, &stats.Department, &stats.SubDepartment, &stats.Mean, &stats.Max, &stats.Min, &stats.Count); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
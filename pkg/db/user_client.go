@@ -0,0 +1,110 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"salaries/pkg/api"
+	"salaries/pkg/domain"
+	"salaries/pkg/tracing"
+	"time"
+)
+
+const userTable = "users"
+
+type DataBaseUserClient interface {
+	Create(ctx context.Context, user *domain.User) (*domain.User, error)
+	ReadByUsername(ctx context.Context, username string) (*domain.User, error)
+	UpdatePasswordHash(ctx context.Context, username, passwordHash string) error
+}
+
+func NewSqliteUserClient(client *sql.DB) DataBaseUserClient {
+	return &dataBaseUserClientImpl{
+		client: client,
+	}
+}
+
+type dataBaseUserClientImpl struct {
+	client *sql.DB
+}
+
+// Create inserts the user and its audit event, api.ErrConflict is returned when the username is taken
+func (d dataBaseUserClientImpl) Create(ctx context.Context, user *domain.User) (*domain.User, error) {
+	ctx, span := startSpan(ctx, userTable, "insert_user", "INSERT")
+	defer span.End()
+
+	tx, err := d.client.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	result, err := tx.ExecContext(ctx, "INSERT INTO users (username, password_hash, role, created_at, updated_at) VALUES (?, ?, ?, ?, ?)",
+		user.Username, user.PasswordHash, user.Role, now, now)
+	if isUniqueViolation(err) {
+		return nil, tracing.RecordError(span, api.ErrConflict)
+	}
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	user.ID, err = result.LastInsertId()
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	if err := insertAuditEvent(ctx, tx, domain.AuditActionCreate, domain.AuditEntityUser, user.ID, nil, user); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	return user, nil
+}
+
+func (d dataBaseUserClientImpl) ReadByUsername(ctx context.Context, username string) (*domain.User, error) {
+	ctx, span := startSpan(ctx, userTable, "select_user", "SELECT")
+	defer span.End()
+
+	var user domain.User
+	err := d.client.QueryRowContext(ctx, "SELECT id, username, password_hash, role FROM users WHERE username = ?", username).
+		Scan(&user.ID, &user.Username, &user.PasswordHash, &user.Role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, api.ErrNotFound
+	}
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	return &user, nil
+}
+
+// UpdatePasswordHash replaces the password of the user, the audit event records the change but never the hashes
+func (d dataBaseUserClientImpl) UpdatePasswordHash(ctx context.Context, username, passwordHash string) error {
+	ctx, span := startSpan(ctx, userTable, "update_user_password", "UPDATE")
+	defer span.End()
+
+	tx, err := d.client.BeginTx(ctx, nil)
+	if err != nil {
+		return tracing.RecordError(span, err)
+	}
+	defer tx.Rollback()
+
+	var user domain.User
+	err = tx.QueryRowContext(ctx, "SELECT id, username, role FROM users WHERE username = ?", username).Scan(&user.ID, &user.Username, &user.Role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return tracing.RecordError(span, api.ErrNotFound)
+	}
+	if err != nil {
+		return tracing.RecordError(span, err)
+	}
+	_, err = tx.ExecContext(ctx, "UPDATE users SET password_hash = ?, updated_at = ? WHERE id = ?", passwordHash, time.Now().UTC(), user.ID)
+	if err != nil {
+		return tracing.RecordError(span, err)
+	}
+	if err := insertAuditEvent(ctx, tx, domain.AuditActionUpdate, domain.AuditEntityUser, user.ID, &user, &user); err != nil {
+		return tracing.RecordError(span, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return tracing.RecordError(span, err)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+// Package distribution describes the shape of a set of amounts: histograms, quantiles, outliers and ranks.
+package distribution
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+const (
+	StrategyFixedWidth = "fixed_width"
+	StrategyFixedCount = "fixed_count"
+	StrategyEdges      = "edges"
+	StrategyLog        = "log"
+)
+
+var ErrInvalidBuckets = errors.New("invalid histogram buckets")
+
+// Buckets describes how the edges of a histogram are chosen, Width is used by fixed_width, Count by fixed_count and log
+// and Edges by edges
+type Buckets struct {
+	Strategy string
+	Width    float64
+	Count    int
+	Edges    []float64
+}
+
+// EdgesFor returns the bucket edges for the values, bucket i holds the values from edges[i] up to edges[i+1] excluded,
+// the last bucket includes its upper edge. Computed edges cover every value, no more than maxBuckets buckets are returned
+func (b Buckets) EdgesFor(values []float64, maxBuckets int) ([]float64, error) {
+	if b.Strategy == StrategyEdges {
+		return explicitEdges(b.Edges, maxBuckets)
+	}
+	if len(values) == 0 {
+		return []float64{}, nil
+	}
+	min, max := values[0], values[0]
+	for _, value := range values {
+		min = math.Min(min, value)
+		max = math.Max(max, value)
+	}
+
+	switch b.Strategy {
+	case StrategyFixedWidth:
+		if !(b.Width > 0) || math.IsInf(b.Width, 1) {
+			return nil, fmt.Errorf("%w: width must be positive", ErrInvalidBuckets)
+		}
+		start := math.Floor(min/b.Width) * b.Width
+		// counted as a float as a tiny width needs more buckets than an int holds
+		buckets := math.Floor((max-start)/b.Width) + 1
+		if math.IsNaN(buckets) || math.IsInf(buckets, 0) || buckets > float64(maxBuckets) {
+			return nil, fmt.Errorf("%w: width %g needs %g buckets, at most %d are allowed", ErrInvalidBuckets, b.Width, buckets, maxBuckets)
+		}
+		count := int(buckets)
+		edges := make([]float64, count+1)
+		for i := range edges {
+			edges[i] = start + float64(i)*b.Width
+		}
+		return edges, nil
+	case StrategyFixedCount:
+		if err := checkCount(b.Count, maxBuckets); err != nil {
+			return nil, err
+		}
+		if max == min {
+			max = min + 1
+		}
+		edges := make([]float64, b.Count+1)
+		for i := range edges {
+			edges[i] = min + (max-min)*float64(i)/float64(b.Count)
+		}
+		edges[b.Count] = max
+		return edges, nil
+	case StrategyLog:
+		if err := checkCount(b.Count, maxBuckets); err != nil {
+			return nil, err
+		}
+		if min <= 0 {
+			return nil, fmt.Errorf("%w: log buckets need positive amounts", ErrInvalidBuckets)
+		}
+		if max == min {
+			max = min * 10
+		}
+		edges := make([]float64, b.Count+1)
+		for i := range edges {
+			edges[i] = min * math.Pow(max/min, float64(i)/float64(b.Count))
+		}
+		edges[0], edges[b.Count] = min, max
+		return edges, nil
+	}
+	return nil, fmt.Errorf("%w: unknown strategy %q", ErrInvalidBuckets, b.Strategy)
+}
+
+func checkCount(count, maxBuckets int) error {
+	if count <= 0 || count > maxBuckets {
+		return fmt.Errorf("%w: the number of buckets must be between 1 and %d", ErrInvalidBuckets, maxBuckets)
+	}
+	return nil
+}
+
+func explicitEdges(edges []float64, maxBuckets int) ([]float64, error) {
+	if len(edges) < 2 || len(edges)-1 > maxBuckets {
+		return nil, fmt.Errorf("%w: between 2 and %d edges are needed", ErrInvalidBuckets, maxBuckets+1)
+	}
+	for i := 1; i < len(edges); i++ {
+		if edges[i] <= edges[i-1] {
+			return nil, fmt.Errorf("%w: edges must be increasing", ErrInvalidBuckets)
+		}
+	}
+	return edges, nil
+}
+
+// Counts is the number of values in each bucket, values outside the edges are counted in Underflow and Overflow
+type Counts struct {
+	Buckets   []int64
+	Underflow int64
+	Overflow  int64
+}
+
+// Count places the values in the buckets delimited by edges
+func Count(edges []float64, values []float64) Counts {
+	counts := Counts{Buckets: make([]int64, 0)}
+	if len(edges) < 2 {
+		counts.Overflow = int64(len(values))
+		return counts
+	}
+	counts.Buckets = make([]int64, len(edges)-1)
+	last := len(edges) - 1
+	for _, value := range values {
+		switch {
+		case value < edges[0]:
+			counts.Underflow++
+		case value > edges[last]:
+			counts.Overflow++
+		case value == edges[last]:
+			counts.Buckets[last-1]++
+		default:
+			// the first edge greater than the value closes its bucket
+			counts.Buckets[sort.Search(len(edges), func(i int) bool { return edges[i] > value })-1]++
+		}
+	}
+	return counts
+}
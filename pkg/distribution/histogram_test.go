@@ -0,0 +1,110 @@
+package distribution_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math"
+	"salaries/pkg/distribution"
+	"testing"
+)
+
+func TestBuckets_EdgesFor(t *testing.T) {
+	values := []float64{15000, 40000, 90000, 100000}
+	tests := []struct {
+		name    string
+		buckets distribution.Buckets
+		values  []float64
+		want    []float64
+		wantErr bool
+	}{
+		{
+			name:    "fixed width starts at a multiple of the width",
+			buckets: distribution.Buckets{Strategy: distribution.StrategyFixedWidth, Width: 25000},
+			values:  values,
+			want:    []float64{0, 25000, 50000, 75000, 100000, 125000},
+		},
+		{
+			name:    "fixed count spans the values",
+			buckets: distribution.Buckets{Strategy: distribution.StrategyFixedCount, Count: 2},
+			values:  values,
+			want:    []float64{15000, 57500, 100000},
+		},
+		{
+			name:    "fixed count with a single value",
+			buckets: distribution.Buckets{Strategy: distribution.StrategyFixedCount, Count: 1},
+			values:  []float64{100},
+			want:    []float64{100, 101},
+		},
+		{
+			name:    "log",
+			buckets: distribution.Buckets{Strategy: distribution.StrategyLog, Count: 2},
+			values:  []float64{100, 1000, 10000},
+			want:    []float64{100, 1000, 10000},
+		},
+		{
+			name:    "explicit edges",
+			buckets: distribution.Buckets{Strategy: distribution.StrategyEdges, Edges: []float64{0, 50000}},
+			values:  values,
+			want:    []float64{0, 50000},
+		},
+		{
+			name:    "no values",
+			buckets: distribution.Buckets{Strategy: distribution.StrategyFixedCount, Count: 10},
+			want:    []float64{},
+		},
+		{
+			name:    "log needs positive amounts",
+			buckets: distribution.Buckets{Strategy: distribution.StrategyLog, Count: 2},
+			values:  []float64{0, 100},
+			wantErr: true,
+		},
+		{
+			name:    "too many buckets",
+			buckets: distribution.Buckets{Strategy: distribution.StrategyFixedWidth, Width: 1},
+			values:  values,
+			wantErr: true,
+		},
+		{
+			name:    "tiny width",
+			buckets: distribution.Buckets{Strategy: distribution.StrategyFixedWidth, Width: 1e-300},
+			values:  []float64{1000, 2000},
+			wantErr: true,
+		},
+		{
+			name:    "infinite width",
+			buckets: distribution.Buckets{Strategy: distribution.StrategyFixedWidth, Width: math.Inf(1)},
+			values:  values,
+			wantErr: true,
+		},
+		{
+			name:    "edges must increase",
+			buckets: distribution.Buckets{Strategy: distribution.StrategyEdges, Edges: []float64{10, 10}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown strategy",
+			buckets: distribution.Buckets{Strategy: "quantile"},
+			values:  values,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edges, err := tt.buckets.EdgesFor(tt.values, 10)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, distribution.ErrInvalidBuckets)
+				return
+			}
+			require.NoError(t, err)
+			assert.InDeltaSlice(t, tt.want, edges, 1e-6)
+		})
+	}
+}
+
+func TestCount(t *testing.T) {
+	counts := distribution.Count([]float64{0, 50000, 100000}, []float64{-1, 0, 49999, 50000, 100000, 100001})
+
+	assert.Equal(t, []int64{2, 2}, counts.Buckets)
+	assert.Equal(t, int64(1), counts.Underflow)
+	assert.Equal(t, int64(1), counts.Overflow)
+}
@@ -0,0 +1,12 @@
+package distribution
+
+import "math"
+
+// LaplaceQuantile returns the value of the Laplace distribution centered on 0 with the scale at the cumulative
+// probability p, from 0 to 1 excluded. Applied to a uniform random p it draws the noise of the Laplace mechanism
+func LaplaceQuantile(p, scale float64) float64 {
+	if p < 0.5 {
+		return scale * math.Log(2*p)
+	}
+	return -scale * math.Log(2-2*p)
+}
@@ -0,0 +1,16 @@
+package distribution_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"salaries/pkg/distribution"
+	"testing"
+)
+
+func TestLaplaceQuantile(t *testing.T) {
+	assert.Equal(t, 0.0, distribution.LaplaceQuantile(0.5, 2))
+	assert.InDelta(t, -2*math.Ln2, distribution.LaplaceQuantile(0.25, 2), 1e-9)
+	assert.InDelta(t, 2*math.Ln2, distribution.LaplaceQuantile(0.75, 2), 1e-9)
+	assert.InDelta(t, -distribution.LaplaceQuantile(0.99, 1), distribution.LaplaceQuantile(0.01, 1), 1e-9)
+	assert.Equal(t, 0.0, distribution.LaplaceQuantile(0.9, 0))
+}
@@ -0,0 +1,116 @@
+package distribution
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	// MethodIQR flags the values further than Threshold interquartile ranges below the first or above the third quartile
+	MethodIQR = "iqr"
+	// MethodZScore flags the values further than Threshold standard deviations from the mean
+	MethodZScore = "zscore"
+	// MethodMAD flags the values whose modified z-score, based on the median absolute deviation, is over Threshold
+	MethodMAD = "mad"
+)
+
+const (
+	DefaultIQRThreshold    = 1.5
+	DefaultZScoreThreshold = 3
+	DefaultMADThreshold    = 3.5
+	// madScale makes the median absolute deviation comparable to the standard deviation of normally distributed values
+	madScale = 0.6745
+)
+
+var ErrInvalidDetector = errors.New("invalid outlier detector")
+
+// Detector finds the outliers of a set of values, the default threshold of the method is used when Threshold is 0
+type Detector struct {
+	Method    string
+	Threshold float64
+}
+
+// Fence is the range of the values that are not outliers, both ends included
+type Fence struct {
+	Low  float64
+	High float64
+}
+
+// Contains reports whether the value is within the fence
+func (f Fence) Contains(value float64) bool {
+	return value >= f.Low && value <= f.High
+}
+
+// Validate checks the method and that the threshold is not negative
+func (d Detector) Validate() error {
+	switch d.Method {
+	case MethodIQR, MethodZScore, MethodMAD:
+	default:
+		return fmt.Errorf("%w: method must be %s, %s or %s", ErrInvalidDetector, MethodIQR, MethodZScore, MethodMAD)
+	}
+	if d.Threshold < 0 || math.IsNaN(d.Threshold) || math.IsInf(d.Threshold, 0) {
+		return fmt.Errorf("%w: threshold must be a positive number", ErrInvalidDetector)
+	}
+	return nil
+}
+
+// Fence returns the range of the values that are not outliers, when the values do not spread every value different from
+// the center is an outlier
+func (d Detector) Fence(values []float64) (Fence, error) {
+	if err := d.Validate(); err != nil {
+		return Fence{}, err
+	}
+	if len(values) == 0 {
+		return Fence{Low: math.Inf(-1), High: math.Inf(1)}, nil
+	}
+	threshold := d.Effective().Threshold
+	switch d.Method {
+	case MethodIQR:
+		sorted := Sorted(values)
+		first, third := Quantile(sorted, 0.25), Quantile(sorted, 0.75)
+		iqr := third - first
+		return Fence{Low: first - threshold*iqr, High: third + threshold*iqr}, nil
+	case MethodZScore:
+		mean := Mean(values)
+		deviation := StandardDeviation(values)
+		return Fence{Low: mean - threshold*deviation, High: mean + threshold*deviation}, nil
+	default:
+		median := Median(values)
+		deviations := make([]float64, len(values))
+		for i, value := range values {
+			deviations[i] = math.Abs(value - median)
+		}
+		spread := threshold * Median(deviations) / madScale
+		return Fence{Low: median - spread, High: median + spread}, nil
+	}
+}
+
+// Effective returns the detector with the default threshold of its method when none is set
+func (d Detector) Effective() Detector {
+	if d.Threshold > 0 {
+		return d
+	}
+	switch d.Method {
+	case MethodIQR:
+		d.Threshold = DefaultIQRThreshold
+	case MethodZScore:
+		d.Threshold = DefaultZScoreThreshold
+	case MethodMAD:
+		d.Threshold = DefaultMADThreshold
+	}
+	return d
+}
+
+// StandardDeviation returns the population standard deviation of the values, 0 when there are none
+func StandardDeviation(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	mean := Mean(values)
+	var sum float64
+	for _, value := range values {
+		sum += (value - mean) * (value - mean)
+	}
+	return math.Sqrt(sum / float64(len(values)))
+}
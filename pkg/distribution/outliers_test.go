@@ -0,0 +1,56 @@
+package distribution_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"salaries/pkg/distribution"
+	"testing"
+)
+
+func TestDetector_Fence(t *testing.T) {
+	values := []float64{90, 95, 100, 100, 105, 110, 1000}
+	tests := []struct {
+		name     string
+		detector distribution.Detector
+		low      float64
+		high     float64
+	}{
+		{
+			name:     "iqr",
+			detector: distribution.Detector{Method: distribution.MethodIQR},
+			low:      97.5 - 1.5*10,
+			high:     107.5 + 1.5*10,
+		},
+		{
+			name:     "iqr with a threshold",
+			detector: distribution.Detector{Method: distribution.MethodIQR, Threshold: 3},
+			low:      97.5 - 3*10,
+			high:     107.5 + 3*10,
+		},
+		{
+			name:     "mad",
+			detector: distribution.Detector{Method: distribution.MethodMAD},
+			low:      100 - 3.5*5/0.6745,
+			high:     100 + 3.5*5/0.6745,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fence, err := tt.detector.Fence(values)
+			require.NoError(t, err)
+			assert.InDelta(t, tt.low, fence.Low, 1e-9)
+			assert.InDelta(t, tt.high, fence.High, 1e-9)
+			assert.True(t, fence.Contains(110))
+			assert.False(t, fence.Contains(1000))
+		})
+	}
+
+	fence, err := distribution.Detector{Method: distribution.MethodZScore, Threshold: 2}.Fence([]float64{2, 4, 4, 4, 5, 5, 7, 9})
+	require.NoError(t, err)
+	assert.Equal(t, distribution.Fence{Low: 1, High: 9}, fence)
+
+	_, err = distribution.Detector{Method: "grubbs"}.Fence(values)
+	assert.ErrorIs(t, err, distribution.ErrInvalidDetector)
+	_, err = distribution.Detector{Method: distribution.MethodIQR, Threshold: -1}.Fence(values)
+	assert.ErrorIs(t, err, distribution.ErrInvalidDetector)
+}
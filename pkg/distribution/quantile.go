@@ -0,0 +1,52 @@
+package distribution
+
+import (
+	"math"
+	"sort"
+)
+
+// Mean returns the average of the values, 0 when there are none
+func Mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	return sum / float64(len(values))
+}
+
+// Sorted returns a sorted copy of the values
+func Sorted(values []float64) []float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	return sorted
+}
+
+// Quantile interpolates the q quantile, between 0 and 1, of sorted values, NaN when there are none
+func Quantile(sorted []float64, q float64) float64 {
+	if len(sorted) == 0 {
+		return math.NaN()
+	}
+	position := q * float64(len(sorted)-1)
+	lower := int(math.Floor(position))
+	upper := int(math.Ceil(position))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(position-float64(lower))
+}
+
+// PercentileRank returns the percentage of the sorted values below value, the values equal to it count for half, NaN
+// when there are none
+func PercentileRank(sorted []float64, value float64) float64 {
+	if len(sorted) == 0 {
+		return math.NaN()
+	}
+	below := sort.SearchFloat64s(sorted, value)
+	notAbove := sort.Search(len(sorted), func(i int) bool { return sorted[i] > value })
+	return (float64(below) + float64(notAbove-below)/2) / float64(len(sorted)) * 100
+}
+
+// Median returns the middle of the values, they do not need to be sorted
+func Median(values []float64) float64 {
+	return Quantile(Sorted(values), 0.5)
+}
@@ -0,0 +1,29 @@
+package distribution_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"salaries/pkg/distribution"
+	"testing"
+)
+
+func TestQuantile(t *testing.T) {
+	sorted := []float64{10, 20, 30, 40}
+
+	assert.Equal(t, 10.0, distribution.Quantile(sorted, 0))
+	assert.Equal(t, 25.0, distribution.Quantile(sorted, 0.5))
+	assert.Equal(t, 32.5, distribution.Quantile(sorted, 0.75))
+	assert.Equal(t, 40.0, distribution.Quantile(sorted, 1))
+	assert.True(t, math.IsNaN(distribution.Quantile(nil, 0.5)))
+
+	assert.Equal(t, 0.0, distribution.PercentileRank(sorted, 5))
+	assert.Equal(t, 12.5, distribution.PercentileRank(sorted, 10))
+	assert.Equal(t, 50.0, distribution.PercentileRank(sorted, 25))
+	assert.Equal(t, 100.0, distribution.PercentileRank(sorted, 45))
+	assert.Equal(t, 50.0, distribution.PercentileRank([]float64{20, 20}, 20))
+	assert.True(t, math.IsNaN(distribution.PercentileRank(nil, 10)))
+
+	assert.Equal(t, 20.0, distribution.Median([]float64{30, 10, 20}))
+	assert.Equal(t, 20.0, distribution.Mean([]float64{30, 10, 20}))
+	assert.Equal(t, 0.0, distribution.Mean(nil))
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AuditActionCreate  = "create"
+	AuditActionRead    = "read"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionRestore = "restore"
+	AuditActionPurge   = "purge"
+
+	AuditEntitySalary  = "salary"
+	AuditEntityUser    = "user"
+	AuditEntityPayBand = "pay_band"
+	AuditEntityBudget  = "budget"
+	// AuditEntityPayrollChange is a planned hire, termination or raise
+	AuditEntityPayrollChange = "payroll_change"
+	// AuditEntityDemographics events have no snapshots, the attributes are only kept in their own table
+	AuditEntityDemographics = "demographics"
+)
+
+// AuditEvent is an append-only record of who did what to a record, with the record before and after the change
+type AuditEvent struct {
+	ID         int64           `json:"id"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	ActorID    int64           `json:"actor_id"`
+	Actor      string          `json:"actor"`
+	Action     string          `json:"action"`
+	Entity     string          `json:"entity"`
+	EntityID   int64           `json:"entity_id"`
+	RequestID  string          `json:"request_id"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Budget is the payroll budget of a department for a fiscal year, fiscal years are named after the year they start in
+type Budget struct {
+	ID         int64   `json:"id"`
+	Department string  `json:"department" binding:"required"`
+	FiscalYear int     `json:"fiscal_year" binding:"required"`
+	Currency   string  `json:"currency" binding:"required"`
+	Amount     float64 `json:"amount" binding:"required"`
+}
+
+// Validate checks the budget is a positive amount for a plausible year
+func (b Budget) Validate() error {
+	if b.FiscalYear < 1900 || b.FiscalYear > 9999 {
+		return errors.New("fiscal_year must be a four digit year")
+	}
+	if b.Amount <= 0 {
+		return errors.New("amount must be positive")
+	}
+	return nil
+}
+
+const (
+	PayrollChangeHire        = "hire"
+	PayrollChangeTermination = "termination"
+	PayrollChangeRaise       = "raise"
+)
+
+// PayrollChange is a planned change of the payroll from EffectiveDate: a hire in a department at an annual Salary, the
+// termination of a salary or a raise of a salary by a Percentage or by an annual Amount in its currency
+type PayrollChange struct {
+	ID            int64     `json:"id"`
+	Kind          string    `json:"kind" binding:"required"`
+	SalaryID      int64     `json:"salary_id,omitempty"`
+	Department    string    `json:"department,omitempty"`
+	SubDepartment string    `json:"sub_department,omitempty"`
+	Salary        float64   `json:"salary,omitempty"`
+	Currency      string    `json:"currency,omitempty"`
+	Percentage    float64   `json:"percentage,omitempty"`
+	Amount        float64   `json:"amount,omitempty"`
+	EffectiveDate time.Time `json:"effective_date" binding:"required"`
+}
+
+// Validate checks the change has the fields of its kind
+func (c PayrollChange) Validate() error {
+	switch c.Kind {
+	case PayrollChangeHire:
+		if c.SalaryID != 0 {
+			return errors.New("a hire has no salary_id")
+		}
+		if c.Department == "" || c.Currency == "" || c.Salary <= 0 {
+			return errors.New("a hire needs a department, a positive salary and a currency")
+		}
+	case PayrollChangeTermination:
+		if c.SalaryID <= 0 {
+			return errors.New("a termination needs a salary_id")
+		}
+	case PayrollChangeRaise:
+		if c.SalaryID <= 0 {
+			return errors.New("a raise needs a salary_id")
+		}
+		if c.Percentage < 0 || c.Amount < 0 || (c.Percentage == 0) == (c.Amount == 0) {
+			return errors.New("a raise needs either a positive percentage or a positive amount")
+		}
+	default:
+		return errors.New("kind must be hire, termination or raise")
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package domain
+
+const (
+	AttributeGender    = "gender"
+	AttributeEthnicity = "ethnicity"
+)
+
+// Demographics are the optional attributes of the person paid a salary, they are only served to the hr and admin roles
+// and never returned with the salary
+type Demographics struct {
+	SalaryID  int64  `json:"salary_id"`
+	Gender    string `json:"gender,omitempty" log:"redact"`
+	Ethnicity string `json:"ethnicity,omitempty" log:"redact"`
+}
+
+// Attribute returns the value of the gender or ethnicity attribute, empty when it is unknown
+func (d Demographics) Attribute(name string) string {
+	switch name {
+	case AttributeGender:
+		return d.Gender
+	case AttributeEthnicity:
+		return d.Ethnicity
+	}
+	return ""
+}
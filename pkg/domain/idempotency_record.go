@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"net/http"
+	"time"
+)
+
+// IdempotencyRecord is the response stored for an Idempotency-Key, a zero StatusCode means the first request is still in progress
+type IdempotencyRecord struct {
+	// Scope keeps the keys of different users apart
+	Scope       string
+	Key         string
+	RequestHash string
+	StatusCode  int
+	Header      http.Header
+	Body        []byte
+	CreatedAt   time.Time
+}
+
+func (r IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
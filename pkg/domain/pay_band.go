@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// PayBand is the salary range of a job level in a currency, a band name can have a version per currency and effective period
+type PayBand struct {
+	ID       int64   `json:"id"`
+	Name     string  `json:"name" binding:"required"`
+	Currency string  `json:"currency" binding:"required"`
+	Min      float64 `json:"min"`
+	Mid      float64 `json:"mid" binding:"required"`
+	Max      float64 `json:"max" binding:"required"`
+	// EffectiveFrom is included and EffectiveTo excluded, a band without EffectiveTo stays effective
+	EffectiveFrom time.Time  `json:"effective_from" binding:"required"`
+	EffectiveTo   *time.Time `json:"effective_to,omitempty"`
+}
+
+// EffectiveAt tells if the band applies at the time
+func (b PayBand) EffectiveAt(at time.Time) bool {
+	return !at.Before(b.EffectiveFrom) && (b.EffectiveTo == nil || at.Before(*b.EffectiveTo))
+}
+
+// Validate checks the band is a range around its midpoint that starts before it ends
+func (b PayBand) Validate() error {
+	if b.Min < 0 || b.Min >= b.Max {
+		return errors.New("min must be positive and lower than max")
+	}
+	if b.Mid < b.Min || b.Mid > b.Max {
+		return errors.New("mid must be between min and max")
+	}
+	if b.EffectiveTo != nil && !b.EffectiveTo.After(b.EffectiveFrom) {
+		return errors.New("effective_to must be after effective_from")
+	}
+	return nil
+}
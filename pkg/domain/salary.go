@@ -1,11 +1,24 @@
 package domain
 
+import "time"
+
 type Salary struct {
 	ID            int64   `gorm:"column:primaryKey" json:"id"`
-	Name          string  `gorm:"column:name" json:"name" binding:"required"`
-	Salary        float64 `gorm:"column:salary" json:"salary,string" binding:"required"`
+	Name          string  `gorm:"column:name" json:"name" binding:"required" log:"redact"`
+	Salary        float64 `gorm:"column:salary" json:"salary,string" binding:"required" log:"redact"`
 	Currency      string  `gorm:"column:currency" json:"currency" binding:"required"`
 	OnContract    bool    `gorm:"column:on_contract" json:"on_contract,string"`
 	Department    string  `gorm:"column:department" json:"department" binding:"required"`
 	SubDepartment string  `gorm:"column:sub_department" json:"sub_department" binding:"required"`
+	// PayBand is the name of the pay band of the job level, a band with this name must exist
+	PayBand string `gorm:"column:pay_band" json:"pay_band,omitempty"`
+	// Version starts at 1 and is incremented by every change, it is the ETag of the salary
+	Version int64 `gorm:"column:version" json:"version,omitempty"`
+}
+
+// DeletedSalary is a soft deleted salary kept in the trash until it is restored or purged
+type DeletedSalary struct {
+	Salary
+	DeletedAt time.Time `json:"deleted_at"`
+	DeletedBy string    `json:"deleted_by,omitempty"`
 }
@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+const (
+	SnapshotSourceScheduled = "scheduled"
+	SnapshotSourceManual    = "manual"
+)
+
+// Groups of the stats endpoints: the whole dataset, the salaries on contract, the departments and the sub-departments
+const (
+	StatsGroupAll           = "all"
+	StatsGroupContract      = "contract"
+	StatsGroupDepartment    = "department"
+	StatsGroupSubDepartment = "sub_department"
+)
+
+// StatsSnapshot is the output of every stats endpoint at TakenAt, taken on a schedule or on demand
+type StatsSnapshot struct {
+	ID      int64           `json:"id"`
+	TakenAt time.Time       `json:"taken_at"`
+	Source  string          `json:"source"`
+	Stats   []SnapshotStats `json:"stats,omitempty"`
+}
+
+// SnapshotStats are the stats of a group, Department and SubDepartment are only set for the groups they name
+type SnapshotStats struct {
+	Group         string  `json:"group"`
+	Department    string  `json:"department,omitempty"`
+	SubDepartment string  `json:"sub_department,omitempty"`
+	Mean          float64 `json:"mean"`
+	Max           float64 `json:"max"`
+	Min           float64 `json:"min"`
+	Count         int64   `json:"count"`
+}
@@ -1,7 +1,20 @@
 package domain
 
+const (
+	RoleAdmin = "admin"
+	// RoleHR can read the demographics of the salaries and the pay equity analysis
+	RoleHR   = "hr"
+	RoleUser = "user"
+)
+
 type User struct {
 	ID       int64  `json:"id"`
 	Username string `json:"username"`
-	Password string `json:"password"`
+	// PasswordHash is the bcrypt hash of the password, it is never serialized
+	PasswordHash string `json:"-" log:"redact"`
+	Role         string `json:"role"`
+}
+
+func (u User) IsAdmin() bool {
+	return u.Role == RoleAdmin
 }
@@ -0,0 +1,57 @@
+package export
+
+import (
+	"errors"
+	"io"
+)
+
+const (
+	CSV    = "csv"
+	XLSX   = "xlsx"
+	NDJSON = "ndjson"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported export format")
+
+var contentTypes = map[string]string{
+	CSV:    "text/csv; charset=utf-8",
+	XLSX:   "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	NDJSON: "application/x-ndjson",
+}
+
+// Writer writes a table one row at a time, rows are flushed as they are written so memory does not grow with the table
+type Writer interface {
+	WriteHeader(columns []string) error
+	WriteRow(values []interface{}) error
+	// Close flushes the rows left and finishes the document, the output is incomplete until it is called
+	Close() error
+}
+
+// NewWriter returns the writer of the format, csv when the format is empty
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case CSV, "":
+		return newCSVWriter(w), nil
+	case XLSX:
+		return newXLSXWriter(w), nil
+	case NDJSON:
+		return newNDJSONWriter(w), nil
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+// ContentType returns the media type of the format, csv when the format is empty
+func ContentType(format string) string {
+	if format == "" {
+		format = CSV
+	}
+	return contentTypes[format]
+}
+
+// FileName returns the name of the exported file with the extension of the format
+func FileName(name, format string) string {
+	if format == "" {
+		format = CSV
+	}
+	return name + "." + format
+}
@@ -0,0 +1,111 @@
+package export_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"salaries/pkg/api"
+	"salaries/pkg/domain"
+	"salaries/pkg/export"
+	"testing"
+)
+
+var salary = &domain.Salary{ID: 1, Name: "Anurag", Salary: 90000, Currency: "USD", OnContract: true, Department: "Banking", SubDepartment: "Loan & Credit"}
+
+func writeSalaries(t *testing.T, format string) []byte {
+	var output bytes.Buffer
+	writer, err := export.NewWriter(format, &output)
+	require.NoError(t, err)
+	require.NoError(t, writer.WriteHeader(export.SalaryColumns))
+	require.NoError(t, writer.WriteRow(export.SalaryRow(salary)))
+	require.NoError(t, writer.Close())
+	return output.Bytes()
+}
+
+func TestWriter_CSV(t *testing.T) {
+	output := writeSalaries(t, export.CSV)
+
+	assert.Equal(t, "id,name,salary,currency,on_contract,department,sub_department\n1,Anurag,90000,USD,true,Banking,Loan & Credit\n", string(output))
+}
+
+func TestWriter_NDJSON(t *testing.T) {
+	output := writeSalaries(t, export.NDJSON)
+
+	assert.JSONEq(t, `{"id":1,"name":"Anurag","salary":90000,"currency":"USD","on_contract":true,"department":"Banking","sub_department":"Loan & Credit"}`, string(output))
+}
+
+func TestWriter_XLSX(t *testing.T) {
+	output := writeSalaries(t, export.XLSX)
+
+	archive, err := zip.NewReader(bytes.NewReader(output), int64(len(output)))
+	require.NoError(t, err)
+	files := map[string]string{}
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		files[file.Name] = string(content)
+	}
+	assert.Contains(t, files, "[Content_Types].xml")
+	assert.Contains(t, files, "xl/workbook.xml")
+	sheet := files["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<c t="inlineStr"><is><t>sub_department</t></is></c>`)
+	assert.Contains(t, sheet, `<c><v>90000</v></c>`)
+	assert.Contains(t, sheet, `<c t="b"><v>1</v></c>`)
+	assert.Contains(t, sheet, `<t>Loan &amp; Credit</t>`)
+	assert.Contains(t, sheet, `</sheetData></worksheet>`)
+}
+
+func TestNewWriter_UnsupportedFormat(t *testing.T) {
+	_, err := export.NewWriter("pdf", io.Discard)
+
+	assert.True(t, errors.Is(err, export.ErrUnsupportedFormat))
+}
+
+func TestWriteTable(t *testing.T) {
+	stats := api.Stats{Mean: 80000, Max: 90000, Min: 70000, Count: 2}
+	tests := []struct {
+		name  string
+		stats interface{}
+		want  string
+	}{
+		{
+			name:  "entire dataset",
+			stats: &stats,
+			want:  "mean,max,min,count\n80000,90000,70000,2\n",
+		},
+		{
+			name:  "departments",
+			stats: []api.DepartmentStats{{Department: "Banking", Stats: stats}},
+			want:  "department,mean,max,min,count\nBanking,80000,90000,70000,2\n",
+		},
+		{
+			name:  "sub-departments",
+			stats: []api.SubDepartmentStats{{SubDepartment: "Loan", DepartmentStats: api.DepartmentStats{Department: "Banking", Stats: stats}}},
+			want:  "department,sub_department,mean,max,min,count\nBanking,Loan,80000,90000,70000,2\n",
+		},
+		{
+			name: "histogram",
+			stats: &api.Histogram{
+				Edges:  []float64{0, 50000, 100000},
+				Groups: []api.HistogramGroup{{Group: "Banking", Count: 3, Counts: []int64{1, 2}}},
+			},
+			want: "group,from,to,count\nBanking,0,50000,1\nBanking,50000,100000,2\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			writer, err := export.NewWriter(export.CSV, &output)
+			require.NoError(t, err)
+
+			require.NoError(t, export.WriteTable(writer, tt.stats))
+			require.NoError(t, writer.Close())
+			assert.Equal(t, tt.want, output.String())
+		})
+	}
+}
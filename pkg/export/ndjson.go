@@ -0,0 +1,42 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+)
+
+type ndjsonWriter struct {
+	buffer  *bufio.Writer
+	encoder *json.Encoder
+	columns []string
+	object  map[string]interface{}
+}
+
+func newNDJSONWriter(w io.Writer) Writer {
+	buffer := bufio.NewWriter(w)
+	return &ndjsonWriter{
+		buffer:  buffer,
+		encoder: json.NewEncoder(buffer),
+	}
+}
+
+// WriteHeader keeps the columns as the keys of every object, nothing is written for the header
+func (n *ndjsonWriter) WriteHeader(columns []string) error {
+	n.columns = columns
+	n.object = make(map[string]interface{}, len(columns))
+	return nil
+}
+
+func (n *ndjsonWriter) WriteRow(values []interface{}) error {
+	for i, column := range n.columns {
+		if i < len(values) {
+			n.object[column] = values[i]
+		}
+	}
+	return n.encoder.Encode(n.object)
+}
+
+func (n *ndjsonWriter) Close() error {
+	return n.buffer.Flush()
+}
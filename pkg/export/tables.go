@@ -0,0 +1,91 @@
+package export
+
+import (
+	"fmt"
+	"salaries/pkg/api"
+	"salaries/pkg/domain"
+)
+
+var (
+	SalaryColumns = []string{"id", "name", "salary", "currency", "on_contract", "department", "sub_department"}
+	statsColumns  = []string{"mean", "max", "min", "count"}
+)
+
+// SalaryRow returns the values of the salary in the order of SalaryColumns
+func SalaryRow(salary *domain.Salary) []interface{} {
+	return []interface{}{salary.ID, salary.Name, salary.Salary, salary.Currency, salary.OnContract, salary.Department, salary.SubDepartment}
+}
+
+// WriteTable writes salaries or the response of a stats endpoint as a table with a row per salary or group
+func WriteTable(w Writer, value interface{}) error {
+	switch s := value.(type) {
+	case []domain.Salary:
+		if err := w.WriteHeader(SalaryColumns); err != nil {
+			return err
+		}
+		for i := range s {
+			if err := w.WriteRow(SalaryRow(&s[i])); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *domain.Salary:
+		if err := w.WriteHeader(SalaryColumns); err != nil {
+			return err
+		}
+		return w.WriteRow(SalaryRow(s))
+	case *api.Stats:
+		if err := w.WriteHeader(statsColumns); err != nil {
+			return err
+		}
+		return w.WriteRow(statsRow(*s))
+	case []api.DepartmentStats:
+		if err := w.WriteHeader(append([]string{"department"}, statsColumns...)); err != nil {
+			return err
+		}
+		for _, departmentStats := range s {
+			if err := w.WriteRow(append([]interface{}{departmentStats.Department}, statsRow(departmentStats.Stats)...)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []api.SubDepartmentStats:
+		if err := w.WriteHeader(append([]string{"department", "sub_department"}, statsColumns...)); err != nil {
+			return err
+		}
+		for _, subDepartmentStats := range s {
+			row := append([]interface{}{subDepartmentStats.DepartmentStats.Department, subDepartmentStats.SubDepartment}, statsRow(subDepartmentStats.DepartmentStats.Stats)...)
+			if err := w.WriteRow(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *api.Histogram:
+		if err := w.WriteHeader([]string{"group", "from", "to", "count"}); err != nil {
+			return err
+		}
+		for _, group := range s.Groups {
+			for i, count := range group.Counts {
+				if err := w.WriteRow([]interface{}{group.Group, s.Edges[i], s.Edges[i+1], count}); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	case *api.AnomalyReport:
+		if err := w.WriteHeader([]string{"id", "name", "department", "sub_department", "currency", "salary", "direction", "low", "high", "median"}); err != nil {
+			return err
+		}
+		for _, a := range s.Anomalies {
+			if err := w.WriteRow([]interface{}{a.SalaryID, a.Name, a.Department, a.SubDepartment, a.Currency, a.Salary, a.Direction, a.Low, a.High, a.Median}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: %T can not be exported", ErrUnsupportedFormat, value)
+}
+
+func statsRow(stats api.Stats) []interface{} {
+	return []interface{}{stats.Mean, stats.Max, stats.Min, stats.Count}
+}
@@ -0,0 +1,137 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"io"
+)
+
+// the static parts of a workbook with a single sheet, cells use inline strings so the sheet can be streamed without a shared strings table
+var xlsxParts = []struct {
+	name    string
+	content string
+}{
+	{
+		name: "[Content_Types].xml",
+		content: xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+			`</Types>`,
+	},
+	{
+		name: "_rels/.rels",
+		content: xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`,
+	},
+	{
+		name: "xl/workbook.xml",
+		content: xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets>` +
+			`</workbook>`,
+	},
+	{
+		name: "xl/_rels/workbook.xml.rels",
+		content: xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+			`</Relationships>`,
+	},
+}
+
+type xlsxWriter struct {
+	archive *zip.Writer
+	sheet   *bufio.Writer
+	err     error
+}
+
+func newXLSXWriter(w io.Writer) Writer {
+	return &xlsxWriter{archive: zip.NewWriter(w)}
+}
+
+func (x *xlsxWriter) WriteHeader(columns []string) error {
+	values := make([]interface{}, len(columns))
+	for i, column := range columns {
+		values[i] = column
+	}
+	return x.WriteRow(values)
+}
+
+func (x *xlsxWriter) WriteRow(values []interface{}) error {
+	if err := x.start(); err != nil {
+		return err
+	}
+	x.write("<row>")
+	for _, value := range values {
+		switch v := value.(type) {
+		case float64, int64, int:
+			x.write(`<c><v>` + formatValue(v) + `</v></c>`)
+		case bool:
+			if v {
+				x.write(`<c t="b"><v>1</v></c>`)
+			} else {
+				x.write(`<c t="b"><v>0</v></c>`)
+			}
+		default:
+			x.write(`<c t="inlineStr"><is><t>`)
+			x.escape(formatValue(v))
+			x.write(`</t></is></c>`)
+		}
+	}
+	x.write("</row>")
+	return x.err
+}
+
+func (x *xlsxWriter) Close() error {
+	if err := x.start(); err != nil {
+		return err
+	}
+	x.write("</sheetData></worksheet>")
+	if x.err == nil {
+		x.err = x.sheet.Flush()
+	}
+	if x.err != nil {
+		return x.err
+	}
+	return x.archive.Close()
+}
+
+// start writes the static parts and opens the sheet, the sheet is the last file of the archive so rows can be appended until Close
+func (x *xlsxWriter) start() error {
+	if x.sheet != nil || x.err != nil {
+		return x.err
+	}
+	for _, part := range xlsxParts {
+		w, err := x.archive.Create(part.name)
+		if err != nil {
+			x.err = err
+			return err
+		}
+		if _, err := io.WriteString(w, part.content); err != nil {
+			x.err = err
+			return err
+		}
+	}
+	w, err := x.archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		x.err = err
+		return err
+	}
+	x.sheet = bufio.NewWriter(w)
+	x.write(xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	return x.err
+}
+
+func (x *xlsxWriter) write(s string) {
+	if x.err == nil {
+		_, x.err = x.sheet.WriteString(s)
+	}
+}
+
+func (x *xlsxWriter) escape(s string) {
+	if x.err == nil {
+		x.err = xml.EscapeText(x.sheet, []byte(s))
+	}
+}
@@ -0,0 +1,102 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"salaries/pkg/domain"
+	"strconv"
+	"strings"
+)
+
+var csvColumns = []string{"name", "salary", "currency", "on_contract", "department", "sub_department"}
+
+type csvDecoder struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+// NewCSVDecoder reads salaries from a CSV with a header row using the json names of the salary fields
+func NewCSVDecoder(r io.Reader) Decoder {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.ReuseRecord = true
+	return &csvDecoder{reader: reader}
+}
+
+func (d *csvDecoder) Next() (*domain.Salary, error) {
+	if d.columns == nil {
+		if err := d.readHeader(); err != nil {
+			return nil, err
+		}
+	}
+	record, err := d.reader.Read()
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	var parseError *csv.ParseError
+	if errors.As(err, &parseError) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRow, parseError.Err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	salary := domain.Salary{
+		Name:          d.field(record, "name"),
+		Currency:      d.field(record, "currency"),
+		Department:    d.field(record, "department"),
+		SubDepartment: d.field(record, "sub_department"),
+	}
+	if value := d.field(record, "salary"); value != "" {
+		salary.Salary, err = strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: salary is not a number", ErrInvalidRow)
+		}
+	}
+	if value := d.field(record, "on_contract"); value != "" {
+		salary.OnContract, err = strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: on_contract is not a boolean", ErrInvalidRow)
+		}
+	}
+	return &salary, nil
+}
+
+func (d *csvDecoder) readHeader() error {
+	header, err := d.reader.Read()
+	if err == io.EOF {
+		return io.EOF
+	}
+	if err != nil {
+		return fmt.Errorf("reading csv header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if !isCSVColumn(column) {
+			return fmt.Errorf("unknown csv column %q", column)
+		}
+		columns[column] = i
+	}
+	d.columns = columns
+	return nil
+}
+
+func (d *csvDecoder) field(record []string, column string) string {
+	i, ok := d.columns[column]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+func isCSVColumn(column string) bool {
+	for _, c := range csvColumns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
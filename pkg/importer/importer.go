@@ -0,0 +1,49 @@
+package importer
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin/binding"
+	"io"
+	"mime"
+	"salaries/pkg/domain"
+)
+
+const (
+	CSVContentType    = "text/csv"
+	JSONContentType   = "application/json"
+	NDJSONContentType = "application/x-ndjson"
+)
+
+var (
+	// ErrInvalidRow wraps the errors of a single row, the decoder can keep reading after it
+	ErrInvalidRow        = errors.New("invalid row")
+	ErrUnsupportedFormat = errors.New("unsupported import format")
+)
+
+// Decoder reads salaries one row at a time without buffering the whole input
+type Decoder interface {
+	// Next returns the next salary or io.EOF when there are no more rows
+	Next() (*domain.Salary, error)
+}
+
+// NewDecoder returns the decoder for the content type of the request body
+func NewDecoder(contentType string, r io.Reader) (Decoder, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+	switch mediaType {
+	case CSVContentType:
+		return NewCSVDecoder(r), nil
+	case JSONContentType:
+		return NewJSONDecoder(r), nil
+	case NDJSONContentType, "application/ndjson", "application/jsonl":
+		return NewNDJSONDecoder(r), nil
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+// Validate applies the same binding rules used when a single salary is created
+func Validate(salary *domain.Salary) error {
+	return binding.Validator.ValidateStruct(salary)
+}
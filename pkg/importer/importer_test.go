@@ -0,0 +1,123 @@
+package importer_test
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"salaries/pkg/domain"
+	"salaries/pkg/importer"
+	"strings"
+	"testing"
+)
+
+type decodedRow struct {
+	salary  *domain.Salary
+	invalid bool
+	fatal   bool
+}
+
+func decodeAll(t *testing.T, decoder importer.Decoder) []decodedRow {
+	var rows []decodedRow
+	for i := 0; i < 100; i++ {
+		salary, err := decoder.Next()
+		if err == io.EOF {
+			return rows
+		}
+		switch {
+		case errors.Is(err, importer.ErrInvalidRow):
+			rows = append(rows, decodedRow{invalid: true})
+		case err != nil:
+			return append(rows, decodedRow{fatal: true})
+		default:
+			rows = append(rows, decodedRow{salary: salary})
+		}
+	}
+	t.Fatal("decoder did not stop")
+	return nil
+}
+
+func TestNewDecoder(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		wantError   bool
+	}{
+		{name: "csv", contentType: "text/csv; charset=utf-8"},
+		{name: "json", contentType: "application/json"},
+		{name: "ndjson", contentType: "application/x-ndjson"},
+		{name: "unsupported", contentType: "application/xml", wantError: true},
+		{name: "missing", contentType: "", wantError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := importer.NewDecoder(tt.contentType, strings.NewReader(""))
+			assert.Equal(t, tt.wantError, errors.Is(err, importer.ErrUnsupportedFormat))
+		})
+	}
+}
+
+func TestDecoders(t *testing.T) {
+	anurag := &domain.Salary{Name: "Anurag", Salary: 90000, Currency: "USD", OnContract: true, Department: "Banking", SubDepartment: "Loan"}
+	tests := []struct {
+		name    string
+		decoder importer.Decoder
+		want    []decodedRow
+	}{
+		{
+			name: "csv",
+			decoder: importer.NewCSVDecoder(strings.NewReader("name,salary,currency,department,on_contract,sub_department\n" +
+				"Anurag,90000,USD,Banking,true,Loan\n" +
+				"Himani,lots,USD,Engineering,,Platform\n" +
+				"Yatendra,30\n")),
+			want: []decodedRow{{salary: anurag}, {invalid: true}, {invalid: true}},
+		},
+		{
+			name:    "csv with unknown column",
+			decoder: importer.NewCSVDecoder(strings.NewReader("name,age\nAnurag,30\n")),
+			want:    []decodedRow{{fatal: true}},
+		},
+		{
+			name: "json",
+			decoder: importer.NewJSONDecoder(strings.NewReader(`[
+				{"name": "Anurag", "salary": "90000", "currency": "USD", "department": "Banking", "on_contract": "true", "sub_department": "Loan"},
+				{"name": "Himani", "salary": 240000},
+				{"name": "Yatendra"}
+			]`)),
+			want: []decodedRow{{salary: anurag}, {invalid: true}, {salary: &domain.Salary{Name: "Yatendra"}}},
+		},
+		{
+			name:    "json syntax error",
+			decoder: importer.NewJSONDecoder(strings.NewReader(`[{"name": "Anurag"}, {"name": `)),
+			want:    []decodedRow{{salary: &domain.Salary{Name: "Anurag"}}, {fatal: true}},
+		},
+		{
+			name:    "json not an array",
+			decoder: importer.NewJSONDecoder(strings.NewReader(`{"name": "Anurag"}`)),
+			want:    []decodedRow{{fatal: true}},
+		},
+		{
+			name: "ndjson",
+			decoder: importer.NewNDJSONDecoder(strings.NewReader(`{"name": "Anurag", "salary": "90000", "currency": "USD", "department": "Banking", "on_contract": "true", "sub_department": "Loan"}
+
+{"name": 
+{"name": "Yatendra"}
+`)),
+			want: []decodedRow{{salary: anurag}, {invalid: true}, {salary: &domain.Salary{Name: "Yatendra"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, decodeAll(t, tt.decoder))
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := domain.Salary{Name: "Anurag", Salary: 90000, Currency: "USD", Department: "Banking", SubDepartment: "Loan"}
+	require.NoError(t, importer.Validate(&valid))
+
+	missingDepartment := valid
+	missingDepartment.Department = ""
+	assert.Error(t, importer.Validate(&missingDepartment))
+}
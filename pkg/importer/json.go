@@ -0,0 +1,94 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"salaries/pkg/domain"
+)
+
+type jsonDecoder struct {
+	decoder *json.Decoder
+	started bool
+}
+
+// NewJSONDecoder reads salaries from a JSON array, one element at a time
+func NewJSONDecoder(r io.Reader) Decoder {
+	return &jsonDecoder{decoder: json.NewDecoder(r)}
+}
+
+func (d *jsonDecoder) Next() (*domain.Salary, error) {
+	if !d.started {
+		if err := d.expectDelim('['); err != nil {
+			return nil, err
+		}
+		d.started = true
+	}
+	if !d.decoder.More() {
+		if err := d.expectDelim(']'); err != nil {
+			return nil, err
+		}
+		if _, err := d.decoder.Token(); err != io.EOF {
+			return nil, errors.New("unexpected data after the json array")
+		}
+		return nil, io.EOF
+	}
+	var salary domain.Salary
+	if err := d.decoder.Decode(&salary); err != nil {
+		return nil, decodeError(err)
+	}
+	return &salary, nil
+}
+
+func (d *jsonDecoder) expectDelim(delim json.Delim) error {
+	token, err := d.decoder.Token()
+	if err == io.EOF && delim == '[' {
+		return io.EOF
+	}
+	if err != nil {
+		return fmt.Errorf("reading json array: %w", err)
+	}
+	if token != delim {
+		return fmt.Errorf("reading json array: expected %q", delim)
+	}
+	return nil
+}
+
+type ndjsonDecoder struct {
+	scanner *bufio.Scanner
+}
+
+// NewNDJSONDecoder reads salaries from newline delimited JSON, blank lines are skipped
+func NewNDJSONDecoder(r io.Reader) Decoder {
+	return &ndjsonDecoder{scanner: bufio.NewScanner(r)}
+}
+
+func (d *ndjsonDecoder) Next() (*domain.Salary, error) {
+	for d.scanner.Scan() {
+		line := bytes.TrimSpace(d.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var salary domain.Salary
+		if err := json.Unmarshal(line, &salary); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidRow, err.Error())
+		}
+		return &salary, nil
+	}
+	if err := d.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// decodeError keeps reading after errors on a single value, syntax errors leave the stream unusable
+func decodeError(err error) error {
+	var syntaxError *json.SyntaxError
+	if errors.As(err, &syntaxError) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return err
+	}
+	return fmt.Errorf("%w: %s", ErrInvalidRow, err.Error())
+}
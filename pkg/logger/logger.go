@@ -1,33 +1,141 @@
 package logger
 
 import (
+	"context"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"os"
+	"salaries/pkg/requestctx"
 )
 
+const (
+	TextFormat = "text"
+	JSONFormat = "json"
+
+	RequestIDField = "request_id"
+	UserIDField    = "user_id"
+	RouteField     = "route"
+	TraceIDField   = "trace_id"
+)
+
+type Fields map[string]interface{}
+
 type Logger interface {
+	Debug(format string, args ...interface{})
 	Info(format string, args ...interface{})
 	Warn(format string, args ...interface{})
 	Error(format string, args ...interface{})
+	// With returns a logger that adds the given fields to every line
+	With(fields Fields) Logger
+	// WithContext returns a logger that adds the request id, user id, route and trace id found in the context
+	WithContext(ctx context.Context) Logger
+}
+
+type Config struct {
+	Level  string
+	Format string
+	Output io.Writer
+	// RedactedFields are masked in addition to DefaultRedactedFields
+	RedactedFields []string
 }
 
 type loggerImpl struct {
-	loggerClient *log.Logger
+	entry    *log.Entry
+	redactor Redactor
 }
 
 func NewLogger() Logger {
+	return NewLoggerWithConfig(Config{
+		Level:  log.InfoLevel.String(),
+		Format: TextFormat,
+	})
+}
+
+func NewLoggerWithConfig(config Config) Logger {
+	loggerClient := log.New()
+	if config.Output != nil {
+		loggerClient.SetOutput(config.Output)
+	} else {
+		loggerClient.SetOutput(os.Stderr)
+	}
+
+	if config.Format == JSONFormat {
+		loggerClient.SetFormatter(&log.JSONFormatter{})
+	} else {
+		loggerClient.SetFormatter(&log.TextFormatter{})
+	}
+
+	level, err := log.ParseLevel(config.Level)
+	if err != nil {
+		level = log.InfoLevel
+		defer loggerClient.Warnf("invalid log level %q, using %s", config.Level, level)
+	}
+	loggerClient.SetLevel(level)
+
 	return loggerImpl{
-		loggerClient: log.New(),
+		entry:    log.NewEntry(loggerClient),
+		redactor: NewRedactor(append(DefaultRedactedFields, config.RedactedFields...)...),
 	}
 }
 
+func (logger loggerImpl) Debug(format string, args ...interface{}) {
+	logger.entry.Debugf(format, logger.redactArgs(format, args)...)
+}
+
 func (logger loggerImpl) Info(format string, args ...interface{}) {
-	logger.loggerClient.Infof(format, args...)
+	logger.entry.Infof(format, logger.redactArgs(format, args)...)
 }
 
 func (logger loggerImpl) Warn(format string, args ...interface{}) {
-	logger.loggerClient.Warnf(format, args...)
+	logger.entry.Warnf(format, logger.redactArgs(format, args)...)
 }
 
 func (logger loggerImpl) Error(format string, args ...interface{}) {
-	logger.loggerClient.Errorf(format, args...)
+	logger.entry.Errorf(format, logger.redactArgs(format, args)...)
+}
+
+func (logger loggerImpl) With(fields Fields) Logger {
+	redacted := make(log.Fields, len(fields))
+	for key, value := range fields {
+		if logger.redactor.IsRedacted(key) {
+			redacted[key] = RedactedValue
+			continue
+		}
+		redacted[key] = logger.redactor.Redact(value)
+	}
+	return loggerImpl{
+		entry:    logger.entry.WithFields(redacted),
+		redactor: logger.redactor,
+	}
+}
+
+func (logger loggerImpl) redactArgs(format string, args []interface{}) []interface{} {
+	labels := formatLabels(format)
+	redacted := make([]interface{}, len(args))
+	for i, arg := range args {
+		if i < len(labels) && labels[i] != "" && logger.redactor.IsRedacted(labels[i]) && isScalar(arg) {
+			redacted[i] = RedactedValue
+			continue
+		}
+		redacted[i] = logger.redactor.Redact(arg)
+	}
+	return redacted
+}
+
+func (logger loggerImpl) WithContext(ctx context.Context) Logger {
+	fields := Fields{}
+	if requestID := requestctx.RequestID(ctx); requestID != "" {
+		fields[RequestIDField] = requestID
+	}
+	if user := requestctx.User(ctx); user != nil {
+		fields[UserIDField] = user.ID
+	}
+	if route := requestctx.Route(ctx); route != "" {
+		fields[RouteField] = route
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		fields[TraceIDField] = spanContext.TraceID().String()
+	}
+	return logger.With(fields)
 }
@@ -0,0 +1,76 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"salaries/pkg/domain"
+	"salaries/pkg/logger"
+	"salaries/pkg/requestctx"
+	"strings"
+	"testing"
+)
+
+func TestLogger_WithContext(t *testing.T) {
+	var output bytes.Buffer
+	testLogger := logger.NewLoggerWithConfig(logger.Config{Level: "info", Format: logger.JSONFormat, Output: &output})
+
+	ctx := requestctx.WithRequestID(context.Background(), "request-1")
+	ctx = requestctx.WithUser(ctx, &domain.User{ID: 7})
+	ctx = requestctx.WithRoute(ctx, "/api/salaries")
+	testLogger.WithContext(ctx).With(logger.Fields{"component": "test"}).Info("deleting salary with id %d", 3)
+
+	var line map[string]interface{}
+	assert.NoError(t, json.Unmarshal(output.Bytes(), &line))
+	assert.Equal(t, "deleting salary with id 3", line["msg"])
+	assert.Equal(t, "info", line["level"])
+	assert.Equal(t, "request-1", line[logger.RequestIDField])
+	assert.Equal(t, float64(7), line[logger.UserIDField])
+	assert.Equal(t, "/api/salaries", line[logger.RouteField])
+	assert.Equal(t, "test", line["component"])
+}
+
+func TestLogger_Level(t *testing.T) {
+	tests := []struct {
+		name     string
+		level    string
+		log      func(testLogger logger.Logger)
+		wantLogs bool
+	}{
+		{
+			name:     "debug filtered at info level",
+			level:    "info",
+			log:      func(testLogger logger.Logger) { testLogger.Debug("debug line") },
+			wantLogs: false,
+		},
+		{
+			name:     "debug written at debug level",
+			level:    "debug",
+			log:      func(testLogger logger.Logger) { testLogger.Debug("debug line") },
+			wantLogs: true,
+		},
+		{
+			name:     "info filtered at error level",
+			level:    "error",
+			log:      func(testLogger logger.Logger) { testLogger.Info("info line") },
+			wantLogs: false,
+		},
+		{
+			name:     "error written at error level",
+			level:    "error",
+			log:      func(testLogger logger.Logger) { testLogger.Error("error line") },
+			wantLogs: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			testLogger := logger.NewLoggerWithConfig(logger.Config{Level: tt.level, Format: logger.TextFormat, Output: &output})
+
+			tt.log(testLogger)
+
+			assert.Equal(t, tt.wantLogs, strings.Contains(output.String(), "line"))
+		})
+	}
+}
@@ -0,0 +1,272 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	RedactedValue = "[REDACTED]"
+
+	// redactTag marks struct fields that must never be logged, e.g. `log:"redact"`
+	redactTag      = "log"
+	redactTagValue = "redact"
+	maxRedactDepth = 8
+)
+
+// DefaultRedactedFields are masked wherever they appear as struct fields, json names or log fields
+var DefaultRedactedFields = []string{"salary", "name", "password", "token", "access_token", "authorization"}
+
+type Redactor interface {
+	// Redact returns a copy of the value that is safe to log
+	Redact(value interface{}) interface{}
+	// IsRedacted tells if a field with this name must be masked
+	IsRedacted(field string) bool
+}
+
+type redactorImpl struct {
+	fields map[string]bool
+}
+
+func NewRedactor(fields ...string) Redactor {
+	registry := map[string]bool{}
+	for _, field := range fields {
+		registry[normalizeField(field)] = true
+	}
+	return redactorImpl{
+		fields: registry,
+	}
+}
+
+func (r redactorImpl) IsRedacted(field string) bool {
+	return r.fields[normalizeField(field)]
+}
+
+func (r redactorImpl) Redact(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	// errors and types with their own string representation (e.g. time.Time) keep it, unless they carry a field that
+	// must be masked which their representation could print, like a Stringer wrapping a salary
+	switch value.(type) {
+	case error, fmt.Stringer:
+		if r.carriesRedacted(reflect.ValueOf(value), 0) {
+			return RedactedValue
+		}
+		return value
+	}
+	return r.redactValue(reflect.ValueOf(value), 0)
+}
+
+// carriesRedacted tells if the value holds a struct with an exported field that must be masked, unexported fields are
+// followed too as a String method can print them
+func (r redactorImpl) carriesRedacted(value reflect.Value, depth int) bool {
+	if depth > maxRedactDepth {
+		return true
+	}
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return !value.IsNil() && r.carriesRedacted(value.Elem(), depth+1)
+	case reflect.Struct:
+		valueType := value.Type()
+		for i := 0; i < valueType.NumField(); i++ {
+			field := valueType.Field(i)
+			if field.IsExported() && r.isRedactedField(field) {
+				return true
+			}
+			if r.carriesRedacted(value.Field(i), depth+1) {
+				return true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if r.carriesRedacted(value.Index(i), depth+1) {
+				return true
+			}
+		}
+	case reflect.Map:
+		for _, key := range value.MapKeys() {
+			if r.carriesRedacted(value.MapIndex(key), depth+1) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isScalar tells if the value is a number or a string, which are masked when they are printed right after the name of a
+// masked field, like the amount of "salary %f", as they can not be told apart from other values by their type
+func isScalar(value interface{}) bool {
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// formatLabels returns the word before the verb of every argument of a printf format, nil when the format picks its
+// arguments by index
+func formatLabels(format string) []string {
+	var labels []string
+	literal := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		label := lastWord(format[literal:i])
+		i++
+		for i < len(format) && strings.IndexByte("+-# 0123456789.*[", format[i]) >= 0 {
+			switch format[i] {
+			case '[':
+				return nil
+			case '*':
+				labels = append(labels, "")
+			}
+			i++
+		}
+		if i < len(format) && format[i] != '%' {
+			labels = append(labels, label)
+		}
+		literal = i + 1
+	}
+	return labels
+}
+
+// lastWord returns the last word of the text when it ends with it or with a separator after it, like "salary: "
+func lastWord(text string) string {
+	text = strings.TrimRight(text, " :=")
+	start := strings.LastIndexFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-'
+	})
+	return text[start+1:]
+}
+
+func (r redactorImpl) redactValue(value reflect.Value, depth int) interface{} {
+	if depth > maxRedactDepth {
+		return RedactedValue
+	}
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+		return r.redactValue(value.Elem(), depth+1)
+	case reflect.Struct:
+		return r.redactStruct(value, depth)
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return nil
+		}
+		items := make(redactedList, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			items[i] = r.redactValue(value.Index(i), depth+1)
+		}
+		return items
+	case reflect.Map:
+		if value.IsNil() {
+			return nil
+		}
+		entries := redactedStruct{}
+		for _, key := range value.MapKeys() {
+			name := fmt.Sprint(key.Interface())
+			if r.IsRedacted(name) {
+				entries = append(entries, redactedField{name: name, value: RedactedValue})
+				continue
+			}
+			entries = append(entries, redactedField{name: name, value: r.redactValue(value.MapIndex(key), depth+1)})
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+		return entries
+	}
+	if !value.CanInterface() {
+		return RedactedValue
+	}
+	return value.Interface()
+}
+
+func (r redactorImpl) redactStruct(value reflect.Value, depth int) interface{} {
+	valueType := value.Type()
+	fields := make(redactedStruct, 0, valueType.NumField())
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if r.isRedactedField(field) {
+			fields = append(fields, redactedField{name: field.Name, value: RedactedValue})
+			continue
+		}
+		fields = append(fields, redactedField{name: field.Name, value: r.redactValue(value.Field(i), depth+1)})
+	}
+	return fields
+}
+
+func (r redactorImpl) isRedactedField(field reflect.StructField) bool {
+	if field.Tag.Get(redactTag) == redactTagValue {
+		return true
+	}
+	if r.IsRedacted(field.Name) {
+		return true
+	}
+	jsonName := strings.Split(field.Tag.Get("json"), ",")[0]
+	return jsonName != "" && r.IsRedacted(jsonName)
+}
+
+func normalizeField(field string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(field), "-", "_"))
+}
+
+type redactedField struct {
+	name  string
+	value interface{}
+}
+
+// redactedStruct prints like %+v so redacted values keep the shape of the original log lines
+type redactedStruct []redactedField
+
+func (s redactedStruct) String() string {
+	parts := make([]string, len(s))
+	for i, field := range s {
+		parts[i] = fmt.Sprintf("%s:%v", field.name, field.value)
+	}
+	return "{" + strings.Join(parts, " ") + "}"
+}
+
+func (s redactedStruct) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+	for i, field := range s {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		name, err := json.Marshal(field.name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(field.value)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(name)
+		buffer.WriteByte(':')
+		buffer.Write(value)
+	}
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+type redactedList []interface{}
+
+func (l redactedList) String() string {
+	parts := make([]string, len(l))
+	for i, item := range l {
+		parts[i] = fmt.Sprintf("%v", item)
+	}
+	return "[" + strings.Join(parts, " ") + "]"
+}
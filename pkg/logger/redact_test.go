@@ -0,0 +1,139 @@
+package logger_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"salaries/pkg/domain"
+	"salaries/pkg/logger"
+	"testing"
+	"time"
+)
+
+var salary = domain.Salary{
+	ID:            1,
+	Name:          "Anurag",
+	Salary:        90000,
+	Currency:      "USD",
+	Department:    "Banking",
+	SubDepartment: "Loan",
+}
+
+func TestLogger_RedactsSalaries(t *testing.T) {
+	type logCall struct {
+		name string
+		log  func(testLogger logger.Logger)
+	}
+	calls := []logCall{
+		{name: "struct", log: func(testLogger logger.Logger) { testLogger.Info("salary %v", salary) }},
+		{name: "pointer", log: func(testLogger logger.Logger) { testLogger.Info("salary %+v", &salary) }},
+		{name: "slice", log: func(testLogger logger.Logger) { testLogger.Info("salaries %v", []domain.Salary{salary}) }},
+		{name: "map", log: func(testLogger logger.Logger) {
+			testLogger.Info("salary %v", map[string]interface{}{"name": "Anurag", "salary": 90000})
+		}},
+		{name: "fields", log: func(testLogger logger.Logger) {
+			testLogger.With(logger.Fields{"salary": 90000, "record": salary}).Info("salary created")
+		}},
+		{name: "user", log: func(testLogger logger.Logger) {
+			testLogger.Info("user %v", domain.User{ID: 1, Username: "pmagnaghi", PasswordHash: "Anurag90000"})
+		}},
+		{name: "stringer", log: func(testLogger logger.Logger) { testLogger.Info("salary %s", salaryStringer{salary: salary}) }},
+		{name: "stringer pointer", log: func(testLogger logger.Logger) { testLogger.Info("record %v", &salaryStringer{salary: salary}) }},
+		{name: "scalars", log: func(testLogger logger.Logger) {
+			testLogger.Info("salary %f in %s, name=%s", salary.Salary, salary.Currency, salary.Name)
+		}},
+	}
+	levels := []struct {
+		name string
+		log  func(testLogger logger.Logger, call logCall)
+	}{
+		{name: "debug", log: func(testLogger logger.Logger, call logCall) { call.log(debugOnly{testLogger}) }},
+		{name: "info", log: func(testLogger logger.Logger, call logCall) { call.log(testLogger) }},
+		{name: "warn", log: func(testLogger logger.Logger, call logCall) { call.log(warnOnly{testLogger}) }},
+		{name: "error", log: func(testLogger logger.Logger, call logCall) { call.log(errorOnly{testLogger}) }},
+	}
+	for _, format := range []string{logger.TextFormat, logger.JSONFormat} {
+		for _, level := range levels {
+			for _, call := range calls {
+				t.Run(format+"/"+level.name+"/"+call.name, func(t *testing.T) {
+					var output bytes.Buffer
+					testLogger := logger.NewLoggerWithConfig(logger.Config{Level: "debug", Format: format, Output: &output})
+
+					level.log(testLogger, call)
+
+					assert.NotEmpty(t, output.String())
+					assert.NotContains(t, output.String(), "90000")
+					assert.NotContains(t, output.String(), "Anurag")
+					assert.Contains(t, output.String(), logger.RedactedValue)
+				})
+			}
+		}
+	}
+}
+
+func TestLogger_RedactsConfiguredFields(t *testing.T) {
+	var output bytes.Buffer
+	testLogger := logger.NewLoggerWithConfig(logger.Config{
+		Level:          "info",
+		Format:         logger.TextFormat,
+		Output:         &output,
+		RedactedFields: []string{"Department"},
+	})
+
+	testLogger.Info("salary %v", salary)
+
+	assert.NotContains(t, output.String(), "Banking")
+	assert.Contains(t, output.String(), "Currency:USD")
+}
+
+func TestRedactor_KeepsSafeValues(t *testing.T) {
+	redactor := logger.NewRedactor(logger.DefaultRedactedFields...)
+
+	assert.Equal(t, 3, redactor.Redact(3))
+	assert.Equal(t, errors.New("failed"), redactor.Redact(errors.New("failed")))
+	now := time.Now()
+	assert.Equal(t, now, redactor.Redact(now))
+	assert.Equal(t, "USD", redactor.Redact("USD"))
+	assert.Nil(t, redactor.Redact(nil))
+	assert.True(t, redactor.IsRedacted("Access-Token"))
+	assert.False(t, redactor.IsRedacted("currency"))
+}
+
+func TestLogger_KeepsScalarsOfOtherFields(t *testing.T) {
+	var output bytes.Buffer
+	testLogger := logger.NewLoggerWithConfig(logger.Config{Level: "info", Format: logger.TextFormat, Output: &output})
+
+	testLogger.Info("salary with id %d in %s, 100%% %s", 7, "USD", "done")
+
+	assert.Contains(t, output.String(), "salary with id 7 in USD, 100% done")
+}
+
+// salaryStringer prints the salary it wraps, which its String method can not be trusted with
+type salaryStringer struct {
+	salary domain.Salary
+}
+
+func (s salaryStringer) String() string {
+	return fmt.Sprintf("%s earns %.0f", s.salary.Name, s.salary.Salary)
+}
+
+// the wrappers below route the info calls of the test cases to the other levels
+
+type debugOnly struct{ logger.Logger }
+
+func (l debugOnly) With(fields logger.Fields) logger.Logger { return debugOnly{l.Logger.With(fields)} }
+
+func (l debugOnly) Info(format string, args ...interface{}) { l.Logger.Debug(format, args...) }
+
+type warnOnly struct{ logger.Logger }
+
+func (l warnOnly) With(fields logger.Fields) logger.Logger { return warnOnly{l.Logger.With(fields)} }
+
+func (l warnOnly) Info(format string, args ...interface{}) { l.Logger.Warn(format, args...) }
+
+type errorOnly struct{ logger.Logger }
+
+func (l errorOnly) With(fields logger.Fields) logger.Logger { return errorOnly{l.Logger.With(fields)} }
+
+func (l errorOnly) Info(format string, args ...interface{}) { l.Logger.Error(format, args...) }
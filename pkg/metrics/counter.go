@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+)
+
+type Counter struct {
+	mu     sync.Mutex
+	labels []Label
+	value  float64
+}
+
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+// Add increases the counter, negative values are ignored because counters can only go up
+func (c *Counter) Add(value float64) {
+	if value < 0 {
+		return
+	}
+	c.mu.Lock()
+	c.value += value
+	c.mu.Unlock()
+}
+
+func (c *Counter) Value() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.value
+}
+
+type CounterVec struct {
+	name       string
+	help       string
+	labelNames []string
+	mu         sync.RWMutex
+	counters   map[string]*Counter
+}
+
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{
+		name:       name,
+		help:       help,
+		labelNames: labelNames,
+		counters:   map[string]*Counter{},
+	}
+}
+
+func (v *CounterVec) WithLabelValues(values ...string) *Counter {
+	checkLabelValues(v.name, v.labelNames, values)
+	key := labelsKey(values)
+
+	v.mu.RLock()
+	counter, ok := v.counters[key]
+	v.mu.RUnlock()
+	if ok {
+		return counter
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if counter, ok = v.counters[key]; !ok {
+		counter = &Counter{labels: makeLabels(v.labelNames, values)}
+		v.counters[key] = counter
+	}
+	return counter
+}
+
+func (v *CounterVec) Collect() []Family {
+	v.mu.RLock()
+	keys := make([]string, 0, len(v.counters))
+	for key := range v.counters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	samples := make([]Sample, 0, len(keys))
+	for _, key := range keys {
+		counter := v.counters[key]
+		samples = append(samples, Sample{Labels: counter.labels, Value: counter.Value()})
+	}
+	v.mu.RUnlock()
+
+	return []Family{{Name: v.name, Help: v.help, Type: CounterType, Samples: samples}}
+}
@@ -0,0 +1,30 @@
+package metrics
+
+import "database/sql"
+
+// NewDBStatsCollector exposes the connection pool stats of a database/sql handle
+func NewDBStatsCollector(db *sql.DB, dbName string) Collector {
+	return CollectorFunc(func() []Family {
+		stats := db.Stats()
+		labels := []Label{{Name: "db_name", Value: dbName}}
+		family := func(name, help, metricType string, value float64) Family {
+			return Family{
+				Name:    name,
+				Help:    help,
+				Type:    metricType,
+				Samples: []Sample{{Labels: labels, Value: value}},
+			}
+		}
+		return []Family{
+			family("go_sql_max_open_connections", "Maximum number of open connections to the database.", GaugeType, float64(stats.MaxOpenConnections)),
+			family("go_sql_open_connections", "The number of established connections both in use and idle.", GaugeType, float64(stats.OpenConnections)),
+			family("go_sql_in_use_connections", "The number of connections currently in use.", GaugeType, float64(stats.InUse)),
+			family("go_sql_idle_connections", "The number of idle connections.", GaugeType, float64(stats.Idle)),
+			family("go_sql_wait_count_total", "The total number of connections waited for.", CounterType, float64(stats.WaitCount)),
+			family("go_sql_wait_duration_seconds_total", "The total time blocked waiting for a new connection.", CounterType, stats.WaitDuration.Seconds()),
+			family("go_sql_max_idle_closed_total", "The total number of connections closed due to SetMaxIdleConns.", CounterType, float64(stats.MaxIdleClosed)),
+			family("go_sql_max_idle_time_closed_total", "The total number of connections closed due to SetConnMaxIdleTime.", CounterType, float64(stats.MaxIdleTimeClosed)),
+			family("go_sql_max_lifetime_closed_total", "The total number of connections closed due to SetConnMaxLifetime.", CounterType, float64(stats.MaxLifetimeClosed)),
+		}
+	})
+}
@@ -0,0 +1,40 @@
+package metrics_test
+
+import (
+	"context"
+	"database/sql"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"salaries/pkg/metrics"
+	"strings"
+	"testing"
+)
+
+func TestDBStatsCollector(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(3)
+	connection, err := db.Conn(context.Background())
+	require.NoError(t, err)
+	defer connection.Close()
+
+	registry := metrics.NewRegistry()
+	registry.MustRegister(metrics.NewDBStatsCollector(db, "salaries"))
+
+	var output strings.Builder
+	assert.NoError(t, registry.WriteText(&output))
+	for _, line := range []string{
+		"# TYPE go_sql_max_open_connections gauge",
+		`go_sql_max_open_connections{db_name="salaries"} 3`,
+		`go_sql_open_connections{db_name="salaries"} 1`,
+		`go_sql_in_use_connections{db_name="salaries"} 1`,
+		`go_sql_idle_connections{db_name="salaries"} 0`,
+		"# TYPE go_sql_wait_count_total counter",
+		`go_sql_wait_count_total{db_name="salaries"} 0`,
+		`go_sql_max_lifetime_closed_total{db_name="salaries"} 0`,
+	} {
+		assert.Contains(t, output.String(), line+"\n")
+	}
+}
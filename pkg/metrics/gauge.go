@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+)
+
+type Gauge struct {
+	mu     sync.Mutex
+	labels []Label
+	value  float64
+}
+
+func (g *Gauge) Set(value float64) {
+	g.mu.Lock()
+	g.value = value
+	g.mu.Unlock()
+}
+
+func (g *Gauge) Add(value float64) {
+	g.mu.Lock()
+	g.value += value
+	g.mu.Unlock()
+}
+
+func (g *Gauge) Inc() {
+	g.Add(1)
+}
+
+func (g *Gauge) Dec() {
+	g.Add(-1)
+}
+
+func (g *Gauge) Value() float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.value
+}
+
+type GaugeVec struct {
+	name       string
+	help       string
+	labelNames []string
+	mu         sync.RWMutex
+	gauges     map[string]*Gauge
+}
+
+func NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+	return &GaugeVec{
+		name:       name,
+		help:       help,
+		labelNames: labelNames,
+		gauges:     map[string]*Gauge{},
+	}
+}
+
+func (v *GaugeVec) WithLabelValues(values ...string) *Gauge {
+	checkLabelValues(v.name, v.labelNames, values)
+	key := labelsKey(values)
+
+	v.mu.RLock()
+	gauge, ok := v.gauges[key]
+	v.mu.RUnlock()
+	if ok {
+		return gauge
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if gauge, ok = v.gauges[key]; !ok {
+		gauge = &Gauge{labels: makeLabels(v.labelNames, values)}
+		v.gauges[key] = gauge
+	}
+	return gauge
+}
+
+func (v *GaugeVec) Collect() []Family {
+	v.mu.RLock()
+	keys := make([]string, 0, len(v.gauges))
+	for key := range v.gauges {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	samples := make([]Sample, 0, len(keys))
+	for _, key := range keys {
+		gauge := v.gauges[key]
+		samples = append(samples, Sample{Labels: gauge.labels, Value: gauge.Value()})
+	}
+	v.mu.RUnlock()
+
+	return []Family{{Name: v.name, Help: v.help, Type: GaugeType, Samples: samples}}
+}
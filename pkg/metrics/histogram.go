@@ -0,0 +1,115 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// DefaultBuckets are latency buckets in seconds, suited for HTTP handlers
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+type Histogram struct {
+	mu      sync.Mutex
+	labels  []Label
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+}
+
+func (h *Histogram) Observe(value float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i, upperBound := range h.buckets {
+		if value <= upperBound {
+			h.counts[i]++
+		}
+	}
+	h.sum += value
+	h.count++
+}
+
+func (h *Histogram) samples() []Sample {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	samples := make([]Sample, 0, len(h.buckets)+3)
+	for i, upperBound := range h.buckets {
+		samples = append(samples, Sample{
+			Suffix: "_bucket",
+			Labels: append(append([]Label{}, h.labels...), Label{Name: "le", Value: formatValue(upperBound)}),
+			Value:  float64(h.counts[i]),
+		})
+	}
+	samples = append(samples,
+		Sample{
+			Suffix: "_bucket",
+			Labels: append(append([]Label{}, h.labels...), Label{Name: "le", Value: formatValue(math.Inf(1))}),
+			Value:  float64(h.count),
+		},
+		Sample{Suffix: "_sum", Labels: h.labels, Value: h.sum},
+		Sample{Suffix: "_count", Labels: h.labels, Value: float64(h.count)},
+	)
+	return samples
+}
+
+type HistogramVec struct {
+	name       string
+	help       string
+	buckets    []float64
+	labelNames []string
+	mu         sync.RWMutex
+	histograms map[string]*Histogram
+}
+
+func NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	sorted := append([]float64{}, buckets...)
+	sort.Float64s(sorted)
+	return &HistogramVec{
+		name:       name,
+		help:       help,
+		buckets:    sorted,
+		labelNames: labelNames,
+		histograms: map[string]*Histogram{},
+	}
+}
+
+func (v *HistogramVec) WithLabelValues(values ...string) *Histogram {
+	checkLabelValues(v.name, v.labelNames, values)
+	key := labelsKey(values)
+
+	v.mu.RLock()
+	histogram, ok := v.histograms[key]
+	v.mu.RUnlock()
+	if ok {
+		return histogram
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if histogram, ok = v.histograms[key]; !ok {
+		histogram = &Histogram{
+			labels:  makeLabels(v.labelNames, values),
+			buckets: v.buckets,
+			counts:  make([]uint64, len(v.buckets)),
+		}
+		v.histograms[key] = histogram
+	}
+	return histogram
+}
+
+func (v *HistogramVec) Collect() []Family {
+	v.mu.RLock()
+	keys := make([]string, 0, len(v.histograms))
+	for key := range v.histograms {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var samples []Sample
+	for _, key := range keys {
+		samples = append(samples, v.histograms[key].samples()...)
+	}
+	v.mu.RUnlock()
+
+	return []Family{{Name: v.name, Help: v.help, Type: HistogramType, Samples: samples}}
+}
@@ -0,0 +1,176 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	CounterType   = "counter"
+	GaugeType     = "gauge"
+	HistogramType = "histogram"
+
+	ContentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+type Label struct {
+	Name  string
+	Value string
+}
+
+type Sample struct {
+	Suffix string
+	Labels []Label
+	Value  float64
+}
+
+type Family struct {
+	Name    string
+	Help    string
+	Type    string
+	Samples []Sample
+}
+
+// Collector is anything able to report its current values as metric families
+type Collector interface {
+	Collect() []Family
+}
+
+// CollectorFunc adapts a plain function to the Collector interface, useful for values computed at scrape time
+type CollectorFunc func() []Family
+
+func (f CollectorFunc) Collect() []Family {
+	return f()
+}
+
+type Registry struct {
+	mu         sync.RWMutex
+	collectors []Collector
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) MustRegister(collectors ...Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, collectors...)
+}
+
+// Gather collects every registered family, merging families with the same name and sorting them by name
+func (r *Registry) Gather() []Family {
+	r.mu.RLock()
+	collectors := make([]Collector, len(r.collectors))
+	copy(collectors, r.collectors)
+	r.mu.RUnlock()
+
+	byName := map[string]*Family{}
+	var names []string
+	for _, collector := range collectors {
+		for _, family := range collector.Collect() {
+			existing, ok := byName[family.Name]
+			if !ok {
+				family := family
+				byName[family.Name] = &family
+				names = append(names, family.Name)
+				continue
+			}
+			existing.Samples = append(existing.Samples, family.Samples...)
+		}
+	}
+	sort.Strings(names)
+
+	families := make([]Family, 0, len(names))
+	for _, name := range names {
+		families = append(families, *byName[name])
+	}
+	return families
+}
+
+// WriteText writes the registry in the Prometheus text exposition format
+func (r *Registry) WriteText(w io.Writer) error {
+	for _, family := range r.Gather() {
+		if family.Help != "" {
+			if _, err := fmt.Fprintf(w, "# HELP %s %s\n", family.Name, escapeHelp(family.Help)); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "# TYPE %s %s\n", family.Name, family.Type); err != nil {
+			return err
+		}
+		for _, sample := range family.Samples {
+			if _, err := fmt.Fprintf(w, "%s%s%s %s\n", family.Name, sample.Suffix, formatLabels(sample.Labels), formatValue(sample.Value)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		w.WriteHeader(http.StatusOK)
+		r.WriteText(w)
+	})
+}
+
+func formatLabels(labels []Label) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	pairs := make([]string, 0, len(labels))
+	for _, label := range labels {
+		pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", label.Name, escapeLabelValue(label.Value)))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+var helpEscaper = strings.NewReplacer("\\", `\\`, "\n", `\n`)
+
+var labelValueEscaper = strings.NewReplacer("\\", `\\`, "\n", `\n`, "\"", `\"`)
+
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
+
+func makeLabels(names, values []string) []Label {
+	labels := make([]Label, len(names))
+	for i, name := range names {
+		labels[i] = Label{Name: name, Value: values[i]}
+	}
+	return labels
+}
+
+func labelsKey(values []string) string {
+	return strings.Join(values, "\xff")
+}
+
+func checkLabelValues(name string, labelNames, values []string) {
+	if len(labelNames) != len(values) {
+		panic(fmt.Sprintf("metric %s expects %d label values, got %d", name, len(labelNames), len(values)))
+	}
+}
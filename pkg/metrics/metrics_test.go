@@ -0,0 +1,117 @@
+package metrics_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"salaries/pkg/metrics"
+	"strings"
+	"testing"
+)
+
+func TestRegistry_WriteText(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func(registry *metrics.Registry)
+		want     []string
+	}{
+		{
+			name: "counter",
+			register: func(registry *metrics.Registry) {
+				counter := metrics.NewCounterVec("logins_total", "Total logins.", "result")
+				registry.MustRegister(counter)
+				counter.WithLabelValues("success").Inc()
+				counter.WithLabelValues("success").Add(2)
+				counter.WithLabelValues("failure").Inc()
+			},
+			want: []string{
+				"# HELP logins_total Total logins.",
+				"# TYPE logins_total counter",
+				`logins_total{result="failure"} 1`,
+				`logins_total{result="success"} 3`,
+			},
+		},
+		{
+			name: "gauge",
+			register: func(registry *metrics.Registry) {
+				gauge := metrics.NewGaugeVec("records", "Records.", "department")
+				registry.MustRegister(gauge)
+				gauge.WithLabelValues("Banking").Set(5)
+				gauge.WithLabelValues("Banking").Dec()
+			},
+			want: []string{
+				"# TYPE records gauge",
+				`records{department="Banking"} 4`,
+			},
+		},
+		{
+			name: "histogram",
+			register: func(registry *metrics.Registry) {
+				histogram := metrics.NewHistogramVec("latency_seconds", "Latency.", []float64{0.1, 1}, "route")
+				registry.MustRegister(histogram)
+				histogram.WithLabelValues("/api").Observe(0.05)
+				histogram.WithLabelValues("/api").Observe(0.5)
+				histogram.WithLabelValues("/api").Observe(5)
+			},
+			want: []string{
+				"# TYPE latency_seconds histogram",
+				`latency_seconds_bucket{route="/api",le="0.1"} 1`,
+				`latency_seconds_bucket{route="/api",le="1"} 2`,
+				`latency_seconds_bucket{route="/api",le="+Inf"} 3`,
+				`latency_seconds_sum{route="/api"} 5.55`,
+				`latency_seconds_count{route="/api"} 3`,
+			},
+		},
+		{
+			name: "escaped label values",
+			register: func(registry *metrics.Registry) {
+				gauge := metrics.NewGaugeVec("records", "Records.", "department")
+				registry.MustRegister(gauge)
+				gauge.WithLabelValues("Sales \"EMEA\"").Set(1)
+			},
+			want: []string{
+				`records{department="Sales \"EMEA\""} 1`,
+			},
+		},
+		{
+			name: "collector func",
+			register: func(registry *metrics.Registry) {
+				registry.MustRegister(metrics.CollectorFunc(func() []metrics.Family {
+					return []metrics.Family{{Name: "up", Type: metrics.GaugeType, Samples: []metrics.Sample{{Value: 1}}}}
+				}))
+			},
+			want: []string{
+				"# TYPE up gauge",
+				"up 1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := metrics.NewRegistry()
+			tt.register(registry)
+
+			var output strings.Builder
+			err := registry.WriteText(&output)
+
+			assert.NoError(t, err)
+			for _, line := range tt.want {
+				assert.Contains(t, output.String(), line+"\n")
+			}
+		})
+	}
+}
+
+func TestRegistry_Handler(t *testing.T) {
+	registry := metrics.NewRegistry()
+	counter := metrics.NewCounterVec("requests_total", "Requests.")
+	registry.MustRegister(counter)
+	counter.WithLabelValues().Inc()
+
+	w := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, metrics.ContentType, w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "requests_total 1\n")
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"salaries/pkg/requestctx"
+	"strings"
+)
+
+const CacheControlHeader = "Cache-Control"
+
+// NewCacheControlMiddleware bypasses the caches of the services for requests with a Cache-Control: no-cache header
+func NewCacheControlMiddleware() gin.HandlerFunc {
+	return func(context *gin.Context) {
+		for _, directive := range strings.Split(context.GetHeader(CacheControlHeader), ",") {
+			if strings.EqualFold(strings.TrimSpace(directive), "no-cache") {
+				context.Request = context.Request.WithContext(requestctx.WithCacheBypass(context.Request.Context()))
+				break
+			}
+		}
+		context.Next()
+	}
+}
@@ -0,0 +1,251 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/gin-gonic/gin"
+	"hash"
+	"io"
+	"net/http"
+	"salaries/pkg/domain"
+	"salaries/pkg/logger"
+	"salaries/pkg/repository"
+	"salaries/pkg/requestctx"
+	"time"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	anonymousIdempotencyScope = "anonymous"
+
+	// maxIdempotentBodySize is the largest body hashed for an Idempotency-Key, the body is read in memory to hash it
+	maxIdempotentBodySize = 1 << 20
+)
+
+// NewIdempotencyMiddleware replays the stored response of a POST retried with the same Idempotency-Key within the window,
+// a key reused with a different request is rejected with 422 and a key whose first request is still running with 409.
+// The body is read in memory to be hashed, so it must not be larger than 1MB, routes streaming their body use
+// NewStreamingIdempotencyMiddleware
+func NewIdempotencyMiddleware(idempotencyRepository repository.IdempotencyRepository, window time.Duration, logger logger.Logger) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		key, ok := idempotencyKey(context)
+		if !ok {
+			return
+		}
+		ctx := context.Request.Context()
+
+		body, err := io.ReadAll(io.LimitReader(context.Request.Body, maxIdempotentBodySize+1))
+		if err != nil {
+			context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			context.Abort()
+			return
+		}
+		if len(body) > maxIdempotentBodySize {
+			context.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "the body of a request with an Idempotency-Key must not be larger than 1MB"})
+			context.Abort()
+			return
+		}
+		context.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		record := &domain.IdempotencyRecord{
+			Scope:       idempotencyScope(context),
+			Key:         key,
+			RequestHash: requestHash(context.Request, body),
+		}
+		stored, err := idempotencyRepository.Reserve(ctx, record, time.Now().Add(-window))
+		if err != nil {
+			logger.WithContext(ctx).Error("failed to reserve idempotency key: %s", err.Error())
+			context.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			context.Abort()
+			return
+		}
+		if stored != nil {
+			replay(context, stored, record.RequestHash)
+			return
+		}
+		serveIdempotent(context, idempotencyRepository, record, logger, func() error { return nil })
+	}
+}
+
+// NewStreamingIdempotencyMiddleware is the idempotency of the routes streaming their body: the body is hashed while the
+// handler reads it and the hash is stored with the response. A retry of a completed request has its body read and
+// hashed before the response is replayed, a retry of a request still running is rejected with 409 without reading it
+func NewStreamingIdempotencyMiddleware(idempotencyRepository repository.IdempotencyRepository, window time.Duration, logger logger.Logger) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		key, ok := idempotencyKey(context)
+		if !ok {
+			return
+		}
+		ctx := context.Request.Context()
+
+		// the hash is only known once the body is read, it is stored by Complete
+		record := &domain.IdempotencyRecord{
+			Scope: idempotencyScope(context),
+			Key:   key,
+		}
+		stored, err := idempotencyRepository.Reserve(ctx, record, time.Now().Add(-window))
+		if err != nil {
+			logger.WithContext(ctx).Error("failed to reserve idempotency key: %s", err.Error())
+			context.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			context.Abort()
+			return
+		}
+		if stored != nil && !stored.Completed() {
+			context.JSON(http.StatusConflict, gin.H{"error": "a request with this Idempotency-Key is still in progress"})
+			context.Abort()
+			return
+		}
+
+		hash := newRequestHash(context.Request)
+		body := context.Request.Body
+		if stored != nil {
+			if _, err := io.Copy(hash, body); err != nil {
+				context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				context.Abort()
+				return
+			}
+			replay(context, stored, hex.EncodeToString(hash.Sum(nil)))
+			return
+		}
+
+		context.Request.Body = hashingBody{Reader: io.TeeReader(body, hash), Closer: body}
+		serveIdempotent(context, idempotencyRepository, record, logger, func() error {
+			// the rest of a body the handler stopped reading is part of the request too
+			if _, err := io.Copy(hash, body); err != nil {
+				return err
+			}
+			record.RequestHash = hex.EncodeToString(hash.Sum(nil))
+			return nil
+		})
+	}
+}
+
+// idempotencyKey returns the Idempotency-Key of a POST, it goes on with the request when there is none and rejects
+// a key that is too long
+func idempotencyKey(context *gin.Context) (string, bool) {
+	key := context.GetHeader(IdempotencyKeyHeader)
+	if context.Request.Method != http.MethodPost || key == "" {
+		context.Next()
+		return "", false
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key must not be longer than 255 characters"})
+		context.Abort()
+		return "", false
+	}
+	return key, true
+}
+
+// serveIdempotent runs the request of a reserved key and stores its response, finish is called before the response is
+// stored and the key is released when it fails
+func serveIdempotent(context *gin.Context, idempotencyRepository repository.IdempotencyRepository, record *domain.IdempotencyRecord, logger logger.Logger, finish func() error) {
+	ctx := context.Request.Context()
+	release := func() {
+		if err := idempotencyRepository.Release(ctx, record.Scope, record.Key); err != nil {
+			logger.WithContext(ctx).Error("failed to release idempotency key: %s", err.Error())
+		}
+	}
+	// a panic is a server error too, the key is released before the panic goes on to the recovery
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			release()
+			panic(recovered)
+		}
+	}()
+
+	writer := &recordingWriter{ResponseWriter: context.Writer}
+	context.Writer = writer
+	context.Next()
+
+	// server errors are not stored so the client can retry them with the same key
+	if writer.Status() >= http.StatusInternalServerError {
+		release()
+		return
+	}
+	if err := finish(); err != nil {
+		logger.WithContext(ctx).Error("failed to hash idempotent request: %s", err.Error())
+		release()
+		return
+	}
+	record.StatusCode = writer.Status()
+	record.Header = storedHeaders(writer.Header())
+	record.Body = writer.body.Bytes()
+	if err := idempotencyRepository.Complete(ctx, record); err != nil {
+		logger.WithContext(ctx).Error("failed to store idempotent response: %s", err.Error())
+	}
+}
+
+func replay(context *gin.Context, stored *domain.IdempotencyRecord, requestHash string) {
+	switch {
+	case stored.RequestHash != requestHash:
+		context.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency-Key was already used with a different request"})
+		context.Abort()
+	case !stored.Completed():
+		context.JSON(http.StatusConflict, gin.H{"error": "a request with this Idempotency-Key is still in progress"})
+		context.Abort()
+	default:
+		for name, values := range stored.Header {
+			context.Writer.Header()[name] = values
+		}
+		context.Header(IdempotentReplayedHeader, "true")
+		context.Status(stored.StatusCode)
+		context.Writer.Write(stored.Body)
+		context.Abort()
+	}
+}
+
+// idempotencyScope keeps the keys of different users apart, the middleware runs after the authentication
+func idempotencyScope(context *gin.Context) string {
+	if user := requestctx.User(context.Request.Context()); user != nil {
+		return user.Username
+	}
+	return anonymousIdempotencyScope
+}
+
+func requestHash(request *http.Request, body []byte) string {
+	hash := newRequestHash(request)
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// newRequestHash starts the hash of a request, its body is written to it
+func newRequestHash(request *http.Request) hash.Hash {
+	hash := sha256.New()
+	io.WriteString(hash, request.Method+" "+request.URL.RequestURI()+"\n")
+	return hash
+}
+
+// hashingBody is a request body that writes what is read from it to the request hash
+type hashingBody struct {
+	io.Reader
+	io.Closer
+}
+
+// storedHeaders keeps the headers describing the body, the ones written by other middlewares are set again on the replay
+func storedHeaders(header http.Header) http.Header {
+	stored := http.Header{}
+	for _, name := range []string{"Content-Type", "Content-Disposition", "Location", "ETag"} {
+		if values := header.Values(name); len(values) > 0 {
+			stored[name] = values
+		}
+	}
+	return stored
+}
+
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(data string) (int, error) {
+	w.body.WriteString(data)
+	return w.ResponseWriter.WriteString(data)
+}
@@ -0,0 +1,182 @@
+package middleware_test
+
+import (
+	"context"
+	"database/sql"
+	"github.com/gin-gonic/gin"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"salaries/pkg/db"
+	"salaries/pkg/domain"
+	"salaries/pkg/logger"
+	"salaries/pkg/middleware"
+	"salaries/pkg/repository"
+	"salaries/pkg/requestctx"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newIdempotentRouter(t *testing.T, handler gin.HandlerFunc) *gin.Engine {
+	return newIdempotentRouterWith(t, middleware.NewIdempotencyMiddleware, handler)
+}
+
+func newIdempotentRouterWith(t *testing.T, newMiddleware func(repository.IdempotencyRepository, time.Duration, logger.Logger) gin.HandlerFunc, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	database, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	database.SetMaxOpenConns(1)
+	t.Cleanup(func() { database.Close() })
+	require.NoError(t, db.Migrate(context.Background(), database))
+
+	idempotencyRepository := repository.NewIdempotencyRepositoryWithClient(db.NewSqliteIdempotencyClient(database))
+	testLogger := logger.NewLoggerWithConfig(logger.Config{Level: "error", Output: io.Discard})
+
+	router := gin.New()
+	router.Use(func(context *gin.Context) {
+		user := &domain.User{Username: context.GetHeader("X-User")}
+		context.Request = context.Request.WithContext(requestctx.WithUser(context.Request.Context(), user))
+	}, newMiddleware(idempotencyRepository, time.Hour, testLogger))
+	router.POST("/salaries", handler)
+	return router
+}
+
+func post(router http.Handler, user, key, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "/salaries", strings.NewReader(body))
+	request.Header.Set("X-User", user)
+	if key != "" {
+		request.Header.Set(middleware.IdempotencyKeyHeader, key)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	calls := 0
+	router := newIdempotentRouter(t, func(context *gin.Context) {
+		calls++
+		body, _ := io.ReadAll(context.Request.Body)
+		if string(body) == "fail" {
+			context.JSON(http.StatusInternalServerError, gin.H{"error": "failed"})
+			return
+		}
+		context.Header("Location", "/salaries/1")
+		context.JSON(http.StatusCreated, gin.H{"call": calls, "body": string(body)})
+	})
+
+	first := post(router, "pmagnaghi", "key-1", "salary")
+	require.Equal(t, http.StatusCreated, first.Code)
+
+	replayed := post(router, "pmagnaghi", "key-1", "salary")
+	assert.Equal(t, http.StatusCreated, replayed.Code)
+	assert.Equal(t, first.Body.String(), replayed.Body.String())
+	assert.Equal(t, "/salaries/1", replayed.Header().Get("Location"))
+	assert.Equal(t, "application/json; charset=utf-8", replayed.Header().Get("Content-Type"))
+	assert.Equal(t, "true", replayed.Header().Get(middleware.IdempotentReplayedHeader))
+	assert.Equal(t, 1, calls)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, post(router, "pmagnaghi", "key-1", "other salary").Code)
+	assert.Equal(t, 1, calls)
+
+	assert.Equal(t, http.StatusCreated, post(router, "analyst", "key-1", "salary").Code, "keys are scoped by user")
+	assert.Equal(t, http.StatusCreated, post(router, "pmagnaghi", "", "salary").Code)
+	assert.Equal(t, 3, calls)
+
+	assert.Equal(t, http.StatusInternalServerError, post(router, "pmagnaghi", "key-2", "fail").Code)
+	assert.Equal(t, http.StatusInternalServerError, post(router, "pmagnaghi", "key-2", "fail").Code, "server errors are not replayed")
+	assert.Equal(t, 5, calls)
+
+	assert.Equal(t, http.StatusBadRequest, post(router, "pmagnaghi", strings.Repeat("k", 256), "salary").Code)
+}
+
+func TestIdempotencyMiddleware_InProgress(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	router := newIdempotentRouter(t, func(context *gin.Context) {
+		close(started)
+		<-release
+		context.JSON(http.StatusCreated, gin.H{})
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- post(router, "pmagnaghi", "key-1", "salary") }()
+	<-started
+
+	assert.Equal(t, http.StatusConflict, post(router, "pmagnaghi", "key-1", "salary").Code)
+	close(release)
+	assert.Equal(t, http.StatusCreated, (<-done).Code)
+}
+
+func TestIdempotencyMiddleware_LargeBody(t *testing.T) {
+	calls := 0
+	router := newIdempotentRouter(t, func(context *gin.Context) {
+		calls++
+		context.JSON(http.StatusCreated, gin.H{})
+	})
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, post(router, "pmagnaghi", "key-1", strings.Repeat("s", 1<<20+1)).Code)
+	assert.Equal(t, http.StatusCreated, post(router, "pmagnaghi", "", strings.Repeat("s", 1<<20+1)).Code, "the body is not read without a key")
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotencyMiddleware_Panic(t *testing.T) {
+	calls := 0
+	router := newIdempotentRouter(t, func(context *gin.Context) {
+		calls++
+		if calls == 1 {
+			panic("failed")
+		}
+		context.JSON(http.StatusCreated, gin.H{})
+	})
+
+	assert.Panics(t, func() { post(router, "pmagnaghi", "key-1", "salary") })
+	assert.Equal(t, http.StatusCreated, post(router, "pmagnaghi", "key-1", "salary").Code, "the key is released on a panic")
+	assert.Equal(t, 2, calls)
+}
+
+func TestStreamingIdempotencyMiddleware(t *testing.T) {
+	calls := 0
+	router := newIdempotentRouterWith(t, middleware.NewStreamingIdempotencyMiddleware, func(context *gin.Context) {
+		calls++
+		// only the start of the body is read, the rest is hashed after the handler
+		io.ReadFull(context.Request.Body, make([]byte, 1))
+		context.JSON(http.StatusCreated, gin.H{"call": calls})
+	})
+	large := strings.Repeat("s", 2<<20)
+
+	first := post(router, "pmagnaghi", "key-1", large)
+	require.Equal(t, http.StatusCreated, first.Code, "the body is not limited")
+
+	replayed := post(router, "pmagnaghi", "key-1", large)
+	assert.Equal(t, http.StatusCreated, replayed.Code)
+	assert.Equal(t, first.Body.String(), replayed.Body.String())
+	assert.Equal(t, "true", replayed.Header().Get(middleware.IdempotentReplayedHeader))
+	assert.Equal(t, 1, calls)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, post(router, "pmagnaghi", "key-1", large+"s").Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestStreamingIdempotencyMiddleware_InProgress(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	router := newIdempotentRouterWith(t, middleware.NewStreamingIdempotencyMiddleware, func(context *gin.Context) {
+		close(started)
+		<-release
+		context.JSON(http.StatusCreated, gin.H{})
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- post(router, "pmagnaghi", "key-1", "salary") }()
+	<-started
+
+	assert.Equal(t, http.StatusConflict, post(router, "pmagnaghi", "key-1", "other salary").Code)
+	close(release)
+	assert.Equal(t, http.StatusCreated, (<-done).Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, post(router, "pmagnaghi", "key-1", "other salary").Code)
+}
@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// NewRequireIfMatchMiddleware rejects changes sent without an If-Match header, so clients can not overwrite a record they have not read
+func NewRequireIfMatchMiddleware() gin.HandlerFunc {
+	return func(context *gin.Context) {
+		if context.GetHeader("If-Match") == "" {
+			context.JSON(http.StatusPreconditionRequired, gin.H{"error": "If-Match header is required"})
+			context.Abort()
+			return
+		}
+		context.Next()
+	}
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"salaries/pkg/logger"
+	"time"
+)
+
+// NewLoggerMiddleware writes one structured access log line per request
+func NewLoggerMiddleware(log logger.Logger) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		start := time.Now()
+		context.Next()
+
+		status := context.Writer.Status()
+		requestLogger := log.WithContext(context.Request.Context()).With(logger.Fields{
+			"method":     context.Request.Method,
+			"path":       context.Request.URL.Path,
+			"status":     status,
+			"latency_ms": time.Since(start).Milliseconds(),
+			"client_ip":  context.ClientIP(),
+		})
+		switch {
+		case status >= 500:
+			requestLogger.Error("request failed")
+		case status >= 400:
+			requestLogger.Warn("request rejected")
+		default:
+			requestLogger.Info("request completed")
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"salaries/pkg/metrics"
+	"strconv"
+	"time"
+)
+
+const unmatchedRoute = "unmatched"
+
+// NewMetricsMiddleware counts the requests and observes their latency by route template and status, it runs before
+// gin.Recovery to see the status of the requests that panicked
+func NewMetricsMiddleware(registry *metrics.Registry) gin.HandlerFunc {
+	requests := metrics.NewCounterVec("http_requests_total", "Total number of HTTP requests by route and status.", "method", "route", "status")
+	latency := metrics.NewHistogramVec("http_request_duration_seconds", "HTTP request latency by route and status.", metrics.DefaultBuckets, "method", "route", "status")
+	registry.MustRegister(requests, latency)
+
+	return func(context *gin.Context) {
+		start := time.Now()
+		context.Next()
+
+		// the route template is used instead of the path to keep the label cardinality bounded
+		route := context.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		status := strconv.Itoa(context.Writer.Status())
+		method := context.Request.Method
+
+		requests.WithLabelValues(method, route, status).Inc()
+		latency.WithLabelValues(method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
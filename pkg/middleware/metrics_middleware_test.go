@@ -0,0 +1,38 @@
+package middleware_test
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"salaries/pkg/metrics"
+	"salaries/pkg/middleware"
+	"strings"
+	"testing"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	registry := metrics.NewRegistry()
+	router := gin.New()
+	router.Use(middleware.NewMetricsMiddleware(registry), gin.Recovery())
+	router.GET("/api/salaries/:id", func(context *gin.Context) {
+		if context.Param("id") == "0" {
+			panic("failed")
+		}
+		context.JSON(http.StatusOK, gin.H{})
+	})
+
+	for _, path := range []string{"/api/salaries/1", "/api/salaries/2", "/api/salaries/0", "/unknown/1", "/unknown/2"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	var output strings.Builder
+	assert.NoError(t, registry.WriteText(&output))
+	assert.Contains(t, output.String(), `http_requests_total{method="GET",route="/api/salaries/:id",status="200"} 2`+"\n", "the route template is the label")
+	assert.Contains(t, output.String(), `http_requests_total{method="GET",route="/api/salaries/:id",status="500"} 1`+"\n", "panics are counted")
+	assert.Contains(t, output.String(), `http_requests_total{method="GET",route="unmatched",status="404"} 2`+"\n")
+	assert.Contains(t, output.String(), `http_request_duration_seconds_count{method="GET",route="/api/salaries/:id",status="200"} 2`+"\n")
+	assert.NotContains(t, output.String(), "/api/salaries/1")
+	assert.NotContains(t, output.String(), "/unknown")
+}
@@ -1,11 +1,12 @@
 package middleware
 
 import (
-	"fmt"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"salaries/pkg/auth"
+	"salaries/pkg/logger"
+	"salaries/pkg/requestctx"
 )
 
 type JwtClaims struct {
@@ -13,15 +14,16 @@ type JwtClaims struct {
 	jwt.StandardClaims
 }
 
-func NewAuthMiddleware(authService auth.Service) gin.HandlerFunc {
+func NewAuthMiddleware(authService auth.Service, logger logger.Logger) gin.HandlerFunc {
 	return func(context *gin.Context) {
-		err := authService.VerifyToken(context)
+		user, err := authService.VerifyToken(context)
 		if err != nil {
 			context.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
-			fmt.Println(err)
+			logger.WithContext(context.Request.Context()).Warn("authentication failed: %s", err.Error())
 			context.Abort()
 			return
 		}
+		context.Request = context.Request.WithContext(requestctx.WithUser(context.Request.Context(), user))
 		context.Next()
 	}
 }
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/gin-gonic/gin"
+	"salaries/pkg/requestctx"
+)
+
+const (
+	RequestIDHeader    = "X-Request-ID"
+	maxRequestIDLength = 128
+)
+
+// NewRequestIDMiddleware propagates the X-Request-ID header of the request or assigns a new one,
+// and stores it with the route in the request context so every log line can be correlated
+func NewRequestIDMiddleware() gin.HandlerFunc {
+	return func(context *gin.Context) {
+		requestID := context.GetHeader(RequestIDHeader)
+		if !isValidRequestID(requestID) {
+			requestID = newRequestID()
+		}
+		context.Header(RequestIDHeader, requestID)
+
+		ctx := requestctx.WithRequestID(context.Request.Context(), requestID)
+		ctx = requestctx.WithRoute(ctx, context.FullPath())
+		context.Request = context.Request.WithContext(ctx)
+		context.Next()
+	}
+}
+
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range requestID {
+		if r < 0x21 || r > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(bytes)
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"salaries/pkg/requestctx"
+)
+
+// NewRoleMiddleware only lets through users with one of the roles, it must run after the auth middleware
+func NewRoleMiddleware(roles ...string) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		user := requestctx.User(context.Request.Context())
+		if user != nil {
+			for _, role := range roles {
+				if user.Role == role {
+					context.Next()
+					return
+				}
+			}
+		}
+		context.JSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+		context.Abort()
+	}
+}
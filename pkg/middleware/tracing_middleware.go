@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+)
+
+var tracer = otel.Tracer("salaries/pkg/middleware")
+
+// NewTracingMiddleware starts a server span per request, continuing the trace of the caller when it is propagated
+func NewTracingMiddleware() gin.HandlerFunc {
+	return func(context *gin.Context) {
+		route := context.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		ctx := otel.GetTextMapPropagator().Extract(context.Request.Context(), propagation.HeaderCarrier(context.Request.Header))
+		ctx, span := tracer.Start(ctx, fmt.Sprintf("%s %s", context.Request.Method, route),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.method", context.Request.Method),
+				attribute.String("http.route", route),
+			),
+		)
+		defer span.End()
+
+		context.Request = context.Request.WithContext(ctx)
+		context.Next()
+
+		status := context.Writer.Status()
+		span.SetAttributes(attribute.Int("http.status_code", status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
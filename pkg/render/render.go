@@ -0,0 +1,120 @@
+package render
+
+import (
+	"errors"
+	"io"
+	"math"
+	"mime"
+	"salaries/pkg/export"
+	"strconv"
+	"strings"
+)
+
+var ErrNotAcceptable = errors.New("none of the accepted media types can be rendered")
+
+// Renderer writes a response body in a single media type
+type Renderer interface {
+	ContentType() string
+	Render(w io.Writer, value interface{}) error
+}
+
+// Registry picks the renderer for the Accept header of a request, the first renderer registered is the default
+type Registry struct {
+	renderers []Renderer
+}
+
+func NewRegistry(renderers ...Renderer) *Registry {
+	return &Registry{renderers: renderers}
+}
+
+// NewDefaultRegistry renders json by default, and csv, ndjson, yaml and xml on request
+func NewDefaultRegistry() *Registry {
+	return NewRegistry(
+		JSONRenderer{},
+		TableRenderer{Format: export.CSV},
+		TableRenderer{Format: export.NDJSON},
+		YAMLRenderer{},
+		XMLRenderer{},
+	)
+}
+
+// Negotiate returns the renderer of the most preferred media type in the Accept header, an empty header accepts anything.
+// A media type gets the quality of the most specific range matching it, so a range with quality 0 excludes the types it
+// names from the wildcards. Types of the same quality are preferred in the order of the header, then of the registry
+func (r *Registry) Negotiate(accept string) (Renderer, error) {
+	if strings.TrimSpace(accept) == "" {
+		return r.renderers[0], nil
+	}
+	ranges := parseAccept(accept)
+	var best Renderer
+	var bestQuality float64
+	var bestIndex int
+	for _, renderer := range r.renderers {
+		quality, index := preference(ranges, mediaType(renderer.ContentType()))
+		if quality > bestQuality || quality > 0 && quality == bestQuality && index < bestIndex {
+			best, bestQuality, bestIndex = renderer, quality, index
+		}
+	}
+	if best == nil {
+		return nil, ErrNotAcceptable
+	}
+	return best, nil
+}
+
+type mediaRange struct {
+	mediaType string
+	quality   float64
+}
+
+// specificity ranks the range matching a media type, an exact type over a type with any subtype over anything. It is
+// -1 when the range does not match
+func (m mediaRange) specificity(mediaType string) int {
+	switch {
+	case m.mediaType == mediaType:
+		return 2
+	case strings.HasSuffix(m.mediaType, "/*") && m.mediaType != "*/*" && strings.HasPrefix(mediaType, strings.TrimSuffix(m.mediaType, "*")):
+		return 1
+	case m.mediaType == "*/*":
+		return 0
+	}
+	return -1
+}
+
+// preference returns the quality of the most specific range matching the media type and the position of the range in
+// the header, the quality is 0 when no range matches
+func preference(ranges []mediaRange, mediaType string) (float64, int) {
+	quality, index, specificity := 0.0, len(ranges), -1
+	for i, mediaRange := range ranges {
+		if rank := mediaRange.specificity(mediaType); rank > specificity {
+			quality, index, specificity = mediaRange.quality, i, rank
+		}
+	}
+	return quality, index
+}
+
+// parseAccept returns the media ranges in the order of the header, ranges with quality 0 are kept to exclude their types
+func parseAccept(accept string) []mediaRange {
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		ranges = append(ranges, mediaRange{mediaType: mediaType, quality: math.Max(0, quality)})
+	}
+	return ranges
+}
+
+func mediaType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return contentType
+	}
+	return mediaType
+}
@@ -0,0 +1,90 @@
+package render_test
+
+import (
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"salaries/pkg/api"
+	"salaries/pkg/domain"
+	"salaries/pkg/render"
+	"testing"
+)
+
+func TestRegistry_Negotiate(t *testing.T) {
+	tests := []struct {
+		name        string
+		accept      string
+		contentType string
+		wantError   bool
+	}{
+		{name: "no accept header", accept: "", contentType: "application/json; charset=utf-8"},
+		{name: "anything", accept: "*/*", contentType: "application/json; charset=utf-8"},
+		{name: "csv", accept: "text/csv", contentType: "text/csv; charset=utf-8"},
+		{name: "ndjson", accept: "application/x-ndjson", contentType: "application/x-ndjson"},
+		{name: "yaml", accept: "application/yaml", contentType: "application/yaml; charset=utf-8"},
+		{name: "xml", accept: "application/xml", contentType: "application/xml; charset=utf-8"},
+		{name: "preferred by quality", accept: "application/json;q=0.5, application/xml", contentType: "application/xml; charset=utf-8"},
+		{name: "wildcard subtype", accept: "text/*", contentType: "text/csv; charset=utf-8"},
+		{name: "skips unsupported types", accept: "text/html, application/yaml;q=0.9", contentType: "application/yaml; charset=utf-8"},
+		{name: "unsupported", accept: "text/html", wantError: true},
+		{name: "not acceptable quality", accept: "application/json;q=0", wantError: true},
+		{name: "excluded from the wildcard", accept: "application/json;q=0, */*", contentType: "text/csv; charset=utf-8"},
+		{name: "excluded from the subtype wildcard", accept: "text/*, text/csv;q=0", wantError: true},
+		{name: "most specific range wins", accept: "*/*;q=0.5, application/yaml, application/json;q=0.1", contentType: "application/yaml; charset=utf-8"},
+		{name: "wildcard below a named type", accept: "*/*;q=0.8, application/xml;q=0.9", contentType: "application/xml; charset=utf-8"},
+		{name: "header order between equal qualities", accept: "application/xml, text/csv", contentType: "application/xml; charset=utf-8"},
+		{name: "everything excluded", accept: "*/*;q=0", wantError: true},
+	}
+	registry := render.NewDefaultRegistry()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer, err := registry.Negotiate(tt.accept)
+			if tt.wantError {
+				assert.True(t, errors.Is(err, render.ErrNotAcceptable))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.contentType, renderer.ContentType())
+		})
+	}
+}
+
+func TestRenderers(t *testing.T) {
+	stats := []api.DepartmentStats{{Department: "Banking", Stats: api.Stats{Mean: 90000, Max: 90000, Min: 90000, Count: 1}}}
+	tests := []struct {
+		name     string
+		renderer render.Renderer
+		value    interface{}
+		want     string
+	}{
+		{
+			name:     "csv",
+			renderer: render.TableRenderer{Format: "csv"},
+			value:    stats,
+			want:     "department,mean,max,min,count\nBanking,90000,90000,90000,1\n",
+		},
+		{
+			name:     "yaml",
+			renderer: render.YAMLRenderer{},
+			value:    stats,
+			want:     "- Department: Banking\n  Stats:\n    Mean: 90000\n    Max: 90000\n    Min: 90000\n    Count: 1\n",
+		},
+		{
+			name:     "xml",
+			renderer: render.XMLRenderer{},
+			value:    []domain.Salary{{ID: 1, Name: "Anurag & Co", Salary: 90000, Currency: "USD", Department: "Banking", SubDepartment: "Loan"}},
+			want: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<response><item><id>1</id><name>Anurag &amp; Co</name><salary>90000</salary><currency>USD</currency>` +
+				`<on_contract>false</on_contract><department>Banking</department><sub_department>Loan</sub_department></item></response>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+
+			require.NoError(t, tt.renderer.Render(&output, tt.value))
+			assert.Equal(t, tt.want, output.String())
+		})
+	}
+}
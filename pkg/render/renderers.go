@@ -0,0 +1,142 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"salaries/pkg/export"
+)
+
+type JSONRenderer struct{}
+
+func (JSONRenderer) ContentType() string {
+	return "application/json; charset=utf-8"
+}
+
+func (JSONRenderer) Render(w io.Writer, value interface{}) error {
+	return json.NewEncoder(w).Encode(value)
+}
+
+// TableRenderer writes salaries and stats as a table in one of the export formats
+type TableRenderer struct {
+	Format string
+}
+
+func (t TableRenderer) ContentType() string {
+	return export.ContentType(t.Format)
+}
+
+func (t TableRenderer) Render(w io.Writer, value interface{}) error {
+	writer, err := export.NewWriter(t.Format, w)
+	if err != nil {
+		return err
+	}
+	if err := export.WriteTable(writer, value); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// YAMLRenderer writes the json document as yaml so both share the field names and their order
+type YAMLRenderer struct{}
+
+func (YAMLRenderer) ContentType() string {
+	return "application/yaml; charset=utf-8"
+}
+
+func (YAMLRenderer) Render(w io.Writer, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return err
+	}
+	blockStyle(&document)
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&document); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// blockStyle drops the flow style and quotes the json document was parsed with
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+// XMLRenderer writes the json document as xml, objects become elements named by their keys and array items are item elements
+type XMLRenderer struct{}
+
+func (XMLRenderer) ContentType() string {
+	return "application/xml; charset=utf-8"
+}
+
+func (XMLRenderer) Render(w io.Writer, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := writeXMLValue(encoder, decoder, "response"); err != nil {
+		return err
+	}
+	return encoder.Flush()
+}
+
+func writeXMLValue(encoder *xml.Encoder, decoder *json.Decoder, name string) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+	switch t := token.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			for decoder.More() {
+				key, err := decoder.Token()
+				if err != nil {
+					return err
+				}
+				if err := writeXMLValue(encoder, decoder, key.(string)); err != nil {
+					return err
+				}
+			}
+		case '[':
+			for decoder.More() {
+				if err := writeXMLValue(encoder, decoder, "item"); err != nil {
+					return err
+				}
+			}
+		default:
+			return errors.New("unexpected json delimiter")
+		}
+		if _, err := decoder.Token(); err != nil {
+			return err
+		}
+	case nil:
+	default:
+		if err := encoder.EncodeToken(xml.CharData(fmt.Sprint(t))); err != nil {
+			return err
+		}
+	}
+	return encoder.EncodeToken(start.End())
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"salaries/pkg/api"
+	dbClient "salaries/pkg/db"
+	"salaries/pkg/domain"
+)
+
+type AuditRepository interface {
+	Find(ctx context.Context, filter api.AuditFilter) ([]domain.AuditEvent, int64, error)
+}
+
+type auditRepositoryImpl struct {
+	dbClient dbClient.DataBaseAuditClient
+}
+
+func NewAuditRepositoryWithClient(dbClient dbClient.DataBaseAuditClient) AuditRepository {
+	return &auditRepositoryImpl{
+		dbClient: dbClient,
+	}
+}
+
+func (a auditRepositoryImpl) Find(ctx context.Context, filter api.AuditFilter) ([]domain.AuditEvent, int64, error) {
+	return a.dbClient.Find(ctx, filter)
+}
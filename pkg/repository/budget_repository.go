@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"salaries/pkg/api"
+	dbClient "salaries/pkg/db"
+	"salaries/pkg/domain"
+)
+
+type BudgetRepository interface {
+	CreateBudget(ctx context.Context, budget *domain.Budget) (*domain.Budget, error)
+	ReadBudgets(ctx context.Context, filter api.BudgetFilter) ([]domain.Budget, error)
+	UpdateBudget(ctx context.Context, budget *domain.Budget) (*domain.Budget, error)
+	DeleteBudgetByID(ctx context.Context, budgetID int64) error
+	CreateChange(ctx context.Context, change *domain.PayrollChange) (*domain.PayrollChange, error)
+	ReadChanges(ctx context.Context, filter api.PayrollChangeFilter) ([]domain.PayrollChange, error)
+	UpdateChange(ctx context.Context, change *domain.PayrollChange) (*domain.PayrollChange, error)
+	DeleteChangeByID(ctx context.Context, changeID int64) error
+}
+
+type budgetRepositoryImpl struct {
+	dbClient dbClient.DataBaseBudgetClient
+}
+
+func NewBudgetRepositoryWithClient(dbClient dbClient.DataBaseBudgetClient) BudgetRepository {
+	return &budgetRepositoryImpl{
+		dbClient: dbClient,
+	}
+}
+
+func (b budgetRepositoryImpl) CreateBudget(ctx context.Context, budget *domain.Budget) (*domain.Budget, error) {
+	return b.dbClient.CreateBudget(ctx, budget)
+}
+
+func (b budgetRepositoryImpl) ReadBudgets(ctx context.Context, filter api.BudgetFilter) ([]domain.Budget, error) {
+	return b.dbClient.ReadBudgets(ctx, filter)
+}
+
+func (b budgetRepositoryImpl) UpdateBudget(ctx context.Context, budget *domain.Budget) (*domain.Budget, error) {
+	return b.dbClient.UpdateBudget(ctx, budget)
+}
+
+func (b budgetRepositoryImpl) DeleteBudgetByID(ctx context.Context, budgetID int64) error {
+	return b.dbClient.DeleteBudgetByID(ctx, budgetID)
+}
+
+func (b budgetRepositoryImpl) CreateChange(ctx context.Context, change *domain.PayrollChange) (*domain.PayrollChange, error) {
+	return b.dbClient.CreateChange(ctx, change)
+}
+
+func (b budgetRepositoryImpl) ReadChanges(ctx context.Context, filter api.PayrollChangeFilter) ([]domain.PayrollChange, error) {
+	return b.dbClient.ReadChanges(ctx, filter)
+}
+
+func (b budgetRepositoryImpl) UpdateChange(ctx context.Context, change *domain.PayrollChange) (*domain.PayrollChange, error) {
+	return b.dbClient.UpdateChange(ctx, change)
+}
+
+func (b budgetRepositoryImpl) DeleteChangeByID(ctx context.Context, changeID int64) error {
+	return b.dbClient.DeleteChangeByID(ctx, changeID)
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"salaries/pkg/api"
+	dbClient "salaries/pkg/db"
+	"salaries/pkg/domain"
+)
+
+type DemographicsRepository interface {
+	Read(ctx context.Context, salaryID int64) (*domain.Demographics, error)
+	Save(ctx context.Context, demographics *domain.Demographics) error
+	Delete(ctx context.Context, salaryID int64) error
+	ReadEach(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary, demographics *domain.Demographics) error) error
+}
+
+type demographicsRepositoryImpl struct {
+	dbClient dbClient.DataBaseDemographicsClient
+}
+
+func NewDemographicsRepositoryWithClient(dbClient dbClient.DataBaseDemographicsClient) DemographicsRepository {
+	return &demographicsRepositoryImpl{
+		dbClient: dbClient,
+	}
+}
+
+func (d demographicsRepositoryImpl) Read(ctx context.Context, salaryID int64) (*domain.Demographics, error) {
+	return d.dbClient.Read(ctx, salaryID)
+}
+
+func (d demographicsRepositoryImpl) Save(ctx context.Context, demographics *domain.Demographics) error {
+	return d.dbClient.Save(ctx, demographics)
+}
+
+func (d demographicsRepositoryImpl) Delete(ctx context.Context, salaryID int64) error {
+	return d.dbClient.Delete(ctx, salaryID)
+}
+
+func (d demographicsRepositoryImpl) ReadEach(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary, demographics *domain.Demographics) error) error {
+	return d.dbClient.ReadEach(ctx, filter, each)
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	dbClient "salaries/pkg/db"
+	"salaries/pkg/domain"
+	"time"
+)
+
+type IdempotencyRepository interface {
+	Reserve(ctx context.Context, record *domain.IdempotencyRecord, expiredBefore time.Time) (*domain.IdempotencyRecord, error)
+	Complete(ctx context.Context, record *domain.IdempotencyRecord) error
+	Release(ctx context.Context, scope, key string) error
+}
+
+type idempotencyRepositoryImpl struct {
+	dbClient dbClient.DataBaseIdempotencyClient
+}
+
+func NewIdempotencyRepositoryWithClient(dbClient dbClient.DataBaseIdempotencyClient) IdempotencyRepository {
+	return &idempotencyRepositoryImpl{
+		dbClient: dbClient,
+	}
+}
+
+func (i idempotencyRepositoryImpl) Reserve(ctx context.Context, record *domain.IdempotencyRecord, expiredBefore time.Time) (*domain.IdempotencyRecord, error) {
+	return i.dbClient.Reserve(ctx, record, expiredBefore)
+}
+
+func (i idempotencyRepositoryImpl) Complete(ctx context.Context, record *domain.IdempotencyRecord) error {
+	return i.dbClient.Complete(ctx, record)
+}
+
+func (i idempotencyRepositoryImpl) Release(ctx context.Context, scope, key string) error {
+	return i.dbClient.Release(ctx, scope, key)
+}
@@ -0,0 +1,83 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package repository
+
+import (
+	"context"
+	"salaries/pkg/api"
+	"salaries/pkg/domain"
+	"sync"
+)
+
+// Ensure, that AuditRepositoryMock does implement AuditRepository.
+// If this is not the case, regenerate this file with moq.
+var _ AuditRepository = &AuditRepositoryMock{}
+
+// AuditRepositoryMock is a mock implementation of AuditRepository.
+//
+//	func TestSomethingThatUsesAuditRepository(t *testing.T) {
+//
+//		// make and configure a mocked AuditRepository
+//		mockedAuditRepository := &AuditRepositoryMock{
+//			FindFunc: func(ctx context.Context, filter api.AuditFilter) ([]domain.AuditEvent, int64, error) {
+//				panic("mock out the Find method")
+//			},
+//		}
+//
+//		// use mockedAuditRepository in code that requires AuditRepository
+//		// and then make assertions.
+//
+//	}
+type AuditRepositoryMock struct {
+	// FindFunc mocks the Find method.
+	FindFunc func(ctx context.Context, filter api.AuditFilter) ([]domain.AuditEvent, int64, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Find holds details about calls to the Find method.
+		Find []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Filter is the filter argument value.
+			Filter api.AuditFilter
+		}
+	}
+	lockFind sync.RWMutex
+}
+
+// Find calls FindFunc.
+func (mock *AuditRepositoryMock) Find(ctx context.Context, filter api.AuditFilter) ([]domain.AuditEvent, int64, error) {
+	if mock.FindFunc == nil {
+		panic("AuditRepositoryMock.FindFunc: method is nil but AuditRepository.Find was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Filter api.AuditFilter
+	}{
+		Ctx:    ctx,
+		Filter: filter,
+	}
+	mock.lockFind.Lock()
+	mock.calls.Find = append(mock.calls.Find, callInfo)
+	mock.lockFind.Unlock()
+	return mock.FindFunc(ctx, filter)
+}
+
+// FindCalls gets all the calls that were made to Find.
+// Check the length with:
+//
+//	len(mockedAuditRepository.FindCalls())
+func (mock *AuditRepositoryMock) FindCalls() []struct {
+	Ctx    context.Context
+	Filter api.AuditFilter
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Filter api.AuditFilter
+	}
+	mock.lockFind.RLock()
+	calls = mock.calls.Find
+	mock.lockFind.RUnlock()
+	return calls
+}
@@ -0,0 +1,239 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package repository
+
+import (
+	"context"
+	"salaries/pkg/api"
+	"salaries/pkg/domain"
+	"sync"
+)
+
+// Ensure, that DemographicsRepositoryMock does implement DemographicsRepository.
+// If this is not the case, regenerate this file with moq.
+var _ DemographicsRepository = &DemographicsRepositoryMock{}
+
+// DemographicsRepositoryMock is a mock implementation of DemographicsRepository.
+//
+//	func TestSomethingThatUsesDemographicsRepository(t *testing.T) {
+//
+//		// make and configure a mocked DemographicsRepository
+//		mockedDemographicsRepository := &DemographicsRepositoryMock{
+//			DeleteFunc: func(ctx context.Context, salaryID int64) error {
+//				panic("mock out the Delete method")
+//			},
+//			ReadFunc: func(ctx context.Context, salaryID int64) (*domain.Demographics, error) {
+//				panic("mock out the Read method")
+//			},
+//			ReadEachFunc: func(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary, demographics *domain.Demographics) error) error {
+//				panic("mock out the ReadEach method")
+//			},
+//			SaveFunc: func(ctx context.Context, demographics *domain.Demographics) error {
+//				panic("mock out the Save method")
+//			},
+//		}
+//
+//		// use mockedDemographicsRepository in code that requires DemographicsRepository
+//		// and then make assertions.
+//
+//	}
+type DemographicsRepositoryMock struct {
+	// DeleteFunc mocks the Delete method.
+	DeleteFunc func(ctx context.Context, salaryID int64) error
+
+	// ReadFunc mocks the Read method.
+	ReadFunc func(ctx context.Context, salaryID int64) (*domain.Demographics, error)
+
+	// ReadEachFunc mocks the ReadEach method.
+	ReadEachFunc func(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary, demographics *domain.Demographics) error) error
+
+	// SaveFunc mocks the Save method.
+	SaveFunc func(ctx context.Context, demographics *domain.Demographics) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Delete holds details about calls to the Delete method.
+		Delete []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// SalaryID is the salaryID argument value.
+			SalaryID int64
+		}
+		// Read holds details about calls to the Read method.
+		Read []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// SalaryID is the salaryID argument value.
+			SalaryID int64
+		}
+		// ReadEach holds details about calls to the ReadEach method.
+		ReadEach []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Filter is the filter argument value.
+			Filter api.SalaryFilter
+			// Each is the each argument value.
+			Each func(salary *domain.Salary, demographics *domain.Demographics) error
+		}
+		// Save holds details about calls to the Save method.
+		Save []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Demographics is the demographics argument value.
+			Demographics *domain.Demographics
+		}
+	}
+	lockDelete   sync.RWMutex
+	lockRead     sync.RWMutex
+	lockReadEach sync.RWMutex
+	lockSave     sync.RWMutex
+}
+
+// Delete calls DeleteFunc.
+func (mock *DemographicsRepositoryMock) Delete(ctx context.Context, salaryID int64) error {
+	if mock.DeleteFunc == nil {
+		panic("DemographicsRepositoryMock.DeleteFunc: method is nil but DemographicsRepository.Delete was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		SalaryID int64
+	}{
+		Ctx:      ctx,
+		SalaryID: salaryID,
+	}
+	mock.lockDelete.Lock()
+	mock.calls.Delete = append(mock.calls.Delete, callInfo)
+	mock.lockDelete.Unlock()
+	return mock.DeleteFunc(ctx, salaryID)
+}
+
+// DeleteCalls gets all the calls that were made to Delete.
+// Check the length with:
+//
+//	len(mockedDemographicsRepository.DeleteCalls())
+func (mock *DemographicsRepositoryMock) DeleteCalls() []struct {
+	Ctx      context.Context
+	SalaryID int64
+} {
+	var calls []struct {
+		Ctx      context.Context
+		SalaryID int64
+	}
+	mock.lockDelete.RLock()
+	calls = mock.calls.Delete
+	mock.lockDelete.RUnlock()
+	return calls
+}
+
+// Read calls ReadFunc.
+func (mock *DemographicsRepositoryMock) Read(ctx context.Context, salaryID int64) (*domain.Demographics, error) {
+	if mock.ReadFunc == nil {
+		panic("DemographicsRepositoryMock.ReadFunc: method is nil but DemographicsRepository.Read was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		SalaryID int64
+	}{
+		Ctx:      ctx,
+		SalaryID: salaryID,
+	}
+	mock.lockRead.Lock()
+	mock.calls.Read = append(mock.calls.Read, callInfo)
+	mock.lockRead.Unlock()
+	return mock.ReadFunc(ctx, salaryID)
+}
+
+// ReadCalls gets all the calls that were made to Read.
+// Check the length with:
+//
+//	len(mockedDemographicsRepository.ReadCalls())
+func (mock *DemographicsRepositoryMock) ReadCalls() []struct {
+	Ctx      context.Context
+	SalaryID int64
+} {
+	var calls []struct {
+		Ctx      context.Context
+		SalaryID int64
+	}
+	mock.lockRead.RLock()
+	calls = mock.calls.Read
+	mock.lockRead.RUnlock()
+	return calls
+}
+
+// ReadEach calls ReadEachFunc.
+func (mock *DemographicsRepositoryMock) ReadEach(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary, demographics *domain.Demographics) error) error {
+	if mock.ReadEachFunc == nil {
+		panic("DemographicsRepositoryMock.ReadEachFunc: method is nil but DemographicsRepository.ReadEach was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Filter api.SalaryFilter
+		Each   func(salary *domain.Salary, demographics *domain.Demographics) error
+	}{
+		Ctx:    ctx,
+		Filter: filter,
+		Each:   each,
+	}
+	mock.lockReadEach.Lock()
+	mock.calls.ReadEach = append(mock.calls.ReadEach, callInfo)
+	mock.lockReadEach.Unlock()
+	return mock.ReadEachFunc(ctx, filter, each)
+}
+
+// ReadEachCalls gets all the calls that were made to ReadEach.
+// Check the length with:
+//
+//	len(mockedDemographicsRepository.ReadEachCalls())
+func (mock *DemographicsRepositoryMock) ReadEachCalls() []struct {
+	Ctx    context.Context
+	Filter api.SalaryFilter
+	Each   func(salary *domain.Salary, demographics *domain.Demographics) error
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Filter api.SalaryFilter
+		Each   func(salary *domain.Salary, demographics *domain.Demographics) error
+	}
+	mock.lockReadEach.RLock()
+	calls = mock.calls.ReadEach
+	mock.lockReadEach.RUnlock()
+	return calls
+}
+
+// Save calls SaveFunc.
+func (mock *DemographicsRepositoryMock) Save(ctx context.Context, demographics *domain.Demographics) error {
+	if mock.SaveFunc == nil {
+		panic("DemographicsRepositoryMock.SaveFunc: method is nil but DemographicsRepository.Save was just called")
+	}
+	callInfo := struct {
+		Ctx          context.Context
+		Demographics *domain.Demographics
+	}{
+		Ctx:          ctx,
+		Demographics: demographics,
+	}
+	mock.lockSave.Lock()
+	mock.calls.Save = append(mock.calls.Save, callInfo)
+	mock.lockSave.Unlock()
+	return mock.SaveFunc(ctx, demographics)
+}
+
+// SaveCalls gets all the calls that were made to Save.
+// Check the length with:
+//
+//	len(mockedDemographicsRepository.SaveCalls())
+func (mock *DemographicsRepositoryMock) SaveCalls() []struct {
+	Ctx          context.Context
+	Demographics *domain.Demographics
+} {
+	var calls []struct {
+		Ctx          context.Context
+		Demographics *domain.Demographics
+	}
+	mock.lockSave.RLock()
+	calls = mock.calls.Save
+	mock.lockSave.RUnlock()
+	return calls
+}
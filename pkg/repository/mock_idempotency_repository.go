@@ -0,0 +1,195 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package repository
+
+import (
+	"context"
+	"salaries/pkg/domain"
+	"sync"
+	"time"
+)
+
+// Ensure, that IdempotencyRepositoryMock does implement IdempotencyRepository.
+// If this is not the case, regenerate this file with moq.
+var _ IdempotencyRepository = &IdempotencyRepositoryMock{}
+
+// IdempotencyRepositoryMock is a mock implementation of IdempotencyRepository.
+//
+//	func TestSomethingThatUsesIdempotencyRepository(t *testing.T) {
+//
+//		// make and configure a mocked IdempotencyRepository
+//		mockedIdempotencyRepository := &IdempotencyRepositoryMock{
+//			CompleteFunc: func(ctx context.Context, record *domain.IdempotencyRecord) error {
+//				panic("mock out the Complete method")
+//			},
+//			ReleaseFunc: func(ctx context.Context, scope string, key string) error {
+//				panic("mock out the Release method")
+//			},
+//			ReserveFunc: func(ctx context.Context, record *domain.IdempotencyRecord, expiredBefore time.Time) (*domain.IdempotencyRecord, error) {
+//				panic("mock out the Reserve method")
+//			},
+//		}
+//
+//		// use mockedIdempotencyRepository in code that requires IdempotencyRepository
+//		// and then make assertions.
+//
+//	}
+type IdempotencyRepositoryMock struct {
+	// CompleteFunc mocks the Complete method.
+	CompleteFunc func(ctx context.Context, record *domain.IdempotencyRecord) error
+
+	// ReleaseFunc mocks the Release method.
+	ReleaseFunc func(ctx context.Context, scope string, key string) error
+
+	// ReserveFunc mocks the Reserve method.
+	ReserveFunc func(ctx context.Context, record *domain.IdempotencyRecord, expiredBefore time.Time) (*domain.IdempotencyRecord, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Complete holds details about calls to the Complete method.
+		Complete []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Record is the record argument value.
+			Record *domain.IdempotencyRecord
+		}
+		// Release holds details about calls to the Release method.
+		Release []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Scope is the scope argument value.
+			Scope string
+			// Key is the key argument value.
+			Key string
+		}
+		// Reserve holds details about calls to the Reserve method.
+		Reserve []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Record is the record argument value.
+			Record *domain.IdempotencyRecord
+			// ExpiredBefore is the expiredBefore argument value.
+			ExpiredBefore time.Time
+		}
+	}
+	lockComplete sync.RWMutex
+	lockRelease  sync.RWMutex
+	lockReserve  sync.RWMutex
+}
+
+// Complete calls CompleteFunc.
+func (mock *IdempotencyRepositoryMock) Complete(ctx context.Context, record *domain.IdempotencyRecord) error {
+	if mock.CompleteFunc == nil {
+		panic("IdempotencyRepositoryMock.CompleteFunc: method is nil but IdempotencyRepository.Complete was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Record *domain.IdempotencyRecord
+	}{
+		Ctx:    ctx,
+		Record: record,
+	}
+	mock.lockComplete.Lock()
+	mock.calls.Complete = append(mock.calls.Complete, callInfo)
+	mock.lockComplete.Unlock()
+	return mock.CompleteFunc(ctx, record)
+}
+
+// CompleteCalls gets all the calls that were made to Complete.
+// Check the length with:
+//
+//	len(mockedIdempotencyRepository.CompleteCalls())
+func (mock *IdempotencyRepositoryMock) CompleteCalls() []struct {
+	Ctx    context.Context
+	Record *domain.IdempotencyRecord
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Record *domain.IdempotencyRecord
+	}
+	mock.lockComplete.RLock()
+	calls = mock.calls.Complete
+	mock.lockComplete.RUnlock()
+	return calls
+}
+
+// Release calls ReleaseFunc.
+func (mock *IdempotencyRepositoryMock) Release(ctx context.Context, scope string, key string) error {
+	if mock.ReleaseFunc == nil {
+		panic("IdempotencyRepositoryMock.ReleaseFunc: method is nil but IdempotencyRepository.Release was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Scope string
+		Key   string
+	}{
+		Ctx:   ctx,
+		Scope: scope,
+		Key:   key,
+	}
+	mock.lockRelease.Lock()
+	mock.calls.Release = append(mock.calls.Release, callInfo)
+	mock.lockRelease.Unlock()
+	return mock.ReleaseFunc(ctx, scope, key)
+}
+
+// ReleaseCalls gets all the calls that were made to Release.
+// Check the length with:
+//
+//	len(mockedIdempotencyRepository.ReleaseCalls())
+func (mock *IdempotencyRepositoryMock) ReleaseCalls() []struct {
+	Ctx   context.Context
+	Scope string
+	Key   string
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Scope string
+		Key   string
+	}
+	mock.lockRelease.RLock()
+	calls = mock.calls.Release
+	mock.lockRelease.RUnlock()
+	return calls
+}
+
+// Reserve calls ReserveFunc.
+func (mock *IdempotencyRepositoryMock) Reserve(ctx context.Context, record *domain.IdempotencyRecord, expiredBefore time.Time) (*domain.IdempotencyRecord, error) {
+	if mock.ReserveFunc == nil {
+		panic("IdempotencyRepositoryMock.ReserveFunc: method is nil but IdempotencyRepository.Reserve was just called")
+	}
+	callInfo := struct {
+		Ctx           context.Context
+		Record        *domain.IdempotencyRecord
+		ExpiredBefore time.Time
+	}{
+		Ctx:           ctx,
+		Record:        record,
+		ExpiredBefore: expiredBefore,
+	}
+	mock.lockReserve.Lock()
+	mock.calls.Reserve = append(mock.calls.Reserve, callInfo)
+	mock.lockReserve.Unlock()
+	return mock.ReserveFunc(ctx, record, expiredBefore)
+}
+
+// ReserveCalls gets all the calls that were made to Reserve.
+// Check the length with:
+//
+//	len(mockedIdempotencyRepository.ReserveCalls())
+func (mock *IdempotencyRepositoryMock) ReserveCalls() []struct {
+	Ctx           context.Context
+	Record        *domain.IdempotencyRecord
+	ExpiredBefore time.Time
+} {
+	var calls []struct {
+		Ctx           context.Context
+		Record        *domain.IdempotencyRecord
+		ExpiredBefore time.Time
+	}
+	mock.lockReserve.RLock()
+	calls = mock.calls.Reserve
+	mock.lockReserve.RUnlock()
+	return calls
+}
@@ -4,9 +4,12 @@
 package repository
 
 import (
+	"context"
 	"salaries/pkg/api"
+	dbClient "salaries/pkg/db"
 	"salaries/pkg/domain"
 	"sync"
+	"time"
 )
 
 // Ensure, that SalaryRepositoryMock does implement SalaryRepository.
@@ -19,27 +22,48 @@ var _ SalaryRepository = &SalaryRepositoryMock{}
 //
 //		// make and configure a mocked SalaryRepository
 //		mockedSalaryRepository := &SalaryRepositoryMock{
-//			CreateFunc: func(salary *domain.Salary) (*domain.Salary, error) {
+//			BatchFunc: func(ctx context.Context) (dbClient.SalaryBatch, error) {
+//				panic("mock out the Batch method")
+//			},
+//			CreateFunc: func(ctx context.Context, salary *domain.Salary) (*domain.Salary, error) {
 //				panic("mock out the Create method")
 //			},
-//			DeleteByIDFunc: func(salaryID int64) error {
+//			DeleteByIDFunc: func(ctx context.Context, salaryID int64, version int64) (*domain.Salary, error) {
 //				panic("mock out the DeleteByID method")
 //			},
-//			GetContractsStatsFunc: func() (*api.Stats, error) {
+//			GetContractsStatsFunc: func(ctx context.Context) (*api.Stats, error) {
 //				panic("mock out the GetContractsStats method")
 //			},
-//			GetDepartmentsStatsFunc: func() ([]api.DepartmentStats, error) {
+//			GetDepartmentsStatsFunc: func(ctx context.Context) ([]api.DepartmentStats, error) {
 //				panic("mock out the GetDepartmentsStats method")
 //			},
-//			GetStatsForAllSalariesFunc: func() (*api.Stats, error) {
+//			GetStatsForAllSalariesFunc: func(ctx context.Context) (*api.Stats, error) {
 //				panic("mock out the GetStatsForAllSalaries method")
 //			},
-//			GetSubDepartmentsStatsFunc: func() ([]api.SubDepartmentStats, error) {
+//			GetSubDepartmentsStatsFunc: func(ctx context.Context) ([]api.SubDepartmentStats, error) {
 //				panic("mock out the GetSubDepartmentsStats method")
 //			},
-//			ReadAllFunc: func() ([]domain.Salary, error) {
+//			PurgeFunc: func(ctx context.Context, deletedBefore time.Time) (int64, error) {
+//				panic("mock out the Purge method")
+//			},
+//			ReadAllFunc: func(ctx context.Context, filter api.SalaryFilter) ([]domain.Salary, error) {
 //				panic("mock out the ReadAll method")
 //			},
+//			ReadByIDFunc: func(ctx context.Context, salaryID int64) (*domain.Salary, error) {
+//				panic("mock out the ReadByID method")
+//			},
+//			ReadEachFunc: func(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary) error) error {
+//				panic("mock out the ReadEach method")
+//			},
+//			ReadTrashFunc: func(ctx context.Context) ([]domain.DeletedSalary, error) {
+//				panic("mock out the ReadTrash method")
+//			},
+//			RestoreFunc: func(ctx context.Context, salaryID int64) (*domain.Salary, error) {
+//				panic("mock out the Restore method")
+//			},
+//			UpdateFunc: func(ctx context.Context, salary *domain.Salary, version int64) (*domain.Salary, error) {
+//				panic("mock out the Update method")
+//			},
 //		}
 //
 //		// use mockedSalaryRepository in code that requires SalaryRepository
@@ -47,78 +71,207 @@ var _ SalaryRepository = &SalaryRepositoryMock{}
 //
 //	}
 type SalaryRepositoryMock struct {
+	// BatchFunc mocks the Batch method.
+	BatchFunc func(ctx context.Context) (dbClient.SalaryBatch, error)
+
 	// CreateFunc mocks the Create method.
-	CreateFunc func(salary *domain.Salary) (*domain.Salary, error)
+	CreateFunc func(ctx context.Context, salary *domain.Salary) (*domain.Salary, error)
 
 	// DeleteByIDFunc mocks the DeleteByID method.
-	DeleteByIDFunc func(salaryID int64) error
+	DeleteByIDFunc func(ctx context.Context, salaryID int64, version int64) (*domain.Salary, error)
 
 	// GetContractsStatsFunc mocks the GetContractsStats method.
-	GetContractsStatsFunc func() (*api.Stats, error)
+	GetContractsStatsFunc func(ctx context.Context) (*api.Stats, error)
 
 	// GetDepartmentsStatsFunc mocks the GetDepartmentsStats method.
-	GetDepartmentsStatsFunc func() ([]api.DepartmentStats, error)
+	GetDepartmentsStatsFunc func(ctx context.Context) ([]api.DepartmentStats, error)
 
 	// GetStatsForAllSalariesFunc mocks the GetStatsForAllSalaries method.
-	GetStatsForAllSalariesFunc func() (*api.Stats, error)
+	GetStatsForAllSalariesFunc func(ctx context.Context) (*api.Stats, error)
 
 	// GetSubDepartmentsStatsFunc mocks the GetSubDepartmentsStats method.
-	GetSubDepartmentsStatsFunc func() ([]api.SubDepartmentStats, error)
+	GetSubDepartmentsStatsFunc func(ctx context.Context) ([]api.SubDepartmentStats, error)
+
+	// PurgeFunc mocks the Purge method.
+	PurgeFunc func(ctx context.Context, deletedBefore time.Time) (int64, error)
 
 	// ReadAllFunc mocks the ReadAll method.
-	ReadAllFunc func() ([]domain.Salary, error)
+	ReadAllFunc func(ctx context.Context, filter api.SalaryFilter) ([]domain.Salary, error)
+
+	// ReadByIDFunc mocks the ReadByID method.
+	ReadByIDFunc func(ctx context.Context, salaryID int64) (*domain.Salary, error)
+
+	// ReadEachFunc mocks the ReadEach method.
+	ReadEachFunc func(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary) error) error
+
+	// ReadTrashFunc mocks the ReadTrash method.
+	ReadTrashFunc func(ctx context.Context) ([]domain.DeletedSalary, error)
+
+	// RestoreFunc mocks the Restore method.
+	RestoreFunc func(ctx context.Context, salaryID int64) (*domain.Salary, error)
+
+	// UpdateFunc mocks the Update method.
+	UpdateFunc func(ctx context.Context, salary *domain.Salary, version int64) (*domain.Salary, error)
 
 	// calls tracks calls to the methods.
 	calls struct {
+		// Batch holds details about calls to the Batch method.
+		Batch []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
 		// Create holds details about calls to the Create method.
 		Create []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// Salary is the salary argument value.
 			Salary *domain.Salary
 		}
 		// DeleteByID holds details about calls to the DeleteByID method.
 		DeleteByID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// SalaryID is the salaryID argument value.
 			SalaryID int64
+			// Version is the version argument value.
+			Version int64
 		}
 		// GetContractsStats holds details about calls to the GetContractsStats method.
 		GetContractsStats []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 		}
 		// GetDepartmentsStats holds details about calls to the GetDepartmentsStats method.
 		GetDepartmentsStats []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 		}
 		// GetStatsForAllSalaries holds details about calls to the GetStatsForAllSalaries method.
 		GetStatsForAllSalaries []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 		}
 		// GetSubDepartmentsStats holds details about calls to the GetSubDepartmentsStats method.
 		GetSubDepartmentsStats []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// Purge holds details about calls to the Purge method.
+		Purge []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// DeletedBefore is the deletedBefore argument value.
+			DeletedBefore time.Time
 		}
 		// ReadAll holds details about calls to the ReadAll method.
 		ReadAll []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Filter is the filter argument value.
+			Filter api.SalaryFilter
+		}
+		// ReadByID holds details about calls to the ReadByID method.
+		ReadByID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// SalaryID is the salaryID argument value.
+			SalaryID int64
+		}
+		// ReadEach holds details about calls to the ReadEach method.
+		ReadEach []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Filter is the filter argument value.
+			Filter api.SalaryFilter
+			// Each is the each argument value.
+			Each func(salary *domain.Salary) error
+		}
+		// ReadTrash holds details about calls to the ReadTrash method.
+		ReadTrash []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// Restore holds details about calls to the Restore method.
+		Restore []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// SalaryID is the salaryID argument value.
+			SalaryID int64
+		}
+		// Update holds details about calls to the Update method.
+		Update []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Salary is the salary argument value.
+			Salary *domain.Salary
+			// Version is the version argument value.
+			Version int64
 		}
 	}
+	lockBatch                  sync.RWMutex
 	lockCreate                 sync.RWMutex
 	lockDeleteByID             sync.RWMutex
 	lockGetContractsStats      sync.RWMutex
 	lockGetDepartmentsStats    sync.RWMutex
 	lockGetStatsForAllSalaries sync.RWMutex
 	lockGetSubDepartmentsStats sync.RWMutex
+	lockPurge                  sync.RWMutex
 	lockReadAll                sync.RWMutex
+	lockReadByID               sync.RWMutex
+	lockReadEach               sync.RWMutex
+	lockReadTrash              sync.RWMutex
+	lockRestore                sync.RWMutex
+	lockUpdate                 sync.RWMutex
+}
+
+// Batch calls BatchFunc.
+func (mock *SalaryRepositoryMock) Batch(ctx context.Context) (dbClient.SalaryBatch, error) {
+	if mock.BatchFunc == nil {
+		panic("SalaryRepositoryMock.BatchFunc: method is nil but SalaryRepository.Batch was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockBatch.Lock()
+	mock.calls.Batch = append(mock.calls.Batch, callInfo)
+	mock.lockBatch.Unlock()
+	return mock.BatchFunc(ctx)
+}
+
+// BatchCalls gets all the calls that were made to Batch.
+// Check the length with:
+//
+//	len(mockedSalaryRepository.BatchCalls())
+func (mock *SalaryRepositoryMock) BatchCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockBatch.RLock()
+	calls = mock.calls.Batch
+	mock.lockBatch.RUnlock()
+	return calls
 }
 
 // Create calls CreateFunc.
-func (mock *SalaryRepositoryMock) Create(salary *domain.Salary) (*domain.Salary, error) {
+func (mock *SalaryRepositoryMock) Create(ctx context.Context, salary *domain.Salary) (*domain.Salary, error) {
 	if mock.CreateFunc == nil {
 		panic("SalaryRepositoryMock.CreateFunc: method is nil but SalaryRepository.Create was just called")
 	}
 	callInfo := struct {
+		Ctx    context.Context
 		Salary *domain.Salary
 	}{
+		Ctx:    ctx,
 		Salary: salary,
 	}
 	mock.lockCreate.Lock()
 	mock.calls.Create = append(mock.calls.Create, callInfo)
 	mock.lockCreate.Unlock()
-	return mock.CreateFunc(salary)
+	return mock.CreateFunc(ctx, salary)
 }
 
 // CreateCalls gets all the calls that were made to Create.
@@ -126,9 +279,11 @@ func (mock *SalaryRepositoryMock) Create(salary *domain.Salary) (*domain.Salary,
 //
 //	len(mockedSalaryRepository.CreateCalls())
 func (mock *SalaryRepositoryMock) CreateCalls() []struct {
+	Ctx    context.Context
 	Salary *domain.Salary
 } {
 	var calls []struct {
+		Ctx    context.Context
 		Salary *domain.Salary
 	}
 	mock.lockCreate.RLock()
@@ -138,19 +293,23 @@ func (mock *SalaryRepositoryMock) CreateCalls() []struct {
 }
 
 // DeleteByID calls DeleteByIDFunc.
-func (mock *SalaryRepositoryMock) DeleteByID(salaryID int64) error {
+func (mock *SalaryRepositoryMock) DeleteByID(ctx context.Context, salaryID int64, version int64) (*domain.Salary, error) {
 	if mock.DeleteByIDFunc == nil {
 		panic("SalaryRepositoryMock.DeleteByIDFunc: method is nil but SalaryRepository.DeleteByID was just called")
 	}
 	callInfo := struct {
+		Ctx      context.Context
 		SalaryID int64
+		Version  int64
 	}{
+		Ctx:      ctx,
 		SalaryID: salaryID,
+		Version:  version,
 	}
 	mock.lockDeleteByID.Lock()
 	mock.calls.DeleteByID = append(mock.calls.DeleteByID, callInfo)
 	mock.lockDeleteByID.Unlock()
-	return mock.DeleteByIDFunc(salaryID)
+	return mock.DeleteByIDFunc(ctx, salaryID, version)
 }
 
 // DeleteByIDCalls gets all the calls that were made to DeleteByID.
@@ -158,10 +317,14 @@ func (mock *SalaryRepositoryMock) DeleteByID(salaryID int64) error {
 //
 //	len(mockedSalaryRepository.DeleteByIDCalls())
 func (mock *SalaryRepositoryMock) DeleteByIDCalls() []struct {
+	Ctx      context.Context
 	SalaryID int64
+	Version  int64
 } {
 	var calls []struct {
+		Ctx      context.Context
 		SalaryID int64
+		Version  int64
 	}
 	mock.lockDeleteByID.RLock()
 	calls = mock.calls.DeleteByID
@@ -170,16 +333,19 @@ func (mock *SalaryRepositoryMock) DeleteByIDCalls() []struct {
 }
 
 // GetContractsStats calls GetContractsStatsFunc.
-func (mock *SalaryRepositoryMock) GetContractsStats() (*api.Stats, error) {
+func (mock *SalaryRepositoryMock) GetContractsStats(ctx context.Context) (*api.Stats, error) {
 	if mock.GetContractsStatsFunc == nil {
 		panic("SalaryRepositoryMock.GetContractsStatsFunc: method is nil but SalaryRepository.GetContractsStats was just called")
 	}
 	callInfo := struct {
-	}{}
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
 	mock.lockGetContractsStats.Lock()
 	mock.calls.GetContractsStats = append(mock.calls.GetContractsStats, callInfo)
 	mock.lockGetContractsStats.Unlock()
-	return mock.GetContractsStatsFunc()
+	return mock.GetContractsStatsFunc(ctx)
 }
 
 // GetContractsStatsCalls gets all the calls that were made to GetContractsStats.
@@ -187,8 +353,10 @@ func (mock *SalaryRepositoryMock) GetContractsStats() (*api.Stats, error) {
 //
 //	len(mockedSalaryRepository.GetContractsStatsCalls())
 func (mock *SalaryRepositoryMock) GetContractsStatsCalls() []struct {
+	Ctx context.Context
 } {
 	var calls []struct {
+		Ctx context.Context
 	}
 	mock.lockGetContractsStats.RLock()
 	calls = mock.calls.GetContractsStats
@@ -197,16 +365,19 @@ func (mock *SalaryRepositoryMock) GetContractsStatsCalls() []struct {
 }
 
 // GetDepartmentsStats calls GetDepartmentsStatsFunc.
-func (mock *SalaryRepositoryMock) GetDepartmentsStats() ([]api.DepartmentStats, error) {
+func (mock *SalaryRepositoryMock) GetDepartmentsStats(ctx context.Context) ([]api.DepartmentStats, error) {
 	if mock.GetDepartmentsStatsFunc == nil {
 		panic("SalaryRepositoryMock.GetDepartmentsStatsFunc: method is nil but SalaryRepository.GetDepartmentsStats was just called")
 	}
 	callInfo := struct {
-	}{}
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
 	mock.lockGetDepartmentsStats.Lock()
 	mock.calls.GetDepartmentsStats = append(mock.calls.GetDepartmentsStats, callInfo)
 	mock.lockGetDepartmentsStats.Unlock()
-	return mock.GetDepartmentsStatsFunc()
+	return mock.GetDepartmentsStatsFunc(ctx)
 }
 
 // GetDepartmentsStatsCalls gets all the calls that were made to GetDepartmentsStats.
@@ -214,8 +385,10 @@ func (mock *SalaryRepositoryMock) GetDepartmentsStats() ([]api.DepartmentStats,
 //
 //	len(mockedSalaryRepository.GetDepartmentsStatsCalls())
 func (mock *SalaryRepositoryMock) GetDepartmentsStatsCalls() []struct {
+	Ctx context.Context
 } {
 	var calls []struct {
+		Ctx context.Context
 	}
 	mock.lockGetDepartmentsStats.RLock()
 	calls = mock.calls.GetDepartmentsStats
@@ -224,16 +397,19 @@ func (mock *SalaryRepositoryMock) GetDepartmentsStatsCalls() []struct {
 }
 
 // GetStatsForAllSalaries calls GetStatsForAllSalariesFunc.
-func (mock *SalaryRepositoryMock) GetStatsForAllSalaries() (*api.Stats, error) {
+func (mock *SalaryRepositoryMock) GetStatsForAllSalaries(ctx context.Context) (*api.Stats, error) {
 	if mock.GetStatsForAllSalariesFunc == nil {
 		panic("SalaryRepositoryMock.GetStatsForAllSalariesFunc: method is nil but SalaryRepository.GetStatsForAllSalaries was just called")
 	}
 	callInfo := struct {
-	}{}
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
 	mock.lockGetStatsForAllSalaries.Lock()
 	mock.calls.GetStatsForAllSalaries = append(mock.calls.GetStatsForAllSalaries, callInfo)
 	mock.lockGetStatsForAllSalaries.Unlock()
-	return mock.GetStatsForAllSalariesFunc()
+	return mock.GetStatsForAllSalariesFunc(ctx)
 }
 
 // GetStatsForAllSalariesCalls gets all the calls that were made to GetStatsForAllSalaries.
@@ -241,8 +417,10 @@ func (mock *SalaryRepositoryMock) GetStatsForAllSalaries() (*api.Stats, error) {
 //
 //	len(mockedSalaryRepository.GetStatsForAllSalariesCalls())
 func (mock *SalaryRepositoryMock) GetStatsForAllSalariesCalls() []struct {
+	Ctx context.Context
 } {
 	var calls []struct {
+		Ctx context.Context
 	}
 	mock.lockGetStatsForAllSalaries.RLock()
 	calls = mock.calls.GetStatsForAllSalaries
@@ -251,16 +429,19 @@ func (mock *SalaryRepositoryMock) GetStatsForAllSalariesCalls() []struct {
 }
 
 // GetSubDepartmentsStats calls GetSubDepartmentsStatsFunc.
-func (mock *SalaryRepositoryMock) GetSubDepartmentsStats() ([]api.SubDepartmentStats, error) {
+func (mock *SalaryRepositoryMock) GetSubDepartmentsStats(ctx context.Context) ([]api.SubDepartmentStats, error) {
 	if mock.GetSubDepartmentsStatsFunc == nil {
 		panic("SalaryRepositoryMock.GetSubDepartmentsStatsFunc: method is nil but SalaryRepository.GetSubDepartmentsStats was just called")
 	}
 	callInfo := struct {
-	}{}
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
 	mock.lockGetSubDepartmentsStats.Lock()
 	mock.calls.GetSubDepartmentsStats = append(mock.calls.GetSubDepartmentsStats, callInfo)
 	mock.lockGetSubDepartmentsStats.Unlock()
-	return mock.GetSubDepartmentsStatsFunc()
+	return mock.GetSubDepartmentsStatsFunc(ctx)
 }
 
 // GetSubDepartmentsStatsCalls gets all the calls that were made to GetSubDepartmentsStats.
@@ -268,8 +449,10 @@ func (mock *SalaryRepositoryMock) GetSubDepartmentsStats() ([]api.SubDepartmentS
 //
 //	len(mockedSalaryRepository.GetSubDepartmentsStatsCalls())
 func (mock *SalaryRepositoryMock) GetSubDepartmentsStatsCalls() []struct {
+	Ctx context.Context
 } {
 	var calls []struct {
+		Ctx context.Context
 	}
 	mock.lockGetSubDepartmentsStats.RLock()
 	calls = mock.calls.GetSubDepartmentsStats
@@ -277,17 +460,58 @@ func (mock *SalaryRepositoryMock) GetSubDepartmentsStatsCalls() []struct {
 	return calls
 }
 
+// Purge calls PurgeFunc.
+func (mock *SalaryRepositoryMock) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	if mock.PurgeFunc == nil {
+		panic("SalaryRepositoryMock.PurgeFunc: method is nil but SalaryRepository.Purge was just called")
+	}
+	callInfo := struct {
+		Ctx           context.Context
+		DeletedBefore time.Time
+	}{
+		Ctx:           ctx,
+		DeletedBefore: deletedBefore,
+	}
+	mock.lockPurge.Lock()
+	mock.calls.Purge = append(mock.calls.Purge, callInfo)
+	mock.lockPurge.Unlock()
+	return mock.PurgeFunc(ctx, deletedBefore)
+}
+
+// PurgeCalls gets all the calls that were made to Purge.
+// Check the length with:
+//
+//	len(mockedSalaryRepository.PurgeCalls())
+func (mock *SalaryRepositoryMock) PurgeCalls() []struct {
+	Ctx           context.Context
+	DeletedBefore time.Time
+} {
+	var calls []struct {
+		Ctx           context.Context
+		DeletedBefore time.Time
+	}
+	mock.lockPurge.RLock()
+	calls = mock.calls.Purge
+	mock.lockPurge.RUnlock()
+	return calls
+}
+
 // ReadAll calls ReadAllFunc.
-func (mock *SalaryRepositoryMock) ReadAll() ([]domain.Salary, error) {
+func (mock *SalaryRepositoryMock) ReadAll(ctx context.Context, filter api.SalaryFilter) ([]domain.Salary, error) {
 	if mock.ReadAllFunc == nil {
 		panic("SalaryRepositoryMock.ReadAllFunc: method is nil but SalaryRepository.ReadAll was just called")
 	}
 	callInfo := struct {
-	}{}
+		Ctx    context.Context
+		Filter api.SalaryFilter
+	}{
+		Ctx:    ctx,
+		Filter: filter,
+	}
 	mock.lockReadAll.Lock()
 	mock.calls.ReadAll = append(mock.calls.ReadAll, callInfo)
 	mock.lockReadAll.Unlock()
-	return mock.ReadAllFunc()
+	return mock.ReadAllFunc(ctx, filter)
 }
 
 // ReadAllCalls gets all the calls that were made to ReadAll.
@@ -295,11 +519,199 @@ func (mock *SalaryRepositoryMock) ReadAll() ([]domain.Salary, error) {
 //
 //	len(mockedSalaryRepository.ReadAllCalls())
 func (mock *SalaryRepositoryMock) ReadAllCalls() []struct {
+	Ctx    context.Context
+	Filter api.SalaryFilter
 } {
 	var calls []struct {
+		Ctx    context.Context
+		Filter api.SalaryFilter
 	}
 	mock.lockReadAll.RLock()
 	calls = mock.calls.ReadAll
 	mock.lockReadAll.RUnlock()
 	return calls
 }
+
+// ReadByID calls ReadByIDFunc.
+func (mock *SalaryRepositoryMock) ReadByID(ctx context.Context, salaryID int64) (*domain.Salary, error) {
+	if mock.ReadByIDFunc == nil {
+		panic("SalaryRepositoryMock.ReadByIDFunc: method is nil but SalaryRepository.ReadByID was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		SalaryID int64
+	}{
+		Ctx:      ctx,
+		SalaryID: salaryID,
+	}
+	mock.lockReadByID.Lock()
+	mock.calls.ReadByID = append(mock.calls.ReadByID, callInfo)
+	mock.lockReadByID.Unlock()
+	return mock.ReadByIDFunc(ctx, salaryID)
+}
+
+// ReadByIDCalls gets all the calls that were made to ReadByID.
+// Check the length with:
+//
+//	len(mockedSalaryRepository.ReadByIDCalls())
+func (mock *SalaryRepositoryMock) ReadByIDCalls() []struct {
+	Ctx      context.Context
+	SalaryID int64
+} {
+	var calls []struct {
+		Ctx      context.Context
+		SalaryID int64
+	}
+	mock.lockReadByID.RLock()
+	calls = mock.calls.ReadByID
+	mock.lockReadByID.RUnlock()
+	return calls
+}
+
+// ReadEach calls ReadEachFunc.
+func (mock *SalaryRepositoryMock) ReadEach(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary) error) error {
+	if mock.ReadEachFunc == nil {
+		panic("SalaryRepositoryMock.ReadEachFunc: method is nil but SalaryRepository.ReadEach was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Filter api.SalaryFilter
+		Each   func(salary *domain.Salary) error
+	}{
+		Ctx:    ctx,
+		Filter: filter,
+		Each:   each,
+	}
+	mock.lockReadEach.Lock()
+	mock.calls.ReadEach = append(mock.calls.ReadEach, callInfo)
+	mock.lockReadEach.Unlock()
+	return mock.ReadEachFunc(ctx, filter, each)
+}
+
+// ReadEachCalls gets all the calls that were made to ReadEach.
+// Check the length with:
+//
+//	len(mockedSalaryRepository.ReadEachCalls())
+func (mock *SalaryRepositoryMock) ReadEachCalls() []struct {
+	Ctx    context.Context
+	Filter api.SalaryFilter
+	Each   func(salary *domain.Salary) error
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Filter api.SalaryFilter
+		Each   func(salary *domain.Salary) error
+	}
+	mock.lockReadEach.RLock()
+	calls = mock.calls.ReadEach
+	mock.lockReadEach.RUnlock()
+	return calls
+}
+
+// ReadTrash calls ReadTrashFunc.
+func (mock *SalaryRepositoryMock) ReadTrash(ctx context.Context) ([]domain.DeletedSalary, error) {
+	if mock.ReadTrashFunc == nil {
+		panic("SalaryRepositoryMock.ReadTrashFunc: method is nil but SalaryRepository.ReadTrash was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockReadTrash.Lock()
+	mock.calls.ReadTrash = append(mock.calls.ReadTrash, callInfo)
+	mock.lockReadTrash.Unlock()
+	return mock.ReadTrashFunc(ctx)
+}
+
+// ReadTrashCalls gets all the calls that were made to ReadTrash.
+// Check the length with:
+//
+//	len(mockedSalaryRepository.ReadTrashCalls())
+func (mock *SalaryRepositoryMock) ReadTrashCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockReadTrash.RLock()
+	calls = mock.calls.ReadTrash
+	mock.lockReadTrash.RUnlock()
+	return calls
+}
+
+// Restore calls RestoreFunc.
+func (mock *SalaryRepositoryMock) Restore(ctx context.Context, salaryID int64) (*domain.Salary, error) {
+	if mock.RestoreFunc == nil {
+		panic("SalaryRepositoryMock.RestoreFunc: method is nil but SalaryRepository.Restore was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		SalaryID int64
+	}{
+		Ctx:      ctx,
+		SalaryID: salaryID,
+	}
+	mock.lockRestore.Lock()
+	mock.calls.Restore = append(mock.calls.Restore, callInfo)
+	mock.lockRestore.Unlock()
+	return mock.RestoreFunc(ctx, salaryID)
+}
+
+// RestoreCalls gets all the calls that were made to Restore.
+// Check the length with:
+//
+//	len(mockedSalaryRepository.RestoreCalls())
+func (mock *SalaryRepositoryMock) RestoreCalls() []struct {
+	Ctx      context.Context
+	SalaryID int64
+} {
+	var calls []struct {
+		Ctx      context.Context
+		SalaryID int64
+	}
+	mock.lockRestore.RLock()
+	calls = mock.calls.Restore
+	mock.lockRestore.RUnlock()
+	return calls
+}
+
+// Update calls UpdateFunc.
+func (mock *SalaryRepositoryMock) Update(ctx context.Context, salary *domain.Salary, version int64) (*domain.Salary, error) {
+	if mock.UpdateFunc == nil {
+		panic("SalaryRepositoryMock.UpdateFunc: method is nil but SalaryRepository.Update was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Salary  *domain.Salary
+		Version int64
+	}{
+		Ctx:     ctx,
+		Salary:  salary,
+		Version: version,
+	}
+	mock.lockUpdate.Lock()
+	mock.calls.Update = append(mock.calls.Update, callInfo)
+	mock.lockUpdate.Unlock()
+	return mock.UpdateFunc(ctx, salary, version)
+}
+
+// UpdateCalls gets all the calls that were made to Update.
+// Check the length with:
+//
+//	len(mockedSalaryRepository.UpdateCalls())
+func (mock *SalaryRepositoryMock) UpdateCalls() []struct {
+	Ctx     context.Context
+	Salary  *domain.Salary
+	Version int64
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Salary  *domain.Salary
+		Version int64
+	}
+	mock.lockUpdate.RLock()
+	calls = mock.calls.Update
+	mock.lockUpdate.RUnlock()
+	return calls
+}
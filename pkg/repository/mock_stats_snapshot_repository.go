@@ -0,0 +1,277 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package repository
+
+import (
+	"context"
+	"salaries/pkg/api"
+	"salaries/pkg/domain"
+	"sync"
+)
+
+// Ensure, that SnapshotRepositoryMock does implement SnapshotRepository.
+// If this is not the case, regenerate this file with moq.
+var _ SnapshotRepository = &SnapshotRepositoryMock{}
+
+// SnapshotRepositoryMock is a mock implementation of SnapshotRepository.
+//
+//	func TestSomethingThatUsesSnapshotRepository(t *testing.T) {
+//
+//		// make and configure a mocked SnapshotRepository
+//		mockedSnapshotRepository := &SnapshotRepositoryMock{
+//			CreateSnapshotFunc: func(ctx context.Context, snapshot *domain.StatsSnapshot) (*domain.StatsSnapshot, error) {
+//				panic("mock out the CreateSnapshot method")
+//			},
+//			ReadHistoryFunc: func(ctx context.Context, query api.StatsHistoryQuery) ([]domain.StatsSnapshot, error) {
+//				panic("mock out the ReadHistory method")
+//			},
+//			ReadLatestSnapshotFunc: func(ctx context.Context) (*domain.StatsSnapshot, error) {
+//				panic("mock out the ReadLatestSnapshot method")
+//			},
+//			ReadSnapshotFunc: func(ctx context.Context, snapshotID int64) (*domain.StatsSnapshot, error) {
+//				panic("mock out the ReadSnapshot method")
+//			},
+//			ReadSnapshotsFunc: func(ctx context.Context, filter api.SnapshotFilter) ([]domain.StatsSnapshot, error) {
+//				panic("mock out the ReadSnapshots method")
+//			},
+//		}
+//
+//		// use mockedSnapshotRepository in code that requires SnapshotRepository
+//		// and then make assertions.
+//
+//	}
+type SnapshotRepositoryMock struct {
+	// CreateSnapshotFunc mocks the CreateSnapshot method.
+	CreateSnapshotFunc func(ctx context.Context, snapshot *domain.StatsSnapshot) (*domain.StatsSnapshot, error)
+
+	// ReadHistoryFunc mocks the ReadHistory method.
+	ReadHistoryFunc func(ctx context.Context, query api.StatsHistoryQuery) ([]domain.StatsSnapshot, error)
+
+	// ReadLatestSnapshotFunc mocks the ReadLatestSnapshot method.
+	ReadLatestSnapshotFunc func(ctx context.Context) (*domain.StatsSnapshot, error)
+
+	// ReadSnapshotFunc mocks the ReadSnapshot method.
+	ReadSnapshotFunc func(ctx context.Context, snapshotID int64) (*domain.StatsSnapshot, error)
+
+	// ReadSnapshotsFunc mocks the ReadSnapshots method.
+	ReadSnapshotsFunc func(ctx context.Context, filter api.SnapshotFilter) ([]domain.StatsSnapshot, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// CreateSnapshot holds details about calls to the CreateSnapshot method.
+		CreateSnapshot []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Snapshot is the snapshot argument value.
+			Snapshot *domain.StatsSnapshot
+		}
+		// ReadHistory holds details about calls to the ReadHistory method.
+		ReadHistory []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Query is the query argument value.
+			Query api.StatsHistoryQuery
+		}
+		// ReadLatestSnapshot holds details about calls to the ReadLatestSnapshot method.
+		ReadLatestSnapshot []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// ReadSnapshot holds details about calls to the ReadSnapshot method.
+		ReadSnapshot []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// SnapshotID is the snapshotID argument value.
+			SnapshotID int64
+		}
+		// ReadSnapshots holds details about calls to the ReadSnapshots method.
+		ReadSnapshots []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Filter is the filter argument value.
+			Filter api.SnapshotFilter
+		}
+	}
+	lockCreateSnapshot     sync.RWMutex
+	lockReadHistory        sync.RWMutex
+	lockReadLatestSnapshot sync.RWMutex
+	lockReadSnapshot       sync.RWMutex
+	lockReadSnapshots      sync.RWMutex
+}
+
+// CreateSnapshot calls CreateSnapshotFunc.
+func (mock *SnapshotRepositoryMock) CreateSnapshot(ctx context.Context, snapshot *domain.StatsSnapshot) (*domain.StatsSnapshot, error) {
+	if mock.CreateSnapshotFunc == nil {
+		panic("SnapshotRepositoryMock.CreateSnapshotFunc: method is nil but SnapshotRepository.CreateSnapshot was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Snapshot *domain.StatsSnapshot
+	}{
+		Ctx:      ctx,
+		Snapshot: snapshot,
+	}
+	mock.lockCreateSnapshot.Lock()
+	mock.calls.CreateSnapshot = append(mock.calls.CreateSnapshot, callInfo)
+	mock.lockCreateSnapshot.Unlock()
+	return mock.CreateSnapshotFunc(ctx, snapshot)
+}
+
+// CreateSnapshotCalls gets all the calls that were made to CreateSnapshot.
+// Check the length with:
+//
+//	len(mockedSnapshotRepository.CreateSnapshotCalls())
+func (mock *SnapshotRepositoryMock) CreateSnapshotCalls() []struct {
+	Ctx      context.Context
+	Snapshot *domain.StatsSnapshot
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Snapshot *domain.StatsSnapshot
+	}
+	mock.lockCreateSnapshot.RLock()
+	calls = mock.calls.CreateSnapshot
+	mock.lockCreateSnapshot.RUnlock()
+	return calls
+}
+
+// ReadHistory calls ReadHistoryFunc.
+func (mock *SnapshotRepositoryMock) ReadHistory(ctx context.Context, query api.StatsHistoryQuery) ([]domain.StatsSnapshot, error) {
+	if mock.ReadHistoryFunc == nil {
+		panic("SnapshotRepositoryMock.ReadHistoryFunc: method is nil but SnapshotRepository.ReadHistory was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Query api.StatsHistoryQuery
+	}{
+		Ctx:   ctx,
+		Query: query,
+	}
+	mock.lockReadHistory.Lock()
+	mock.calls.ReadHistory = append(mock.calls.ReadHistory, callInfo)
+	mock.lockReadHistory.Unlock()
+	return mock.ReadHistoryFunc(ctx, query)
+}
+
+// ReadHistoryCalls gets all the calls that were made to ReadHistory.
+// Check the length with:
+//
+//	len(mockedSnapshotRepository.ReadHistoryCalls())
+func (mock *SnapshotRepositoryMock) ReadHistoryCalls() []struct {
+	Ctx   context.Context
+	Query api.StatsHistoryQuery
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Query api.StatsHistoryQuery
+	}
+	mock.lockReadHistory.RLock()
+	calls = mock.calls.ReadHistory
+	mock.lockReadHistory.RUnlock()
+	return calls
+}
+
+// ReadLatestSnapshot calls ReadLatestSnapshotFunc.
+func (mock *SnapshotRepositoryMock) ReadLatestSnapshot(ctx context.Context) (*domain.StatsSnapshot, error) {
+	if mock.ReadLatestSnapshotFunc == nil {
+		panic("SnapshotRepositoryMock.ReadLatestSnapshotFunc: method is nil but SnapshotRepository.ReadLatestSnapshot was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockReadLatestSnapshot.Lock()
+	mock.calls.ReadLatestSnapshot = append(mock.calls.ReadLatestSnapshot, callInfo)
+	mock.lockReadLatestSnapshot.Unlock()
+	return mock.ReadLatestSnapshotFunc(ctx)
+}
+
+// ReadLatestSnapshotCalls gets all the calls that were made to ReadLatestSnapshot.
+// Check the length with:
+//
+//	len(mockedSnapshotRepository.ReadLatestSnapshotCalls())
+func (mock *SnapshotRepositoryMock) ReadLatestSnapshotCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockReadLatestSnapshot.RLock()
+	calls = mock.calls.ReadLatestSnapshot
+	mock.lockReadLatestSnapshot.RUnlock()
+	return calls
+}
+
+// ReadSnapshot calls ReadSnapshotFunc.
+func (mock *SnapshotRepositoryMock) ReadSnapshot(ctx context.Context, snapshotID int64) (*domain.StatsSnapshot, error) {
+	if mock.ReadSnapshotFunc == nil {
+		panic("SnapshotRepositoryMock.ReadSnapshotFunc: method is nil but SnapshotRepository.ReadSnapshot was just called")
+	}
+	callInfo := struct {
+		Ctx        context.Context
+		SnapshotID int64
+	}{
+		Ctx:        ctx,
+		SnapshotID: snapshotID,
+	}
+	mock.lockReadSnapshot.Lock()
+	mock.calls.ReadSnapshot = append(mock.calls.ReadSnapshot, callInfo)
+	mock.lockReadSnapshot.Unlock()
+	return mock.ReadSnapshotFunc(ctx, snapshotID)
+}
+
+// ReadSnapshotCalls gets all the calls that were made to ReadSnapshot.
+// Check the length with:
+//
+//	len(mockedSnapshotRepository.ReadSnapshotCalls())
+func (mock *SnapshotRepositoryMock) ReadSnapshotCalls() []struct {
+	Ctx        context.Context
+	SnapshotID int64
+} {
+	var calls []struct {
+		Ctx        context.Context
+		SnapshotID int64
+	}
+	mock.lockReadSnapshot.RLock()
+	calls = mock.calls.ReadSnapshot
+	mock.lockReadSnapshot.RUnlock()
+	return calls
+}
+
+// ReadSnapshots calls ReadSnapshotsFunc.
+func (mock *SnapshotRepositoryMock) ReadSnapshots(ctx context.Context, filter api.SnapshotFilter) ([]domain.StatsSnapshot, error) {
+	if mock.ReadSnapshotsFunc == nil {
+		panic("SnapshotRepositoryMock.ReadSnapshotsFunc: method is nil but SnapshotRepository.ReadSnapshots was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Filter api.SnapshotFilter
+	}{
+		Ctx:    ctx,
+		Filter: filter,
+	}
+	mock.lockReadSnapshots.Lock()
+	mock.calls.ReadSnapshots = append(mock.calls.ReadSnapshots, callInfo)
+	mock.lockReadSnapshots.Unlock()
+	return mock.ReadSnapshotsFunc(ctx, filter)
+}
+
+// ReadSnapshotsCalls gets all the calls that were made to ReadSnapshots.
+// Check the length with:
+//
+//	len(mockedSnapshotRepository.ReadSnapshotsCalls())
+func (mock *SnapshotRepositoryMock) ReadSnapshotsCalls() []struct {
+	Ctx    context.Context
+	Filter api.SnapshotFilter
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Filter api.SnapshotFilter
+	}
+	mock.lockReadSnapshots.RLock()
+	calls = mock.calls.ReadSnapshots
+	mock.lockReadSnapshots.RUnlock()
+	return calls
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"salaries/pkg/api"
+	dbClient "salaries/pkg/db"
+	"salaries/pkg/domain"
+)
+
+type PayBandRepository interface {
+	Create(ctx context.Context, band *domain.PayBand) (*domain.PayBand, error)
+	ReadAll(ctx context.Context, filter api.PayBandFilter) ([]domain.PayBand, error)
+	ReadByID(ctx context.Context, bandID int64) (*domain.PayBand, error)
+	Update(ctx context.Context, band *domain.PayBand) (*domain.PayBand, error)
+	DeleteByID(ctx context.Context, bandID int64) error
+}
+
+type payBandRepositoryImpl struct {
+	dbClient dbClient.DataBasePayBandClient
+}
+
+func NewPayBandRepositoryWithClient(dbClient dbClient.DataBasePayBandClient) PayBandRepository {
+	return &payBandRepositoryImpl{
+		dbClient: dbClient,
+	}
+}
+
+func (p payBandRepositoryImpl) Create(ctx context.Context, band *domain.PayBand) (*domain.PayBand, error) {
+	return p.dbClient.Create(ctx, band)
+}
+
+func (p payBandRepositoryImpl) ReadAll(ctx context.Context, filter api.PayBandFilter) ([]domain.PayBand, error) {
+	return p.dbClient.ReadAll(ctx, filter)
+}
+
+func (p payBandRepositoryImpl) ReadByID(ctx context.Context, bandID int64) (*domain.PayBand, error) {
+	return p.dbClient.ReadByID(ctx, bandID)
+}
+
+func (p payBandRepositoryImpl) Update(ctx context.Context, band *domain.PayBand) (*domain.PayBand, error) {
+	return p.dbClient.Update(ctx, band)
+}
+
+func (p payBandRepositoryImpl) DeleteByID(ctx context.Context, bandID int64) error {
+	return p.dbClient.DeleteByID(ctx, bandID)
+}
@@ -1,69 +1,92 @@
 package repository
 
 import (
-	"database/sql"
-	_ "github.com/mattn/go-sqlite3"
+	"context"
 	"salaries/pkg/api"
 	dbClient "salaries/pkg/db"
 	"salaries/pkg/domain"
-	"salaries/pkg/logger"
+	"time"
 )
 
 type SalaryRepository interface {
-	Create(salary *domain.Salary) (*domain.Salary, error)
-	ReadAll() ([]domain.Salary, error)
-	DeleteByID(salaryID int64) error
-	GetStatsForAllSalaries() (*api.Stats, error)
-	GetContractsStats() (*api.Stats, error)
-	GetDepartmentsStats() ([]api.DepartmentStats, error)
-	GetSubDepartmentsStats() ([]api.SubDepartmentStats, error)
+	Create(ctx context.Context, salary *domain.Salary) (*domain.Salary, error)
+	Batch(ctx context.Context) (dbClient.SalaryBatch, error)
+	ReadAll(ctx context.Context, filter api.SalaryFilter) ([]domain.Salary, error)
+	ReadEach(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary) error) error
+	ReadByID(ctx context.Context, salaryID int64) (*domain.Salary, error)
+	Update(ctx context.Context, salary *domain.Salary, version int64) (*domain.Salary, error)
+	DeleteByID(ctx context.Context, salaryID int64, version int64) (*domain.Salary, error)
+	ReadTrash(ctx context.Context) ([]domain.DeletedSalary, error)
+	Restore(ctx context.Context, salaryID int64) (*domain.Salary, error)
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
+	GetStatsForAllSalaries(ctx context.Context) (*api.Stats, error)
+	GetContractsStats(ctx context.Context) (*api.Stats, error)
+	GetDepartmentsStats(ctx context.Context) ([]api.DepartmentStats, error)
+	GetSubDepartmentsStats(ctx context.Context) ([]api.SubDepartmentStats, error)
 }
 
 type salaryRepositoryImpl struct {
 	dbClient dbClient.DataBaseSalaryClient
 }
 
-func NewSalaryRepository(logger logger.Logger) (SalaryRepository, error) {
-	db, err := sql.Open("sqlite3", "salaries.db")
-
-	if err != nil {
-		logger.Error("error open database", err)
-	}
-
-	dbClient := dbClient.NewSqlite(db)
-	return NewSalaryRepositoryWithClient(dbClient), nil
-}
-
 func NewSalaryRepositoryWithClient(dbClient dbClient.DataBaseSalaryClient) SalaryRepository {
 	return &salaryRepositoryImpl{
 		dbClient: dbClient,
 	}
 }
 
-func (s salaryRepositoryImpl) Create(salary *domain.Salary) (*domain.Salary, error) {
-	return s.dbClient.Create(salary)
+func (s salaryRepositoryImpl) Create(ctx context.Context, salary *domain.Salary) (*domain.Salary, error) {
+	return s.dbClient.Create(ctx, salary)
+}
+
+func (s salaryRepositoryImpl) Batch(ctx context.Context) (dbClient.SalaryBatch, error) {
+	return s.dbClient.Batch(ctx)
+}
+
+func (s salaryRepositoryImpl) ReadAll(ctx context.Context, filter api.SalaryFilter) ([]domain.Salary, error) {
+	return s.dbClient.ReadAll(ctx, filter)
+}
+
+func (s salaryRepositoryImpl) ReadEach(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary) error) error {
+	return s.dbClient.ReadEach(ctx, filter, each)
+}
+
+func (s salaryRepositoryImpl) ReadByID(ctx context.Context, salaryID int64) (*domain.Salary, error) {
+	return s.dbClient.ReadByID(ctx, salaryID)
+}
+
+func (s salaryRepositoryImpl) Update(ctx context.Context, salary *domain.Salary, version int64) (*domain.Salary, error) {
+	return s.dbClient.Update(ctx, salary, version)
+}
+
+func (s salaryRepositoryImpl) DeleteByID(ctx context.Context, salaryID int64, version int64) (*domain.Salary, error) {
+	return s.dbClient.DeleteByID(ctx, salaryID, version)
+}
+
+func (s salaryRepositoryImpl) ReadTrash(ctx context.Context) ([]domain.DeletedSalary, error) {
+	return s.dbClient.ReadTrash(ctx)
 }
 
-func (s salaryRepositoryImpl) ReadAll() ([]domain.Salary, error) {
-	return s.dbClient.ReadAll()
+func (s salaryRepositoryImpl) Restore(ctx context.Context, salaryID int64) (*domain.Salary, error) {
+	return s.dbClient.Restore(ctx, salaryID)
 }
 
-func (s salaryRepositoryImpl) DeleteByID(salaryID int64) error {
-	return s.dbClient.DeleteByID(salaryID)
+func (s salaryRepositoryImpl) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	return s.dbClient.Purge(ctx, deletedBefore)
 }
 
-func (s salaryRepositoryImpl) GetStatsForAllSalaries() (*api.Stats, error) {
-	return s.dbClient.GetStatsForAllSalaries()
+func (s salaryRepositoryImpl) GetStatsForAllSalaries(ctx context.Context) (*api.Stats, error) {
+	return s.dbClient.GetStatsForAllSalaries(ctx)
 }
 
-func (s salaryRepositoryImpl) GetContractsStats() (*api.Stats, error) {
-	return s.dbClient.GetContractsStats()
+func (s salaryRepositoryImpl) GetContractsStats(ctx context.Context) (*api.Stats, error) {
+	return s.dbClient.GetContractsStats(ctx)
 }
 
-func (s salaryRepositoryImpl) GetDepartmentsStats() ([]api.DepartmentStats, error) {
-	return s.dbClient.GetDepartmentsStats()
+func (s salaryRepositoryImpl) GetDepartmentsStats(ctx context.Context) ([]api.DepartmentStats, error) {
+	return s.dbClient.GetDepartmentsStats(ctx)
 }
 
-func (s salaryRepositoryImpl) GetSubDepartmentsStats() ([]api.SubDepartmentStats, error) {
-	return s.dbClient.GetSubDepartmentsStats()
+func (s salaryRepositoryImpl) GetSubDepartmentsStats(ctx context.Context) ([]api.SubDepartmentStats, error) {
+	return s.dbClient.GetSubDepartmentsStats(ctx)
 }
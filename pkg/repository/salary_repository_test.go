@@ -1,8 +1,10 @@
 package repository_test
 
 import (
+	"context"
 	"errors"
 	"github.com/stretchr/testify/assert"
+	"salaries/pkg/api"
 	"salaries/pkg/db"
 	"salaries/pkg/domain"
 	"salaries/pkg/repository"
@@ -31,7 +33,7 @@ func TestRepository_Create(t *testing.T) {
 			},
 			fields: repositoryFields{
 				dbClient: &db.DataBaseSalaryClientMock{
-					CreateFunc: func(salary *domain.Salary) (*domain.Salary, error) {
+					CreateFunc: func(ctx context.Context, salary *domain.Salary) (*domain.Salary, error) {
 						return &domain.Salary{
 							ID:            1,
 							Name:          "Anurag",
@@ -56,7 +58,7 @@ func TestRepository_Create(t *testing.T) {
 			},
 			fields: repositoryFields{
 				dbClient: &db.DataBaseSalaryClientMock{
-					CreateFunc: func(salary *domain.Salary) (*domain.Salary, error) {
+					CreateFunc: func(ctx context.Context, salary *domain.Salary) (*domain.Salary, error) {
 						return nil, errors.New("error")
 					},
 				},
@@ -68,7 +70,7 @@ func TestRepository_Create(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			salaryRepository := repository.NewSalaryRepositoryWithClient(tt.fields.dbClient)
 
-			salary, err := salaryRepository.Create(tt.salary)
+			salary, err := salaryRepository.Create(context.Background(), tt.salary)
 			if !tt.wantError {
 				tt.salary.ID = salary.ID
 				assert.Equal(t, tt.salary, salary)
@@ -101,7 +103,7 @@ func TestRepository_ReadAll(t *testing.T) {
 			salaries: salaries,
 			fields: repositoryFields{
 				dbClient: &db.DataBaseSalaryClientMock{
-					ReadAllFunc: func() ([]domain.Salary, error) {
+					ReadAllFunc: func(ctx context.Context, filter api.SalaryFilter) ([]domain.Salary, error) {
 						return salaries, nil
 					},
 				},
@@ -113,7 +115,7 @@ func TestRepository_ReadAll(t *testing.T) {
 			salaries: nil,
 			fields: repositoryFields{
 				dbClient: &db.DataBaseSalaryClientMock{
-					ReadAllFunc: func() ([]domain.Salary, error) {
+					ReadAllFunc: func(ctx context.Context, filter api.SalaryFilter) ([]domain.Salary, error) {
 						return nil, errors.New("error")
 					},
 				},
@@ -125,7 +127,7 @@ func TestRepository_ReadAll(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			salaryRepository := repository.NewSalaryRepositoryWithClient(tt.fields.dbClient)
 
-			salaries, err := salaryRepository.ReadAll()
+			salaries, err := salaryRepository.ReadAll(context.Background(), api.SalaryFilter{})
 			if !tt.wantError {
 				assert.EqualValues(t, tt.salaries, salaries)
 			}
@@ -146,8 +148,8 @@ func TestRepository_DeleteByID(t *testing.T) {
 			ID:   1,
 			fields: repositoryFields{
 				dbClient: &db.DataBaseSalaryClientMock{
-					DeleteByIDFunc: func(id int64) error {
-						return nil
+					DeleteByIDFunc: func(ctx context.Context, id int64, version int64) (*domain.Salary, error) {
+						return &domain.Salary{ID: id}, nil
 					},
 				},
 			},
@@ -158,8 +160,8 @@ func TestRepository_DeleteByID(t *testing.T) {
 			ID:   2,
 			fields: repositoryFields{
 				dbClient: &db.DataBaseSalaryClientMock{
-					DeleteByIDFunc: func(id int64) error {
-						return errors.New("error")
+					DeleteByIDFunc: func(ctx context.Context, id int64, version int64) (*domain.Salary, error) {
+						return nil, errors.New("error")
 					},
 				},
 			},
@@ -170,7 +172,7 @@ func TestRepository_DeleteByID(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			salaryRepository := repository.NewSalaryRepositoryWithClient(tt.fields.dbClient)
 
-			err := salaryRepository.DeleteByID(tt.ID)
+			_, err := salaryRepository.DeleteByID(context.Background(), tt.ID, 0)
 			assert.Equal(t, tt.wantError, err != nil)
 		})
 	}
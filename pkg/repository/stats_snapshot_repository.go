@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"salaries/pkg/api"
+	dbClient "salaries/pkg/db"
+	"salaries/pkg/domain"
+)
+
+type SnapshotRepository interface {
+	CreateSnapshot(ctx context.Context, snapshot *domain.StatsSnapshot) (*domain.StatsSnapshot, error)
+	ReadSnapshots(ctx context.Context, filter api.SnapshotFilter) ([]domain.StatsSnapshot, error)
+	ReadSnapshot(ctx context.Context, snapshotID int64) (*domain.StatsSnapshot, error)
+	ReadLatestSnapshot(ctx context.Context) (*domain.StatsSnapshot, error)
+	ReadHistory(ctx context.Context, query api.StatsHistoryQuery) ([]domain.StatsSnapshot, error)
+}
+
+type snapshotRepositoryImpl struct {
+	dbClient dbClient.DataBaseSnapshotClient
+}
+
+func NewSnapshotRepositoryWithClient(dbClient dbClient.DataBaseSnapshotClient) SnapshotRepository {
+	return &snapshotRepositoryImpl{
+		dbClient: dbClient,
+	}
+}
+
+func (s snapshotRepositoryImpl) CreateSnapshot(ctx context.Context, snapshot *domain.StatsSnapshot) (*domain.StatsSnapshot, error) {
+	return s.dbClient.CreateSnapshot(ctx, snapshot)
+}
+
+func (s snapshotRepositoryImpl) ReadSnapshots(ctx context.Context, filter api.SnapshotFilter) ([]domain.StatsSnapshot, error) {
+	return s.dbClient.ReadSnapshots(ctx, filter)
+}
+
+func (s snapshotRepositoryImpl) ReadSnapshot(ctx context.Context, snapshotID int64) (*domain.StatsSnapshot, error) {
+	return s.dbClient.ReadSnapshot(ctx, snapshotID)
+}
+
+func (s snapshotRepositoryImpl) ReadLatestSnapshot(ctx context.Context) (*domain.StatsSnapshot, error) {
+	return s.dbClient.ReadLatestSnapshot(ctx)
+}
+
+func (s snapshotRepositoryImpl) ReadHistory(ctx context.Context, query api.StatsHistoryQuery) ([]domain.StatsSnapshot, error) {
+	return s.dbClient.ReadHistory(ctx, query)
+}
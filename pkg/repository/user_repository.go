@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	dbClient "salaries/pkg/db"
+	"salaries/pkg/domain"
+)
+
+type UserRepository interface {
+	Create(ctx context.Context, user *domain.User) (*domain.User, error)
+	ReadByUsername(ctx context.Context, username string) (*domain.User, error)
+	UpdatePasswordHash(ctx context.Context, username, passwordHash string) error
+}
+
+type userRepositoryImpl struct {
+	dbClient dbClient.DataBaseUserClient
+}
+
+func NewUserRepositoryWithClient(dbClient dbClient.DataBaseUserClient) UserRepository {
+	return &userRepositoryImpl{
+		dbClient: dbClient,
+	}
+}
+
+func (u userRepositoryImpl) Create(ctx context.Context, user *domain.User) (*domain.User, error) {
+	return u.dbClient.Create(ctx, user)
+}
+
+func (u userRepositoryImpl) ReadByUsername(ctx context.Context, username string) (*domain.User, error) {
+	return u.dbClient.ReadByUsername(ctx, username)
+}
+
+func (u userRepositoryImpl) UpdatePasswordHash(ctx context.Context, username, passwordHash string) error {
+	return u.dbClient.UpdatePasswordHash(ctx, username, passwordHash)
+}
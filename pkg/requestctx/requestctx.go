@@ -0,0 +1,53 @@
+package requestctx
+
+import (
+	"context"
+	"salaries/pkg/domain"
+)
+
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	userKey
+	routeKey
+	cacheBypassKey
+)
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+func WithUser(ctx context.Context, user *domain.User) context.Context {
+	return context.WithValue(ctx, userKey, user)
+}
+
+// User returns the authenticated user of the request or nil for anonymous requests
+func User(ctx context.Context) *domain.User {
+	user, _ := ctx.Value(userKey).(*domain.User)
+	return user
+}
+
+func WithRoute(ctx context.Context, route string) context.Context {
+	return context.WithValue(ctx, routeKey, route)
+}
+
+func Route(ctx context.Context) string {
+	route, _ := ctx.Value(routeKey).(string)
+	return route
+}
+
+// WithCacheBypass makes the services answer the request from the database instead of their caches
+func WithCacheBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheBypassKey, true)
+}
+
+func CacheBypass(ctx context.Context) bool {
+	bypass, _ := ctx.Value(cacheBypassKey).(bool)
+	return bypass
+}
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"github.com/gin-gonic/gin"
+	"salaries/pkg/auth"
+	"salaries/pkg/config"
+	"salaries/pkg/controller"
+	"salaries/pkg/domain"
+	"salaries/pkg/logger"
+	"salaries/pkg/metrics"
+	"salaries/pkg/middleware"
+	"salaries/pkg/repository"
+	"salaries/pkg/service"
+)
+
+// NewRouter wires the middlewares, controllers and routes of the api
+func NewRouter(cfg config.Config, authService auth.Service, salaryService service.SalaryService, auditService service.AuditService, payBandService service.PayBandService, payEquityService service.PayEquityService, budgetService service.BudgetService, snapshotService service.SnapshotService, idempotencyRepository repository.IdempotencyRepository, registry *metrics.Registry, logger logger.Logger) *gin.Engine {
+	router := gin.New()
+	router.SetTrustedProxies([]string{cfg.TrustedProxy})
+	router.Use(middleware.NewRequestIDMiddleware())
+	router.Use(middleware.NewCacheControlMiddleware())
+	router.Use(middleware.NewTracingMiddleware())
+	router.Use(middleware.NewLoggerMiddleware(logger))
+	// the metrics are registered before the recovery so a panic is counted with the 500 it is answered with
+	router.Use(middleware.NewMetricsMiddleware(registry))
+	router.Use(gin.Recovery())
+
+	router.GET("/metrics", gin.WrapH(registry.Handler()))
+
+	authController := auth.NewAuthController(authService)
+
+	publicRoutes := router.Group("/auth")
+	publicRoutes.POST("/login", authController.Login)
+
+	maxBatchSize := cfg.MaxBatchSize
+	if maxBatchSize <= 0 {
+		maxBatchSize = config.DefaultMaxBatchSize
+	}
+	salaryController := controller.NewSalaryController(salaryService, maxBatchSize)
+
+	trashRetention := cfg.TrashRetention
+	if trashRetention <= 0 {
+		trashRetention = config.DefaultTrashRetention
+	}
+	trashController := controller.NewTrashController(salaryService, trashRetention)
+
+	var preconditions []gin.HandlerFunc
+	if cfg.RequireIfMatch {
+		preconditions = append(preconditions, middleware.NewRequireIfMatchMiddleware())
+	}
+
+	idempotencyWindow := cfg.IdempotencyWindow
+	if idempotencyWindow <= 0 {
+		idempotencyWindow = config.DefaultIdempotencyWindow
+	}
+	idempotency := middleware.NewIdempotencyMiddleware(idempotencyRepository, idempotencyWindow, logger)
+
+	protectedRoutes := router.Group("/api/salaries")
+	protectedRoutes.Use(middleware.NewAuthMiddleware(authService, logger), idempotency)
+	protectedRoutes.GET("", salaryController.GetAll)
+	protectedRoutes.POST("", salaryController.Create)
+	protectedRoutes.DELETE("", salaryController.DeleteBatch)
+	protectedRoutes.POST("/batch", salaryController.CreateBatch)
+	protectedRoutes.GET("/export", salaryController.Export)
+	protectedRoutes.GET("/:id", salaryController.GetByID)
+	protectedRoutes.PUT("/:id", append(preconditions, salaryController.Update)...)
+	protectedRoutes.DELETE("/:id", append(preconditions, salaryController.Delete)...)
+	protectedRoutes.POST("/:id/restore", trashController.Restore)
+	protectedRoutes.GET("/trash", trashController.GetAll)
+	protectedRoutes.GET("/stats", salaryController.GetStatisticsEntireDataset)
+	protectedRoutes.GET("/stats/contracts", salaryController.GetContractsStats)
+	protectedRoutes.GET("/stats/departments", salaryController.GetDepartmentsStats)
+	protectedRoutes.GET("/stats/sub-departments", salaryController.GetSubDepartmentsStats)
+	protectedRoutes.GET("/stats/histogram", salaryController.GetHistogram)
+	protectedRoutes.GET("/stats/rank", salaryController.GetRank)
+	protectedRoutes.GET("/anomalies", salaryController.GetAnomalies)
+	protectedRoutes.POST("/simulations", salaryController.Simulate)
+
+	// the import streams its body, it is hashed while it is read instead of in memory
+	importRoutes := router.Group("/api/salaries")
+	importRoutes.Use(middleware.NewAuthMiddleware(authService, logger), middleware.NewStreamingIdempotencyMiddleware(idempotencyRepository, idempotencyWindow, logger))
+	importRoutes.POST("/import", salaryController.Import)
+
+	trashAdminRoutes := router.Group("/api/salaries/trash")
+	trashAdminRoutes.Use(middleware.NewAuthMiddleware(authService, logger), middleware.NewRoleMiddleware(domain.RoleAdmin))
+	trashAdminRoutes.DELETE("", trashController.Purge)
+
+	payBandController := controller.NewPayBandController(payBandService)
+
+	payBandRoutes := router.Group("/api/pay-bands")
+	payBandRoutes.Use(middleware.NewAuthMiddleware(authService, logger))
+	payBandRoutes.GET("", payBandController.GetAll)
+	payBandRoutes.GET("/compa-ratios", payBandController.GetCompaRatios)
+	payBandRoutes.GET("/out-of-range", payBandController.GetOutOfRange)
+	payBandRoutes.GET("/:id", payBandController.GetByID)
+
+	payBandAdminRoutes := router.Group("/api/pay-bands")
+	payBandAdminRoutes.Use(middleware.NewAuthMiddleware(authService, logger), middleware.NewRoleMiddleware(domain.RoleAdmin), idempotency)
+	payBandAdminRoutes.POST("", payBandController.Create)
+	payBandAdminRoutes.PUT("/:id", payBandController.Update)
+	payBandAdminRoutes.DELETE("/:id", payBandController.Delete)
+
+	payEquityController := controller.NewPayEquityController(payEquityService)
+
+	hrRoutes := router.Group("/api/salaries")
+	hrRoutes.Use(middleware.NewAuthMiddleware(authService, logger), middleware.NewRoleMiddleware(domain.RoleHR, domain.RoleAdmin))
+	hrRoutes.GET("/:id/demographics", payEquityController.GetDemographics)
+	hrRoutes.PUT("/:id/demographics", payEquityController.SaveDemographics)
+	hrRoutes.DELETE("/:id/demographics", payEquityController.DeleteDemographics)
+	hrRoutes.GET("/stats/pay-equity", payEquityController.GetPayEquity)
+
+	budgetController := controller.NewBudgetController(budgetService)
+
+	payrollRoutes := router.Group("/api/payroll")
+	payrollRoutes.Use(middleware.NewAuthMiddleware(authService, logger))
+	payrollRoutes.GET("/budgets", budgetController.GetBudgets)
+	payrollRoutes.GET("/changes", budgetController.GetChanges)
+	payrollRoutes.GET("/projection", budgetController.GetProjection)
+	payrollRoutes.GET("/variance", budgetController.GetVariance)
+
+	payrollAdminRoutes := router.Group("/api/payroll")
+	payrollAdminRoutes.Use(middleware.NewAuthMiddleware(authService, logger), middleware.NewRoleMiddleware(domain.RoleAdmin), idempotency)
+	payrollAdminRoutes.POST("/budgets", budgetController.CreateBudget)
+	payrollAdminRoutes.PUT("/budgets/:id", budgetController.UpdateBudget)
+	payrollAdminRoutes.DELETE("/budgets/:id", budgetController.DeleteBudget)
+	payrollAdminRoutes.POST("/changes", budgetController.CreateChange)
+	payrollAdminRoutes.PUT("/changes/:id", budgetController.UpdateChange)
+	payrollAdminRoutes.DELETE("/changes/:id", budgetController.DeleteChange)
+
+	snapshotController := controller.NewSnapshotController(snapshotService)
+
+	statsRoutes := router.Group("/api/salaries/stats")
+	statsRoutes.Use(middleware.NewAuthMiddleware(authService, logger))
+	statsRoutes.GET("/history", snapshotController.GetHistory)
+	statsRoutes.GET("/diff", snapshotController.GetDiff)
+	statsRoutes.GET("/snapshots", snapshotController.GetSnapshots)
+	statsRoutes.GET("/snapshots/:id", snapshotController.GetSnapshot)
+
+	statsAdminRoutes := router.Group("/api/salaries/stats")
+	statsAdminRoutes.Use(middleware.NewAuthMiddleware(authService, logger), middleware.NewRoleMiddleware(domain.RoleAdmin), idempotency)
+	statsAdminRoutes.POST("/snapshots", snapshotController.TakeSnapshot)
+
+	auditController := controller.NewAuditController(auditService)
+
+	adminRoutes := router.Group("/api/audit")
+	adminRoutes.Use(middleware.NewAuthMiddleware(authService, logger), middleware.NewRoleMiddleware(domain.RoleAdmin))
+	adminRoutes.GET("", auditController.GetAll)
+
+	return router
+}
@@ -0,0 +1,55 @@
+// Package servertest runs the api for the tests of its clients
+package servertest
+
+import (
+	"context"
+	"database/sql"
+	"github.com/gin-gonic/gin"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http/httptest"
+	"salaries/pkg/auth"
+	"salaries/pkg/config"
+	"salaries/pkg/db"
+	"salaries/pkg/logger"
+	"salaries/pkg/metrics"
+	"salaries/pkg/repository"
+	"salaries/pkg/server"
+	"salaries/pkg/service"
+	"testing"
+	"time"
+)
+
+// SigningKey signs the access tokens of the test server
+const SigningKey = "test-signing-key"
+
+// NewServer runs the real router on an in-memory database with the migrated demo admin, it is closed with the test
+func NewServer(t testing.TB) *httptest.Server {
+	gin.SetMode(gin.TestMode)
+	database, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	database.SetMaxOpenConns(1)
+	t.Cleanup(func() { database.Close() })
+	require.NoError(t, db.Migrate(context.Background(), database))
+
+	testLogger := logger.NewLoggerWithConfig(logger.Config{Level: "error", Output: io.Discard})
+	registry := metrics.NewRegistry()
+	salaryRepository := repository.NewSalaryRepositoryWithClient(db.NewSqlite(database))
+	auditRepository := repository.NewAuditRepositoryWithClient(db.NewSqliteAuditClient(database))
+	userRepository := repository.NewUserRepositoryWithClient(db.NewSqliteUserClient(database))
+	router := server.NewRouter(config.Config{},
+		auth.NewAuthService(userRepository, SigningKey, time.Hour, testLogger, registry),
+		service.NewSalaryService(salaryRepository, testLogger, service.WithStatsCache(service.NewStatsCache(salaryRepository, testLogger))),
+		service.NewAuditService(auditRepository, testLogger),
+		service.NewPayBandService(repository.NewPayBandRepositoryWithClient(db.NewSqlitePayBandClient(database)), salaryRepository, nil, testLogger),
+		service.NewPayEquityService(repository.NewDemographicsRepositoryWithClient(db.NewSqliteDemographicsClient(database)), nil, config.DefaultPayEquityMinGroupSize, testLogger),
+		service.NewBudgetService(repository.NewBudgetRepositoryWithClient(db.NewSqliteBudgetClient(database)), salaryRepository, nil, time.January, config.DefaultBudgetCurrency, service.StatsPrivacy{}, testLogger),
+		service.NewSnapshotService(repository.NewSnapshotRepositoryWithClient(db.NewSqliteSnapshotClient(database)), salaryRepository, service.StatsPrivacy{}, testLogger),
+		repository.NewIdempotencyRepositoryWithClient(db.NewSqliteIdempotencyClient(database)),
+		registry, testLogger)
+
+	testServer := httptest.NewServer(router)
+	t.Cleanup(testServer.Close)
+	return testServer
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"salaries/pkg/api"
+	"salaries/pkg/logger"
+	"salaries/pkg/repository"
+	"salaries/pkg/tracing"
+)
+
+const (
+	DefaultAuditPageSize = 50
+	MaxAuditPageSize     = 500
+)
+
+type AuditService interface {
+	Find(ctx context.Context, filter api.AuditFilter) (*api.AuditPage, error)
+}
+
+type auditServiceImpl struct {
+	auditRepository repository.AuditRepository
+	logger          logger.Logger
+}
+
+func NewAuditService(auditRepository repository.AuditRepository, logger logger.Logger) AuditService {
+	return &auditServiceImpl{
+		auditRepository: auditRepository,
+		logger:          logger,
+	}
+}
+
+func (s auditServiceImpl) Find(ctx context.Context, filter api.AuditFilter) (*api.AuditPage, error) {
+	ctx, span := tracer.Start(ctx, "AuditService.Find")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultAuditPageSize
+	}
+	if filter.Limit > MaxAuditPageSize {
+		filter.Limit = MaxAuditPageSize
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	logger.Info("getting audit events")
+	events, total, err := s.auditRepository.Find(ctx, filter)
+	if err != nil {
+		logger.Error("error getting audit events: %s", err.Error())
+		return nil, tracing.RecordError(span, err)
+	}
+	logger.Info("audit events retrieved")
+	return &api.AuditPage{
+		Events: events,
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}, nil
+}
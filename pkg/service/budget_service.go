@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"salaries/pkg/api"
+	"salaries/pkg/currency"
+	"salaries/pkg/domain"
+	"salaries/pkg/logger"
+	"salaries/pkg/repository"
+	"salaries/pkg/tracing"
+	"time"
+)
+
+type BudgetService interface {
+	CreateBudget(ctx context.Context, budget *domain.Budget) error
+	GetBudgets(ctx context.Context, filter api.BudgetFilter) ([]domain.Budget, error)
+	UpdateBudget(ctx context.Context, budget *domain.Budget) error
+	DeleteBudgetByID(ctx context.Context, id int64) error
+	CreateChange(ctx context.Context, change *domain.PayrollChange) error
+	GetChanges(ctx context.Context, filter api.PayrollChangeFilter) ([]domain.PayrollChange, error)
+	UpdateChange(ctx context.Context, change *domain.PayrollChange) error
+	DeleteChangeByID(ctx context.Context, id int64) error
+	GetProjection(ctx context.Context, query api.PayrollQuery) (*api.PayrollProjection, error)
+	GetVariance(ctx context.Context, query api.PayrollQuery) (*api.VarianceReport, error)
+}
+
+type budgetServiceImpl struct {
+	budgetRepository repository.BudgetRepository
+	salaryRepository repository.SalaryRepository
+	rates            currency.Rates
+	fiscalYearStart  time.Month
+	currency         string
+	privacy          StatsPrivacy
+	logger           logger.Logger
+}
+
+// NewBudgetService manages the department budgets and planned payroll changes and projects the payroll cost of the
+// fiscal years starting in fiscalYearStart, costs are converted with the rates and reported in currency by default. The
+// projected departments go through the privacy of the stats
+func NewBudgetService(budgetRepository repository.BudgetRepository, salaryRepository repository.SalaryRepository, rates currency.Rates, fiscalYearStart time.Month, currency string, privacy StatsPrivacy, logger logger.Logger) BudgetService {
+	return &budgetServiceImpl{
+		budgetRepository: budgetRepository,
+		salaryRepository: salaryRepository,
+		rates:            rates,
+		fiscalYearStart:  fiscalYearStart,
+		currency:         currency,
+		privacy:          privacy,
+		logger:           logger,
+	}
+}
+
+func (s budgetServiceImpl) CreateBudget(ctx context.Context, budget *domain.Budget) error {
+	ctx, span := tracer.Start(ctx, "BudgetService.CreateBudget")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("creating %d budget of %s", budget.FiscalYear, budget.Department)
+	if _, err := s.budgetRepository.CreateBudget(ctx, budget); err != nil {
+		logger.Error("error creating budget: %s", err.Error())
+		return tracing.RecordError(span, err)
+	}
+	logger.Info("budget created with id %d", budget.ID)
+	return nil
+}
+
+func (s budgetServiceImpl) GetBudgets(ctx context.Context, filter api.BudgetFilter) ([]domain.Budget, error) {
+	ctx, span := tracer.Start(ctx, "BudgetService.GetBudgets")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("getting budgets")
+	budgets, err := s.budgetRepository.ReadBudgets(ctx, filter)
+	if err != nil {
+		logger.Error("error getting budgets: %s", err.Error())
+		return nil, tracing.RecordError(span, err)
+	}
+	logger.Info("%d budgets retrieved", len(budgets))
+	return budgets, nil
+}
+
+func (s budgetServiceImpl) UpdateBudget(ctx context.Context, budget *domain.Budget) error {
+	ctx, span := tracer.Start(ctx, "BudgetService.UpdateBudget")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("updating budget with id %d", budget.ID)
+	if _, err := s.budgetRepository.UpdateBudget(ctx, budget); err != nil {
+		logger.Error("error updating budget with id %d: %s", budget.ID, err.Error())
+		return tracing.RecordError(span, err)
+	}
+	logger.Info("budget updated with id %d", budget.ID)
+	return nil
+}
+
+func (s budgetServiceImpl) DeleteBudgetByID(ctx context.Context, id int64) error {
+	ctx, span := tracer.Start(ctx, "BudgetService.DeleteBudgetByID")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("deleting budget with id %d", id)
+	if err := s.budgetRepository.DeleteBudgetByID(ctx, id); err != nil {
+		logger.Error("error deleting budget with id %d: %s", id, err.Error())
+		return tracing.RecordError(span, err)
+	}
+	logger.Info("budget deleted with id %d", id)
+	return nil
+}
+
+func (s budgetServiceImpl) CreateChange(ctx context.Context, change *domain.PayrollChange) error {
+	ctx, span := tracer.Start(ctx, "BudgetService.CreateChange")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("planning %s effective %s", change.Kind, change.EffectiveDate.Format(time.RFC3339))
+	if _, err := s.budgetRepository.CreateChange(ctx, change); err != nil {
+		logger.Error("error planning %s: %s", change.Kind, err.Error())
+		return tracing.RecordError(span, err)
+	}
+	logger.Info("payroll change created with id %d", change.ID)
+	return nil
+}
+
+func (s budgetServiceImpl) GetChanges(ctx context.Context, filter api.PayrollChangeFilter) ([]domain.PayrollChange, error) {
+	ctx, span := tracer.Start(ctx, "BudgetService.GetChanges")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("getting payroll changes")
+	changes, err := s.budgetRepository.ReadChanges(ctx, filter)
+	if err != nil {
+		logger.Error("error getting payroll changes: %s", err.Error())
+		return nil, tracing.RecordError(span, err)
+	}
+	logger.Info("%d payroll changes retrieved", len(changes))
+	return changes, nil
+}
+
+func (s budgetServiceImpl) UpdateChange(ctx context.Context, change *domain.PayrollChange) error {
+	ctx, span := tracer.Start(ctx, "BudgetService.UpdateChange")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("updating payroll change with id %d", change.ID)
+	if _, err := s.budgetRepository.UpdateChange(ctx, change); err != nil {
+		logger.Error("error updating payroll change with id %d: %s", change.ID, err.Error())
+		return tracing.RecordError(span, err)
+	}
+	logger.Info("payroll change updated with id %d", change.ID)
+	return nil
+}
+
+func (s budgetServiceImpl) DeleteChangeByID(ctx context.Context, id int64) error {
+	ctx, span := tracer.Start(ctx, "BudgetService.DeleteChangeByID")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("deleting payroll change with id %d", id)
+	if err := s.budgetRepository.DeleteChangeByID(ctx, id); err != nil {
+		logger.Error("error deleting payroll change with id %d: %s", id, err.Error())
+		return tracing.RecordError(span, err)
+	}
+	logger.Info("payroll change deleted with id %d", id)
+	return nil
+}
@@ -0,0 +1,116 @@
+package service_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"salaries/pkg/api"
+	"salaries/pkg/currency"
+	"salaries/pkg/domain"
+	"salaries/pkg/repository"
+	"salaries/pkg/service"
+	"testing"
+	"time"
+)
+
+func TestBudgetService_Projection(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	salaries := []domain.Salary{
+		{ID: 1, Salary: 120000, Currency: "USD", Department: "Engineering"},
+		{ID: 2, Salary: 60000, Currency: "EUR", Department: "Engineering"},
+		{ID: 3, Salary: 50000, Currency: "USD", Department: "Banking"},
+	}
+	salaryRepository := &repository.SalaryRepositoryMock{
+		ReadEachFunc: func(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary) error) error {
+			for i := range salaries {
+				if filter.Matches(&salaries[i]) {
+					if err := each(&salaries[i]); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+	}
+	budgetRepository := &repository.BudgetRepositoryMock{
+		ReadChangesFunc: func(ctx context.Context, filter api.PayrollChangeFilter) ([]domain.PayrollChange, error) {
+			assert.Equal(t, date(2027, 1, 1), filter.To)
+			return []domain.PayrollChange{
+				{Kind: domain.PayrollChangeTermination, SalaryID: 3, EffectiveDate: date(2025, 12, 1)},
+				{Kind: domain.PayrollChangeHire, Department: "Banking", Salary: 72000, Currency: "USD", EffectiveDate: date(2026, 3, 16)},
+				{Kind: domain.PayrollChangeTermination, SalaryID: 2, EffectiveDate: date(2026, 4, 1)},
+				{Kind: domain.PayrollChangeRaise, SalaryID: 1, Percentage: 10, EffectiveDate: date(2026, 7, 1)},
+				{Kind: domain.PayrollChangeRaise, SalaryID: 99, Amount: 1000, EffectiveDate: date(2026, 7, 1)},
+			}, nil
+		},
+		ReadBudgetsFunc: func(ctx context.Context, filter api.BudgetFilter) ([]domain.Budget, error) {
+			assert.Equal(t, 2026, filter.FiscalYear)
+			return []domain.Budget{
+				{Department: "Banking", FiscalYear: 2026, Currency: "EUR", Amount: 50000},
+				{Department: "Engineering", FiscalYear: 2026, Currency: "USD", Amount: 140000},
+			}, nil
+		},
+	}
+	budgetService := service.NewBudgetService(budgetRepository, salaryRepository, currency.Rates{"USD": 1, "EUR": 1.5}, time.January, "USD", service.StatsPrivacy{}, getTestLogger())
+
+	projection, err := budgetService.GetProjection(context.Background(), api.PayrollQuery{FiscalYear: 2026})
+	require.NoError(t, err)
+	assert.Equal(t, "USD", projection.Currency)
+	assert.Equal(t, date(2026, 1, 1), projection.From)
+	require.Len(t, projection.Departments, 2)
+
+	banking := projection.Departments[0]
+	assert.Equal(t, "Banking", banking.Department)
+	assert.Equal(t, "2026-03", banking.Monthly[2].Month)
+	assert.InDelta(t, 0, banking.Monthly[1].Cost, 1e-9, "terminated before the fiscal year")
+	assert.InDelta(t, 6000*16/31.0, banking.Monthly[2].Cost, 1e-9, "hired on the 16th")
+	assert.Equal(t, 1, banking.Monthly[2].Headcount)
+	assert.InDelta(t, 6000*16/31.0+6000*9, banking.Annual, 1e-9)
+
+	engineering := projection.Departments[1]
+	assert.InDelta(t, 10000+7500, engineering.Monthly[0].Cost, 1e-9)
+	assert.Equal(t, 2, engineering.Monthly[2].Headcount)
+	assert.Equal(t, 1, engineering.Monthly[3].Headcount, "terminated on April 1st")
+	assert.InDelta(t, 11000, engineering.Monthly[6].Cost, 1e-9, "raised in July")
+	assert.InDelta(t, 126000+22500, engineering.Annual, 1e-9)
+	assert.InDelta(t, banking.Annual+engineering.Annual, projection.Annual, 1e-9)
+
+	projection, err = budgetService.GetProjection(context.Background(), api.PayrollQuery{FiscalYear: 2026, Department: "Engineering", Currency: "eur"})
+	require.NoError(t, err)
+	require.Len(t, projection.Departments, 1)
+	assert.InDelta(t, (126000+22500)/1.5, projection.Annual, 1e-9)
+
+	report, err := budgetService.GetVariance(context.Background(), api.PayrollQuery{FiscalYear: 2026})
+	require.NoError(t, err)
+	require.Len(t, report.Departments, 2)
+	assert.Equal(t, api.BudgetStatusUnder, report.Departments[0].Status)
+	assert.InDelta(t, 75000, report.Departments[0].Budget, 1e-9, "converted from EUR")
+	assert.Equal(t, api.BudgetStatusOver, report.Departments[1].Status)
+	assert.InDelta(t, -8500, report.Departments[1].Variance, 1e-9)
+	assert.InDelta(t, -8500/140000.0*100, *report.Departments[1].VariancePercent, 1e-9)
+	assert.InDelta(t, report.Budget-report.Projected, report.Variance, 1e-9)
+
+	_, err = budgetService.GetProjection(context.Background(), api.PayrollQuery{FiscalYear: 26})
+	assert.ErrorIs(t, err, api.ErrInvalidQuery)
+	_, err = budgetService.GetProjection(context.Background(), api.PayrollQuery{FiscalYear: 2026, Currency: "GBP"})
+	assert.ErrorIs(t, err, currency.ErrUnknownCurrency)
+
+	budgetService = service.NewBudgetService(budgetRepository, salaryRepository, currency.Rates{"USD": 1, "EUR": 1.5}, time.January, "USD", service.StatsPrivacy{MinGroupSize: 2}, getTestLogger())
+	projection, err = budgetService.GetProjection(context.Background(), api.PayrollQuery{FiscalYear: 2026})
+	require.NoError(t, err)
+	require.Len(t, projection.Departments, 1, "the single person hired in Banking can not be told from the total of Engineering")
+	assert.Equal(t, api.OtherGroup, projection.Departments[0].Department)
+	assert.Equal(t, 3, projection.Departments[0].Monthly[2].Headcount)
+	assert.InDelta(t, projection.Annual, projection.Departments[0].Annual, 1e-9)
+
+	report, err = budgetService.GetVariance(context.Background(), api.PayrollQuery{FiscalYear: 2026})
+	require.NoError(t, err)
+	require.Len(t, report.Departments, 1)
+	assert.InDelta(t, 75000+140000, report.Departments[0].Budget, 1e-9, "the budgets of the merged departments")
+
+	budgetService = service.NewBudgetService(budgetRepository, salaryRepository, currency.Rates{"USD": 1, "EUR": 1.5}, time.January, "USD", service.StatsPrivacy{MinGroupSize: 3}, getTestLogger())
+	_, err = budgetService.GetProjection(context.Background(), api.PayrollQuery{FiscalYear: 2026, Department: "Engineering"})
+	assert.ErrorIs(t, err, api.ErrGroupTooSmall)
+}
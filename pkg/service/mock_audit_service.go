@@ -0,0 +1,82 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package service
+
+import (
+	"context"
+	"salaries/pkg/api"
+	"sync"
+)
+
+// Ensure, that AuditServiceMock does implement AuditService.
+// If this is not the case, regenerate this file with moq.
+var _ AuditService = &AuditServiceMock{}
+
+// AuditServiceMock is a mock implementation of AuditService.
+//
+//	func TestSomethingThatUsesAuditService(t *testing.T) {
+//
+//		// make and configure a mocked AuditService
+//		mockedAuditService := &AuditServiceMock{
+//			FindFunc: func(ctx context.Context, filter api.AuditFilter) (*api.AuditPage, error) {
+//				panic("mock out the Find method")
+//			},
+//		}
+//
+//		// use mockedAuditService in code that requires AuditService
+//		// and then make assertions.
+//
+//	}
+type AuditServiceMock struct {
+	// FindFunc mocks the Find method.
+	FindFunc func(ctx context.Context, filter api.AuditFilter) (*api.AuditPage, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Find holds details about calls to the Find method.
+		Find []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Filter is the filter argument value.
+			Filter api.AuditFilter
+		}
+	}
+	lockFind sync.RWMutex
+}
+
+// Find calls FindFunc.
+func (mock *AuditServiceMock) Find(ctx context.Context, filter api.AuditFilter) (*api.AuditPage, error) {
+	if mock.FindFunc == nil {
+		panic("AuditServiceMock.FindFunc: method is nil but AuditService.Find was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Filter api.AuditFilter
+	}{
+		Ctx:    ctx,
+		Filter: filter,
+	}
+	mock.lockFind.Lock()
+	mock.calls.Find = append(mock.calls.Find, callInfo)
+	mock.lockFind.Unlock()
+	return mock.FindFunc(ctx, filter)
+}
+
+// FindCalls gets all the calls that were made to Find.
+// Check the length with:
+//
+//	len(mockedAuditService.FindCalls())
+func (mock *AuditServiceMock) FindCalls() []struct {
+	Ctx    context.Context
+	Filter api.AuditFilter
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Filter api.AuditFilter
+	}
+	mock.lockFind.RLock()
+	calls = mock.calls.Find
+	mock.lockFind.RUnlock()
+	return calls
+}
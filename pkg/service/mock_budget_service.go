@@ -0,0 +1,533 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package service
+
+import (
+	"context"
+	"salaries/pkg/api"
+	"salaries/pkg/domain"
+	"sync"
+)
+
+// Ensure, that BudgetServiceMock does implement BudgetService.
+// If this is not the case, regenerate this file with moq.
+var _ BudgetService = &BudgetServiceMock{}
+
+// BudgetServiceMock is a mock implementation of BudgetService.
+//
+//	func TestSomethingThatUsesBudgetService(t *testing.T) {
+//
+//		// make and configure a mocked BudgetService
+//		mockedBudgetService := &BudgetServiceMock{
+//			CreateBudgetFunc: func(ctx context.Context, budget *domain.Budget) error {
+//				panic("mock out the CreateBudget method")
+//			},
+//			CreateChangeFunc: func(ctx context.Context, change *domain.PayrollChange) error {
+//				panic("mock out the CreateChange method")
+//			},
+//			DeleteBudgetByIDFunc: func(ctx context.Context, id int64) error {
+//				panic("mock out the DeleteBudgetByID method")
+//			},
+//			DeleteChangeByIDFunc: func(ctx context.Context, id int64) error {
+//				panic("mock out the DeleteChangeByID method")
+//			},
+//			GetBudgetsFunc: func(ctx context.Context, filter api.BudgetFilter) ([]domain.Budget, error) {
+//				panic("mock out the GetBudgets method")
+//			},
+//			GetChangesFunc: func(ctx context.Context, filter api.PayrollChangeFilter) ([]domain.PayrollChange, error) {
+//				panic("mock out the GetChanges method")
+//			},
+//			GetProjectionFunc: func(ctx context.Context, query api.PayrollQuery) (*api.PayrollProjection, error) {
+//				panic("mock out the GetProjection method")
+//			},
+//			GetVarianceFunc: func(ctx context.Context, query api.PayrollQuery) (*api.VarianceReport, error) {
+//				panic("mock out the GetVariance method")
+//			},
+//			UpdateBudgetFunc: func(ctx context.Context, budget *domain.Budget) error {
+//				panic("mock out the UpdateBudget method")
+//			},
+//			UpdateChangeFunc: func(ctx context.Context, change *domain.PayrollChange) error {
+//				panic("mock out the UpdateChange method")
+//			},
+//		}
+//
+//		// use mockedBudgetService in code that requires BudgetService
+//		// and then make assertions.
+//
+//	}
+type BudgetServiceMock struct {
+	// CreateBudgetFunc mocks the CreateBudget method.
+	CreateBudgetFunc func(ctx context.Context, budget *domain.Budget) error
+
+	// CreateChangeFunc mocks the CreateChange method.
+	CreateChangeFunc func(ctx context.Context, change *domain.PayrollChange) error
+
+	// DeleteBudgetByIDFunc mocks the DeleteBudgetByID method.
+	DeleteBudgetByIDFunc func(ctx context.Context, id int64) error
+
+	// DeleteChangeByIDFunc mocks the DeleteChangeByID method.
+	DeleteChangeByIDFunc func(ctx context.Context, id int64) error
+
+	// GetBudgetsFunc mocks the GetBudgets method.
+	GetBudgetsFunc func(ctx context.Context, filter api.BudgetFilter) ([]domain.Budget, error)
+
+	// GetChangesFunc mocks the GetChanges method.
+	GetChangesFunc func(ctx context.Context, filter api.PayrollChangeFilter) ([]domain.PayrollChange, error)
+
+	// GetProjectionFunc mocks the GetProjection method.
+	GetProjectionFunc func(ctx context.Context, query api.PayrollQuery) (*api.PayrollProjection, error)
+
+	// GetVarianceFunc mocks the GetVariance method.
+	GetVarianceFunc func(ctx context.Context, query api.PayrollQuery) (*api.VarianceReport, error)
+
+	// UpdateBudgetFunc mocks the UpdateBudget method.
+	UpdateBudgetFunc func(ctx context.Context, budget *domain.Budget) error
+
+	// UpdateChangeFunc mocks the UpdateChange method.
+	UpdateChangeFunc func(ctx context.Context, change *domain.PayrollChange) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// CreateBudget holds details about calls to the CreateBudget method.
+		CreateBudget []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Budget is the budget argument value.
+			Budget *domain.Budget
+		}
+		// CreateChange holds details about calls to the CreateChange method.
+		CreateChange []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Change is the change argument value.
+			Change *domain.PayrollChange
+		}
+		// DeleteBudgetByID holds details about calls to the DeleteBudgetByID method.
+		DeleteBudgetByID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int64
+		}
+		// DeleteChangeByID holds details about calls to the DeleteChangeByID method.
+		DeleteChangeByID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int64
+		}
+		// GetBudgets holds details about calls to the GetBudgets method.
+		GetBudgets []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Filter is the filter argument value.
+			Filter api.BudgetFilter
+		}
+		// GetChanges holds details about calls to the GetChanges method.
+		GetChanges []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Filter is the filter argument value.
+			Filter api.PayrollChangeFilter
+		}
+		// GetProjection holds details about calls to the GetProjection method.
+		GetProjection []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Query is the query argument value.
+			Query api.PayrollQuery
+		}
+		// GetVariance holds details about calls to the GetVariance method.
+		GetVariance []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Query is the query argument value.
+			Query api.PayrollQuery
+		}
+		// UpdateBudget holds details about calls to the UpdateBudget method.
+		UpdateBudget []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Budget is the budget argument value.
+			Budget *domain.Budget
+		}
+		// UpdateChange holds details about calls to the UpdateChange method.
+		UpdateChange []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Change is the change argument value.
+			Change *domain.PayrollChange
+		}
+	}
+	lockCreateBudget     sync.RWMutex
+	lockCreateChange     sync.RWMutex
+	lockDeleteBudgetByID sync.RWMutex
+	lockDeleteChangeByID sync.RWMutex
+	lockGetBudgets       sync.RWMutex
+	lockGetChanges       sync.RWMutex
+	lockGetProjection    sync.RWMutex
+	lockGetVariance      sync.RWMutex
+	lockUpdateBudget     sync.RWMutex
+	lockUpdateChange     sync.RWMutex
+}
+
+// CreateBudget calls CreateBudgetFunc.
+func (mock *BudgetServiceMock) CreateBudget(ctx context.Context, budget *domain.Budget) error {
+	if mock.CreateBudgetFunc == nil {
+		panic("BudgetServiceMock.CreateBudgetFunc: method is nil but BudgetService.CreateBudget was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Budget *domain.Budget
+	}{
+		Ctx:    ctx,
+		Budget: budget,
+	}
+	mock.lockCreateBudget.Lock()
+	mock.calls.CreateBudget = append(mock.calls.CreateBudget, callInfo)
+	mock.lockCreateBudget.Unlock()
+	return mock.CreateBudgetFunc(ctx, budget)
+}
+
+// CreateBudgetCalls gets all the calls that were made to CreateBudget.
+// Check the length with:
+//
+//	len(mockedBudgetService.CreateBudgetCalls())
+func (mock *BudgetServiceMock) CreateBudgetCalls() []struct {
+	Ctx    context.Context
+	Budget *domain.Budget
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Budget *domain.Budget
+	}
+	mock.lockCreateBudget.RLock()
+	calls = mock.calls.CreateBudget
+	mock.lockCreateBudget.RUnlock()
+	return calls
+}
+
+// CreateChange calls CreateChangeFunc.
+func (mock *BudgetServiceMock) CreateChange(ctx context.Context, change *domain.PayrollChange) error {
+	if mock.CreateChangeFunc == nil {
+		panic("BudgetServiceMock.CreateChangeFunc: method is nil but BudgetService.CreateChange was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Change *domain.PayrollChange
+	}{
+		Ctx:    ctx,
+		Change: change,
+	}
+	mock.lockCreateChange.Lock()
+	mock.calls.CreateChange = append(mock.calls.CreateChange, callInfo)
+	mock.lockCreateChange.Unlock()
+	return mock.CreateChangeFunc(ctx, change)
+}
+
+// CreateChangeCalls gets all the calls that were made to CreateChange.
+// Check the length with:
+//
+//	len(mockedBudgetService.CreateChangeCalls())
+func (mock *BudgetServiceMock) CreateChangeCalls() []struct {
+	Ctx    context.Context
+	Change *domain.PayrollChange
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Change *domain.PayrollChange
+	}
+	mock.lockCreateChange.RLock()
+	calls = mock.calls.CreateChange
+	mock.lockCreateChange.RUnlock()
+	return calls
+}
+
+// DeleteBudgetByID calls DeleteBudgetByIDFunc.
+func (mock *BudgetServiceMock) DeleteBudgetByID(ctx context.Context, id int64) error {
+	if mock.DeleteBudgetByIDFunc == nil {
+		panic("BudgetServiceMock.DeleteBudgetByIDFunc: method is nil but BudgetService.DeleteBudgetByID was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  int64
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockDeleteBudgetByID.Lock()
+	mock.calls.DeleteBudgetByID = append(mock.calls.DeleteBudgetByID, callInfo)
+	mock.lockDeleteBudgetByID.Unlock()
+	return mock.DeleteBudgetByIDFunc(ctx, id)
+}
+
+// DeleteBudgetByIDCalls gets all the calls that were made to DeleteBudgetByID.
+// Check the length with:
+//
+//	len(mockedBudgetService.DeleteBudgetByIDCalls())
+func (mock *BudgetServiceMock) DeleteBudgetByIDCalls() []struct {
+	Ctx context.Context
+	ID  int64
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int64
+	}
+	mock.lockDeleteBudgetByID.RLock()
+	calls = mock.calls.DeleteBudgetByID
+	mock.lockDeleteBudgetByID.RUnlock()
+	return calls
+}
+
+// DeleteChangeByID calls DeleteChangeByIDFunc.
+func (mock *BudgetServiceMock) DeleteChangeByID(ctx context.Context, id int64) error {
+	if mock.DeleteChangeByIDFunc == nil {
+		panic("BudgetServiceMock.DeleteChangeByIDFunc: method is nil but BudgetService.DeleteChangeByID was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  int64
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockDeleteChangeByID.Lock()
+	mock.calls.DeleteChangeByID = append(mock.calls.DeleteChangeByID, callInfo)
+	mock.lockDeleteChangeByID.Unlock()
+	return mock.DeleteChangeByIDFunc(ctx, id)
+}
+
+// DeleteChangeByIDCalls gets all the calls that were made to DeleteChangeByID.
+// Check the length with:
+//
+//	len(mockedBudgetService.DeleteChangeByIDCalls())
+func (mock *BudgetServiceMock) DeleteChangeByIDCalls() []struct {
+	Ctx context.Context
+	ID  int64
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int64
+	}
+	mock.lockDeleteChangeByID.RLock()
+	calls = mock.calls.DeleteChangeByID
+	mock.lockDeleteChangeByID.RUnlock()
+	return calls
+}
+
+// GetBudgets calls GetBudgetsFunc.
+func (mock *BudgetServiceMock) GetBudgets(ctx context.Context, filter api.BudgetFilter) ([]domain.Budget, error) {
+	if mock.GetBudgetsFunc == nil {
+		panic("BudgetServiceMock.GetBudgetsFunc: method is nil but BudgetService.GetBudgets was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Filter api.BudgetFilter
+	}{
+		Ctx:    ctx,
+		Filter: filter,
+	}
+	mock.lockGetBudgets.Lock()
+	mock.calls.GetBudgets = append(mock.calls.GetBudgets, callInfo)
+	mock.lockGetBudgets.Unlock()
+	return mock.GetBudgetsFunc(ctx, filter)
+}
+
+// GetBudgetsCalls gets all the calls that were made to GetBudgets.
+// Check the length with:
+//
+//	len(mockedBudgetService.GetBudgetsCalls())
+func (mock *BudgetServiceMock) GetBudgetsCalls() []struct {
+	Ctx    context.Context
+	Filter api.BudgetFilter
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Filter api.BudgetFilter
+	}
+	mock.lockGetBudgets.RLock()
+	calls = mock.calls.GetBudgets
+	mock.lockGetBudgets.RUnlock()
+	return calls
+}
+
+// GetChanges calls GetChangesFunc.
+func (mock *BudgetServiceMock) GetChanges(ctx context.Context, filter api.PayrollChangeFilter) ([]domain.PayrollChange, error) {
+	if mock.GetChangesFunc == nil {
+		panic("BudgetServiceMock.GetChangesFunc: method is nil but BudgetService.GetChanges was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Filter api.PayrollChangeFilter
+	}{
+		Ctx:    ctx,
+		Filter: filter,
+	}
+	mock.lockGetChanges.Lock()
+	mock.calls.GetChanges = append(mock.calls.GetChanges, callInfo)
+	mock.lockGetChanges.Unlock()
+	return mock.GetChangesFunc(ctx, filter)
+}
+
+// GetChangesCalls gets all the calls that were made to GetChanges.
+// Check the length with:
+//
+//	len(mockedBudgetService.GetChangesCalls())
+func (mock *BudgetServiceMock) GetChangesCalls() []struct {
+	Ctx    context.Context
+	Filter api.PayrollChangeFilter
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Filter api.PayrollChangeFilter
+	}
+	mock.lockGetChanges.RLock()
+	calls = mock.calls.GetChanges
+	mock.lockGetChanges.RUnlock()
+	return calls
+}
+
+// GetProjection calls GetProjectionFunc.
+func (mock *BudgetServiceMock) GetProjection(ctx context.Context, query api.PayrollQuery) (*api.PayrollProjection, error) {
+	if mock.GetProjectionFunc == nil {
+		panic("BudgetServiceMock.GetProjectionFunc: method is nil but BudgetService.GetProjection was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Query api.PayrollQuery
+	}{
+		Ctx:   ctx,
+		Query: query,
+	}
+	mock.lockGetProjection.Lock()
+	mock.calls.GetProjection = append(mock.calls.GetProjection, callInfo)
+	mock.lockGetProjection.Unlock()
+	return mock.GetProjectionFunc(ctx, query)
+}
+
+// GetProjectionCalls gets all the calls that were made to GetProjection.
+// Check the length with:
+//
+//	len(mockedBudgetService.GetProjectionCalls())
+func (mock *BudgetServiceMock) GetProjectionCalls() []struct {
+	Ctx   context.Context
+	Query api.PayrollQuery
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Query api.PayrollQuery
+	}
+	mock.lockGetProjection.RLock()
+	calls = mock.calls.GetProjection
+	mock.lockGetProjection.RUnlock()
+	return calls
+}
+
+// GetVariance calls GetVarianceFunc.
+func (mock *BudgetServiceMock) GetVariance(ctx context.Context, query api.PayrollQuery) (*api.VarianceReport, error) {
+	if mock.GetVarianceFunc == nil {
+		panic("BudgetServiceMock.GetVarianceFunc: method is nil but BudgetService.GetVariance was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Query api.PayrollQuery
+	}{
+		Ctx:   ctx,
+		Query: query,
+	}
+	mock.lockGetVariance.Lock()
+	mock.calls.GetVariance = append(mock.calls.GetVariance, callInfo)
+	mock.lockGetVariance.Unlock()
+	return mock.GetVarianceFunc(ctx, query)
+}
+
+// GetVarianceCalls gets all the calls that were made to GetVariance.
+// Check the length with:
+//
+//	len(mockedBudgetService.GetVarianceCalls())
+func (mock *BudgetServiceMock) GetVarianceCalls() []struct {
+	Ctx   context.Context
+	Query api.PayrollQuery
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Query api.PayrollQuery
+	}
+	mock.lockGetVariance.RLock()
+	calls = mock.calls.GetVariance
+	mock.lockGetVariance.RUnlock()
+	return calls
+}
+
+// UpdateBudget calls UpdateBudgetFunc.
+func (mock *BudgetServiceMock) UpdateBudget(ctx context.Context, budget *domain.Budget) error {
+	if mock.UpdateBudgetFunc == nil {
+		panic("BudgetServiceMock.UpdateBudgetFunc: method is nil but BudgetService.UpdateBudget was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Budget *domain.Budget
+	}{
+		Ctx:    ctx,
+		Budget: budget,
+	}
+	mock.lockUpdateBudget.Lock()
+	mock.calls.UpdateBudget = append(mock.calls.UpdateBudget, callInfo)
+	mock.lockUpdateBudget.Unlock()
+	return mock.UpdateBudgetFunc(ctx, budget)
+}
+
+// UpdateBudgetCalls gets all the calls that were made to UpdateBudget.
+// Check the length with:
+//
+//	len(mockedBudgetService.UpdateBudgetCalls())
+func (mock *BudgetServiceMock) UpdateBudgetCalls() []struct {
+	Ctx    context.Context
+	Budget *domain.Budget
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Budget *domain.Budget
+	}
+	mock.lockUpdateBudget.RLock()
+	calls = mock.calls.UpdateBudget
+	mock.lockUpdateBudget.RUnlock()
+	return calls
+}
+
+// UpdateChange calls UpdateChangeFunc.
+func (mock *BudgetServiceMock) UpdateChange(ctx context.Context, change *domain.PayrollChange) error {
+	if mock.UpdateChangeFunc == nil {
+		panic("BudgetServiceMock.UpdateChangeFunc: method is nil but BudgetService.UpdateChange was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Change *domain.PayrollChange
+	}{
+		Ctx:    ctx,
+		Change: change,
+	}
+	mock.lockUpdateChange.Lock()
+	mock.calls.UpdateChange = append(mock.calls.UpdateChange, callInfo)
+	mock.lockUpdateChange.Unlock()
+	return mock.UpdateChangeFunc(ctx, change)
+}
+
+// UpdateChangeCalls gets all the calls that were made to UpdateChange.
+// Check the length with:
+//
+//	len(mockedBudgetService.UpdateChangeCalls())
+func (mock *BudgetServiceMock) UpdateChangeCalls() []struct {
+	Ctx    context.Context
+	Change *domain.PayrollChange
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Change *domain.PayrollChange
+	}
+	mock.lockUpdateChange.RLock()
+	calls = mock.calls.UpdateChange
+	mock.lockUpdateChange.RUnlock()
+	return calls
+}
@@ -0,0 +1,383 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package service
+
+import (
+	"context"
+	"salaries/pkg/api"
+	"salaries/pkg/domain"
+	"sync"
+)
+
+// Ensure, that PayBandServiceMock does implement PayBandService.
+// If this is not the case, regenerate this file with moq.
+var _ PayBandService = &PayBandServiceMock{}
+
+// PayBandServiceMock is a mock implementation of PayBandService.
+//
+//	func TestSomethingThatUsesPayBandService(t *testing.T) {
+//
+//		// make and configure a mocked PayBandService
+//		mockedPayBandService := &PayBandServiceMock{
+//			CreateFunc: func(ctx context.Context, band *domain.PayBand) error {
+//				panic("mock out the Create method")
+//			},
+//			DeleteByIDFunc: func(ctx context.Context, id int64) error {
+//				panic("mock out the DeleteByID method")
+//			},
+//			GetAllFunc: func(ctx context.Context, filter api.PayBandFilter) ([]domain.PayBand, error) {
+//				panic("mock out the GetAll method")
+//			},
+//			GetByIDFunc: func(ctx context.Context, id int64) (*domain.PayBand, error) {
+//				panic("mock out the GetByID method")
+//			},
+//			GetCompaRatiosFunc: func(ctx context.Context, query api.CompaRatioQuery) (*api.CompaRatioReport, error) {
+//				panic("mock out the GetCompaRatios method")
+//			},
+//			GetOutOfRangeFunc: func(ctx context.Context, query api.CompaRatioQuery) (*api.OutOfRangeReport, error) {
+//				panic("mock out the GetOutOfRange method")
+//			},
+//			UpdateFunc: func(ctx context.Context, band *domain.PayBand) error {
+//				panic("mock out the Update method")
+//			},
+//		}
+//
+//		// use mockedPayBandService in code that requires PayBandService
+//		// and then make assertions.
+//
+//	}
+type PayBandServiceMock struct {
+	// CreateFunc mocks the Create method.
+	CreateFunc func(ctx context.Context, band *domain.PayBand) error
+
+	// DeleteByIDFunc mocks the DeleteByID method.
+	DeleteByIDFunc func(ctx context.Context, id int64) error
+
+	// GetAllFunc mocks the GetAll method.
+	GetAllFunc func(ctx context.Context, filter api.PayBandFilter) ([]domain.PayBand, error)
+
+	// GetByIDFunc mocks the GetByID method.
+	GetByIDFunc func(ctx context.Context, id int64) (*domain.PayBand, error)
+
+	// GetCompaRatiosFunc mocks the GetCompaRatios method.
+	GetCompaRatiosFunc func(ctx context.Context, query api.CompaRatioQuery) (*api.CompaRatioReport, error)
+
+	// GetOutOfRangeFunc mocks the GetOutOfRange method.
+	GetOutOfRangeFunc func(ctx context.Context, query api.CompaRatioQuery) (*api.OutOfRangeReport, error)
+
+	// UpdateFunc mocks the Update method.
+	UpdateFunc func(ctx context.Context, band *domain.PayBand) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Create holds details about calls to the Create method.
+		Create []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Band is the band argument value.
+			Band *domain.PayBand
+		}
+		// DeleteByID holds details about calls to the DeleteByID method.
+		DeleteByID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int64
+		}
+		// GetAll holds details about calls to the GetAll method.
+		GetAll []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Filter is the filter argument value.
+			Filter api.PayBandFilter
+		}
+		// GetByID holds details about calls to the GetByID method.
+		GetByID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int64
+		}
+		// GetCompaRatios holds details about calls to the GetCompaRatios method.
+		GetCompaRatios []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Query is the query argument value.
+			Query api.CompaRatioQuery
+		}
+		// GetOutOfRange holds details about calls to the GetOutOfRange method.
+		GetOutOfRange []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Query is the query argument value.
+			Query api.CompaRatioQuery
+		}
+		// Update holds details about calls to the Update method.
+		Update []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Band is the band argument value.
+			Band *domain.PayBand
+		}
+	}
+	lockCreate         sync.RWMutex
+	lockDeleteByID     sync.RWMutex
+	lockGetAll         sync.RWMutex
+	lockGetByID        sync.RWMutex
+	lockGetCompaRatios sync.RWMutex
+	lockGetOutOfRange  sync.RWMutex
+	lockUpdate         sync.RWMutex
+}
+
+// Create calls CreateFunc.
+func (mock *PayBandServiceMock) Create(ctx context.Context, band *domain.PayBand) error {
+	if mock.CreateFunc == nil {
+		panic("PayBandServiceMock.CreateFunc: method is nil but PayBandService.Create was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Band *domain.PayBand
+	}{
+		Ctx:  ctx,
+		Band: band,
+	}
+	mock.lockCreate.Lock()
+	mock.calls.Create = append(mock.calls.Create, callInfo)
+	mock.lockCreate.Unlock()
+	return mock.CreateFunc(ctx, band)
+}
+
+// CreateCalls gets all the calls that were made to Create.
+// Check the length with:
+//
+//	len(mockedPayBandService.CreateCalls())
+func (mock *PayBandServiceMock) CreateCalls() []struct {
+	Ctx  context.Context
+	Band *domain.PayBand
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Band *domain.PayBand
+	}
+	mock.lockCreate.RLock()
+	calls = mock.calls.Create
+	mock.lockCreate.RUnlock()
+	return calls
+}
+
+// DeleteByID calls DeleteByIDFunc.
+func (mock *PayBandServiceMock) DeleteByID(ctx context.Context, id int64) error {
+	if mock.DeleteByIDFunc == nil {
+		panic("PayBandServiceMock.DeleteByIDFunc: method is nil but PayBandService.DeleteByID was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  int64
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockDeleteByID.Lock()
+	mock.calls.DeleteByID = append(mock.calls.DeleteByID, callInfo)
+	mock.lockDeleteByID.Unlock()
+	return mock.DeleteByIDFunc(ctx, id)
+}
+
+// DeleteByIDCalls gets all the calls that were made to DeleteByID.
+// Check the length with:
+//
+//	len(mockedPayBandService.DeleteByIDCalls())
+func (mock *PayBandServiceMock) DeleteByIDCalls() []struct {
+	Ctx context.Context
+	ID  int64
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int64
+	}
+	mock.lockDeleteByID.RLock()
+	calls = mock.calls.DeleteByID
+	mock.lockDeleteByID.RUnlock()
+	return calls
+}
+
+// GetAll calls GetAllFunc.
+func (mock *PayBandServiceMock) GetAll(ctx context.Context, filter api.PayBandFilter) ([]domain.PayBand, error) {
+	if mock.GetAllFunc == nil {
+		panic("PayBandServiceMock.GetAllFunc: method is nil but PayBandService.GetAll was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Filter api.PayBandFilter
+	}{
+		Ctx:    ctx,
+		Filter: filter,
+	}
+	mock.lockGetAll.Lock()
+	mock.calls.GetAll = append(mock.calls.GetAll, callInfo)
+	mock.lockGetAll.Unlock()
+	return mock.GetAllFunc(ctx, filter)
+}
+
+// GetAllCalls gets all the calls that were made to GetAll.
+// Check the length with:
+//
+//	len(mockedPayBandService.GetAllCalls())
+func (mock *PayBandServiceMock) GetAllCalls() []struct {
+	Ctx    context.Context
+	Filter api.PayBandFilter
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Filter api.PayBandFilter
+	}
+	mock.lockGetAll.RLock()
+	calls = mock.calls.GetAll
+	mock.lockGetAll.RUnlock()
+	return calls
+}
+
+// GetByID calls GetByIDFunc.
+func (mock *PayBandServiceMock) GetByID(ctx context.Context, id int64) (*domain.PayBand, error) {
+	if mock.GetByIDFunc == nil {
+		panic("PayBandServiceMock.GetByIDFunc: method is nil but PayBandService.GetByID was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  int64
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetByID.Lock()
+	mock.calls.GetByID = append(mock.calls.GetByID, callInfo)
+	mock.lockGetByID.Unlock()
+	return mock.GetByIDFunc(ctx, id)
+}
+
+// GetByIDCalls gets all the calls that were made to GetByID.
+// Check the length with:
+//
+//	len(mockedPayBandService.GetByIDCalls())
+func (mock *PayBandServiceMock) GetByIDCalls() []struct {
+	Ctx context.Context
+	ID  int64
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int64
+	}
+	mock.lockGetByID.RLock()
+	calls = mock.calls.GetByID
+	mock.lockGetByID.RUnlock()
+	return calls
+}
+
+// GetCompaRatios calls GetCompaRatiosFunc.
+func (mock *PayBandServiceMock) GetCompaRatios(ctx context.Context, query api.CompaRatioQuery) (*api.CompaRatioReport, error) {
+	if mock.GetCompaRatiosFunc == nil {
+		panic("PayBandServiceMock.GetCompaRatiosFunc: method is nil but PayBandService.GetCompaRatios was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Query api.CompaRatioQuery
+	}{
+		Ctx:   ctx,
+		Query: query,
+	}
+	mock.lockGetCompaRatios.Lock()
+	mock.calls.GetCompaRatios = append(mock.calls.GetCompaRatios, callInfo)
+	mock.lockGetCompaRatios.Unlock()
+	return mock.GetCompaRatiosFunc(ctx, query)
+}
+
+// GetCompaRatiosCalls gets all the calls that were made to GetCompaRatios.
+// Check the length with:
+//
+//	len(mockedPayBandService.GetCompaRatiosCalls())
+func (mock *PayBandServiceMock) GetCompaRatiosCalls() []struct {
+	Ctx   context.Context
+	Query api.CompaRatioQuery
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Query api.CompaRatioQuery
+	}
+	mock.lockGetCompaRatios.RLock()
+	calls = mock.calls.GetCompaRatios
+	mock.lockGetCompaRatios.RUnlock()
+	return calls
+}
+
+// GetOutOfRange calls GetOutOfRangeFunc.
+func (mock *PayBandServiceMock) GetOutOfRange(ctx context.Context, query api.CompaRatioQuery) (*api.OutOfRangeReport, error) {
+	if mock.GetOutOfRangeFunc == nil {
+		panic("PayBandServiceMock.GetOutOfRangeFunc: method is nil but PayBandService.GetOutOfRange was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Query api.CompaRatioQuery
+	}{
+		Ctx:   ctx,
+		Query: query,
+	}
+	mock.lockGetOutOfRange.Lock()
+	mock.calls.GetOutOfRange = append(mock.calls.GetOutOfRange, callInfo)
+	mock.lockGetOutOfRange.Unlock()
+	return mock.GetOutOfRangeFunc(ctx, query)
+}
+
+// GetOutOfRangeCalls gets all the calls that were made to GetOutOfRange.
+// Check the length with:
+//
+//	len(mockedPayBandService.GetOutOfRangeCalls())
+func (mock *PayBandServiceMock) GetOutOfRangeCalls() []struct {
+	Ctx   context.Context
+	Query api.CompaRatioQuery
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Query api.CompaRatioQuery
+	}
+	mock.lockGetOutOfRange.RLock()
+	calls = mock.calls.GetOutOfRange
+	mock.lockGetOutOfRange.RUnlock()
+	return calls
+}
+
+// Update calls UpdateFunc.
+func (mock *PayBandServiceMock) Update(ctx context.Context, band *domain.PayBand) error {
+	if mock.UpdateFunc == nil {
+		panic("PayBandServiceMock.UpdateFunc: method is nil but PayBandService.Update was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Band *domain.PayBand
+	}{
+		Ctx:  ctx,
+		Band: band,
+	}
+	mock.lockUpdate.Lock()
+	mock.calls.Update = append(mock.calls.Update, callInfo)
+	mock.lockUpdate.Unlock()
+	return mock.UpdateFunc(ctx, band)
+}
+
+// UpdateCalls gets all the calls that were made to Update.
+// Check the length with:
+//
+//	len(mockedPayBandService.UpdateCalls())
+func (mock *PayBandServiceMock) UpdateCalls() []struct {
+	Ctx  context.Context
+	Band *domain.PayBand
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Band *domain.PayBand
+	}
+	mock.lockUpdate.RLock()
+	calls = mock.calls.Update
+	mock.lockUpdate.RUnlock()
+	return calls
+}
@@ -0,0 +1,233 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package service
+
+import (
+	"context"
+	"salaries/pkg/api"
+	"salaries/pkg/domain"
+	"sync"
+)
+
+// Ensure, that PayEquityServiceMock does implement PayEquityService.
+// If this is not the case, regenerate this file with moq.
+var _ PayEquityService = &PayEquityServiceMock{}
+
+// PayEquityServiceMock is a mock implementation of PayEquityService.
+//
+//	func TestSomethingThatUsesPayEquityService(t *testing.T) {
+//
+//		// make and configure a mocked PayEquityService
+//		mockedPayEquityService := &PayEquityServiceMock{
+//			DeleteDemographicsFunc: func(ctx context.Context, salaryID int64) error {
+//				panic("mock out the DeleteDemographics method")
+//			},
+//			GetDemographicsFunc: func(ctx context.Context, salaryID int64) (*domain.Demographics, error) {
+//				panic("mock out the GetDemographics method")
+//			},
+//			GetPayEquityFunc: func(ctx context.Context, query api.PayEquityQuery) (*api.PayEquityReport, error) {
+//				panic("mock out the GetPayEquity method")
+//			},
+//			SaveDemographicsFunc: func(ctx context.Context, demographics *domain.Demographics) error {
+//				panic("mock out the SaveDemographics method")
+//			},
+//		}
+//
+//		// use mockedPayEquityService in code that requires PayEquityService
+//		// and then make assertions.
+//
+//	}
+type PayEquityServiceMock struct {
+	// DeleteDemographicsFunc mocks the DeleteDemographics method.
+	DeleteDemographicsFunc func(ctx context.Context, salaryID int64) error
+
+	// GetDemographicsFunc mocks the GetDemographics method.
+	GetDemographicsFunc func(ctx context.Context, salaryID int64) (*domain.Demographics, error)
+
+	// GetPayEquityFunc mocks the GetPayEquity method.
+	GetPayEquityFunc func(ctx context.Context, query api.PayEquityQuery) (*api.PayEquityReport, error)
+
+	// SaveDemographicsFunc mocks the SaveDemographics method.
+	SaveDemographicsFunc func(ctx context.Context, demographics *domain.Demographics) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// DeleteDemographics holds details about calls to the DeleteDemographics method.
+		DeleteDemographics []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// SalaryID is the salaryID argument value.
+			SalaryID int64
+		}
+		// GetDemographics holds details about calls to the GetDemographics method.
+		GetDemographics []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// SalaryID is the salaryID argument value.
+			SalaryID int64
+		}
+		// GetPayEquity holds details about calls to the GetPayEquity method.
+		GetPayEquity []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Query is the query argument value.
+			Query api.PayEquityQuery
+		}
+		// SaveDemographics holds details about calls to the SaveDemographics method.
+		SaveDemographics []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Demographics is the demographics argument value.
+			Demographics *domain.Demographics
+		}
+	}
+	lockDeleteDemographics sync.RWMutex
+	lockGetDemographics    sync.RWMutex
+	lockGetPayEquity       sync.RWMutex
+	lockSaveDemographics   sync.RWMutex
+}
+
+// DeleteDemographics calls DeleteDemographicsFunc.
+func (mock *PayEquityServiceMock) DeleteDemographics(ctx context.Context, salaryID int64) error {
+	if mock.DeleteDemographicsFunc == nil {
+		panic("PayEquityServiceMock.DeleteDemographicsFunc: method is nil but PayEquityService.DeleteDemographics was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		SalaryID int64
+	}{
+		Ctx:      ctx,
+		SalaryID: salaryID,
+	}
+	mock.lockDeleteDemographics.Lock()
+	mock.calls.DeleteDemographics = append(mock.calls.DeleteDemographics, callInfo)
+	mock.lockDeleteDemographics.Unlock()
+	return mock.DeleteDemographicsFunc(ctx, salaryID)
+}
+
+// DeleteDemographicsCalls gets all the calls that were made to DeleteDemographics.
+// Check the length with:
+//
+//	len(mockedPayEquityService.DeleteDemographicsCalls())
+func (mock *PayEquityServiceMock) DeleteDemographicsCalls() []struct {
+	Ctx      context.Context
+	SalaryID int64
+} {
+	var calls []struct {
+		Ctx      context.Context
+		SalaryID int64
+	}
+	mock.lockDeleteDemographics.RLock()
+	calls = mock.calls.DeleteDemographics
+	mock.lockDeleteDemographics.RUnlock()
+	return calls
+}
+
+// GetDemographics calls GetDemographicsFunc.
+func (mock *PayEquityServiceMock) GetDemographics(ctx context.Context, salaryID int64) (*domain.Demographics, error) {
+	if mock.GetDemographicsFunc == nil {
+		panic("PayEquityServiceMock.GetDemographicsFunc: method is nil but PayEquityService.GetDemographics was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		SalaryID int64
+	}{
+		Ctx:      ctx,
+		SalaryID: salaryID,
+	}
+	mock.lockGetDemographics.Lock()
+	mock.calls.GetDemographics = append(mock.calls.GetDemographics, callInfo)
+	mock.lockGetDemographics.Unlock()
+	return mock.GetDemographicsFunc(ctx, salaryID)
+}
+
+// GetDemographicsCalls gets all the calls that were made to GetDemographics.
+// Check the length with:
+//
+//	len(mockedPayEquityService.GetDemographicsCalls())
+func (mock *PayEquityServiceMock) GetDemographicsCalls() []struct {
+	Ctx      context.Context
+	SalaryID int64
+} {
+	var calls []struct {
+		Ctx      context.Context
+		SalaryID int64
+	}
+	mock.lockGetDemographics.RLock()
+	calls = mock.calls.GetDemographics
+	mock.lockGetDemographics.RUnlock()
+	return calls
+}
+
+// GetPayEquity calls GetPayEquityFunc.
+func (mock *PayEquityServiceMock) GetPayEquity(ctx context.Context, query api.PayEquityQuery) (*api.PayEquityReport, error) {
+	if mock.GetPayEquityFunc == nil {
+		panic("PayEquityServiceMock.GetPayEquityFunc: method is nil but PayEquityService.GetPayEquity was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Query api.PayEquityQuery
+	}{
+		Ctx:   ctx,
+		Query: query,
+	}
+	mock.lockGetPayEquity.Lock()
+	mock.calls.GetPayEquity = append(mock.calls.GetPayEquity, callInfo)
+	mock.lockGetPayEquity.Unlock()
+	return mock.GetPayEquityFunc(ctx, query)
+}
+
+// GetPayEquityCalls gets all the calls that were made to GetPayEquity.
+// Check the length with:
+//
+//	len(mockedPayEquityService.GetPayEquityCalls())
+func (mock *PayEquityServiceMock) GetPayEquityCalls() []struct {
+	Ctx   context.Context
+	Query api.PayEquityQuery
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Query api.PayEquityQuery
+	}
+	mock.lockGetPayEquity.RLock()
+	calls = mock.calls.GetPayEquity
+	mock.lockGetPayEquity.RUnlock()
+	return calls
+}
+
+// SaveDemographics calls SaveDemographicsFunc.
+func (mock *PayEquityServiceMock) SaveDemographics(ctx context.Context, demographics *domain.Demographics) error {
+	if mock.SaveDemographicsFunc == nil {
+		panic("PayEquityServiceMock.SaveDemographicsFunc: method is nil but PayEquityService.SaveDemographics was just called")
+	}
+	callInfo := struct {
+		Ctx          context.Context
+		Demographics *domain.Demographics
+	}{
+		Ctx:          ctx,
+		Demographics: demographics,
+	}
+	mock.lockSaveDemographics.Lock()
+	mock.calls.SaveDemographics = append(mock.calls.SaveDemographics, callInfo)
+	mock.lockSaveDemographics.Unlock()
+	return mock.SaveDemographicsFunc(ctx, demographics)
+}
+
+// SaveDemographicsCalls gets all the calls that were made to SaveDemographics.
+// Check the length with:
+//
+//	len(mockedPayEquityService.SaveDemographicsCalls())
+func (mock *PayEquityServiceMock) SaveDemographicsCalls() []struct {
+	Ctx          context.Context
+	Demographics *domain.Demographics
+} {
+	var calls []struct {
+		Ctx          context.Context
+		Demographics *domain.Demographics
+	}
+	mock.lockSaveDemographics.RLock()
+	calls = mock.calls.SaveDemographics
+	mock.lockSaveDemographics.RUnlock()
+	return calls
+}
@@ -4,9 +4,12 @@
 package service
 
 import (
+	"context"
 	"salaries/pkg/api"
 	"salaries/pkg/domain"
+	"salaries/pkg/importer"
 	"sync"
+	"time"
 )
 
 // Ensure, that SalaryServiceMock does implement SalaryService.
@@ -19,27 +22,69 @@ var _ SalaryService = &SalaryServiceMock{}
 //
 //		// make and configure a mocked SalaryService
 //		mockedSalaryService := &SalaryServiceMock{
-//			CreateFunc: func(salary *domain.Salary) error {
+//			CreateFunc: func(ctx context.Context, salary *domain.Salary) ([]api.Anomaly, error) {
 //				panic("mock out the Create method")
 //			},
-//			DeleteByIDFunc: func(id int64) error {
+//			CreateManyFunc: func(ctx context.Context, salaries []domain.Salary, partial bool) (*api.BatchResult, error) {
+//				panic("mock out the CreateMany method")
+//			},
+//			DeleteByIDFunc: func(ctx context.Context, id int64, version int64) error {
 //				panic("mock out the DeleteByID method")
 //			},
-//			GetAllFunc: func() ([]domain.Salary, error) {
+//			DeleteManyFunc: func(ctx context.Context, ids []int64, partial bool) (*api.BatchResult, error) {
+//				panic("mock out the DeleteMany method")
+//			},
+//			DeleteMatchingFunc: func(ctx context.Context, filter api.SalaryFilter, limit int, partial bool) (*api.BatchResult, error) {
+//				panic("mock out the DeleteMatching method")
+//			},
+//			ExportFunc: func(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary) error) error {
+//				panic("mock out the Export method")
+//			},
+//			GetAllFunc: func(ctx context.Context, filter api.SalaryFilter) ([]domain.Salary, error) {
 //				panic("mock out the GetAll method")
 //			},
-//			GetContractsStatsFunc: func() (*api.Stats, error) {
+//			GetAnomaliesFunc: func(ctx context.Context, query api.AnomalyQuery) (*api.AnomalyReport, error) {
+//				panic("mock out the GetAnomalies method")
+//			},
+//			GetByIDFunc: func(ctx context.Context, id int64) (*domain.Salary, error) {
+//				panic("mock out the GetByID method")
+//			},
+//			GetContractsStatsFunc: func(ctx context.Context) (*api.Stats, error) {
 //				panic("mock out the GetContractsStats method")
 //			},
-//			GetDepartmentsStatsFunc: func() ([]api.DepartmentStats, error) {
+//			GetDepartmentsStatsFunc: func(ctx context.Context) ([]api.DepartmentStats, error) {
 //				panic("mock out the GetDepartmentsStats method")
 //			},
-//			GetStatsForAllSalariesFunc: func() (*api.Stats, error) {
+//			GetHistogramFunc: func(ctx context.Context, query api.HistogramQuery) (*api.Histogram, error) {
+//				panic("mock out the GetHistogram method")
+//			},
+//			GetRankFunc: func(ctx context.Context, query api.RankQuery) (*api.PercentileRank, error) {
+//				panic("mock out the GetRank method")
+//			},
+//			GetStatsForAllSalariesFunc: func(ctx context.Context) (*api.Stats, error) {
 //				panic("mock out the GetStatsForAllSalaries method")
 //			},
-//			GetSubDepartmentsStatsFunc: func() ([]api.SubDepartmentStats, error) {
+//			GetSubDepartmentsStatsFunc: func(ctx context.Context) ([]api.SubDepartmentStats, error) {
 //				panic("mock out the GetSubDepartmentsStats method")
 //			},
+//			GetTrashFunc: func(ctx context.Context) ([]domain.DeletedSalary, error) {
+//				panic("mock out the GetTrash method")
+//			},
+//			ImportFunc: func(ctx context.Context, decoder importer.Decoder, dryRun bool) (*api.ImportReport, error) {
+//				panic("mock out the Import method")
+//			},
+//			PurgeFunc: func(ctx context.Context, olderThan time.Duration) (*api.PurgeResult, error) {
+//				panic("mock out the Purge method")
+//			},
+//			RestoreFunc: func(ctx context.Context, id int64) (*domain.Salary, error) {
+//				panic("mock out the Restore method")
+//			},
+//			SimulateFunc: func(ctx context.Context, request api.SimulationRequest) (*api.Simulation, error) {
+//				panic("mock out the Simulate method")
+//			},
+//			UpdateFunc: func(ctx context.Context, salary *domain.Salary, version int64) error {
+//				panic("mock out the Update method")
+//			},
 //		}
 //
 //		// use mockedSalaryService in code that requires SalaryService
@@ -48,77 +93,263 @@ var _ SalaryService = &SalaryServiceMock{}
 //	}
 type SalaryServiceMock struct {
 	// CreateFunc mocks the Create method.
-	CreateFunc func(salary *domain.Salary) error
+	CreateFunc func(ctx context.Context, salary *domain.Salary) ([]api.Anomaly, error)
+
+	// CreateManyFunc mocks the CreateMany method.
+	CreateManyFunc func(ctx context.Context, salaries []domain.Salary, partial bool) (*api.BatchResult, error)
 
 	// DeleteByIDFunc mocks the DeleteByID method.
-	DeleteByIDFunc func(id int64) error
+	DeleteByIDFunc func(ctx context.Context, id int64, version int64) error
+
+	// DeleteManyFunc mocks the DeleteMany method.
+	DeleteManyFunc func(ctx context.Context, ids []int64, partial bool) (*api.BatchResult, error)
+
+	// DeleteMatchingFunc mocks the DeleteMatching method.
+	DeleteMatchingFunc func(ctx context.Context, filter api.SalaryFilter, limit int, partial bool) (*api.BatchResult, error)
+
+	// ExportFunc mocks the Export method.
+	ExportFunc func(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary) error) error
 
 	// GetAllFunc mocks the GetAll method.
-	GetAllFunc func() ([]domain.Salary, error)
+	GetAllFunc func(ctx context.Context, filter api.SalaryFilter) ([]domain.Salary, error)
+
+	// GetAnomaliesFunc mocks the GetAnomalies method.
+	GetAnomaliesFunc func(ctx context.Context, query api.AnomalyQuery) (*api.AnomalyReport, error)
+
+	// GetByIDFunc mocks the GetByID method.
+	GetByIDFunc func(ctx context.Context, id int64) (*domain.Salary, error)
 
 	// GetContractsStatsFunc mocks the GetContractsStats method.
-	GetContractsStatsFunc func() (*api.Stats, error)
+	GetContractsStatsFunc func(ctx context.Context) (*api.Stats, error)
 
 	// GetDepartmentsStatsFunc mocks the GetDepartmentsStats method.
-	GetDepartmentsStatsFunc func() ([]api.DepartmentStats, error)
+	GetDepartmentsStatsFunc func(ctx context.Context) ([]api.DepartmentStats, error)
+
+	// GetHistogramFunc mocks the GetHistogram method.
+	GetHistogramFunc func(ctx context.Context, query api.HistogramQuery) (*api.Histogram, error)
+
+	// GetRankFunc mocks the GetRank method.
+	GetRankFunc func(ctx context.Context, query api.RankQuery) (*api.PercentileRank, error)
 
 	// GetStatsForAllSalariesFunc mocks the GetStatsForAllSalaries method.
-	GetStatsForAllSalariesFunc func() (*api.Stats, error)
+	GetStatsForAllSalariesFunc func(ctx context.Context) (*api.Stats, error)
 
 	// GetSubDepartmentsStatsFunc mocks the GetSubDepartmentsStats method.
-	GetSubDepartmentsStatsFunc func() ([]api.SubDepartmentStats, error)
+	GetSubDepartmentsStatsFunc func(ctx context.Context) ([]api.SubDepartmentStats, error)
+
+	// GetTrashFunc mocks the GetTrash method.
+	GetTrashFunc func(ctx context.Context) ([]domain.DeletedSalary, error)
+
+	// ImportFunc mocks the Import method.
+	ImportFunc func(ctx context.Context, decoder importer.Decoder, dryRun bool) (*api.ImportReport, error)
+
+	// PurgeFunc mocks the Purge method.
+	PurgeFunc func(ctx context.Context, olderThan time.Duration) (*api.PurgeResult, error)
+
+	// RestoreFunc mocks the Restore method.
+	RestoreFunc func(ctx context.Context, id int64) (*domain.Salary, error)
+
+	// SimulateFunc mocks the Simulate method.
+	SimulateFunc func(ctx context.Context, request api.SimulationRequest) (*api.Simulation, error)
+
+	// UpdateFunc mocks the Update method.
+	UpdateFunc func(ctx context.Context, salary *domain.Salary, version int64) error
 
 	// calls tracks calls to the methods.
 	calls struct {
 		// Create holds details about calls to the Create method.
 		Create []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// Salary is the salary argument value.
 			Salary *domain.Salary
 		}
+		// CreateMany holds details about calls to the CreateMany method.
+		CreateMany []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Salaries is the salaries argument value.
+			Salaries []domain.Salary
+			// Partial is the partial argument value.
+			Partial bool
+		}
 		// DeleteByID holds details about calls to the DeleteByID method.
 		DeleteByID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// ID is the id argument value.
 			ID int64
+			// Version is the version argument value.
+			Version int64
+		}
+		// DeleteMany holds details about calls to the DeleteMany method.
+		DeleteMany []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Ids is the ids argument value.
+			Ids []int64
+			// Partial is the partial argument value.
+			Partial bool
+		}
+		// DeleteMatching holds details about calls to the DeleteMatching method.
+		DeleteMatching []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Filter is the filter argument value.
+			Filter api.SalaryFilter
+			// Limit is the limit argument value.
+			Limit int
+			// Partial is the partial argument value.
+			Partial bool
+		}
+		// Export holds details about calls to the Export method.
+		Export []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Filter is the filter argument value.
+			Filter api.SalaryFilter
+			// Each is the each argument value.
+			Each func(salary *domain.Salary) error
 		}
 		// GetAll holds details about calls to the GetAll method.
 		GetAll []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Filter is the filter argument value.
+			Filter api.SalaryFilter
+		}
+		// GetAnomalies holds details about calls to the GetAnomalies method.
+		GetAnomalies []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Query is the query argument value.
+			Query api.AnomalyQuery
+		}
+		// GetByID holds details about calls to the GetByID method.
+		GetByID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int64
 		}
 		// GetContractsStats holds details about calls to the GetContractsStats method.
 		GetContractsStats []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 		}
 		// GetDepartmentsStats holds details about calls to the GetDepartmentsStats method.
 		GetDepartmentsStats []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// GetHistogram holds details about calls to the GetHistogram method.
+		GetHistogram []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Query is the query argument value.
+			Query api.HistogramQuery
+		}
+		// GetRank holds details about calls to the GetRank method.
+		GetRank []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Query is the query argument value.
+			Query api.RankQuery
 		}
 		// GetStatsForAllSalaries holds details about calls to the GetStatsForAllSalaries method.
 		GetStatsForAllSalaries []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 		}
 		// GetSubDepartmentsStats holds details about calls to the GetSubDepartmentsStats method.
 		GetSubDepartmentsStats []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// GetTrash holds details about calls to the GetTrash method.
+		GetTrash []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// Import holds details about calls to the Import method.
+		Import []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Decoder is the decoder argument value.
+			Decoder importer.Decoder
+			// DryRun is the dryRun argument value.
+			DryRun bool
+		}
+		// Purge holds details about calls to the Purge method.
+		Purge []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// OlderThan is the olderThan argument value.
+			OlderThan time.Duration
+		}
+		// Restore holds details about calls to the Restore method.
+		Restore []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int64
+		}
+		// Simulate holds details about calls to the Simulate method.
+		Simulate []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Request is the request argument value.
+			Request api.SimulationRequest
+		}
+		// Update holds details about calls to the Update method.
+		Update []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Salary is the salary argument value.
+			Salary *domain.Salary
+			// Version is the version argument value.
+			Version int64
 		}
 	}
 	lockCreate                 sync.RWMutex
+	lockCreateMany             sync.RWMutex
 	lockDeleteByID             sync.RWMutex
+	lockDeleteMany             sync.RWMutex
+	lockDeleteMatching         sync.RWMutex
+	lockExport                 sync.RWMutex
 	lockGetAll                 sync.RWMutex
+	lockGetAnomalies           sync.RWMutex
+	lockGetByID                sync.RWMutex
 	lockGetContractsStats      sync.RWMutex
 	lockGetDepartmentsStats    sync.RWMutex
+	lockGetHistogram           sync.RWMutex
+	lockGetRank                sync.RWMutex
 	lockGetStatsForAllSalaries sync.RWMutex
 	lockGetSubDepartmentsStats sync.RWMutex
+	lockGetTrash               sync.RWMutex
+	lockImport                 sync.RWMutex
+	lockPurge                  sync.RWMutex
+	lockRestore                sync.RWMutex
+	lockSimulate               sync.RWMutex
+	lockUpdate                 sync.RWMutex
 }
 
 // Create calls CreateFunc.
-func (mock *SalaryServiceMock) Create(salary *domain.Salary) error {
+func (mock *SalaryServiceMock) Create(ctx context.Context, salary *domain.Salary) ([]api.Anomaly, error) {
 	if mock.CreateFunc == nil {
 		panic("SalaryServiceMock.CreateFunc: method is nil but SalaryService.Create was just called")
 	}
 	callInfo := struct {
+		Ctx    context.Context
 		Salary *domain.Salary
 	}{
+		Ctx:    ctx,
 		Salary: salary,
 	}
 	mock.lockCreate.Lock()
 	mock.calls.Create = append(mock.calls.Create, callInfo)
 	mock.lockCreate.Unlock()
-	return mock.CreateFunc(salary)
+	return mock.CreateFunc(ctx, salary)
 }
 
 // CreateCalls gets all the calls that were made to Create.
@@ -126,9 +357,11 @@ func (mock *SalaryServiceMock) Create(salary *domain.Salary) error {
 //
 //	len(mockedSalaryService.CreateCalls())
 func (mock *SalaryServiceMock) CreateCalls() []struct {
+	Ctx    context.Context
 	Salary *domain.Salary
 } {
 	var calls []struct {
+		Ctx    context.Context
 		Salary *domain.Salary
 	}
 	mock.lockCreate.RLock()
@@ -137,20 +370,64 @@ func (mock *SalaryServiceMock) CreateCalls() []struct {
 	return calls
 }
 
+// CreateMany calls CreateManyFunc.
+func (mock *SalaryServiceMock) CreateMany(ctx context.Context, salaries []domain.Salary, partial bool) (*api.BatchResult, error) {
+	if mock.CreateManyFunc == nil {
+		panic("SalaryServiceMock.CreateManyFunc: method is nil but SalaryService.CreateMany was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Salaries []domain.Salary
+		Partial  bool
+	}{
+		Ctx:      ctx,
+		Salaries: salaries,
+		Partial:  partial,
+	}
+	mock.lockCreateMany.Lock()
+	mock.calls.CreateMany = append(mock.calls.CreateMany, callInfo)
+	mock.lockCreateMany.Unlock()
+	return mock.CreateManyFunc(ctx, salaries, partial)
+}
+
+// CreateManyCalls gets all the calls that were made to CreateMany.
+// Check the length with:
+//
+//	len(mockedSalaryService.CreateManyCalls())
+func (mock *SalaryServiceMock) CreateManyCalls() []struct {
+	Ctx      context.Context
+	Salaries []domain.Salary
+	Partial  bool
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Salaries []domain.Salary
+		Partial  bool
+	}
+	mock.lockCreateMany.RLock()
+	calls = mock.calls.CreateMany
+	mock.lockCreateMany.RUnlock()
+	return calls
+}
+
 // DeleteByID calls DeleteByIDFunc.
-func (mock *SalaryServiceMock) DeleteByID(id int64) error {
+func (mock *SalaryServiceMock) DeleteByID(ctx context.Context, id int64, version int64) error {
 	if mock.DeleteByIDFunc == nil {
 		panic("SalaryServiceMock.DeleteByIDFunc: method is nil but SalaryService.DeleteByID was just called")
 	}
 	callInfo := struct {
-		ID int64
+		Ctx     context.Context
+		ID      int64
+		Version int64
 	}{
-		ID: id,
+		Ctx:     ctx,
+		ID:      id,
+		Version: version,
 	}
 	mock.lockDeleteByID.Lock()
 	mock.calls.DeleteByID = append(mock.calls.DeleteByID, callInfo)
 	mock.lockDeleteByID.Unlock()
-	return mock.DeleteByIDFunc(id)
+	return mock.DeleteByIDFunc(ctx, id, version)
 }
 
 // DeleteByIDCalls gets all the calls that were made to DeleteByID.
@@ -158,10 +435,14 @@ func (mock *SalaryServiceMock) DeleteByID(id int64) error {
 //
 //	len(mockedSalaryService.DeleteByIDCalls())
 func (mock *SalaryServiceMock) DeleteByIDCalls() []struct {
-	ID int64
+	Ctx     context.Context
+	ID      int64
+	Version int64
 } {
 	var calls []struct {
-		ID int64
+		Ctx     context.Context
+		ID      int64
+		Version int64
 	}
 	mock.lockDeleteByID.RLock()
 	calls = mock.calls.DeleteByID
@@ -169,17 +450,146 @@ func (mock *SalaryServiceMock) DeleteByIDCalls() []struct {
 	return calls
 }
 
+// DeleteMany calls DeleteManyFunc.
+func (mock *SalaryServiceMock) DeleteMany(ctx context.Context, ids []int64, partial bool) (*api.BatchResult, error) {
+	if mock.DeleteManyFunc == nil {
+		panic("SalaryServiceMock.DeleteManyFunc: method is nil but SalaryService.DeleteMany was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Ids     []int64
+		Partial bool
+	}{
+		Ctx:     ctx,
+		Ids:     ids,
+		Partial: partial,
+	}
+	mock.lockDeleteMany.Lock()
+	mock.calls.DeleteMany = append(mock.calls.DeleteMany, callInfo)
+	mock.lockDeleteMany.Unlock()
+	return mock.DeleteManyFunc(ctx, ids, partial)
+}
+
+// DeleteManyCalls gets all the calls that were made to DeleteMany.
+// Check the length with:
+//
+//	len(mockedSalaryService.DeleteManyCalls())
+func (mock *SalaryServiceMock) DeleteManyCalls() []struct {
+	Ctx     context.Context
+	Ids     []int64
+	Partial bool
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Ids     []int64
+		Partial bool
+	}
+	mock.lockDeleteMany.RLock()
+	calls = mock.calls.DeleteMany
+	mock.lockDeleteMany.RUnlock()
+	return calls
+}
+
+// DeleteMatching calls DeleteMatchingFunc.
+func (mock *SalaryServiceMock) DeleteMatching(ctx context.Context, filter api.SalaryFilter, limit int, partial bool) (*api.BatchResult, error) {
+	if mock.DeleteMatchingFunc == nil {
+		panic("SalaryServiceMock.DeleteMatchingFunc: method is nil but SalaryService.DeleteMatching was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Filter  api.SalaryFilter
+		Limit   int
+		Partial bool
+	}{
+		Ctx:     ctx,
+		Filter:  filter,
+		Limit:   limit,
+		Partial: partial,
+	}
+	mock.lockDeleteMatching.Lock()
+	mock.calls.DeleteMatching = append(mock.calls.DeleteMatching, callInfo)
+	mock.lockDeleteMatching.Unlock()
+	return mock.DeleteMatchingFunc(ctx, filter, limit, partial)
+}
+
+// DeleteMatchingCalls gets all the calls that were made to DeleteMatching.
+// Check the length with:
+//
+//	len(mockedSalaryService.DeleteMatchingCalls())
+func (mock *SalaryServiceMock) DeleteMatchingCalls() []struct {
+	Ctx     context.Context
+	Filter  api.SalaryFilter
+	Limit   int
+	Partial bool
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Filter  api.SalaryFilter
+		Limit   int
+		Partial bool
+	}
+	mock.lockDeleteMatching.RLock()
+	calls = mock.calls.DeleteMatching
+	mock.lockDeleteMatching.RUnlock()
+	return calls
+}
+
+// Export calls ExportFunc.
+func (mock *SalaryServiceMock) Export(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary) error) error {
+	if mock.ExportFunc == nil {
+		panic("SalaryServiceMock.ExportFunc: method is nil but SalaryService.Export was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Filter api.SalaryFilter
+		Each   func(salary *domain.Salary) error
+	}{
+		Ctx:    ctx,
+		Filter: filter,
+		Each:   each,
+	}
+	mock.lockExport.Lock()
+	mock.calls.Export = append(mock.calls.Export, callInfo)
+	mock.lockExport.Unlock()
+	return mock.ExportFunc(ctx, filter, each)
+}
+
+// ExportCalls gets all the calls that were made to Export.
+// Check the length with:
+//
+//	len(mockedSalaryService.ExportCalls())
+func (mock *SalaryServiceMock) ExportCalls() []struct {
+	Ctx    context.Context
+	Filter api.SalaryFilter
+	Each   func(salary *domain.Salary) error
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Filter api.SalaryFilter
+		Each   func(salary *domain.Salary) error
+	}
+	mock.lockExport.RLock()
+	calls = mock.calls.Export
+	mock.lockExport.RUnlock()
+	return calls
+}
+
 // GetAll calls GetAllFunc.
-func (mock *SalaryServiceMock) GetAll() ([]domain.Salary, error) {
+func (mock *SalaryServiceMock) GetAll(ctx context.Context, filter api.SalaryFilter) ([]domain.Salary, error) {
 	if mock.GetAllFunc == nil {
 		panic("SalaryServiceMock.GetAllFunc: method is nil but SalaryService.GetAll was just called")
 	}
 	callInfo := struct {
-	}{}
+		Ctx    context.Context
+		Filter api.SalaryFilter
+	}{
+		Ctx:    ctx,
+		Filter: filter,
+	}
 	mock.lockGetAll.Lock()
 	mock.calls.GetAll = append(mock.calls.GetAll, callInfo)
 	mock.lockGetAll.Unlock()
-	return mock.GetAllFunc()
+	return mock.GetAllFunc(ctx, filter)
 }
 
 // GetAllCalls gets all the calls that were made to GetAll.
@@ -187,8 +597,12 @@ func (mock *SalaryServiceMock) GetAll() ([]domain.Salary, error) {
 //
 //	len(mockedSalaryService.GetAllCalls())
 func (mock *SalaryServiceMock) GetAllCalls() []struct {
+	Ctx    context.Context
+	Filter api.SalaryFilter
 } {
 	var calls []struct {
+		Ctx    context.Context
+		Filter api.SalaryFilter
 	}
 	mock.lockGetAll.RLock()
 	calls = mock.calls.GetAll
@@ -196,17 +610,92 @@ func (mock *SalaryServiceMock) GetAllCalls() []struct {
 	return calls
 }
 
+// GetAnomalies calls GetAnomaliesFunc.
+func (mock *SalaryServiceMock) GetAnomalies(ctx context.Context, query api.AnomalyQuery) (*api.AnomalyReport, error) {
+	if mock.GetAnomaliesFunc == nil {
+		panic("SalaryServiceMock.GetAnomaliesFunc: method is nil but SalaryService.GetAnomalies was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Query api.AnomalyQuery
+	}{
+		Ctx:   ctx,
+		Query: query,
+	}
+	mock.lockGetAnomalies.Lock()
+	mock.calls.GetAnomalies = append(mock.calls.GetAnomalies, callInfo)
+	mock.lockGetAnomalies.Unlock()
+	return mock.GetAnomaliesFunc(ctx, query)
+}
+
+// GetAnomaliesCalls gets all the calls that were made to GetAnomalies.
+// Check the length with:
+//
+//	len(mockedSalaryService.GetAnomaliesCalls())
+func (mock *SalaryServiceMock) GetAnomaliesCalls() []struct {
+	Ctx   context.Context
+	Query api.AnomalyQuery
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Query api.AnomalyQuery
+	}
+	mock.lockGetAnomalies.RLock()
+	calls = mock.calls.GetAnomalies
+	mock.lockGetAnomalies.RUnlock()
+	return calls
+}
+
+// GetByID calls GetByIDFunc.
+func (mock *SalaryServiceMock) GetByID(ctx context.Context, id int64) (*domain.Salary, error) {
+	if mock.GetByIDFunc == nil {
+		panic("SalaryServiceMock.GetByIDFunc: method is nil but SalaryService.GetByID was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  int64
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetByID.Lock()
+	mock.calls.GetByID = append(mock.calls.GetByID, callInfo)
+	mock.lockGetByID.Unlock()
+	return mock.GetByIDFunc(ctx, id)
+}
+
+// GetByIDCalls gets all the calls that were made to GetByID.
+// Check the length with:
+//
+//	len(mockedSalaryService.GetByIDCalls())
+func (mock *SalaryServiceMock) GetByIDCalls() []struct {
+	Ctx context.Context
+	ID  int64
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int64
+	}
+	mock.lockGetByID.RLock()
+	calls = mock.calls.GetByID
+	mock.lockGetByID.RUnlock()
+	return calls
+}
+
 // GetContractsStats calls GetContractsStatsFunc.
-func (mock *SalaryServiceMock) GetContractsStats() (*api.Stats, error) {
+func (mock *SalaryServiceMock) GetContractsStats(ctx context.Context) (*api.Stats, error) {
 	if mock.GetContractsStatsFunc == nil {
 		panic("SalaryServiceMock.GetContractsStatsFunc: method is nil but SalaryService.GetContractsStats was just called")
 	}
 	callInfo := struct {
-	}{}
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
 	mock.lockGetContractsStats.Lock()
 	mock.calls.GetContractsStats = append(mock.calls.GetContractsStats, callInfo)
 	mock.lockGetContractsStats.Unlock()
-	return mock.GetContractsStatsFunc()
+	return mock.GetContractsStatsFunc(ctx)
 }
 
 // GetContractsStatsCalls gets all the calls that were made to GetContractsStats.
@@ -214,8 +703,10 @@ func (mock *SalaryServiceMock) GetContractsStats() (*api.Stats, error) {
 //
 //	len(mockedSalaryService.GetContractsStatsCalls())
 func (mock *SalaryServiceMock) GetContractsStatsCalls() []struct {
+	Ctx context.Context
 } {
 	var calls []struct {
+		Ctx context.Context
 	}
 	mock.lockGetContractsStats.RLock()
 	calls = mock.calls.GetContractsStats
@@ -224,16 +715,19 @@ func (mock *SalaryServiceMock) GetContractsStatsCalls() []struct {
 }
 
 // GetDepartmentsStats calls GetDepartmentsStatsFunc.
-func (mock *SalaryServiceMock) GetDepartmentsStats() ([]api.DepartmentStats, error) {
+func (mock *SalaryServiceMock) GetDepartmentsStats(ctx context.Context) ([]api.DepartmentStats, error) {
 	if mock.GetDepartmentsStatsFunc == nil {
 		panic("SalaryServiceMock.GetDepartmentsStatsFunc: method is nil but SalaryService.GetDepartmentsStats was just called")
 	}
 	callInfo := struct {
-	}{}
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
 	mock.lockGetDepartmentsStats.Lock()
 	mock.calls.GetDepartmentsStats = append(mock.calls.GetDepartmentsStats, callInfo)
 	mock.lockGetDepartmentsStats.Unlock()
-	return mock.GetDepartmentsStatsFunc()
+	return mock.GetDepartmentsStatsFunc(ctx)
 }
 
 // GetDepartmentsStatsCalls gets all the calls that were made to GetDepartmentsStats.
@@ -241,8 +735,10 @@ func (mock *SalaryServiceMock) GetDepartmentsStats() ([]api.DepartmentStats, err
 //
 //	len(mockedSalaryService.GetDepartmentsStatsCalls())
 func (mock *SalaryServiceMock) GetDepartmentsStatsCalls() []struct {
+	Ctx context.Context
 } {
 	var calls []struct {
+		Ctx context.Context
 	}
 	mock.lockGetDepartmentsStats.RLock()
 	calls = mock.calls.GetDepartmentsStats
@@ -250,17 +746,92 @@ func (mock *SalaryServiceMock) GetDepartmentsStatsCalls() []struct {
 	return calls
 }
 
+// GetHistogram calls GetHistogramFunc.
+func (mock *SalaryServiceMock) GetHistogram(ctx context.Context, query api.HistogramQuery) (*api.Histogram, error) {
+	if mock.GetHistogramFunc == nil {
+		panic("SalaryServiceMock.GetHistogramFunc: method is nil but SalaryService.GetHistogram was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Query api.HistogramQuery
+	}{
+		Ctx:   ctx,
+		Query: query,
+	}
+	mock.lockGetHistogram.Lock()
+	mock.calls.GetHistogram = append(mock.calls.GetHistogram, callInfo)
+	mock.lockGetHistogram.Unlock()
+	return mock.GetHistogramFunc(ctx, query)
+}
+
+// GetHistogramCalls gets all the calls that were made to GetHistogram.
+// Check the length with:
+//
+//	len(mockedSalaryService.GetHistogramCalls())
+func (mock *SalaryServiceMock) GetHistogramCalls() []struct {
+	Ctx   context.Context
+	Query api.HistogramQuery
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Query api.HistogramQuery
+	}
+	mock.lockGetHistogram.RLock()
+	calls = mock.calls.GetHistogram
+	mock.lockGetHistogram.RUnlock()
+	return calls
+}
+
+// GetRank calls GetRankFunc.
+func (mock *SalaryServiceMock) GetRank(ctx context.Context, query api.RankQuery) (*api.PercentileRank, error) {
+	if mock.GetRankFunc == nil {
+		panic("SalaryServiceMock.GetRankFunc: method is nil but SalaryService.GetRank was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Query api.RankQuery
+	}{
+		Ctx:   ctx,
+		Query: query,
+	}
+	mock.lockGetRank.Lock()
+	mock.calls.GetRank = append(mock.calls.GetRank, callInfo)
+	mock.lockGetRank.Unlock()
+	return mock.GetRankFunc(ctx, query)
+}
+
+// GetRankCalls gets all the calls that were made to GetRank.
+// Check the length with:
+//
+//	len(mockedSalaryService.GetRankCalls())
+func (mock *SalaryServiceMock) GetRankCalls() []struct {
+	Ctx   context.Context
+	Query api.RankQuery
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Query api.RankQuery
+	}
+	mock.lockGetRank.RLock()
+	calls = mock.calls.GetRank
+	mock.lockGetRank.RUnlock()
+	return calls
+}
+
 // GetStatsForAllSalaries calls GetStatsForAllSalariesFunc.
-func (mock *SalaryServiceMock) GetStatsForAllSalaries() (*api.Stats, error) {
+func (mock *SalaryServiceMock) GetStatsForAllSalaries(ctx context.Context) (*api.Stats, error) {
 	if mock.GetStatsForAllSalariesFunc == nil {
 		panic("SalaryServiceMock.GetStatsForAllSalariesFunc: method is nil but SalaryService.GetStatsForAllSalaries was just called")
 	}
 	callInfo := struct {
-	}{}
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
 	mock.lockGetStatsForAllSalaries.Lock()
 	mock.calls.GetStatsForAllSalaries = append(mock.calls.GetStatsForAllSalaries, callInfo)
 	mock.lockGetStatsForAllSalaries.Unlock()
-	return mock.GetStatsForAllSalariesFunc()
+	return mock.GetStatsForAllSalariesFunc(ctx)
 }
 
 // GetStatsForAllSalariesCalls gets all the calls that were made to GetStatsForAllSalaries.
@@ -268,8 +839,10 @@ func (mock *SalaryServiceMock) GetStatsForAllSalaries() (*api.Stats, error) {
 //
 //	len(mockedSalaryService.GetStatsForAllSalariesCalls())
 func (mock *SalaryServiceMock) GetStatsForAllSalariesCalls() []struct {
+	Ctx context.Context
 } {
 	var calls []struct {
+		Ctx context.Context
 	}
 	mock.lockGetStatsForAllSalaries.RLock()
 	calls = mock.calls.GetStatsForAllSalaries
@@ -278,16 +851,19 @@ func (mock *SalaryServiceMock) GetStatsForAllSalariesCalls() []struct {
 }
 
 // GetSubDepartmentsStats calls GetSubDepartmentsStatsFunc.
-func (mock *SalaryServiceMock) GetSubDepartmentsStats() ([]api.SubDepartmentStats, error) {
+func (mock *SalaryServiceMock) GetSubDepartmentsStats(ctx context.Context) ([]api.SubDepartmentStats, error) {
 	if mock.GetSubDepartmentsStatsFunc == nil {
 		panic("SalaryServiceMock.GetSubDepartmentsStatsFunc: method is nil but SalaryService.GetSubDepartmentsStats was just called")
 	}
 	callInfo := struct {
-	}{}
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
 	mock.lockGetSubDepartmentsStats.Lock()
 	mock.calls.GetSubDepartmentsStats = append(mock.calls.GetSubDepartmentsStats, callInfo)
 	mock.lockGetSubDepartmentsStats.Unlock()
-	return mock.GetSubDepartmentsStatsFunc()
+	return mock.GetSubDepartmentsStatsFunc(ctx)
 }
 
 // GetSubDepartmentsStatsCalls gets all the calls that were made to GetSubDepartmentsStats.
@@ -295,11 +871,233 @@ func (mock *SalaryServiceMock) GetSubDepartmentsStats() ([]api.SubDepartmentStat
 //
 //	len(mockedSalaryService.GetSubDepartmentsStatsCalls())
 func (mock *SalaryServiceMock) GetSubDepartmentsStatsCalls() []struct {
+	Ctx context.Context
 } {
 	var calls []struct {
+		Ctx context.Context
 	}
 	mock.lockGetSubDepartmentsStats.RLock()
 	calls = mock.calls.GetSubDepartmentsStats
 	mock.lockGetSubDepartmentsStats.RUnlock()
 	return calls
 }
+
+// GetTrash calls GetTrashFunc.
+func (mock *SalaryServiceMock) GetTrash(ctx context.Context) ([]domain.DeletedSalary, error) {
+	if mock.GetTrashFunc == nil {
+		panic("SalaryServiceMock.GetTrashFunc: method is nil but SalaryService.GetTrash was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockGetTrash.Lock()
+	mock.calls.GetTrash = append(mock.calls.GetTrash, callInfo)
+	mock.lockGetTrash.Unlock()
+	return mock.GetTrashFunc(ctx)
+}
+
+// GetTrashCalls gets all the calls that were made to GetTrash.
+// Check the length with:
+//
+//	len(mockedSalaryService.GetTrashCalls())
+func (mock *SalaryServiceMock) GetTrashCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockGetTrash.RLock()
+	calls = mock.calls.GetTrash
+	mock.lockGetTrash.RUnlock()
+	return calls
+}
+
+// Import calls ImportFunc.
+func (mock *SalaryServiceMock) Import(ctx context.Context, decoder importer.Decoder, dryRun bool) (*api.ImportReport, error) {
+	if mock.ImportFunc == nil {
+		panic("SalaryServiceMock.ImportFunc: method is nil but SalaryService.Import was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Decoder importer.Decoder
+		DryRun  bool
+	}{
+		Ctx:     ctx,
+		Decoder: decoder,
+		DryRun:  dryRun,
+	}
+	mock.lockImport.Lock()
+	mock.calls.Import = append(mock.calls.Import, callInfo)
+	mock.lockImport.Unlock()
+	return mock.ImportFunc(ctx, decoder, dryRun)
+}
+
+// ImportCalls gets all the calls that were made to Import.
+// Check the length with:
+//
+//	len(mockedSalaryService.ImportCalls())
+func (mock *SalaryServiceMock) ImportCalls() []struct {
+	Ctx     context.Context
+	Decoder importer.Decoder
+	DryRun  bool
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Decoder importer.Decoder
+		DryRun  bool
+	}
+	mock.lockImport.RLock()
+	calls = mock.calls.Import
+	mock.lockImport.RUnlock()
+	return calls
+}
+
+// Purge calls PurgeFunc.
+func (mock *SalaryServiceMock) Purge(ctx context.Context, olderThan time.Duration) (*api.PurgeResult, error) {
+	if mock.PurgeFunc == nil {
+		panic("SalaryServiceMock.PurgeFunc: method is nil but SalaryService.Purge was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		OlderThan time.Duration
+	}{
+		Ctx:       ctx,
+		OlderThan: olderThan,
+	}
+	mock.lockPurge.Lock()
+	mock.calls.Purge = append(mock.calls.Purge, callInfo)
+	mock.lockPurge.Unlock()
+	return mock.PurgeFunc(ctx, olderThan)
+}
+
+// PurgeCalls gets all the calls that were made to Purge.
+// Check the length with:
+//
+//	len(mockedSalaryService.PurgeCalls())
+func (mock *SalaryServiceMock) PurgeCalls() []struct {
+	Ctx       context.Context
+	OlderThan time.Duration
+} {
+	var calls []struct {
+		Ctx       context.Context
+		OlderThan time.Duration
+	}
+	mock.lockPurge.RLock()
+	calls = mock.calls.Purge
+	mock.lockPurge.RUnlock()
+	return calls
+}
+
+// Restore calls RestoreFunc.
+func (mock *SalaryServiceMock) Restore(ctx context.Context, id int64) (*domain.Salary, error) {
+	if mock.RestoreFunc == nil {
+		panic("SalaryServiceMock.RestoreFunc: method is nil but SalaryService.Restore was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  int64
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockRestore.Lock()
+	mock.calls.Restore = append(mock.calls.Restore, callInfo)
+	mock.lockRestore.Unlock()
+	return mock.RestoreFunc(ctx, id)
+}
+
+// RestoreCalls gets all the calls that were made to Restore.
+// Check the length with:
+//
+//	len(mockedSalaryService.RestoreCalls())
+func (mock *SalaryServiceMock) RestoreCalls() []struct {
+	Ctx context.Context
+	ID  int64
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int64
+	}
+	mock.lockRestore.RLock()
+	calls = mock.calls.Restore
+	mock.lockRestore.RUnlock()
+	return calls
+}
+
+// Simulate calls SimulateFunc.
+func (mock *SalaryServiceMock) Simulate(ctx context.Context, request api.SimulationRequest) (*api.Simulation, error) {
+	if mock.SimulateFunc == nil {
+		panic("SalaryServiceMock.SimulateFunc: method is nil but SalaryService.Simulate was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Request api.SimulationRequest
+	}{
+		Ctx:     ctx,
+		Request: request,
+	}
+	mock.lockSimulate.Lock()
+	mock.calls.Simulate = append(mock.calls.Simulate, callInfo)
+	mock.lockSimulate.Unlock()
+	return mock.SimulateFunc(ctx, request)
+}
+
+// SimulateCalls gets all the calls that were made to Simulate.
+// Check the length with:
+//
+//	len(mockedSalaryService.SimulateCalls())
+func (mock *SalaryServiceMock) SimulateCalls() []struct {
+	Ctx     context.Context
+	Request api.SimulationRequest
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Request api.SimulationRequest
+	}
+	mock.lockSimulate.RLock()
+	calls = mock.calls.Simulate
+	mock.lockSimulate.RUnlock()
+	return calls
+}
+
+// Update calls UpdateFunc.
+func (mock *SalaryServiceMock) Update(ctx context.Context, salary *domain.Salary, version int64) error {
+	if mock.UpdateFunc == nil {
+		panic("SalaryServiceMock.UpdateFunc: method is nil but SalaryService.Update was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Salary  *domain.Salary
+		Version int64
+	}{
+		Ctx:     ctx,
+		Salary:  salary,
+		Version: version,
+	}
+	mock.lockUpdate.Lock()
+	mock.calls.Update = append(mock.calls.Update, callInfo)
+	mock.lockUpdate.Unlock()
+	return mock.UpdateFunc(ctx, salary, version)
+}
+
+// UpdateCalls gets all the calls that were made to Update.
+// Check the length with:
+//
+//	len(mockedSalaryService.UpdateCalls())
+func (mock *SalaryServiceMock) UpdateCalls() []struct {
+	Ctx     context.Context
+	Salary  *domain.Salary
+	Version int64
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Salary  *domain.Salary
+		Version int64
+	}
+	mock.lockUpdate.RLock()
+	calls = mock.calls.Update
+	mock.lockUpdate.RUnlock()
+	return calls
+}
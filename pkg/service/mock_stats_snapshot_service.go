@@ -0,0 +1,334 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package service
+
+import (
+	"context"
+	"salaries/pkg/api"
+	"salaries/pkg/domain"
+	"sync"
+	"time"
+)
+
+// Ensure, that SnapshotServiceMock does implement SnapshotService.
+// If this is not the case, regenerate this file with moq.
+var _ SnapshotService = &SnapshotServiceMock{}
+
+// SnapshotServiceMock is a mock implementation of SnapshotService.
+//
+//	func TestSomethingThatUsesSnapshotService(t *testing.T) {
+//
+//		// make and configure a mocked SnapshotService
+//		mockedSnapshotService := &SnapshotServiceMock{
+//			GetDiffFunc: func(ctx context.Context, query api.SnapshotDiffQuery) (*api.SnapshotDiff, error) {
+//				panic("mock out the GetDiff method")
+//			},
+//			GetHistoryFunc: func(ctx context.Context, query api.StatsHistoryQuery) (*api.StatsHistory, error) {
+//				panic("mock out the GetHistory method")
+//			},
+//			GetSnapshotFunc: func(ctx context.Context, id int64) (*domain.StatsSnapshot, error) {
+//				panic("mock out the GetSnapshot method")
+//			},
+//			GetSnapshotsFunc: func(ctx context.Context, filter api.SnapshotFilter) ([]domain.StatsSnapshot, error) {
+//				panic("mock out the GetSnapshots method")
+//			},
+//			ScheduleFunc: func(ctx context.Context, interval time.Duration)  {
+//				panic("mock out the Schedule method")
+//			},
+//			TakeSnapshotFunc: func(ctx context.Context, source string) (*domain.StatsSnapshot, error) {
+//				panic("mock out the TakeSnapshot method")
+//			},
+//		}
+//
+//		// use mockedSnapshotService in code that requires SnapshotService
+//		// and then make assertions.
+//
+//	}
+type SnapshotServiceMock struct {
+	// GetDiffFunc mocks the GetDiff method.
+	GetDiffFunc func(ctx context.Context, query api.SnapshotDiffQuery) (*api.SnapshotDiff, error)
+
+	// GetHistoryFunc mocks the GetHistory method.
+	GetHistoryFunc func(ctx context.Context, query api.StatsHistoryQuery) (*api.StatsHistory, error)
+
+	// GetSnapshotFunc mocks the GetSnapshot method.
+	GetSnapshotFunc func(ctx context.Context, id int64) (*domain.StatsSnapshot, error)
+
+	// GetSnapshotsFunc mocks the GetSnapshots method.
+	GetSnapshotsFunc func(ctx context.Context, filter api.SnapshotFilter) ([]domain.StatsSnapshot, error)
+
+	// ScheduleFunc mocks the Schedule method.
+	ScheduleFunc func(ctx context.Context, interval time.Duration)
+
+	// TakeSnapshotFunc mocks the TakeSnapshot method.
+	TakeSnapshotFunc func(ctx context.Context, source string) (*domain.StatsSnapshot, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// GetDiff holds details about calls to the GetDiff method.
+		GetDiff []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Query is the query argument value.
+			Query api.SnapshotDiffQuery
+		}
+		// GetHistory holds details about calls to the GetHistory method.
+		GetHistory []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Query is the query argument value.
+			Query api.StatsHistoryQuery
+		}
+		// GetSnapshot holds details about calls to the GetSnapshot method.
+		GetSnapshot []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int64
+		}
+		// GetSnapshots holds details about calls to the GetSnapshots method.
+		GetSnapshots []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Filter is the filter argument value.
+			Filter api.SnapshotFilter
+		}
+		// Schedule holds details about calls to the Schedule method.
+		Schedule []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Interval is the interval argument value.
+			Interval time.Duration
+		}
+		// TakeSnapshot holds details about calls to the TakeSnapshot method.
+		TakeSnapshot []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Source is the source argument value.
+			Source string
+		}
+	}
+	lockGetDiff      sync.RWMutex
+	lockGetHistory   sync.RWMutex
+	lockGetSnapshot  sync.RWMutex
+	lockGetSnapshots sync.RWMutex
+	lockSchedule     sync.RWMutex
+	lockTakeSnapshot sync.RWMutex
+}
+
+// GetDiff calls GetDiffFunc.
+func (mock *SnapshotServiceMock) GetDiff(ctx context.Context, query api.SnapshotDiffQuery) (*api.SnapshotDiff, error) {
+	if mock.GetDiffFunc == nil {
+		panic("SnapshotServiceMock.GetDiffFunc: method is nil but SnapshotService.GetDiff was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Query api.SnapshotDiffQuery
+	}{
+		Ctx:   ctx,
+		Query: query,
+	}
+	mock.lockGetDiff.Lock()
+	mock.calls.GetDiff = append(mock.calls.GetDiff, callInfo)
+	mock.lockGetDiff.Unlock()
+	return mock.GetDiffFunc(ctx, query)
+}
+
+// GetDiffCalls gets all the calls that were made to GetDiff.
+// Check the length with:
+//
+//	len(mockedSnapshotService.GetDiffCalls())
+func (mock *SnapshotServiceMock) GetDiffCalls() []struct {
+	Ctx   context.Context
+	Query api.SnapshotDiffQuery
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Query api.SnapshotDiffQuery
+	}
+	mock.lockGetDiff.RLock()
+	calls = mock.calls.GetDiff
+	mock.lockGetDiff.RUnlock()
+	return calls
+}
+
+// GetHistory calls GetHistoryFunc.
+func (mock *SnapshotServiceMock) GetHistory(ctx context.Context, query api.StatsHistoryQuery) (*api.StatsHistory, error) {
+	if mock.GetHistoryFunc == nil {
+		panic("SnapshotServiceMock.GetHistoryFunc: method is nil but SnapshotService.GetHistory was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Query api.StatsHistoryQuery
+	}{
+		Ctx:   ctx,
+		Query: query,
+	}
+	mock.lockGetHistory.Lock()
+	mock.calls.GetHistory = append(mock.calls.GetHistory, callInfo)
+	mock.lockGetHistory.Unlock()
+	return mock.GetHistoryFunc(ctx, query)
+}
+
+// GetHistoryCalls gets all the calls that were made to GetHistory.
+// Check the length with:
+//
+//	len(mockedSnapshotService.GetHistoryCalls())
+func (mock *SnapshotServiceMock) GetHistoryCalls() []struct {
+	Ctx   context.Context
+	Query api.StatsHistoryQuery
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Query api.StatsHistoryQuery
+	}
+	mock.lockGetHistory.RLock()
+	calls = mock.calls.GetHistory
+	mock.lockGetHistory.RUnlock()
+	return calls
+}
+
+// GetSnapshot calls GetSnapshotFunc.
+func (mock *SnapshotServiceMock) GetSnapshot(ctx context.Context, id int64) (*domain.StatsSnapshot, error) {
+	if mock.GetSnapshotFunc == nil {
+		panic("SnapshotServiceMock.GetSnapshotFunc: method is nil but SnapshotService.GetSnapshot was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  int64
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetSnapshot.Lock()
+	mock.calls.GetSnapshot = append(mock.calls.GetSnapshot, callInfo)
+	mock.lockGetSnapshot.Unlock()
+	return mock.GetSnapshotFunc(ctx, id)
+}
+
+// GetSnapshotCalls gets all the calls that were made to GetSnapshot.
+// Check the length with:
+//
+//	len(mockedSnapshotService.GetSnapshotCalls())
+func (mock *SnapshotServiceMock) GetSnapshotCalls() []struct {
+	Ctx context.Context
+	ID  int64
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int64
+	}
+	mock.lockGetSnapshot.RLock()
+	calls = mock.calls.GetSnapshot
+	mock.lockGetSnapshot.RUnlock()
+	return calls
+}
+
+// GetSnapshots calls GetSnapshotsFunc.
+func (mock *SnapshotServiceMock) GetSnapshots(ctx context.Context, filter api.SnapshotFilter) ([]domain.StatsSnapshot, error) {
+	if mock.GetSnapshotsFunc == nil {
+		panic("SnapshotServiceMock.GetSnapshotsFunc: method is nil but SnapshotService.GetSnapshots was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Filter api.SnapshotFilter
+	}{
+		Ctx:    ctx,
+		Filter: filter,
+	}
+	mock.lockGetSnapshots.Lock()
+	mock.calls.GetSnapshots = append(mock.calls.GetSnapshots, callInfo)
+	mock.lockGetSnapshots.Unlock()
+	return mock.GetSnapshotsFunc(ctx, filter)
+}
+
+// GetSnapshotsCalls gets all the calls that were made to GetSnapshots.
+// Check the length with:
+//
+//	len(mockedSnapshotService.GetSnapshotsCalls())
+func (mock *SnapshotServiceMock) GetSnapshotsCalls() []struct {
+	Ctx    context.Context
+	Filter api.SnapshotFilter
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Filter api.SnapshotFilter
+	}
+	mock.lockGetSnapshots.RLock()
+	calls = mock.calls.GetSnapshots
+	mock.lockGetSnapshots.RUnlock()
+	return calls
+}
+
+// Schedule calls ScheduleFunc.
+func (mock *SnapshotServiceMock) Schedule(ctx context.Context, interval time.Duration) {
+	if mock.ScheduleFunc == nil {
+		panic("SnapshotServiceMock.ScheduleFunc: method is nil but SnapshotService.Schedule was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Interval time.Duration
+	}{
+		Ctx:      ctx,
+		Interval: interval,
+	}
+	mock.lockSchedule.Lock()
+	mock.calls.Schedule = append(mock.calls.Schedule, callInfo)
+	mock.lockSchedule.Unlock()
+	mock.ScheduleFunc(ctx, interval)
+}
+
+// ScheduleCalls gets all the calls that were made to Schedule.
+// Check the length with:
+//
+//	len(mockedSnapshotService.ScheduleCalls())
+func (mock *SnapshotServiceMock) ScheduleCalls() []struct {
+	Ctx      context.Context
+	Interval time.Duration
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Interval time.Duration
+	}
+	mock.lockSchedule.RLock()
+	calls = mock.calls.Schedule
+	mock.lockSchedule.RUnlock()
+	return calls
+}
+
+// TakeSnapshot calls TakeSnapshotFunc.
+func (mock *SnapshotServiceMock) TakeSnapshot(ctx context.Context, source string) (*domain.StatsSnapshot, error) {
+	if mock.TakeSnapshotFunc == nil {
+		panic("SnapshotServiceMock.TakeSnapshotFunc: method is nil but SnapshotService.TakeSnapshot was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Source string
+	}{
+		Ctx:    ctx,
+		Source: source,
+	}
+	mock.lockTakeSnapshot.Lock()
+	mock.calls.TakeSnapshot = append(mock.calls.TakeSnapshot, callInfo)
+	mock.lockTakeSnapshot.Unlock()
+	return mock.TakeSnapshotFunc(ctx, source)
+}
+
+// TakeSnapshotCalls gets all the calls that were made to TakeSnapshot.
+// Check the length with:
+//
+//	len(mockedSnapshotService.TakeSnapshotCalls())
+func (mock *SnapshotServiceMock) TakeSnapshotCalls() []struct {
+	Ctx    context.Context
+	Source string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Source string
+	}
+	mock.lockTakeSnapshot.RLock()
+	calls = mock.calls.TakeSnapshot
+	mock.lockTakeSnapshot.RUnlock()
+	return calls
+}
@@ -0,0 +1,150 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package service
+
+import (
+	"context"
+	"salaries/pkg/domain"
+	"sync"
+)
+
+// Ensure, that UserServiceMock does implement UserService.
+// If this is not the case, regenerate this file with moq.
+var _ UserService = &UserServiceMock{}
+
+// UserServiceMock is a mock implementation of UserService.
+//
+//	func TestSomethingThatUsesUserService(t *testing.T) {
+//
+//		// make and configure a mocked UserService
+//		mockedUserService := &UserServiceMock{
+//			CreateFunc: func(ctx context.Context, username string, password string, role string) (*domain.User, error) {
+//				panic("mock out the Create method")
+//			},
+//			ResetPasswordFunc: func(ctx context.Context, username string, password string) error {
+//				panic("mock out the ResetPassword method")
+//			},
+//		}
+//
+//		// use mockedUserService in code that requires UserService
+//		// and then make assertions.
+//
+//	}
+type UserServiceMock struct {
+	// CreateFunc mocks the Create method.
+	CreateFunc func(ctx context.Context, username string, password string, role string) (*domain.User, error)
+
+	// ResetPasswordFunc mocks the ResetPassword method.
+	ResetPasswordFunc func(ctx context.Context, username string, password string) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Create holds details about calls to the Create method.
+		Create []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+			// Password is the password argument value.
+			Password string
+			// Role is the role argument value.
+			Role string
+		}
+		// ResetPassword holds details about calls to the ResetPassword method.
+		ResetPassword []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+			// Password is the password argument value.
+			Password string
+		}
+	}
+	lockCreate        sync.RWMutex
+	lockResetPassword sync.RWMutex
+}
+
+// Create calls CreateFunc.
+func (mock *UserServiceMock) Create(ctx context.Context, username string, password string, role string) (*domain.User, error) {
+	if mock.CreateFunc == nil {
+		panic("UserServiceMock.CreateFunc: method is nil but UserService.Create was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+		Password string
+		Role     string
+	}{
+		Ctx:      ctx,
+		Username: username,
+		Password: password,
+		Role:     role,
+	}
+	mock.lockCreate.Lock()
+	mock.calls.Create = append(mock.calls.Create, callInfo)
+	mock.lockCreate.Unlock()
+	return mock.CreateFunc(ctx, username, password, role)
+}
+
+// CreateCalls gets all the calls that were made to Create.
+// Check the length with:
+//
+//	len(mockedUserService.CreateCalls())
+func (mock *UserServiceMock) CreateCalls() []struct {
+	Ctx      context.Context
+	Username string
+	Password string
+	Role     string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+		Password string
+		Role     string
+	}
+	mock.lockCreate.RLock()
+	calls = mock.calls.Create
+	mock.lockCreate.RUnlock()
+	return calls
+}
+
+// ResetPassword calls ResetPasswordFunc.
+func (mock *UserServiceMock) ResetPassword(ctx context.Context, username string, password string) error {
+	if mock.ResetPasswordFunc == nil {
+		panic("UserServiceMock.ResetPasswordFunc: method is nil but UserService.ResetPassword was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+		Password string
+	}{
+		Ctx:      ctx,
+		Username: username,
+		Password: password,
+	}
+	mock.lockResetPassword.Lock()
+	mock.calls.ResetPassword = append(mock.calls.ResetPassword, callInfo)
+	mock.lockResetPassword.Unlock()
+	return mock.ResetPasswordFunc(ctx, username, password)
+}
+
+// ResetPasswordCalls gets all the calls that were made to ResetPassword.
+// Check the length with:
+//
+//	len(mockedUserService.ResetPasswordCalls())
+func (mock *UserServiceMock) ResetPasswordCalls() []struct {
+	Ctx      context.Context
+	Username string
+	Password string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+		Password string
+	}
+	mock.lockResetPassword.RLock()
+	calls = mock.calls.ResetPassword
+	mock.lockResetPassword.RUnlock()
+	return calls
+}
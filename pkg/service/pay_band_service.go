@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"salaries/pkg/api"
+	"salaries/pkg/currency"
+	"salaries/pkg/domain"
+	"salaries/pkg/logger"
+	"salaries/pkg/repository"
+	"salaries/pkg/tracing"
+	"sort"
+	"strings"
+	"time"
+)
+
+type PayBandService interface {
+	Create(ctx context.Context, band *domain.PayBand) error
+	GetAll(ctx context.Context, filter api.PayBandFilter) ([]domain.PayBand, error)
+	GetByID(ctx context.Context, id int64) (*domain.PayBand, error)
+	Update(ctx context.Context, band *domain.PayBand) error
+	DeleteByID(ctx context.Context, id int64) error
+	GetCompaRatios(ctx context.Context, query api.CompaRatioQuery) (*api.CompaRatioReport, error)
+	GetOutOfRange(ctx context.Context, query api.CompaRatioQuery) (*api.OutOfRangeReport, error)
+}
+
+type payBandServiceImpl struct {
+	payBandRepository repository.PayBandRepository
+	salaryRepository  repository.SalaryRepository
+	rates             currency.Rates
+	logger            logger.Logger
+}
+
+// NewPayBandService manages the pay bands, salaries in another currency than their band are converted with the rates
+func NewPayBandService(payBandRepository repository.PayBandRepository, salaryRepository repository.SalaryRepository, rates currency.Rates, logger logger.Logger) PayBandService {
+	return &payBandServiceImpl{
+		payBandRepository: payBandRepository,
+		salaryRepository:  salaryRepository,
+		rates:             rates,
+		logger:            logger,
+	}
+}
+
+func (s payBandServiceImpl) Create(ctx context.Context, band *domain.PayBand) error {
+	ctx, span := tracer.Start(ctx, "PayBandService.Create")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("creating pay band %s in %s", band.Name, band.Currency)
+	if _, err := s.payBandRepository.Create(ctx, band); err != nil {
+		logger.Error("error creating pay band: %s", err.Error())
+		return tracing.RecordError(span, err)
+	}
+	logger.Info("pay band created with id %d", band.ID)
+	return nil
+}
+
+func (s payBandServiceImpl) GetAll(ctx context.Context, filter api.PayBandFilter) ([]domain.PayBand, error) {
+	ctx, span := tracer.Start(ctx, "PayBandService.GetAll")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("getting pay bands")
+	bands, err := s.payBandRepository.ReadAll(ctx, filter)
+	if err != nil {
+		logger.Error("error getting pay bands: %s", err.Error())
+		return nil, tracing.RecordError(span, err)
+	}
+	logger.Info("%d pay bands retrieved", len(bands))
+	return bands, nil
+}
+
+func (s payBandServiceImpl) GetByID(ctx context.Context, id int64) (*domain.PayBand, error) {
+	ctx, span := tracer.Start(ctx, "PayBandService.GetByID")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("getting pay band with id %d", id)
+	band, err := s.payBandRepository.ReadByID(ctx, id)
+	if err != nil {
+		logger.Error("error getting pay band with id %d: %s", id, err.Error())
+		return nil, tracing.RecordError(span, err)
+	}
+	return band, nil
+}
+
+func (s payBandServiceImpl) Update(ctx context.Context, band *domain.PayBand) error {
+	ctx, span := tracer.Start(ctx, "PayBandService.Update")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("updating pay band with id %d", band.ID)
+	if _, err := s.payBandRepository.Update(ctx, band); err != nil {
+		logger.Error("error updating pay band with id %d: %s", band.ID, err.Error())
+		return tracing.RecordError(span, err)
+	}
+	logger.Info("pay band updated with id %d", band.ID)
+	return nil
+}
+
+func (s payBandServiceImpl) DeleteByID(ctx context.Context, id int64) error {
+	ctx, span := tracer.Start(ctx, "PayBandService.DeleteByID")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("deleting pay band with id %d", id)
+	if err := s.payBandRepository.DeleteByID(ctx, id); err != nil {
+		logger.Error("error deleting pay band with id %d: %s", id, err.Error())
+		return tracing.RecordError(span, err)
+	}
+	logger.Info("pay band deleted with id %d", id)
+	return nil
+}
+
+// GetCompaRatios places every salary matching the filter and assigned to a band in the version of the band effective at the date
+func (s payBandServiceImpl) GetCompaRatios(ctx context.Context, query api.CompaRatioQuery) (*api.CompaRatioReport, error) {
+	ctx, span := tracer.Start(ctx, "PayBandService.GetCompaRatios")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("getting compa-ratios")
+	report, err := s.compaRatios(ctx, query)
+	if err != nil {
+		logger.Error("error getting compa-ratios: %s", err.Error())
+		return nil, tracing.RecordError(span, err)
+	}
+	logger.Info("%d compa-ratios retrieved, %d salaries without an effective band", len(report.Salaries), len(report.Unmatched))
+	return report, nil
+}
+
+// GetOutOfRange groups by department the salaries below the minimum or above the maximum of their band
+func (s payBandServiceImpl) GetOutOfRange(ctx context.Context, query api.CompaRatioQuery) (*api.OutOfRangeReport, error) {
+	ctx, span := tracer.Start(ctx, "PayBandService.GetOutOfRange")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("getting salaries out of their pay band")
+	compaRatios, err := s.compaRatios(ctx, query)
+	if err != nil {
+		logger.Error("error getting salaries out of their pay band: %s", err.Error())
+		return nil, tracing.RecordError(span, err)
+	}
+
+	departments := map[string]*api.OutOfRangeDepartment{}
+	for _, compaRatio := range compaRatios.Salaries {
+		if compaRatio.Position == api.BandPositionWithin {
+			continue
+		}
+		department, ok := departments[compaRatio.Department]
+		if !ok {
+			department = &api.OutOfRangeDepartment{
+				Department:   compaRatio.Department,
+				BelowMinimum: []api.CompaRatio{},
+				AboveMaximum: []api.CompaRatio{},
+			}
+			departments[compaRatio.Department] = department
+		}
+		if compaRatio.Position == api.BandPositionBelow {
+			department.BelowMinimum = append(department.BelowMinimum, compaRatio)
+		} else {
+			department.AboveMaximum = append(department.AboveMaximum, compaRatio)
+		}
+	}
+	report := &api.OutOfRangeReport{AsOf: compaRatios.AsOf, Departments: []api.OutOfRangeDepartment{}}
+	for _, department := range departments {
+		report.Departments = append(report.Departments, *department)
+	}
+	sort.Slice(report.Departments, func(i, j int) bool {
+		return report.Departments[i].Department < report.Departments[j].Department
+	})
+	logger.Info("salaries out of their pay band found in %d departments", len(report.Departments))
+	return report, nil
+}
+
+func (s payBandServiceImpl) compaRatios(ctx context.Context, query api.CompaRatioQuery) (*api.CompaRatioReport, error) {
+	asOf := query.AsOf
+	if asOf.IsZero() {
+		asOf = time.Now()
+	}
+	bands, err := s.payBandRepository.ReadAll(ctx, api.PayBandFilter{AsOf: asOf})
+	if err != nil {
+		return nil, err
+	}
+	bandsByName := map[string][]domain.PayBand{}
+	for _, band := range bands {
+		bandsByName[band.Name] = append(bandsByName[band.Name], band)
+	}
+
+	report := &api.CompaRatioReport{AsOf: asOf.UTC(), Salaries: []api.CompaRatio{}, Unmatched: []int64{}}
+	err = s.salaryRepository.ReadEach(ctx, query.SalaryFilter, func(salary *domain.Salary) error {
+		if salary.PayBand == "" {
+			return nil
+		}
+		band, amount, err := s.bandFor(salary, bandsByName[salary.PayBand])
+		if errors.Is(err, currency.ErrUnknownCurrency) {
+			report.Unmatched = append(report.Unmatched, salary.ID)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		report.Salaries = append(report.Salaries, compaRatio(salary, band, amount))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// bandFor picks the band in the currency of the salary, or else the first band the salary can be converted to, and returns the salary in its currency
+func (s payBandServiceImpl) bandFor(salary *domain.Salary, bands []domain.PayBand) (*domain.PayBand, float64, error) {
+	for i := range bands {
+		if strings.EqualFold(bands[i].Currency, salary.Currency) {
+			return &bands[i], salary.Salary, nil
+		}
+	}
+	for i := range bands {
+		if amount, err := s.rates.Convert(salary.Salary, salary.Currency, bands[i].Currency); err == nil {
+			return &bands[i], amount, nil
+		}
+	}
+	return nil, 0, currency.ErrUnknownCurrency
+}
+
+func compaRatio(salary *domain.Salary, band *domain.PayBand, amount float64) api.CompaRatio {
+	position := api.BandPositionWithin
+	if amount < band.Min {
+		position = api.BandPositionBelow
+	} else if amount > band.Max {
+		position = api.BandPositionAbove
+	}
+	return api.CompaRatio{
+		SalaryID:         salary.ID,
+		Name:             salary.Name,
+		Department:       salary.Department,
+		SubDepartment:    salary.SubDepartment,
+		PayBand:          band.Name,
+		PayBandID:        band.ID,
+		Currency:         band.Currency,
+		Salary:           amount,
+		CompaRatio:       amount / band.Mid,
+		RangePenetration: (amount - band.Min) / (band.Max - band.Min),
+		Position:         position,
+	}
+}
@@ -0,0 +1,73 @@
+package service_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"salaries/pkg/api"
+	"salaries/pkg/currency"
+	"salaries/pkg/domain"
+	"salaries/pkg/repository"
+	"salaries/pkg/service"
+	"testing"
+	"time"
+)
+
+func TestPayBandService_CompaRatios(t *testing.T) {
+	asOf := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	payBandRepository := &repository.PayBandRepositoryMock{
+		ReadAllFunc: func(ctx context.Context, filter api.PayBandFilter) ([]domain.PayBand, error) {
+			assert.Equal(t, asOf, filter.AsOf)
+			return []domain.PayBand{
+				{ID: 1, Name: "L3", Currency: "EUR", Min: 60000, Mid: 75000, Max: 90000},
+				{ID: 2, Name: "L3", Currency: "USD", Min: 80000, Mid: 100000, Max: 120000},
+			}, nil
+		},
+	}
+	salaries := []domain.Salary{
+		{ID: 1, Salary: 100000, Currency: "USD", Department: "Engineering", PayBand: "L3"},
+		{ID: 2, Salary: 50000, Currency: "GBP", Department: "Engineering", PayBand: "L3"},
+		{ID: 3, Salary: 70000, Currency: "USD", Department: "Banking", PayBand: "L3"},
+		{ID: 4, Salary: 100000, Currency: "EUR", Department: "Engineering", PayBand: "L3"},
+		{ID: 5, Salary: 90000, Currency: "INR", Department: "Engineering", PayBand: "L3"},
+		{ID: 6, Salary: 90000, Currency: "USD", Department: "Engineering"},
+	}
+	salaryRepository := &repository.SalaryRepositoryMock{
+		ReadEachFunc: func(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary) error) error {
+			for i := range salaries {
+				if err := each(&salaries[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	payBandService := service.NewPayBandService(payBandRepository, salaryRepository, currency.Rates{"USD": 1, "GBP": 1.6}, getTestLogger())
+
+	report, err := payBandService.GetCompaRatios(context.Background(), api.CompaRatioQuery{AsOf: asOf})
+	require.NoError(t, err)
+	require.Len(t, report.Salaries, 4)
+	assert.Equal(t, []int64{5}, report.Unmatched, "INR can not be converted")
+
+	assert.Equal(t, int64(2), report.Salaries[0].PayBandID)
+	assert.InDelta(t, 1.0, report.Salaries[0].CompaRatio, 1e-9)
+	assert.InDelta(t, 0.5, report.Salaries[0].RangePenetration, 1e-9)
+	assert.Equal(t, api.BandPositionWithin, report.Salaries[0].Position)
+
+	assert.Equal(t, "USD", report.Salaries[1].Currency, "GBP is converted to the USD band")
+	assert.InDelta(t, 80000, report.Salaries[1].Salary, 1e-9)
+	assert.Equal(t, api.BandPositionWithin, report.Salaries[1].Position)
+
+	assert.Equal(t, api.BandPositionBelow, report.Salaries[2].Position)
+	assert.Equal(t, int64(1), report.Salaries[3].PayBandID, "EUR salaries use the EUR band")
+	assert.Equal(t, api.BandPositionAbove, report.Salaries[3].Position)
+
+	outOfRange, err := payBandService.GetOutOfRange(context.Background(), api.CompaRatioQuery{AsOf: asOf})
+	require.NoError(t, err)
+	require.Len(t, outOfRange.Departments, 2)
+	assert.Equal(t, "Banking", outOfRange.Departments[0].Department)
+	assert.Len(t, outOfRange.Departments[0].BelowMinimum, 1)
+	assert.Empty(t, outOfRange.Departments[0].AboveMaximum)
+	assert.Equal(t, "Engineering", outOfRange.Departments[1].Department)
+	assert.Equal(t, int64(4), outOfRange.Departments[1].AboveMaximum[0].SalaryID)
+}
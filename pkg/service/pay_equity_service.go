@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"salaries/pkg/api"
+	"salaries/pkg/currency"
+	"salaries/pkg/distribution"
+	"salaries/pkg/domain"
+	"salaries/pkg/logger"
+	"salaries/pkg/repository"
+	"salaries/pkg/tracing"
+	"sort"
+	"strings"
+)
+
+type PayEquityService interface {
+	GetDemographics(ctx context.Context, salaryID int64) (*domain.Demographics, error)
+	SaveDemographics(ctx context.Context, demographics *domain.Demographics) error
+	DeleteDemographics(ctx context.Context, salaryID int64) error
+	GetPayEquity(ctx context.Context, query api.PayEquityQuery) (*api.PayEquityReport, error)
+}
+
+type payEquityServiceImpl struct {
+	demographicsRepository repository.DemographicsRepository
+	rates                  currency.Rates
+	minGroupSize           int
+	logger                 logger.Logger
+}
+
+// NewPayEquityService analyses the pay of the demographic groups, groups smaller than minGroupSize are never reported
+// so nobody can be identified
+func NewPayEquityService(demographicsRepository repository.DemographicsRepository, rates currency.Rates, minGroupSize int, logger logger.Logger) PayEquityService {
+	return &payEquityServiceImpl{
+		demographicsRepository: demographicsRepository,
+		rates:                  rates,
+		minGroupSize:           minGroupSize,
+		logger:                 logger,
+	}
+}
+
+func (s payEquityServiceImpl) GetDemographics(ctx context.Context, salaryID int64) (*domain.Demographics, error) {
+	ctx, span := tracer.Start(ctx, "PayEquityService.GetDemographics")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("getting demographics of salary with id %d", salaryID)
+	demographics, err := s.demographicsRepository.Read(ctx, salaryID)
+	if err != nil {
+		logger.Error("error getting demographics of salary with id %d: %s", salaryID, err.Error())
+		return nil, tracing.RecordError(span, err)
+	}
+	return demographics, nil
+}
+
+func (s payEquityServiceImpl) SaveDemographics(ctx context.Context, demographics *domain.Demographics) error {
+	ctx, span := tracer.Start(ctx, "PayEquityService.SaveDemographics")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("saving demographics of salary with id %d", demographics.SalaryID)
+	if err := s.demographicsRepository.Save(ctx, demographics); err != nil {
+		logger.Error("error saving demographics of salary with id %d: %s", demographics.SalaryID, err.Error())
+		return tracing.RecordError(span, err)
+	}
+	return nil
+}
+
+func (s payEquityServiceImpl) DeleteDemographics(ctx context.Context, salaryID int64) error {
+	ctx, span := tracer.Start(ctx, "PayEquityService.DeleteDemographics")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("deleting demographics of salary with id %d", salaryID)
+	if err := s.demographicsRepository.Delete(ctx, salaryID); err != nil {
+		logger.Error("error deleting demographics of salary with id %d: %s", salaryID, err.Error())
+		return tracing.RecordError(span, err)
+	}
+	return nil
+}
+
+type payEquityCellKey struct {
+	department    string
+	subDepartment string
+	payBand       string
+}
+
+// GetPayEquity compares the mean and median pay of the groups of the attribute with the reference group, over all the
+// salaries matching the filter and within each department, sub-department and pay band
+func (s payEquityServiceImpl) GetPayEquity(ctx context.Context, query api.PayEquityQuery) (*api.PayEquityReport, error) {
+	ctx, span := tracer.Start(ctx, "PayEquityService.GetPayEquity")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	if query.Attribute != domain.AttributeGender && query.Attribute != domain.AttributeEthnicity {
+		return nil, tracing.RecordError(span, fmt.Errorf("%w: attribute must be %s or %s", api.ErrInvalidQuery, domain.AttributeGender, domain.AttributeEthnicity))
+	}
+	minGroupSize := s.minGroupSize
+	if query.MinGroupSize > minGroupSize {
+		minGroupSize = query.MinGroupSize
+	}
+	logger.Info("getting pay equity by %s", query.Attribute)
+
+	all := map[string][]float64{}
+	cells := map[payEquityCellKey]map[string][]float64{}
+	err := s.demographicsRepository.ReadEach(ctx, query.SalaryFilter, func(salary *domain.Salary, demographics *domain.Demographics) error {
+		group := demographics.Attribute(query.Attribute)
+		if group == "" {
+			return nil
+		}
+		amount := salary.Salary
+		if query.ConvertTo != "" {
+			var err error
+			if amount, err = s.rates.Convert(salary.Salary, salary.Currency, query.ConvertTo); err != nil {
+				return err
+			}
+		}
+		all[group] = append(all[group], amount)
+		key := payEquityCellKey{salary.Department, salary.SubDepartment, salary.PayBand}
+		if cells[key] == nil {
+			cells[key] = map[string][]float64{}
+		}
+		cells[key][group] = append(cells[key][group], amount)
+		return nil
+	})
+	if err != nil {
+		logger.Error("error getting pay equity: %s", err.Error())
+		return nil, tracing.RecordError(span, err)
+	}
+
+	reference := query.Reference
+	if reference == "" {
+		reference = largestGroup(all)
+	}
+	report := &api.PayEquityReport{
+		Attribute:    query.Attribute,
+		Reference:    reference,
+		Currency:     strings.ToUpper(query.ConvertTo),
+		MinGroupSize: minGroupSize,
+		Raw:          payGroups(all, reference, minGroupSize),
+		Adjusted:     []api.AdjustedPayGap{},
+		Cells:        []api.PayEquityCell{},
+	}
+
+	keys := make([]payEquityCellKey, 0, len(cells))
+	for key := range cells {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].department != keys[j].department {
+			return keys[i].department < keys[j].department
+		}
+		if keys[i].subDepartment != keys[j].subDepartment {
+			return keys[i].subDepartment < keys[j].subDepartment
+		}
+		return keys[i].payBand < keys[j].payBand
+	})
+	adjusted := map[string]*api.AdjustedPayGap{}
+	for _, key := range keys {
+		cell := api.PayEquityCell{
+			Department:    key.department,
+			SubDepartment: key.subDepartment,
+			PayBand:       key.payBand,
+			Groups:        payGroups(cells[key], reference, minGroupSize),
+		}
+		report.Cells = append(report.Cells, cell)
+		for _, group := range cell.Groups {
+			if group.Group == reference || group.MeanGap == nil {
+				continue
+			}
+			gap, ok := adjusted[group.Group]
+			if !ok {
+				gap = &api.AdjustedPayGap{Group: group.Group}
+				adjusted[group.Group] = gap
+			}
+			gap.Count += group.Count
+			gap.Cells++
+			gap.MeanGap += *group.MeanGap * float64(group.Count)
+			gap.MedianGap += *group.MedianGap * float64(group.Count)
+		}
+	}
+	for _, gap := range adjusted {
+		gap.MeanGap /= float64(gap.Count)
+		gap.MedianGap /= float64(gap.Count)
+		report.Adjusted = append(report.Adjusted, *gap)
+	}
+	sort.Slice(report.Adjusted, func(i, j int) bool { return report.Adjusted[i].Group < report.Adjusted[j].Group })
+	logger.Info("pay equity by %s retrieved for %d cells", query.Attribute, len(report.Cells))
+	return report, nil
+}
+
+// payGroups reports the groups sorted by name, with their gaps when the reference group is reported too
+func payGroups(groups map[string][]float64, reference string, minGroupSize int) []api.PayGroup {
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var referenceMean, referenceMedian float64
+	referenceReported := len(groups[reference]) >= minGroupSize && len(groups[reference]) > 0
+	if referenceReported {
+		referenceMean = distribution.Mean(groups[reference])
+		referenceMedian = distribution.Median(groups[reference])
+	}
+	payGroups := make([]api.PayGroup, 0, len(names))
+	for _, name := range names {
+		values := groups[name]
+		if len(values) < minGroupSize {
+			payGroups = append(payGroups, api.PayGroup{Group: name, Suppressed: true})
+			continue
+		}
+		group := api.PayGroup{
+			Group:  name,
+			Count:  int64(len(values)),
+			Mean:   distribution.Mean(values),
+			Median: distribution.Median(values),
+		}
+		if referenceReported {
+			meanGap := (referenceMean - group.Mean) / referenceMean
+			medianGap := (referenceMedian - group.Median) / referenceMedian
+			group.MeanGap, group.MedianGap = &meanGap, &medianGap
+		}
+		payGroups = append(payGroups, group)
+	}
+	return payGroups
+}
+
+// largestGroup returns the group with the most salaries, the first by name on a tie
+func largestGroup(groups map[string][]float64) string {
+	var largest string
+	for name, values := range groups {
+		if len(values) > len(groups[largest]) || (len(values) == len(groups[largest]) && name < largest) {
+			largest = name
+		}
+	}
+	return largest
+}
@@ -0,0 +1,70 @@
+package service_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"salaries/pkg/api"
+	"salaries/pkg/domain"
+	"salaries/pkg/repository"
+	"salaries/pkg/service"
+	"testing"
+)
+
+func TestPayEquityService_GetPayEquity(t *testing.T) {
+	var salaries []domain.Salary
+	var demographics []domain.Demographics
+	add := func(count int, amount float64, department, payBand, gender string) {
+		for i := 0; i < count; i++ {
+			id := int64(len(salaries) + 1)
+			salaries = append(salaries, domain.Salary{ID: id, Salary: amount, Currency: "USD", Department: department, PayBand: payBand})
+			demographics = append(demographics, domain.Demographics{SalaryID: id, Gender: gender})
+		}
+	}
+	add(6, 100000, "Engineering", "L3", "male")
+	add(5, 90000, "Engineering", "L3", "female")
+	add(2, 95000, "Engineering", "L3", "non-binary")
+	add(5, 50000, "Banking", "L2", "male")
+	add(5, 50000, "Banking", "L2", "female")
+	add(3, 70000, "Banking", "L2", "")
+	demographicsRepository := &repository.DemographicsRepositoryMock{
+		ReadEachFunc: func(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary, demographics *domain.Demographics) error) error {
+			for i := range salaries {
+				if err := each(&salaries[i], &demographics[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	payEquityService := service.NewPayEquityService(demographicsRepository, nil, 5, getTestLogger())
+
+	report, err := payEquityService.GetPayEquity(context.Background(), api.PayEquityQuery{Attribute: domain.AttributeGender, MinGroupSize: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "male", report.Reference, "the largest group")
+	assert.Equal(t, 5, report.MinGroupSize, "the configured minimum can not be lowered")
+
+	require.Len(t, report.Raw, 3)
+	assert.Equal(t, "female", report.Raw[0].Group)
+	assert.Equal(t, int64(10), report.Raw[0].Count)
+	assert.InDelta(t, 70000, report.Raw[0].Mean, 1e-9)
+	assert.InDelta(t, (850000.0/11-70000)/(850000.0/11), *report.Raw[0].MeanGap, 1e-9)
+	assert.Equal(t, api.PayGroup{Group: "non-binary", Suppressed: true}, report.Raw[2])
+	assert.InDelta(t, 0, *report.Raw[1].MeanGap, 1e-9, "the reference")
+
+	require.Len(t, report.Cells, 2)
+	assert.Equal(t, "Banking", report.Cells[0].Department)
+	assert.InDelta(t, 0.1, *report.Cells[1].Groups[0].MedianGap, 1e-9)
+
+	require.Len(t, report.Adjusted, 1)
+	assert.Equal(t, api.AdjustedPayGap{Group: "female", Count: 10, Cells: 2, MeanGap: 0.05, MedianGap: 0.05}, report.Adjusted[0])
+
+	report, err = payEquityService.GetPayEquity(context.Background(), api.PayEquityQuery{Attribute: domain.AttributeGender, MinGroupSize: 6})
+	require.NoError(t, err)
+	assert.True(t, report.Cells[0].Groups[1].Suppressed)
+	assert.Nil(t, report.Cells[1].Groups[0].MeanGap, "female is suppressed in Engineering")
+	assert.Empty(t, report.Adjusted)
+
+	_, err = payEquityService.GetPayEquity(context.Background(), api.PayEquityQuery{Attribute: "salary"})
+	assert.ErrorIs(t, err, api.ErrInvalidQuery)
+}
@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"salaries/pkg/api"
+	"salaries/pkg/domain"
+	"salaries/pkg/tracing"
+	"sort"
+	"strings"
+	"time"
+)
+
+// GetProjection projects the monthly and annual payroll cost of the fiscal year per department
+func (s budgetServiceImpl) GetProjection(ctx context.Context, query api.PayrollQuery) (*api.PayrollProjection, error) {
+	ctx, span := tracer.Start(ctx, "BudgetService.GetProjection")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	projection, _, err := s.project(ctx, query)
+	if err != nil {
+		logger.Error("error projecting the payroll: %s", err.Error())
+		return nil, tracing.RecordError(span, err)
+	}
+	logger.Info("payroll of fiscal year %d projected for %d departments", projection.FiscalYear, len(projection.Departments))
+	return projection, nil
+}
+
+// GetVariance compares the budget of every department for the fiscal year with its projected cost
+func (s budgetServiceImpl) GetVariance(ctx context.Context, query api.PayrollQuery) (*api.VarianceReport, error) {
+	ctx, span := tracer.Start(ctx, "BudgetService.GetVariance")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	query.Department = ""
+	projection, reported, err := s.project(ctx, query)
+	if err != nil {
+		logger.Error("error projecting the payroll: %s", err.Error())
+		return nil, tracing.RecordError(span, err)
+	}
+	budgets, err := s.budgetRepository.ReadBudgets(ctx, api.BudgetFilter{FiscalYear: projection.FiscalYear})
+	if err != nil {
+		logger.Error("error getting budgets: %s", err.Error())
+		return nil, tracing.RecordError(span, err)
+	}
+
+	report := &api.VarianceReport{
+		FiscalYear:  projection.FiscalYear,
+		Currency:    projection.Currency,
+		Projected:   projection.Annual,
+		Departments: []api.BudgetVariance{},
+	}
+	variances := map[string]*api.BudgetVariance{}
+	for _, department := range projection.Departments {
+		variances[department.Department] = &api.BudgetVariance{Department: department.Department, Projected: department.Annual}
+	}
+	for _, budget := range budgets {
+		amount, err := s.rates.Convert(budget.Amount, budget.Currency, projection.Currency)
+		if err != nil {
+			return nil, tracing.RecordError(span, err)
+		}
+		// the budget of a department merged by the privacy goes to the group it is reported in
+		department := budget.Department
+		if name, ok := reported[department]; ok {
+			department = name
+		}
+		variance, ok := variances[department]
+		if !ok {
+			variance = &api.BudgetVariance{Department: department}
+			variances[department] = variance
+		}
+		variance.Budget += amount
+		report.Budget += amount
+	}
+	for _, variance := range variances {
+		variance.Variance = variance.Budget - variance.Projected
+		switch {
+		case variance.Budget == 0:
+			variance.Status = api.BudgetStatusUnbudgeted
+		case variance.Variance < 0:
+			variance.Status = api.BudgetStatusOver
+		default:
+			variance.Status = api.BudgetStatusUnder
+		}
+		if variance.Budget > 0 {
+			percent := variance.Variance / variance.Budget * 100
+			variance.VariancePercent = &percent
+		}
+		report.Departments = append(report.Departments, *variance)
+	}
+	sort.Slice(report.Departments, func(i, j int) bool { return report.Departments[i].Department < report.Departments[j].Department })
+	report.Variance = report.Budget - report.Projected
+	logger.Info("budget variance of fiscal year %d computed for %d departments", report.FiscalYear, len(report.Departments))
+	return report, nil
+}
+
+// project builds the pay timeline of every current salary and planned hire from the changes effective before the end
+// of the fiscal year, changes effective before its start apply from the start. Departments with too few people for the
+// privacy of the stats are merged, the department each department is reported in is returned with the projection
+func (s budgetServiceImpl) project(ctx context.Context, query api.PayrollQuery) (*api.PayrollProjection, map[string]string, error) {
+	fiscalYear := query.FiscalYear
+	if fiscalYear == 0 {
+		now := time.Now().UTC()
+		fiscalYear = now.Year()
+		if now.Month() < s.fiscalYearStart {
+			fiscalYear--
+		}
+	}
+	if fiscalYear < 1900 || fiscalYear > 9999 {
+		return nil, nil, fmt.Errorf("%w: fiscal_year must be a four digit year", api.ErrInvalidQuery)
+	}
+	reportCurrency := strings.ToUpper(query.Currency)
+	if reportCurrency == "" {
+		reportCurrency = s.currency
+	}
+	from := time.Date(fiscalYear, s.fiscalYearStart, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(1, 0, 0)
+
+	timelines := map[int64]*payTimeline{}
+	var ordered []*payTimeline
+	err := s.salaryRepository.ReadEach(ctx, api.SalaryFilter{Department: query.Department}, func(salary *domain.Salary) error {
+		timeline := &payTimeline{department: salary.Department, currency: salary.Currency, steps: []payStep{{from: from, rate: salary.Salary}}}
+		timelines[salary.ID] = timeline
+		ordered = append(ordered, timeline)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	changes, err := s.budgetRepository.ReadChanges(ctx, api.PayrollChangeFilter{To: to})
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, change := range changes {
+		effective := change.EffectiveDate.UTC()
+		if effective.Before(from) {
+			effective = from
+		}
+		if change.Kind == domain.PayrollChangeHire {
+			if query.Department == "" || query.Department == change.Department {
+				ordered = append(ordered, &payTimeline{department: change.Department, currency: change.Currency, steps: []payStep{{from: effective, rate: change.Salary}}})
+			}
+			continue
+		}
+		timeline, ok := timelines[change.SalaryID]
+		if !ok {
+			continue
+		}
+		switch change.Kind {
+		case domain.PayrollChangeTermination:
+			timeline.terminate(effective)
+		case domain.PayrollChangeRaise:
+			timeline.raise(effective, change.Percentage, change.Amount)
+		}
+	}
+
+	months := fiscalMonths(from)
+	projection := &api.PayrollProjection{
+		FiscalYear:  fiscalYear,
+		Currency:    reportCurrency,
+		From:        from,
+		To:          to,
+		Monthly:     newMonthlyCosts(months),
+		Departments: []api.DepartmentPayroll{},
+	}
+	departments := map[string]*api.DepartmentPayroll{}
+	people := map[string]int64{}
+	for _, timeline := range ordered {
+		counted := false
+		for i, month := range months {
+			cost, paid := timeline.cost(month, month.AddDate(0, 1, 0))
+			if !paid {
+				continue
+			}
+			if cost, err = s.rates.Convert(cost, timeline.currency, reportCurrency); err != nil {
+				return nil, nil, err
+			}
+			department, ok := departments[timeline.department]
+			if !ok {
+				department = &api.DepartmentPayroll{Department: timeline.department, Monthly: newMonthlyCosts(months)}
+				departments[timeline.department] = department
+			}
+			department.Monthly[i].Cost += cost
+			department.Monthly[i].Headcount++
+			projection.Monthly[i].Cost += cost
+			projection.Monthly[i].Headcount++
+			if !counted {
+				people[timeline.department]++
+				counted = true
+			}
+		}
+	}
+
+	var total int64
+	for _, count := range people {
+		total += count
+	}
+	if s.privacy.tooSmall(total) {
+		return nil, nil, fmt.Errorf("%w: fewer than %d people are paid", api.ErrGroupTooSmall, s.privacy.MinGroupSize)
+	}
+	reported := s.privacy.assign(people)
+	merged := map[string]*api.DepartmentPayroll{}
+	for name, department := range departments {
+		group, ok := merged[reported[name]]
+		if !ok {
+			group = &api.DepartmentPayroll{Department: reported[name], Monthly: newMonthlyCosts(months)}
+			merged[reported[name]] = group
+		}
+		for i, cost := range department.Monthly {
+			group.Monthly[i].Cost += cost.Cost
+			group.Monthly[i].Headcount += cost.Headcount
+		}
+	}
+	for _, department := range merged {
+		department.Annual = s.perturbCosts(ctx, department.Monthly)
+		projection.Departments = append(projection.Departments, *department)
+	}
+	projection.Annual = s.perturbCosts(ctx, projection.Monthly)
+	sort.Slice(projection.Departments, func(i, j int) bool {
+		return otherLast(projection.Departments[i].Department, projection.Departments[j].Department)
+	})
+	return projection, reported, nil
+}
+
+// perturbCosts adds the noise of the stats to the monthly costs and headcounts for the users that do not see them
+// exactly, a single person costs a twelfth of a salary a month. It returns the annual cost, the sum of the months
+func (s budgetServiceImpl) perturbCosts(ctx context.Context, monthly []api.MonthlyCost) float64 {
+	var annual float64
+	for i := range monthly {
+		monthly[i].Cost = s.privacy.perturbAmount(ctx, monthly[i].Cost, 1.0/12)
+		monthly[i].Headcount = int(s.privacy.perturbCount(ctx, int64(monthly[i].Headcount), 1))
+		annual += monthly[i].Cost
+	}
+	return annual
+}
+
+// payStep is an annual rate paid from a date until the next step or the end of the timeline
+type payStep struct {
+	from time.Time
+	rate float64
+}
+
+// payTimeline is the pay of a person over the fiscal year, steps are in date order and the person is not paid from end
+type payTimeline struct {
+	department string
+	currency   string
+	steps      []payStep
+	end        *time.Time
+}
+
+func (t *payTimeline) terminate(at time.Time) {
+	if t.end == nil || at.Before(*t.end) {
+		t.end = &at
+	}
+}
+
+// raise adds a step from the date, raises are applied in date order on the rate paid at the date
+func (t *payTimeline) raise(at time.Time, percentage, amount float64) {
+	last := t.steps[len(t.steps)-1]
+	if at.Before(last.from) {
+		at = last.from
+	}
+	rate := last.rate*(1+percentage/100) + amount
+	if at.Equal(last.from) {
+		t.steps[len(t.steps)-1].rate = rate
+		return
+	}
+	t.steps = append(t.steps, payStep{from: at, rate: rate})
+}
+
+// cost returns the pay of the month from monthStart to monthEnd, a twelfth of the annual rate for every day paid over
+// the days of the month, and whether anything is paid in the month
+func (t *payTimeline) cost(monthStart, monthEnd time.Time) (float64, bool) {
+	days := monthEnd.Sub(monthStart).Hours() / 24
+	var cost float64
+	var paid bool
+	for i, step := range t.steps {
+		start, end := step.from, monthEnd
+		if i+1 < len(t.steps) {
+			end = t.steps[i+1].from
+		}
+		if t.end != nil && t.end.Before(end) {
+			end = *t.end
+		}
+		if start.Before(monthStart) {
+			start = monthStart
+		}
+		if end.After(monthEnd) {
+			end = monthEnd
+		}
+		if !start.Before(end) {
+			continue
+		}
+		paid = true
+		cost += step.rate / 12 * end.Sub(start).Hours() / 24 / days
+	}
+	return cost, paid
+}
+
+// fiscalMonths returns the first day of the twelve months of the fiscal year starting at from
+func fiscalMonths(from time.Time) []time.Time {
+	months := make([]time.Time, 12)
+	for i := range months {
+		months[i] = from.AddDate(0, i, 0)
+	}
+	return months
+}
+
+func newMonthlyCosts(months []time.Time) []api.MonthlyCost {
+	costs := make([]api.MonthlyCost, len(months))
+	for i, month := range months {
+		costs[i].Month = month.Format("2006-01")
+	}
+	return costs
+}
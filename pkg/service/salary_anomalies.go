@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"salaries/pkg/api"
+	"salaries/pkg/distribution"
+	"salaries/pkg/domain"
+	"salaries/pkg/tracing"
+	"sort"
+	"strings"
+)
+
+// defaultAnomalyMinGroupSize is the fewest salaries an outlier is looked for in, smaller groups have no meaningful spread
+const defaultAnomalyMinGroupSize = 5
+
+type anomalyGroupKey struct {
+	department    string
+	subDepartment string
+	currency      string
+}
+
+// GetAnomalies flags the salaries matching the filter that are outliers in their department and sub-department. The
+// ranges of the groups get the noise of the stats endpoints
+func (s salaryServiceImpl) GetAnomalies(ctx context.Context, query api.AnomalyQuery) (*api.AnomalyReport, error) {
+	ctx, span := tracer.Start(ctx, "SalaryService.GetAnomalies")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("Getting salary anomalies")
+	detector := s.detector
+	if query.Method != "" {
+		detector = distribution.Detector{Method: query.Method}
+	}
+	if query.Threshold != 0 {
+		detector.Threshold = query.Threshold
+	}
+	if err := detector.Validate(); err != nil {
+		return nil, tracing.RecordError(span, fmt.Errorf("%w: %s", api.ErrInvalidQuery, err))
+	}
+
+	groups := map[anomalyGroupKey][]domain.Salary{}
+	amounts := map[anomalyGroupKey][]float64{}
+	err := s.salaryRepository.ReadEach(ctx, query.SalaryFilter, func(salary *domain.Salary) error {
+		amount, err := s.amount(salary, query.ConvertTo)
+		if err != nil {
+			return err
+		}
+		key := anomalyGroupKey{salary.Department, salary.SubDepartment, strings.ToUpper(salary.Currency)}
+		if query.ConvertTo != "" {
+			key.currency = strings.ToUpper(query.ConvertTo)
+		}
+		converted := *salary
+		converted.Salary, converted.Currency = amount, key.currency
+		groups[key] = append(groups[key], converted)
+		amounts[key] = append(amounts[key], amount)
+		return nil
+	})
+	if err != nil {
+		logger.Error("error getting salary anomalies: %s", err.Error())
+		return nil, tracing.RecordError(span, err)
+	}
+
+	report := &api.AnomalyReport{
+		Method:       detector.Method,
+		Threshold:    detector.Effective().Threshold,
+		Currency:     strings.ToUpper(query.ConvertTo),
+		MinGroupSize: s.anomalyGroupSize(),
+		Anomalies:    []api.Anomaly{},
+	}
+	for key, salaries := range groups {
+		if len(salaries) < report.MinGroupSize {
+			continue
+		}
+		fence, err := detector.Fence(amounts[key])
+		if err != nil {
+			return nil, tracing.RecordError(span, err)
+		}
+		median := distribution.Median(amounts[key])
+		var anomalies []api.Anomaly
+		for i := range salaries {
+			if anomaly, ok := anomalyOf(&salaries[i], fence, median, len(salaries)); ok {
+				anomalies = append(anomalies, anomaly)
+			}
+		}
+		report.Anomalies = append(report.Anomalies, s.perturbAnomalies(ctx, anomalies)...)
+	}
+	sort.Slice(report.Anomalies, func(i, j int) bool {
+		a, b := report.Anomalies[i], report.Anomalies[j]
+		if a.Department != b.Department {
+			return a.Department < b.Department
+		}
+		if a.SubDepartment != b.SubDepartment {
+			return a.SubDepartment < b.SubDepartment
+		}
+		return a.SalaryID < b.SalaryID
+	})
+	logger.Info("%d salary anomalies found", len(report.Anomalies))
+	return report, nil
+}
+
+// peerAnomalies compares a salary with the other salaries of its department and sub-department, converted to its
+// currency, peers that can not be converted are left out
+func (s salaryServiceImpl) peerAnomalies(ctx context.Context, salary *domain.Salary) ([]api.Anomaly, error) {
+	var peers []float64
+	filter := api.SalaryFilter{Department: salary.Department, SubDepartment: salary.SubDepartment}
+	err := s.salaryRepository.ReadEach(ctx, filter, func(peer *domain.Salary) error {
+		if peer.ID == salary.ID || peer.Department != salary.Department || peer.SubDepartment != salary.SubDepartment {
+			return nil
+		}
+		if amount, err := s.rates.Convert(peer.Salary, peer.Currency, salary.Currency); err == nil {
+			peers = append(peers, amount)
+		}
+		return nil
+	})
+	if err != nil || len(peers) < s.anomalyGroupSize() {
+		return nil, err
+	}
+	fence, err := s.detector.Fence(peers)
+	if err != nil {
+		return nil, err
+	}
+	if anomaly, ok := anomalyOf(salary, fence, distribution.Median(peers), len(peers)); ok {
+		return s.perturbAnomalies(ctx, []api.Anomaly{anomaly}), nil
+	}
+	return nil, nil
+}
+
+// anomalyGroupSize is the fewest salaries outliers are looked for in, groups too small for the stats are not checked
+func (s salaryServiceImpl) anomalyGroupSize() int {
+	if s.privacy.MinGroupSize > s.anomalyMinGroupSize {
+		return s.privacy.MinGroupSize
+	}
+	return s.anomalyMinGroupSize
+}
+
+// perturbAnomalies adds the noise of the stats to the range and median of the peers of the anomalies of a group, the
+// noise is drawn once for the group so that it does not average out over its anomalies
+func (s salaryServiceImpl) perturbAnomalies(ctx context.Context, anomalies []api.Anomaly) []api.Anomaly {
+	if len(anomalies) == 0 || !s.privacy.noisy(ctx) {
+		return anomalies
+	}
+	low := s.privacy.perturbAmount(ctx, anomalies[0].Low, 1)
+	high := s.privacy.perturbAmount(ctx, anomalies[0].High, 1)
+	median := s.privacy.perturbAmount(ctx, anomalies[0].Median, 1)
+	for i := range anomalies {
+		anomalies[i].Low, anomalies[i].High, anomalies[i].Median = low, high, median
+	}
+	return anomalies
+}
+
+func anomalyOf(salary *domain.Salary, fence distribution.Fence, median float64, peers int) (api.Anomaly, bool) {
+	if fence.Contains(salary.Salary) {
+		return api.Anomaly{}, false
+	}
+	direction := api.AnomalyDirectionHigh
+	if salary.Salary < fence.Low {
+		direction = api.AnomalyDirectionLow
+	}
+	return api.Anomaly{
+		SalaryID:      salary.ID,
+		Name:          salary.Name,
+		Department:    salary.Department,
+		SubDepartment: salary.SubDepartment,
+		Currency:      salary.Currency,
+		Salary:        salary.Salary,
+		Direction:     direction,
+		Low:           fence.Low,
+		High:          fence.High,
+		Median:        median,
+		Peers:         peers,
+	}, true
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"salaries/pkg/api"
+	"salaries/pkg/db"
+	"salaries/pkg/domain"
+	"salaries/pkg/importer"
+	"salaries/pkg/tracing"
+)
+
+// CreateMany validates the salaries and inserts them in a single transaction, without partial nothing is written if any salary is invalid
+func (s salaryServiceImpl) CreateMany(ctx context.Context, salaries []domain.Salary, partial bool) (*api.BatchResult, error) {
+	ctx, span := tracer.Start(ctx, "SalaryService.CreateMany")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("creating %d salaries, partial %t", len(salaries), partial)
+	result := newBatchResult(len(salaries), partial)
+	for i := range salaries {
+		if err := importer.Validate(&salaries[i]); err != nil {
+			failItem(result, i, err)
+		}
+	}
+	err := s.applyBatch(ctx, result, func(batch db.SalaryBatch, i int) error {
+		err := batch.Add(ctx, &salaries[i])
+		if errors.Is(err, api.ErrUnknownPayBand) {
+			failItem(result, i, err)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		succeedItem(result, i, salaries[i].ID, api.BatchStatusCreated)
+		return nil
+	})
+	if err != nil {
+		logger.Error("error creating salaries: %s", err.Error())
+		return nil, tracing.RecordError(span, err)
+	}
+	logger.Info("%d salaries created, %d failed", result.Succeeded, result.Failed)
+	return result, nil
+}
+
+// DeleteMany deletes the salaries in a single transaction, without partial nothing is deleted if any salary does not exist
+func (s salaryServiceImpl) DeleteMany(ctx context.Context, ids []int64, partial bool) (*api.BatchResult, error) {
+	ctx, span := tracer.Start(ctx, "SalaryService.DeleteMany")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("deleting %d salaries, partial %t", len(ids), partial)
+	result := newBatchResult(len(ids), partial)
+	err := s.applyBatch(ctx, result, func(batch db.SalaryBatch, i int) error {
+		result.Items[i].ID = ids[i]
+		err := batch.Delete(ctx, ids[i])
+		if errors.Is(err, api.ErrNotFound) {
+			failItem(result, i, err)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		succeedItem(result, i, ids[i], api.BatchStatusDeleted)
+		return nil
+	})
+	if err != nil {
+		logger.Error("error deleting salaries: %s", err.Error())
+		return nil, tracing.RecordError(span, err)
+	}
+	logger.Info("%d salaries deleted, %d failed", result.Succeeded, result.Failed)
+	return result, nil
+}
+
+// DeleteMatching deletes the salaries matching the filter, api.ErrBatchTooLarge is returned without deleting anything when more than limit match
+func (s salaryServiceImpl) DeleteMatching(ctx context.Context, filter api.SalaryFilter, limit int, partial bool) (*api.BatchResult, error) {
+	ctx, span := tracer.Start(ctx, "SalaryService.DeleteMatching")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	var ids []int64
+	err := s.salaryRepository.ReadEach(ctx, filter, func(salary *domain.Salary) error {
+		if len(ids) == limit {
+			return api.ErrBatchTooLarge
+		}
+		ids = append(ids, salary.ID)
+		return nil
+	})
+	if err != nil {
+		logger.Error("error finding the salaries to delete: %s", err.Error())
+		return nil, tracing.RecordError(span, err)
+	}
+	return s.DeleteMany(ctx, ids, partial)
+}
+
+// applyBatch applies the items that did not fail yet in one transaction, all-or-nothing batches with failures are rolled back and their applied items reported as skipped
+func (s salaryServiceImpl) applyBatch(ctx context.Context, result *api.BatchResult, apply func(batch db.SalaryBatch, i int) error) error {
+	if !result.Partial && result.Failed > 0 {
+		return nil
+	}
+	batch, err := s.salaryRepository.Batch(ctx)
+	if err != nil {
+		return err
+	}
+	defer batch.Rollback()
+	s.statsCache.begin()
+	defer s.statsCache.invalidate()
+
+	for i := range result.Items {
+		if result.Items[i].Status == api.BatchStatusFailed {
+			continue
+		}
+		if err := apply(batch, i); err != nil {
+			return err
+		}
+	}
+	if !result.Partial && result.Failed > 0 {
+		for i, item := range result.Items {
+			switch item.Status {
+			case api.BatchStatusCreated:
+				result.Items[i] = api.BatchItemResult{Index: i, Status: api.BatchStatusSkipped}
+			case api.BatchStatusDeleted:
+				result.Items[i].Status = api.BatchStatusSkipped
+			}
+		}
+		result.Succeeded = 0
+		return nil
+	}
+	return batch.Commit()
+}
+
+// newBatchResult starts with every item skipped, until it succeeds or fails
+func newBatchResult(items int, partial bool) *api.BatchResult {
+	result := &api.BatchResult{Partial: partial, Items: make([]api.BatchItemResult, items)}
+	for i := range result.Items {
+		result.Items[i] = api.BatchItemResult{Index: i, Status: api.BatchStatusSkipped}
+	}
+	return result
+}
+
+func succeedItem(result *api.BatchResult, i int, id int64, status string) {
+	result.Items[i].ID = id
+	result.Items[i].Status = status
+	result.Succeeded++
+}
+
+func failItem(result *api.BatchResult, i int, err error) {
+	result.Items[i].Status = api.BatchStatusFailed
+	result.Items[i].Error = err.Error()
+	result.Failed++
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"salaries/pkg/api"
+	"salaries/pkg/distribution"
+	"salaries/pkg/domain"
+	"salaries/pkg/tracing"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultHistogramBuckets = 10
+	maxHistogramBuckets     = 1000
+)
+
+// GetHistogram counts the salaries matching the filter in buckets, optionally per group and converted to a single currency.
+// Groups too small for the privacy of the stats are merged, the users that get noisy stats get buckets computed from
+// a noisy range with noisy counts and the others get the buckets too small for the privacy merged
+func (s salaryServiceImpl) GetHistogram(ctx context.Context, query api.HistogramQuery) (*api.Histogram, error) {
+	ctx, span := tracer.Start(ctx, "SalaryService.GetHistogram")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("Getting salary histogram")
+	buckets, err := histogramBuckets(query)
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	groupOf, err := histogramGroup(query.GroupBy)
+	if err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+
+	var values []float64
+	groups := map[string][]float64{}
+	err = s.salaryRepository.ReadEach(ctx, query.SalaryFilter, func(salary *domain.Salary) error {
+		amount, err := s.amount(salary, query.ConvertTo)
+		if err != nil {
+			return err
+		}
+		values = append(values, amount)
+		group := groupOf(salary)
+		groups[group] = append(groups[group], amount)
+		return nil
+	})
+	if err != nil {
+		logger.Error("error getting salary histogram: %s", err.Error())
+		return nil, tracing.RecordError(span, err)
+	}
+
+	if s.privacy.tooSmall(int64(len(values))) {
+		return nil, tracing.RecordError(span, fmt.Errorf("%w: fewer than %d salaries match", api.ErrGroupTooSmall, s.privacy.MinGroupSize))
+	}
+	groups = s.privacy.mergeValues(groups)
+	noisy := s.privacy.noisy(ctx)
+	bounds := values
+	if noisy && len(values) > 0 {
+		noisyStats := s.privacy.perturb(ctx, statsOf(values))
+		bounds = []float64{noisyStats.Min, noisyStats.Max}
+	}
+
+	edges, err := buckets.EdgesFor(bounds, maxHistogramBuckets)
+	if err != nil {
+		return nil, tracing.RecordError(span, fmt.Errorf("%w: %s", api.ErrInvalidQuery, err))
+	}
+	if !noisy {
+		edges = s.privacy.mergeBuckets(edges, groups)
+	}
+	histogram := &api.Histogram{
+		Strategy: buckets.Strategy,
+		Currency: strings.ToUpper(query.ConvertTo),
+		GroupBy:  query.GroupBy,
+		Edges:    edges,
+		Groups:   []api.HistogramGroup{},
+	}
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		counts := distribution.Count(edges, groups[name])
+		for i := range counts.Buckets {
+			counts.Buckets[i] = s.privacy.perturbCount(ctx, counts.Buckets[i], 1)
+		}
+		histogram.Groups = append(histogram.Groups, api.HistogramGroup{
+			Group:     name,
+			Count:     s.privacy.perturbCount(ctx, int64(len(groups[name])), 1),
+			Counts:    counts.Buckets,
+			Underflow: s.privacy.perturbCount(ctx, counts.Underflow, 1),
+			Overflow:  s.privacy.perturbCount(ctx, counts.Overflow, 1),
+		})
+	}
+	logger.Info("salary histogram retrieved")
+	return histogram, nil
+}
+
+// amount returns the salary in the currency, or as it is stored when no currency is given
+func (s salaryServiceImpl) amount(salary *domain.Salary, currency string) (float64, error) {
+	if currency == "" {
+		return salary.Salary, nil
+	}
+	return s.rates.Convert(salary.Salary, salary.Currency, currency)
+}
+
+func histogramBuckets(query api.HistogramQuery) (distribution.Buckets, error) {
+	buckets := distribution.Buckets{
+		Strategy: query.Strategy,
+		Width:    query.Width,
+		Count:    query.Buckets,
+	}
+	if buckets.Strategy == "" {
+		buckets.Strategy = distribution.StrategyFixedCount
+	}
+	if buckets.Count == 0 {
+		buckets.Count = defaultHistogramBuckets
+	}
+	if buckets.Strategy == distribution.StrategyEdges {
+		for _, field := range strings.Split(query.Edges, ",") {
+			edge, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+			if err != nil {
+				return buckets, fmt.Errorf("%w: invalid edge %q", api.ErrInvalidQuery, field)
+			}
+			buckets.Edges = append(buckets.Edges, edge)
+		}
+	}
+	return buckets, nil
+}
+
+// histogramGroup returns the function naming the group of a salary, every salary is in the same unnamed group without grouping
+func histogramGroup(groupBy string) (func(salary *domain.Salary) string, error) {
+	switch groupBy {
+	case "":
+		return func(*domain.Salary) string { return "" }, nil
+	case api.GroupByDepartment:
+		return func(salary *domain.Salary) string { return salary.Department }, nil
+	case api.GroupByContract:
+		return func(salary *domain.Salary) string { return strconv.FormatBool(salary.OnContract) }, nil
+	}
+	return nil, fmt.Errorf("%w: group_by must be %s or %s", api.ErrInvalidQuery, api.GroupByDepartment, api.GroupByContract)
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"salaries/pkg/logger"
+	"salaries/pkg/metrics"
+	"salaries/pkg/repository"
+	"sync"
+	"time"
+)
+
+// salaryMetricsInterval is how long the salary gauges are served before the dataset is read again, so frequent or
+// concurrent scrapes don't each run the aggregate queries
+const salaryMetricsInterval = 30 * time.Second
+
+// NewSalaryMetricsCollector exposes business gauges computed from the salaries dataset at scrape time,
+// it reads from the repository directly to keep the scrapes out of the service logs. The gauges are
+// kept for salaryMetricsInterval, a collection that failed is done again on the next scrape
+func NewSalaryMetricsCollector(salaryRepository repository.SalaryRepository, logger logger.Logger) metrics.Collector {
+	var mu sync.Mutex
+	var families []metrics.Family
+	var collectedAt time.Time
+	return metrics.CollectorFunc(func() []metrics.Family {
+		mu.Lock()
+		defer mu.Unlock()
+		if !collectedAt.IsZero() && time.Since(collectedAt) < salaryMetricsInterval {
+			return families
+		}
+		var complete bool
+		families, complete = collectSalaryMetrics(salaryRepository, logger)
+		collectedAt = time.Time{}
+		if complete {
+			collectedAt = time.Now()
+		}
+		return families
+	})
+}
+
+// collectSalaryMetrics reads the gauges, it reports whether every one of them could be read
+func collectSalaryMetrics(salaryRepository repository.SalaryRepository, logger logger.Logger) ([]metrics.Family, bool) {
+	var families []metrics.Family
+	complete := true
+
+	stats, err := salaryRepository.GetStatsForAllSalaries(context.Background())
+	if err != nil {
+		logger.Error("error collecting salary metrics: %s", err.Error())
+		complete = false
+	} else {
+		families = append(families, metrics.Family{
+			Name:    "salaries_records",
+			Help:    "Number of salary records.",
+			Type:    metrics.GaugeType,
+			Samples: []metrics.Sample{{Value: float64(stats.Count)}},
+		})
+	}
+
+	contractStats, err := salaryRepository.GetContractsStats(context.Background())
+	if err != nil {
+		logger.Error("error collecting contract metrics: %s", err.Error())
+		complete = false
+	} else {
+		families = append(families, metrics.Family{
+			Name:    "salaries_on_contract_records",
+			Help:    "Number of salary records on contract.",
+			Type:    metrics.GaugeType,
+			Samples: []metrics.Sample{{Value: float64(contractStats.Count)}},
+		})
+	}
+
+	departmentsStats, err := salaryRepository.GetDepartmentsStats(context.Background())
+	if err != nil {
+		logger.Error("error collecting department metrics: %s", err.Error())
+		return families, false
+	}
+	samples := make([]metrics.Sample, 0, len(departmentsStats))
+	for _, departmentStats := range departmentsStats {
+		samples = append(samples, metrics.Sample{
+			Labels: []metrics.Label{{Name: "department", Value: departmentStats.Department}},
+			Value:  float64(departmentStats.Stats.Count),
+		})
+	}
+	families = append(families, metrics.Family{
+		Name:    "salaries_department_records",
+		Help:    "Number of salary records per department.",
+		Type:    metrics.GaugeType,
+		Samples: samples,
+	})
+	return families, complete
+}
@@ -0,0 +1,41 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"salaries/pkg/api"
+	"salaries/pkg/repository"
+	"salaries/pkg/service"
+	"testing"
+)
+
+func TestSalaryMetricsCollector(t *testing.T) {
+	fail := true
+	salaryRepository := &repository.SalaryRepositoryMock{
+		GetStatsForAllSalariesFunc: func(ctx context.Context) (*api.Stats, error) {
+			return &api.Stats{Count: 3}, nil
+		},
+		GetContractsStatsFunc: func(ctx context.Context) (*api.Stats, error) {
+			if fail {
+				return nil, errors.New("error")
+			}
+			return &api.Stats{Count: 1}, nil
+		},
+		GetDepartmentsStatsFunc: func(ctx context.Context) ([]api.DepartmentStats, error) {
+			return []api.DepartmentStats{{Department: "Banking", Stats: api.Stats{Count: 3}}}, nil
+		},
+	}
+	collector := service.NewSalaryMetricsCollector(salaryRepository, getTestLogger())
+
+	assert.Len(t, collector.Collect(), 2)
+	fail = false
+	families := collector.Collect()
+	require.Len(t, families, 3, "a failed collection is done again")
+	assert.Equal(t, "salaries_on_contract_records", families[1].Name)
+	assert.Equal(t, 1.0, families[1].Samples[0].Value)
+
+	assert.Equal(t, families, collector.Collect())
+	assert.Len(t, salaryRepository.GetStatsForAllSalariesCalls(), 2, "the gauges are kept between scrapes")
+}
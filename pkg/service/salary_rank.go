@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"salaries/pkg/api"
+	"salaries/pkg/distribution"
+	"salaries/pkg/domain"
+	"salaries/pkg/tracing"
+	"sort"
+	"strings"
+)
+
+// defaultRankMinGroupSize is the fewest salaries a rank is given among, a rank in a smaller group tells too much about
+// the people in it
+const defaultRankMinGroupSize = 5
+
+// rankStep is what the ranks are rounded to, so they do not move with every salary an amount passes
+const rankStep = 10
+
+// rankPercentiles are the boundaries that can be reported around a rank. Only the ones interpolated between two salaries
+// are, the others are the salary of a single person
+var rankPercentiles = []int{10, 25, 50, 75, 90}
+
+// GetRank returns where the amount stands among the salaries matching the query, the same salaries the stats are
+// computed on. Groups smaller than the minimum group size, or the one of the stats privacy when it is larger, are refused
+// with api.ErrGroupTooSmall, and the users that get the stats with noise get the rank with noise too
+func (s salaryServiceImpl) GetRank(ctx context.Context, query api.RankQuery) (*api.PercentileRank, error) {
+	ctx, span := tracer.Start(ctx, "SalaryService.GetRank")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("Getting salary rank")
+	if query.Amount <= 0 {
+		return nil, tracing.RecordError(span, fmt.Errorf("%w: amount must be positive", api.ErrInvalidQuery))
+	}
+	currency := strings.ToUpper(query.Currency)
+	filter := api.SalaryFilter{
+		Department:    query.Department,
+		SubDepartment: query.SubDepartment,
+		OnContract:    query.OnContract,
+	}
+	var values []float64
+	err := s.salaryRepository.ReadEach(ctx, filter, func(salary *domain.Salary) error {
+		amount, err := s.amount(salary, currency)
+		if err != nil {
+			return err
+		}
+		values = append(values, amount)
+		return nil
+	})
+	if err != nil {
+		logger.Error("error getting salary rank: %s", err.Error())
+		return nil, tracing.RecordError(span, err)
+	}
+	minGroupSize := s.rankMinGroupSize
+	if s.privacy.MinGroupSize > minGroupSize {
+		minGroupSize = s.privacy.MinGroupSize
+	}
+	if len(values) < minGroupSize {
+		return nil, tracing.RecordError(span, fmt.Errorf("%w: fewer than %d salaries match", api.ErrGroupTooSmall, minGroupSize))
+	}
+
+	sort.Float64s(values)
+	percentileRank := s.privacy.perturbRank(ctx, distribution.PercentileRank(values, query.Amount), minGroupSize)
+	rank := &api.PercentileRank{
+		Amount:         query.Amount,
+		Currency:       currency,
+		Department:     query.Department,
+		SubDepartment:  query.SubDepartment,
+		OnContract:     query.OnContract,
+		Count:          s.privacy.perturbCount(ctx, int64(len(values)), 1),
+		PercentileRank: math.Round(percentileRank/rankStep) * rankStep,
+	}
+	for _, percentile := range rankPercentiles {
+		position := float64(percentile) / 100 * float64(len(values)-1)
+		if position == math.Trunc(position) {
+			continue
+		}
+		boundary := api.PercentileBoundary{
+			Percentile: percentile,
+			Value:      s.privacy.perturbQuantile(ctx, distribution.Quantile(values, float64(percentile)/100)),
+		}
+		if boundary.Value <= query.Amount {
+			rank.Lower = &boundary
+		} else if rank.Upper == nil {
+			rank.Upper = &boundary
+		}
+	}
+	logger.Info("salary rank retrieved")
+	return rank, nil
+}
@@ -1,101 +1,462 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"go.opentelemetry.io/otel"
+	"io"
 	"salaries/pkg/api"
+	"salaries/pkg/currency"
+	"salaries/pkg/distribution"
 	"salaries/pkg/domain"
+	"salaries/pkg/importer"
 	"salaries/pkg/logger"
 	"salaries/pkg/repository"
+	"salaries/pkg/tracing"
+	"time"
 )
 
+var tracer = otel.Tracer("salaries/pkg/service")
+
 type SalaryService interface {
-	Create(*domain.Salary) error
-	GetAll() ([]domain.Salary, error)
-	DeleteByID(id int64) error
-	GetStatsForAllSalaries() (*api.Stats, error)
-	GetContractsStats() (*api.Stats, error)
-	GetDepartmentsStats() ([]api.DepartmentStats, error)
-	GetSubDepartmentsStats() ([]api.SubDepartmentStats, error)
+	Create(ctx context.Context, salary *domain.Salary) ([]api.Anomaly, error)
+	CreateMany(ctx context.Context, salaries []domain.Salary, partial bool) (*api.BatchResult, error)
+	Import(ctx context.Context, decoder importer.Decoder, dryRun bool) (*api.ImportReport, error)
+	GetAll(ctx context.Context, filter api.SalaryFilter) ([]domain.Salary, error)
+	Export(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary) error) error
+	GetByID(ctx context.Context, id int64) (*domain.Salary, error)
+	Update(ctx context.Context, salary *domain.Salary, version int64) error
+	DeleteByID(ctx context.Context, id int64, version int64) error
+	DeleteMany(ctx context.Context, ids []int64, partial bool) (*api.BatchResult, error)
+	DeleteMatching(ctx context.Context, filter api.SalaryFilter, limit int, partial bool) (*api.BatchResult, error)
+	GetTrash(ctx context.Context) ([]domain.DeletedSalary, error)
+	Restore(ctx context.Context, id int64) (*domain.Salary, error)
+	Purge(ctx context.Context, olderThan time.Duration) (*api.PurgeResult, error)
+	GetStatsForAllSalaries(ctx context.Context) (*api.Stats, error)
+	GetContractsStats(ctx context.Context) (*api.Stats, error)
+	GetDepartmentsStats(ctx context.Context) ([]api.DepartmentStats, error)
+	GetSubDepartmentsStats(ctx context.Context) ([]api.SubDepartmentStats, error)
+	GetHistogram(ctx context.Context, query api.HistogramQuery) (*api.Histogram, error)
+	GetAnomalies(ctx context.Context, query api.AnomalyQuery) (*api.AnomalyReport, error)
+	Simulate(ctx context.Context, request api.SimulationRequest) (*api.Simulation, error)
+	GetRank(ctx context.Context, query api.RankQuery) (*api.PercentileRank, error)
 }
 
 type salaryServiceImpl struct {
 	salaryRepository repository.SalaryRepository
 	logger           logger.Logger
+	rates            currency.Rates
+	// detector finds the outliers, new salaries are only checked when warnOnCreate is set
+	detector            distribution.Detector
+	anomalyMinGroupSize int
+	warnOnCreate        bool
+	// statsCache answers the stats endpoints when it is set, every write of the salaries goes through it
+	statsCache *StatsCache
+	// rankMinGroupSize is the fewest salaries a rank is given among
+	rankMinGroupSize int
+	privacy          StatsPrivacy
+}
+
+type Option func(s *salaryServiceImpl)
+
+// WithExchangeRates lets the stats convert amounts between the currencies with a rate
+func WithExchangeRates(rates currency.Rates) Option {
+	return func(s *salaryServiceImpl) {
+		s.rates = rates
+	}
+}
+
+// WithAnomalyWarnings checks every new salary against the salaries of its department and sub-department and warns when
+// it is an outlier, groups smaller than minGroupSize are not checked
+func WithAnomalyWarnings(detector distribution.Detector, minGroupSize int) Option {
+	return func(s *salaryServiceImpl) {
+		s.detector = detector
+		if minGroupSize > 0 {
+			s.anomalyMinGroupSize = minGroupSize
+		}
+		s.warnOnCreate = true
+	}
+}
+
+// WithStatsCache answers the stats endpoints from running aggregates kept up to date by the writes of the service
+func WithStatsCache(cache *StatsCache) Option {
+	return func(s *salaryServiceImpl) {
+		s.statsCache = cache
+	}
 }
 
-func NewSalaryService(salaryRepository repository.SalaryRepository, logger logger.Logger) SalaryService {
-	return &salaryServiceImpl{
-		salaryRepository: salaryRepository,
-		logger:           logger,
+// WithRankMinGroupSize refuses to rank an amount among fewer than minGroupSize salaries
+func WithRankMinGroupSize(minGroupSize int) Option {
+	return func(s *salaryServiceImpl) {
+		if minGroupSize > 0 {
+			s.rankMinGroupSize = minGroupSize
+		}
 	}
 }
 
-func (s salaryServiceImpl) Create(salary *domain.Salary) error {
-	s.logger.Info(fmt.Sprintf("creating salary %v", salary))
-	salary, err := s.salaryRepository.Create(salary)
+// WithStatsPrivacy keeps the stats endpoints from telling the salaries of single people
+func WithStatsPrivacy(privacy StatsPrivacy) Option {
+	return func(s *salaryServiceImpl) {
+		s.privacy = privacy
+	}
+}
+
+func NewSalaryService(salaryRepository repository.SalaryRepository, logger logger.Logger, options ...Option) SalaryService {
+	s := &salaryServiceImpl{
+		salaryRepository:    salaryRepository,
+		logger:              logger,
+		detector:            distribution.Detector{Method: distribution.MethodIQR},
+		anomalyMinGroupSize: defaultAnomalyMinGroupSize,
+		rankMinGroupSize:    defaultRankMinGroupSize,
+	}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+// Create adds the salary and, with anomaly warnings, returns a warning when it is an outlier among its peers. The salary
+// is created either way and a failed check is only logged
+func (s salaryServiceImpl) Create(ctx context.Context, salary *domain.Salary) ([]api.Anomaly, error) {
+	ctx, span := tracer.Start(ctx, "SalaryService.Create")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("creating salary %v", salary)
+	s.statsCache.begin()
+	salary, err := s.salaryRepository.Create(ctx, salary)
 	if err != nil {
-		return err
+		s.statsCache.end(nil, nil)
+		logger.Error("salary not created: %s", err.Error())
+		return nil, tracing.RecordError(span, err)
 	}
-	s.logger.Info(fmt.Sprintf("salary created %v", salary))
-	return nil
+	s.statsCache.end(nil, salary)
+	logger.Info("salary created %v", salary)
+	if !s.warnOnCreate {
+		return nil, nil
+	}
+	warnings, err := s.peerAnomalies(ctx, salary)
+	if err != nil {
+		logger.Error("error checking salary with id %d for anomalies: %s", salary.ID, err.Error())
+		return nil, nil
+	}
+	if len(warnings) > 0 {
+		logger.Info("salary with id %d is an outlier among its peers", salary.ID)
+	}
+	return warnings, nil
 }
 
-func (s salaryServiceImpl) GetAll() ([]domain.Salary, error) {
-	s.logger.Info("Getting all salaries")
-	salaries, err := s.salaryRepository.ReadAll()
+// Import validates every row, pay band included, and writes them in a single transaction, nothing is written if any row
+// is invalid or on a dry run
+func (s salaryServiceImpl) Import(ctx context.Context, decoder importer.Decoder, dryRun bool) (*api.ImportReport, error) {
+	ctx, span := tracer.Start(ctx, "SalaryService.Import")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("importing salaries, dry run %t", dryRun)
+	// a dry run opens the batch too to check the pay bands, it is always rolled back
+	batch, err := s.salaryRepository.Batch(ctx)
 	if err != nil {
-		return nil, err
+		logger.Error("error importing salaries: %s", err.Error())
+		return nil, tracing.RecordError(span, err)
+	}
+	defer batch.Rollback()
+	if !dryRun {
+		s.statsCache.begin()
+		defer s.statsCache.invalidate()
 	}
-	s.logger.Info(fmt.Sprintf("salaries retrieved"))
+
+	report := &api.ImportReport{DryRun: dryRun, Errors: []api.ImportRowError{}}
+	for {
+		salary, err := decoder.Next()
+		if err == io.EOF {
+			break
+		}
+		row := report.Rows + 1
+		if err != nil {
+			report.Errors = append(report.Errors, api.ImportRowError{Row: row, Error: err.Error()})
+			if !errors.Is(err, importer.ErrInvalidRow) {
+				break
+			}
+			report.Rows = row
+			continue
+		}
+		report.Rows = row
+		if err := importer.Validate(salary); err != nil {
+			report.Errors = append(report.Errors, api.ImportRowError{Row: row, Error: err.Error()})
+			continue
+		}
+		if err := batch.CheckPayBand(ctx, salary.PayBand); errors.Is(err, api.ErrUnknownPayBand) {
+			report.Errors = append(report.Errors, api.ImportRowError{Row: row, Error: fmt.Sprintf("%s: %s", err.Error(), salary.PayBand)})
+			continue
+		} else if err != nil {
+			logger.Error("error importing salaries: %s", err.Error())
+			return nil, tracing.RecordError(span, err)
+		}
+		report.Valid++
+		if !dryRun && len(report.Errors) == 0 {
+			if err := batch.Add(ctx, salary); err != nil {
+				logger.Error("error importing salaries: %s", err.Error())
+				return nil, tracing.RecordError(span, err)
+			}
+		}
+	}
+
+	if dryRun || len(report.Errors) > 0 {
+		logger.Info("salaries not imported, %d rows read with %d errors", report.Rows, len(report.Errors))
+		return report, nil
+	}
+	if err := batch.Commit(); err != nil {
+		logger.Error("error importing salaries: %s", err.Error())
+		return nil, tracing.RecordError(span, err)
+	}
+	report.Imported = report.Valid
+	logger.Info("%d salaries imported", report.Imported)
+	return report, nil
+}
+
+func (s salaryServiceImpl) GetAll(ctx context.Context, filter api.SalaryFilter) ([]domain.Salary, error) {
+	ctx, span := tracer.Start(ctx, "SalaryService.GetAll")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("Getting all salaries")
+	salaries, err := s.salaryRepository.ReadAll(ctx, filter)
+	if err != nil {
+		logger.Error("error getting salaries: %s", err.Error())
+		return nil, tracing.RecordError(span, err)
+	}
+	logger.Info("salaries retrieved")
 	return salaries, nil
 }
 
-func (s salaryServiceImpl) DeleteByID(salaryID int64) error {
-	s.logger.Info(fmt.Sprintf("deleting salary with id %d", salaryID))
-	err := s.salaryRepository.DeleteByID(salaryID)
+// Export streams the salaries matching the filter to each, one row at a time
+func (s salaryServiceImpl) Export(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary) error) error {
+	ctx, span := tracer.Start(ctx, "SalaryService.Export")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("exporting salaries")
+	rows := 0
+	err := s.salaryRepository.ReadEach(ctx, filter, func(salary *domain.Salary) error {
+		rows++
+		return each(salary)
+	})
 	if err != nil {
-		return err
+		logger.Error("error exporting salaries after %d rows: %s", rows, err.Error())
+		return tracing.RecordError(span, err)
 	}
-	s.logger.Info(fmt.Sprintf("salary deleted with id %d", salaryID))
+	logger.Info("%d salaries exported", rows)
 	return nil
 }
 
-func (s salaryServiceImpl) GetStatsForAllSalaries() (*api.Stats, error) {
-	s.logger.Info("Getting stats")
-	stats, err := s.salaryRepository.GetStatsForAllSalaries()
+func (s salaryServiceImpl) GetByID(ctx context.Context, salaryID int64) (*domain.Salary, error) {
+	ctx, span := tracer.Start(ctx, "SalaryService.GetByID")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("getting salary with id %d", salaryID)
+	salary, err := s.salaryRepository.ReadByID(ctx, salaryID)
 	if err != nil {
-		return nil, err
+		logger.Error("error getting salary with id %d: %s", salaryID, err.Error())
+		return nil, tracing.RecordError(span, err)
 	}
-	s.logger.Info(fmt.Sprintf("stats retrieved"))
-	return stats, nil
+	logger.Info("salary retrieved with id %d", salaryID)
+	return salary, nil
 }
 
-func (s salaryServiceImpl) GetContractsStats() (*api.Stats, error) {
-	s.logger.Info("Getting contract stats")
-	stats, err := s.salaryRepository.GetContractsStats()
+// Update replaces the salary, version is the one the change was made against or 0 to overwrite whatever is stored
+func (s salaryServiceImpl) Update(ctx context.Context, salary *domain.Salary, version int64) error {
+	ctx, span := tracer.Start(ctx, "SalaryService.Update")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("updating salary with id %d", salary.ID)
+	s.statsCache.begin()
+	before, err := s.salaryRepository.Update(ctx, salary, version)
 	if err != nil {
-		return nil, err
+		s.statsCache.end(nil, nil)
+		logger.Error("error updating salary with id %d: %s", salary.ID, err.Error())
+		return tracing.RecordError(span, err)
 	}
-	s.logger.Info(fmt.Sprintf("contract stats retrieved"))
-	return stats, nil
+	s.statsCache.end(before, salary)
+	logger.Info("salary updated with id %d to version %d", salary.ID, salary.Version)
+	return nil
 }
 
-func (s salaryServiceImpl) GetDepartmentsStats() ([]api.DepartmentStats, error) {
-	s.logger.Info("Getting departments stats")
-	stats, err := s.salaryRepository.GetDepartmentsStats()
+// DeleteByID deletes the salary, version is the one the deletion was made against or 0 to delete whatever is stored
+func (s salaryServiceImpl) DeleteByID(ctx context.Context, salaryID int64, version int64) error {
+	ctx, span := tracer.Start(ctx, "SalaryService.DeleteByID")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("deleting salary with id %d", salaryID)
+	s.statsCache.begin()
+	deleted, err := s.salaryRepository.DeleteByID(ctx, salaryID, version)
+	s.statsCache.end(deleted, nil)
 	if err != nil {
-		return nil, err
+		logger.Error("error deleting salary with id %d: %s", salaryID, err.Error())
+		return tracing.RecordError(span, err)
 	}
-	s.logger.Info(fmt.Sprintf("departments stats retrieved"))
-	return stats, nil
+	logger.Info("salary deleted with id %d", salaryID)
+	return nil
+}
+
+func (s salaryServiceImpl) GetTrash(ctx context.Context) ([]domain.DeletedSalary, error) {
+	ctx, span := tracer.Start(ctx, "SalaryService.GetTrash")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("getting deleted salaries")
+	salaries, err := s.salaryRepository.ReadTrash(ctx)
+	if err != nil {
+		logger.Error("error getting deleted salaries: %s", err.Error())
+		return nil, tracing.RecordError(span, err)
+	}
+	logger.Info("%d deleted salaries retrieved", len(salaries))
+	return salaries, nil
+}
+
+func (s salaryServiceImpl) Restore(ctx context.Context, salaryID int64) (*domain.Salary, error) {
+	ctx, span := tracer.Start(ctx, "SalaryService.Restore")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("restoring salary with id %d", salaryID)
+	s.statsCache.begin()
+	salary, err := s.salaryRepository.Restore(ctx, salaryID)
+	s.statsCache.end(nil, salary)
+	if err != nil {
+		logger.Error("error restoring salary with id %d: %s", salaryID, err.Error())
+		return nil, tracing.RecordError(span, err)
+	}
+	logger.Info("salary restored with id %d", salaryID)
+	return salary, nil
+}
+
+// Purge permanently removes the salaries that have been in the trash for longer than olderThan
+func (s salaryServiceImpl) Purge(ctx context.Context, olderThan time.Duration) (*api.PurgeResult, error) {
+	ctx, span := tracer.Start(ctx, "SalaryService.Purge")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	result := &api.PurgeResult{DeletedBefore: time.Now().UTC().Add(-olderThan)}
+	logger.Info("purging salaries deleted before %s", result.DeletedBefore.Format(time.RFC3339))
+	purged, err := s.salaryRepository.Purge(ctx, result.DeletedBefore)
+	if err != nil {
+		logger.Error("error purging salaries: %s", err.Error())
+		return nil, tracing.RecordError(span, err)
+	}
+	result.Purged = purged
+	logger.Info("%d salaries purged", purged)
+	return result, nil
 }
 
-func (s salaryServiceImpl) GetSubDepartmentsStats() ([]api.SubDepartmentStats, error) {
-	s.logger.Info("Getting sub-departments stats")
-	stats, err := s.salaryRepository.GetSubDepartmentsStats()
+func (s salaryServiceImpl) GetStatsForAllSalaries(ctx context.Context) (*api.Stats, error) {
+	ctx, span := tracer.Start(ctx, "SalaryService.GetStatsForAllSalaries")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("Getting stats")
+	stats, err := s.statsForAllSalaries(ctx)
+	if err != nil {
+		logger.Error("error getting stats: %s", err.Error())
+		return nil, tracing.RecordError(span, err)
+	}
+	if err := s.privacy.check(stats, 0); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	noisy := s.privacy.perturb(ctx, *stats)
+	return &noisy, nil
+}
+
+func (s salaryServiceImpl) statsForAllSalaries(ctx context.Context) (*api.Stats, error) {
+	if stats, ok := s.statsCache.overall(ctx); ok {
+		s.logger.WithContext(ctx).Info("stats retrieved from the cache")
+		return stats, nil
+	}
+	stats, err := s.salaryRepository.GetStatsForAllSalaries(ctx)
 	if err != nil {
 		return nil, err
 	}
-	s.logger.Info(fmt.Sprintf("sub-departments stats retrieved"))
+	s.logger.WithContext(ctx).Info("stats retrieved")
 	return stats, nil
 }
+
+// GetContractsStats returns the stats of the salaries on contract, with a minimum group size they are refused when the
+// salaries on contract or the others are too few
+func (s salaryServiceImpl) GetContractsStats(ctx context.Context) (*api.Stats, error) {
+	ctx, span := tracer.Start(ctx, "SalaryService.GetContractsStats")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("Getting contract stats")
+	stats, ok := s.statsCache.contracts(ctx)
+	if ok {
+		logger.Info("contract stats retrieved from the cache")
+	} else {
+		var err error
+		if stats, err = s.salaryRepository.GetContractsStats(ctx); err != nil {
+			logger.Error("error getting contract stats: %s", err.Error())
+			return nil, tracing.RecordError(span, err)
+		}
+		logger.Info("contract stats retrieved")
+	}
+	if s.privacy.MinGroupSize > 1 {
+		overall, err := s.statsForAllSalaries(ctx)
+		if err != nil {
+			logger.Error("error getting contract stats: %s", err.Error())
+			return nil, tracing.RecordError(span, err)
+		}
+		if err := s.privacy.check(stats, overall.Count-stats.Count); err != nil {
+			return nil, tracing.RecordError(span, err)
+		}
+	}
+	noisy := s.privacy.perturb(ctx, *stats)
+	return &noisy, nil
+}
+
+// GetDepartmentsStats returns the stats of every department, with a minimum group size the small departments are
+// merged into an api.OtherGroup department
+func (s salaryServiceImpl) GetDepartmentsStats(ctx context.Context) ([]api.DepartmentStats, error) {
+	ctx, span := tracer.Start(ctx, "SalaryService.GetDepartmentsStats")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("Getting departments stats")
+	stats, ok := s.statsCache.departments(ctx)
+	if ok {
+		logger.Info("departments stats retrieved from the cache")
+	} else {
+		var err error
+		if stats, err = s.salaryRepository.GetDepartmentsStats(ctx); err != nil {
+			logger.Error("error getting departments stats: %s", err.Error())
+			return nil, tracing.RecordError(span, err)
+		}
+		logger.Info("departments stats retrieved")
+	}
+	return s.privacy.perturbDepartments(ctx, s.privacy.departments(stats)), nil
+}
+
+// GetSubDepartmentsStats returns the stats of every sub-department, with a minimum group size the small sub-departments
+// are merged into an api.OtherGroup sub-department of their department
+func (s salaryServiceImpl) GetSubDepartmentsStats(ctx context.Context) ([]api.SubDepartmentStats, error) {
+	ctx, span := tracer.Start(ctx, "SalaryService.GetSubDepartmentsStats")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("Getting sub-departments stats")
+	stats, ok := s.statsCache.subDepartments(ctx)
+	if ok {
+		logger.Info("sub-departments stats retrieved from the cache")
+	} else {
+		var err error
+		if stats, err = s.salaryRepository.GetSubDepartmentsStats(ctx); err != nil {
+			logger.Error("error getting sub-departments stats: %s", err.Error())
+			return nil, tracing.RecordError(span, err)
+		}
+		logger.Info("sub-departments stats retrieved")
+	}
+	return s.privacy.perturbSubDepartments(ctx, s.privacy.subDepartments(stats)), nil
+}
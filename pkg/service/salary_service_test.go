@@ -1,12 +1,22 @@
 package service_test
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math"
+	"salaries/pkg/api"
+	"salaries/pkg/currency"
+	"salaries/pkg/db"
+	"salaries/pkg/distribution"
 	"salaries/pkg/domain"
+	"salaries/pkg/importer"
 	"salaries/pkg/logger"
 	"salaries/pkg/repository"
 	"salaries/pkg/service"
+	"strings"
 	"testing"
 )
 
@@ -37,7 +47,7 @@ func TestRepository_Create(t *testing.T) {
 			},
 			fields: serviceFields{
 				repository: repository.SalaryRepositoryMock{
-					CreateFunc: func(salary *domain.Salary) (*domain.Salary, error) {
+					CreateFunc: func(ctx context.Context, salary *domain.Salary) (*domain.Salary, error) {
 						return &domain.Salary{
 							ID:            1,
 							Name:          "Anurag",
@@ -62,7 +72,7 @@ func TestRepository_Create(t *testing.T) {
 			},
 			fields: serviceFields{
 				repository: repository.SalaryRepositoryMock{
-					CreateFunc: func(salary *domain.Salary) (*domain.Salary, error) {
+					CreateFunc: func(ctx context.Context, salary *domain.Salary) (*domain.Salary, error) {
 						return nil, errors.New("error")
 					},
 				},
@@ -70,10 +80,11 @@ func TestRepository_Create(t *testing.T) {
 			wantError: true,
 		},
 	}
-	for _, tt := range tests {
+	for i := range tests {
+		tt := &tests[i]
 		t.Run(tt.name, func(t *testing.T) {
 			salaryService := service.NewSalaryService(&tt.fields.repository, getTestLogger())
-			err := salaryService.Create(tt.salary)
+			_, err := salaryService.Create(context.Background(), tt.salary)
 			assert.Equal(t, tt.wantError, err != nil)
 		})
 	}
@@ -101,7 +112,7 @@ func TestRepository_GetAll(t *testing.T) {
 			salaries: salaries,
 			fields: serviceFields{
 				repository: repository.SalaryRepositoryMock{
-					ReadAllFunc: func() ([]domain.Salary, error) {
+					ReadAllFunc: func(ctx context.Context, filter api.SalaryFilter) ([]domain.Salary, error) {
 						return salaries, nil
 					},
 				},
@@ -113,7 +124,7 @@ func TestRepository_GetAll(t *testing.T) {
 			salaries: nil,
 			fields: serviceFields{
 				repository: repository.SalaryRepositoryMock{
-					ReadAllFunc: func() ([]domain.Salary, error) {
+					ReadAllFunc: func(ctx context.Context, filter api.SalaryFilter) ([]domain.Salary, error) {
 						return nil, errors.New("error")
 					},
 				},
@@ -121,11 +132,12 @@ func TestRepository_GetAll(t *testing.T) {
 			wantError: true,
 		},
 	}
-	for _, tt := range tests {
+	for i := range tests {
+		tt := &tests[i]
 		t.Run(tt.name, func(t *testing.T) {
 			salaryService := service.NewSalaryService(&tt.fields.repository, getTestLogger())
 
-			salaries, err := salaryService.GetAll()
+			salaries, err := salaryService.GetAll(context.Background(), api.SalaryFilter{})
 			if !tt.wantError {
 				assert.EqualValues(t, tt.salaries, salaries)
 			}
@@ -146,8 +158,8 @@ func TestRepository_Delete(t *testing.T) {
 			ID:   1,
 			fields: serviceFields{
 				repository: repository.SalaryRepositoryMock{
-					DeleteByIDFunc: func(id int64) error {
-						return nil
+					DeleteByIDFunc: func(ctx context.Context, id int64, version int64) (*domain.Salary, error) {
+						return &domain.Salary{ID: id}, nil
 					},
 				},
 			},
@@ -158,20 +170,576 @@ func TestRepository_Delete(t *testing.T) {
 			ID:   2,
 			fields: serviceFields{
 				repository: repository.SalaryRepositoryMock{
-					DeleteByIDFunc: func(id int64) error {
-						return errors.New("error")
+					DeleteByIDFunc: func(ctx context.Context, id int64, version int64) (*domain.Salary, error) {
+						return nil, errors.New("error")
 					},
 				},
 			},
 			wantError: true,
 		},
 	}
-	for _, tt := range tests {
+	for i := range tests {
+		tt := &tests[i]
 		t.Run(tt.name, func(t *testing.T) {
 			salaryService := service.NewSalaryService(&tt.fields.repository, getTestLogger())
 
-			err := salaryService.DeleteByID(tt.ID)
+			err := salaryService.DeleteByID(context.Background(), tt.ID, 0)
 			assert.Equal(t, tt.wantError, err != nil)
 		})
 	}
 }
+
+func TestService_LogsDoNotLeakSalaries(t *testing.T) {
+	var output bytes.Buffer
+	testLogger := logger.NewLoggerWithConfig(logger.Config{Level: "debug", Format: logger.JSONFormat, Output: &output})
+	salaries := []domain.Salary{
+		{
+			ID:            1,
+			Name:          "Anurag",
+			Salary:        90000,
+			Currency:      "USD",
+			Department:    "Banking",
+			SubDepartment: "Loan",
+		},
+	}
+	salaryService := service.NewSalaryService(&repository.SalaryRepositoryMock{
+		CreateFunc: func(ctx context.Context, salary *domain.Salary) (*domain.Salary, error) {
+			salary.ID = 1
+			return salary, nil
+		},
+		ReadAllFunc: func(ctx context.Context, filter api.SalaryFilter) ([]domain.Salary, error) {
+			return salaries, nil
+		},
+	}, testLogger)
+
+	_, err := salaryService.Create(context.Background(), &salaries[0])
+	assert.NoError(t, err)
+	_, err = salaryService.GetAll(context.Background(), api.SalaryFilter{})
+	assert.NoError(t, err)
+
+	assert.Contains(t, output.String(), logger.RedactedValue)
+	assert.NotContains(t, output.String(), "90000")
+	assert.NotContains(t, output.String(), "Anurag")
+}
+
+func TestService_Import(t *testing.T) {
+	const rows = `{"name": "Anurag", "salary": "90000", "currency": "USD", "department": "Banking", "sub_department": "Loan"}
+{"name": "Himani", "salary": "240000", "currency": "USD", "department": "Engineering", "sub_department": "Platform"}
+`
+	const unknownPayBand = `{"name": "Raghav", "salary": "70000", "currency": "USD", "department": "Banking", "sub_department": "Loan", "pay_band": "L9"}
+`
+	tests := []struct {
+		name         string
+		input        string
+		dryRun       bool
+		wantAdded    int
+		wantCommit   bool
+		wantImported int
+		wantErrors   []int
+	}{
+		{
+			name:         "imports every row",
+			input:        rows,
+			wantAdded:    2,
+			wantCommit:   true,
+			wantImported: 2,
+		},
+		{
+			name:   "dry run",
+			input:  rows,
+			dryRun: true,
+		},
+		{
+			name:       "invalid rows",
+			input:      rows + `{"name": "Yatendra", "salary": "30"}` + "\n" + `{"name": ` + "\n",
+			wantAdded:  2,
+			wantErrors: []int{3, 4},
+		},
+		{
+			name:       "unknown pay band",
+			input:      rows + unknownPayBand,
+			wantAdded:  2,
+			wantErrors: []int{3},
+		},
+		{
+			name:       "unknown pay band on a dry run",
+			input:      unknownPayBand + rows,
+			dryRun:     true,
+			wantErrors: []int{1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batch := &db.SalaryBatchMock{
+				AddFunc: func(ctx context.Context, salary *domain.Salary) error {
+					return nil
+				},
+				CheckPayBandFunc: func(ctx context.Context, name string) error {
+					if name == "L9" {
+						return api.ErrUnknownPayBand
+					}
+					return nil
+				},
+				CommitFunc: func() error {
+					return nil
+				},
+				RollbackFunc: func() error {
+					return nil
+				},
+			}
+			salaryRepository := &repository.SalaryRepositoryMock{
+				BatchFunc: func(ctx context.Context) (db.SalaryBatch, error) {
+					return batch, nil
+				},
+			}
+			salaryService := service.NewSalaryService(salaryRepository, getTestLogger())
+
+			report, err := salaryService.Import(context.Background(), importer.NewNDJSONDecoder(strings.NewReader(tt.input)), tt.dryRun)
+			require.NoError(t, err)
+
+			var errorRows []int
+			for _, rowError := range report.Errors {
+				errorRows = append(errorRows, rowError.Row)
+			}
+			assert.Equal(t, tt.wantErrors, errorRows)
+			assert.Equal(t, tt.wantImported, report.Imported)
+			assert.Len(t, batch.AddCalls(), tt.wantAdded)
+			assert.Equal(t, tt.wantCommit, len(batch.CommitCalls()) == 1)
+			assert.Len(t, batch.RollbackCalls(), 1)
+		})
+	}
+}
+
+func TestService_CreateMany(t *testing.T) {
+	valid := domain.Salary{Name: "Anurag", Salary: 90000, Currency: "USD", Department: "Banking", SubDepartment: "Loan"}
+	tests := []struct {
+		name          string
+		salaries      []domain.Salary
+		partial       bool
+		wantStatuses  []string
+		wantSucceeded int
+		wantCommit    bool
+	}{
+		{
+			name:          "creates every salary",
+			salaries:      []domain.Salary{valid, valid},
+			wantStatuses:  []string{api.BatchStatusCreated, api.BatchStatusCreated},
+			wantSucceeded: 2,
+			wantCommit:    true,
+		},
+		{
+			name:         "invalid salary rejects the batch",
+			salaries:     []domain.Salary{valid, {Name: "Yatendra"}},
+			wantStatuses: []string{api.BatchStatusSkipped, api.BatchStatusFailed},
+		},
+		{
+			name:          "partial batch creates the valid salaries",
+			salaries:      []domain.Salary{valid, {Name: "Yatendra"}},
+			partial:       true,
+			wantStatuses:  []string{api.BatchStatusCreated, api.BatchStatusFailed},
+			wantSucceeded: 1,
+			wantCommit:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := int64(0)
+			batch := &db.SalaryBatchMock{
+				AddFunc: func(ctx context.Context, salary *domain.Salary) error {
+					id++
+					salary.ID = id
+					return nil
+				},
+				CommitFunc: func() error {
+					return nil
+				},
+				RollbackFunc: func() error {
+					return nil
+				},
+			}
+			salaryService := service.NewSalaryService(&repository.SalaryRepositoryMock{
+				BatchFunc: func(ctx context.Context) (db.SalaryBatch, error) {
+					return batch, nil
+				},
+			}, getTestLogger())
+
+			result, err := salaryService.CreateMany(context.Background(), tt.salaries, tt.partial)
+			require.NoError(t, err)
+
+			var statuses []string
+			for _, item := range result.Items {
+				statuses = append(statuses, item.Status)
+				assert.Equal(t, item.Status == api.BatchStatusCreated, item.ID != 0)
+			}
+			assert.Equal(t, tt.wantStatuses, statuses)
+			assert.Equal(t, tt.wantSucceeded, result.Succeeded)
+			assert.Equal(t, tt.wantCommit, len(batch.CommitCalls()) == 1)
+		})
+	}
+}
+
+func TestService_DeleteMany(t *testing.T) {
+	tests := []struct {
+		name          string
+		ids           []int64
+		partial       bool
+		wantStatuses  []string
+		wantSucceeded int
+		wantCommit    bool
+	}{
+		{
+			name:          "deletes every salary",
+			ids:           []int64{1, 2},
+			wantStatuses:  []string{api.BatchStatusDeleted, api.BatchStatusDeleted},
+			wantSucceeded: 2,
+			wantCommit:    true,
+		},
+		{
+			name:         "missing salary rolls the batch back",
+			ids:          []int64{1, 404},
+			wantStatuses: []string{api.BatchStatusSkipped, api.BatchStatusFailed},
+		},
+		{
+			name:          "partial batch deletes the existing salaries",
+			ids:           []int64{1, 404},
+			partial:       true,
+			wantStatuses:  []string{api.BatchStatusDeleted, api.BatchStatusFailed},
+			wantSucceeded: 1,
+			wantCommit:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batch := &db.SalaryBatchMock{
+				DeleteFunc: func(ctx context.Context, salaryID int64) error {
+					if salaryID == 404 {
+						return api.ErrNotFound
+					}
+					return nil
+				},
+				CommitFunc: func() error {
+					return nil
+				},
+				RollbackFunc: func() error {
+					return nil
+				},
+			}
+			salaryService := service.NewSalaryService(&repository.SalaryRepositoryMock{
+				BatchFunc: func(ctx context.Context) (db.SalaryBatch, error) {
+					return batch, nil
+				},
+			}, getTestLogger())
+
+			result, err := salaryService.DeleteMany(context.Background(), tt.ids, tt.partial)
+			require.NoError(t, err)
+
+			var statuses []string
+			for i, item := range result.Items {
+				statuses = append(statuses, item.Status)
+				assert.Equal(t, tt.ids[i], item.ID)
+			}
+			assert.Equal(t, tt.wantStatuses, statuses)
+			assert.Equal(t, tt.wantSucceeded, result.Succeeded)
+			assert.Equal(t, tt.wantCommit, len(batch.CommitCalls()) == 1)
+		})
+	}
+}
+
+func TestService_DeleteMatchingRejectsLargeBatches(t *testing.T) {
+	salaryRepository := &repository.SalaryRepositoryMock{
+		ReadEachFunc: func(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary) error) error {
+			for id := int64(1); id <= 3; id++ {
+				if err := each(&domain.Salary{ID: id}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	salaryService := service.NewSalaryService(salaryRepository, getTestLogger())
+
+	_, err := salaryService.DeleteMatching(context.Background(), api.SalaryFilter{Department: "Banking"}, 2, false)
+
+	assert.ErrorIs(t, err, api.ErrBatchTooLarge)
+	assert.Empty(t, salaryRepository.BatchCalls())
+}
+
+func TestService_GetHistogram(t *testing.T) {
+	salaries := []domain.Salary{
+		{Salary: 30000, Currency: "USD", Department: "Banking"},
+		{Salary: 50000, Currency: "EUR", Department: "Engineering"},
+		{Salary: 90000, Currency: "USD", Department: "Engineering"},
+	}
+	salaryRepository := &repository.SalaryRepositoryMock{
+		ReadEachFunc: func(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary) error) error {
+			for i := range salaries {
+				if err := each(&salaries[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	salaryService := service.NewSalaryService(salaryRepository, getTestLogger(), service.WithExchangeRates(currency.Rates{"USD": 1, "EUR": 1.2}))
+
+	histogram, err := salaryService.GetHistogram(context.Background(), api.HistogramQuery{
+		Strategy:  "edges",
+		Edges:     "0,50000,100000",
+		GroupBy:   api.GroupByDepartment,
+		ConvertTo: "usd",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "USD", histogram.Currency)
+	assert.Equal(t, []float64{0, 50000, 100000}, histogram.Edges)
+	assert.Equal(t, []api.HistogramGroup{
+		{Group: "Banking", Count: 1, Counts: []int64{1, 0}},
+		{Group: "Engineering", Count: 2, Counts: []int64{0, 2}},
+	}, histogram.Groups)
+
+	histogram, err = salaryService.GetHistogram(context.Background(), api.HistogramQuery{Buckets: 2})
+	require.NoError(t, err)
+	assert.Equal(t, "fixed_count", histogram.Strategy)
+	assert.Equal(t, []float64{30000, 60000, 90000}, histogram.Edges)
+	assert.Equal(t, []api.HistogramGroup{{Count: 3, Counts: []int64{2, 1}}}, histogram.Groups)
+
+	_, err = salaryService.GetHistogram(context.Background(), api.HistogramQuery{ConvertTo: "GBP"})
+	assert.ErrorIs(t, err, currency.ErrUnknownCurrency)
+
+	_, err = salaryService.GetHistogram(context.Background(), api.HistogramQuery{GroupBy: "name"})
+	assert.ErrorIs(t, err, api.ErrInvalidQuery)
+
+	_, err = salaryService.GetHistogram(context.Background(), api.HistogramQuery{Strategy: "log", Buckets: 5000})
+	assert.ErrorIs(t, err, api.ErrInvalidQuery)
+}
+
+func TestService_GetAnomalies(t *testing.T) {
+	salaries := []domain.Salary{
+		{ID: 1, Salary: 90000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"},
+		{ID: 2, Salary: 95000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"},
+		{ID: 3, Salary: 100000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"},
+		{ID: 4, Salary: 105000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"},
+		{ID: 5, Salary: 110000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"},
+		{ID: 6, Salary: 1000000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"},
+		{ID: 7, Salary: 90000, Currency: "INR", Department: "Engineering", SubDepartment: "Platform"},
+		{ID: 8, Salary: 5000000, Currency: "USD", Department: "Banking", SubDepartment: "Loan"},
+	}
+	salaryRepository := &repository.SalaryRepositoryMock{
+		ReadEachFunc: func(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary) error) error {
+			for i := range salaries {
+				if err := each(&salaries[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	salaryService := service.NewSalaryService(salaryRepository, getTestLogger(), service.WithExchangeRates(currency.Rates{"USD": 1, "INR": 0.012}))
+
+	report, err := salaryService.GetAnomalies(context.Background(), api.AnomalyQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, "iqr", report.Method)
+	assert.Equal(t, 1.5, report.Threshold)
+	require.Len(t, report.Anomalies, 1, "the INR and Banking groups are too small")
+	assert.Equal(t, int64(6), report.Anomalies[0].SalaryID)
+	assert.Equal(t, api.AnomalyDirectionHigh, report.Anomalies[0].Direction)
+	assert.InDelta(t, 127500, report.Anomalies[0].High, 1e-9)
+	assert.Equal(t, 6, report.Anomalies[0].Peers)
+
+	report, err = salaryService.GetAnomalies(context.Background(), api.AnomalyQuery{ConvertTo: "usd"})
+	require.NoError(t, err)
+	require.Len(t, report.Anomalies, 2)
+	assert.Equal(t, int64(6), report.Anomalies[0].SalaryID)
+	assert.Equal(t, int64(7), report.Anomalies[1].SalaryID, "an INR amount entered in USD")
+	assert.Equal(t, api.AnomalyDirectionLow, report.Anomalies[1].Direction)
+	assert.Equal(t, "USD", report.Anomalies[1].Currency)
+
+	report, err = salaryService.GetAnomalies(context.Background(), api.AnomalyQuery{Method: "zscore", Threshold: 3})
+	require.NoError(t, err)
+	assert.Empty(t, report.Anomalies, "the outlier inflates the standard deviation of a small group")
+
+	_, err = salaryService.GetAnomalies(context.Background(), api.AnomalyQuery{Method: "grubbs"})
+	assert.ErrorIs(t, err, api.ErrInvalidQuery)
+}
+
+func TestService_CreateWarnsAboutOutliers(t *testing.T) {
+	peers := []domain.Salary{
+		{ID: 1, Salary: 90000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"},
+		{ID: 2, Salary: 95000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"},
+		{ID: 3, Salary: 100000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"},
+		{ID: 4, Salary: 105000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"},
+		{ID: 5, Salary: 110000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"},
+		{ID: 6, Salary: 90000, Currency: "INR", Department: "Engineering", SubDepartment: "Platform"},
+	}
+	salaryRepository := &repository.SalaryRepositoryMock{
+		CreateFunc: func(ctx context.Context, salary *domain.Salary) (*domain.Salary, error) {
+			salary.ID = 7
+			return salary, nil
+		},
+		ReadEachFunc: func(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary) error) error {
+			assert.Equal(t, api.SalaryFilter{Department: "Engineering", SubDepartment: "Platform"}, filter)
+			for i := range peers {
+				if err := each(&peers[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	salaryService := service.NewSalaryService(salaryRepository, getTestLogger(),
+		service.WithAnomalyWarnings(distribution.Detector{Method: distribution.MethodIQR}, 5))
+
+	warnings, err := salaryService.Create(context.Background(), &domain.Salary{Name: "Anurag", Salary: 1000000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"})
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, int64(7), warnings[0].SalaryID)
+	assert.Equal(t, 5, warnings[0].Peers, "the INR peer can not be converted")
+
+	warnings, err = salaryService.Create(context.Background(), &domain.Salary{Name: "Anurag", Salary: 100000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"})
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+}
+
+func TestService_Simulate(t *testing.T) {
+	salaries := []domain.Salary{
+		{ID: 1, Salary: 100000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"},
+		{ID: 2, Salary: 100000, Currency: "USD", OnContract: true, Department: "Engineering", SubDepartment: "Platform"},
+		{ID: 3, Salary: 50000, Currency: "EUR", OnContract: true, Department: "Banking", SubDepartment: "Loan"},
+		{ID: 4, Salary: 200000, Currency: "USD", Department: "Banking", SubDepartment: "Loan"},
+	}
+	salaryRepository := &repository.SalaryRepositoryMock{
+		ReadEachFunc: func(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary) error) error {
+			for i := range salaries {
+				if err := each(&salaries[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	salaryService := service.NewSalaryService(salaryRepository, getTestLogger(), service.WithExchangeRates(currency.Rates{"USD": 1, "EUR": 1.25}))
+	onContract := true
+
+	simulation, err := salaryService.Simulate(context.Background(), api.SimulationRequest{
+		Rules: []api.SimulationRule{
+			{Filter: api.SalaryFilter{Department: "Engineering"}, Percentage: 5},
+			{Filter: api.SalaryFilter{OnContract: &onContract}, Percentage: 3, MaxIncrease: 2000, Currency: "USD"},
+			{Filter: api.SalaryFilter{Department: "Banking"}, Amount: 10000, MaxSalary: 205000},
+		},
+		ConvertTo: "USD",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 4, simulation.Raised)
+	require.Len(t, simulation.Salaries, 4)
+	assert.InDelta(t, 105000, simulation.Salaries[0].After, 1e-9)
+	assert.Equal(t, []int{0}, simulation.Salaries[0].Rules)
+	assert.InDelta(t, 107000, simulation.Salaries[1].After, 1e-9, "the contract raise is capped after the 5% raise")
+	assert.InDelta(t, 50000+1500+10000, simulation.Salaries[2].After, 1e-9, "capped at 2000 USD in EUR")
+	assert.InDelta(t, 205000, simulation.Salaries[3].After, 1e-9, "capped at the maximum salary")
+	assert.InDelta(t, 5000+7000+11500*1.25+5000, simulation.Increase, 1e-9)
+
+	assert.Equal(t, int64(4), simulation.Before.Overall.Count)
+	assert.InDelta(t, 62500, simulation.Before.Overall.Min, 1e-9)
+	assert.InDelta(t, (62500+200000)/2.0, simulation.Before.Departments[0].Stats.Mean, 1e-9)
+	assert.Equal(t, "Banking", simulation.After.Departments[0].Department)
+	assert.InDelta(t, (61500*1.25+205000)/2, simulation.After.Departments[0].Stats.Mean, 1e-9)
+	assert.Equal(t, "Platform", simulation.After.SubDepartments[1].SubDepartment)
+	assert.InDelta(t, 107000, simulation.After.SubDepartments[1].DepartmentStats.Stats.Max, 1e-9)
+	assert.Equal(t, 100000.0, salaries[0].Salary, "salaries are not changed")
+
+	_, err = salaryService.Simulate(context.Background(), api.SimulationRequest{Rules: []api.SimulationRule{{Percentage: 5, Amount: 1000}}})
+	assert.ErrorIs(t, err, api.ErrInvalidQuery)
+	_, err = salaryService.Simulate(context.Background(), api.SimulationRequest{})
+	assert.ErrorIs(t, err, api.ErrInvalidQuery)
+	_, err = salaryService.Simulate(context.Background(), api.SimulationRequest{Rules: []api.SimulationRule{{Amount: 1000, Currency: "GBP"}}})
+	assert.ErrorIs(t, err, currency.ErrUnknownCurrency)
+}
+
+func TestService_GetRank(t *testing.T) {
+	salaries := []domain.Salary{
+		{Salary: 40000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"},
+		{Salary: 50000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"},
+		{Salary: 60000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"},
+		{Salary: 50000, Currency: "EUR", Department: "Engineering", SubDepartment: "Platform"},
+		{Salary: 70000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"},
+		{Salary: 80000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"},
+		{Salary: 90000, Currency: "USD", Department: "Banking", SubDepartment: "Loan"},
+	}
+	salaryRepository := &repository.SalaryRepositoryMock{
+		ReadEachFunc: func(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary) error) error {
+			for i := range salaries {
+				if !filter.Matches(&salaries[i]) {
+					continue
+				}
+				if err := each(&salaries[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	salaryService := service.NewSalaryService(salaryRepository, getTestLogger(), service.WithExchangeRates(currency.Rates{"USD": 1, "EUR": 1.2}))
+
+	rank, err := salaryService.GetRank(context.Background(), api.RankQuery{Amount: 60000, Currency: "usd", Department: "Engineering", SubDepartment: "Platform"})
+	require.NoError(t, err)
+	assert.Equal(t, "USD", rank.Currency)
+	assert.Equal(t, int64(6), rank.Count)
+	assert.InDelta(t, 50, rank.PercentileRank, 1e-9, "two salaries below and two equal once the EUR salary is converted")
+	assert.Equal(t, &api.PercentileBoundary{Percentile: 50, Value: 60000}, rank.Lower)
+	assert.Equal(t, &api.PercentileBoundary{Percentile: 75, Value: 67500}, rank.Upper)
+
+	rank, err = salaryService.GetRank(context.Background(), api.RankQuery{Amount: 10000, Currency: "USD", Department: "Engineering"})
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, rank.PercentileRank)
+	assert.Nil(t, rank.Lower)
+	assert.Equal(t, 10, rank.Upper.Percentile)
+
+	_, err = salaryService.GetRank(context.Background(), api.RankQuery{Amount: 60000, Currency: "USD", Department: "Banking"})
+	assert.ErrorIs(t, err, api.ErrGroupTooSmall)
+
+	_, err = service.NewSalaryService(salaryRepository, getTestLogger(), service.WithRankMinGroupSize(1)).
+		GetRank(context.Background(), api.RankQuery{Amount: 60000, Currency: "USD", Department: "Banking"})
+	assert.NoError(t, err)
+
+	_, err = salaryService.GetRank(context.Background(), api.RankQuery{Amount: 60000, Currency: "GBP", Department: "Engineering"})
+	assert.ErrorIs(t, err, currency.ErrUnknownCurrency)
+
+	_, err = salaryService.GetRank(context.Background(), api.RankQuery{Amount: -1, Currency: "USD"})
+	assert.ErrorIs(t, err, api.ErrInvalidQuery)
+
+	rank, err = salaryService.GetRank(context.Background(), api.RankQuery{Amount: 55000, Currency: "USD", Department: "Engineering"})
+	require.NoError(t, err)
+	assert.Equal(t, 30.0, rank.PercentileRank, "two salaries of six below are rounded to tens")
+
+	rank, err = salaryService.GetRank(context.Background(), api.RankQuery{Amount: 72000, Currency: "USD", SubDepartment: "Platform"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), rank.Count)
+	assert.Equal(t, &api.PercentileBoundary{Percentile: 75, Value: 67500}, rank.Lower)
+	assert.Equal(t, &api.PercentileBoundary{Percentile: 90, Value: 75000}, rank.Upper)
+
+	salaries = salaries[1:]
+	rank, err = salaryService.GetRank(context.Background(), api.RankQuery{Amount: 62000, Currency: "USD", Department: "Engineering"})
+	require.NoError(t, err)
+	assert.Equal(t, &api.PercentileBoundary{Percentile: 10, Value: 54000}, rank.Lower, "the 25th, 50th and 75th are single salaries of five")
+	assert.Equal(t, &api.PercentileBoundary{Percentile: 90, Value: 76000}, rank.Upper)
+
+	_, err = service.NewSalaryService(salaryRepository, getTestLogger(), service.WithExchangeRates(currency.Rates{"USD": 1, "EUR": 1.2}),
+		service.WithStatsPrivacy(service.StatsPrivacy{MinGroupSize: 6})).
+		GetRank(context.Background(), api.RankQuery{Amount: 60000, Currency: "USD", Department: "Engineering"})
+	assert.ErrorIs(t, err, api.ErrGroupTooSmall, "the stats privacy raises the rank min group size")
+
+	noisyService := service.NewSalaryService(salaryRepository, getTestLogger(), service.WithExchangeRates(currency.Rates{"USD": 1, "EUR": 1.2}),
+		service.WithStatsPrivacy(service.StatsPrivacy{Epsilon: 1, MinSalary: 0, MaxSalary: 100000}))
+	for i := 0; i < 20; i++ {
+		rank, err = noisyService.GetRank(context.Background(), api.RankQuery{Amount: 62000, Currency: "USD", Department: "Engineering"})
+		require.NoError(t, err)
+		assert.Zero(t, math.Mod(rank.PercentileRank, 10))
+		assert.GreaterOrEqual(t, rank.PercentileRank, 0.0)
+		assert.LessOrEqual(t, rank.PercentileRank, 100.0)
+		for _, boundary := range []*api.PercentileBoundary{rank.Lower, rank.Upper} {
+			if boundary != nil {
+				assert.GreaterOrEqual(t, boundary.Value, 0.0)
+				assert.LessOrEqual(t, boundary.Value, 100000.0)
+			}
+		}
+	}
+}
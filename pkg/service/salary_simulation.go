@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"salaries/pkg/api"
+	"salaries/pkg/domain"
+	"salaries/pkg/tracing"
+	"sort"
+	"strings"
+)
+
+// maxSimulationRules limits the rules of a simulation, every rule is checked against every salary
+const maxSimulationRules = 100
+
+// Simulate projects the salaries raised by the rules and the stats before and after the raises, nothing is written. The
+// stats go through the privacy of the stats endpoints
+func (s salaryServiceImpl) Simulate(ctx context.Context, request api.SimulationRequest) (*api.Simulation, error) {
+	ctx, span := tracer.Start(ctx, "SalaryService.Simulate")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("Simulating %d raise rules", len(request.Rules))
+	if err := request.Validate(maxSimulationRules); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+
+	simulation := &api.Simulation{
+		Currency: strings.ToUpper(request.ConvertTo),
+		Salaries: []api.SimulatedSalary{},
+	}
+	var salaries []domain.Salary
+	var before, after []float64
+	err := s.salaryRepository.ReadEach(ctx, api.SalaryFilter{}, func(salary *domain.Salary) error {
+		simulated, err := s.raise(salary, request.Rules)
+		if err != nil {
+			return err
+		}
+		beforeAmount, err := s.amount(salary, request.ConvertTo)
+		if err != nil {
+			return err
+		}
+		raised := *salary
+		raised.Salary = simulated.After
+		afterAmount, err := s.amount(&raised, request.ConvertTo)
+		if err != nil {
+			return err
+		}
+		if len(simulated.Rules) > 0 {
+			simulation.Raised++
+		}
+		simulation.Increase += afterAmount - beforeAmount
+		simulation.Salaries = append(simulation.Salaries, simulated)
+		salaries = append(salaries, *salary)
+		before = append(before, beforeAmount)
+		after = append(after, afterAmount)
+		return nil
+	})
+	if err != nil {
+		logger.Error("error simulating raises: %s", err.Error())
+		return nil, tracing.RecordError(span, err)
+	}
+	if s.privacy.tooSmall(int64(len(salaries))) {
+		return nil, tracing.RecordError(span, fmt.Errorf("%w: fewer than %d salaries", api.ErrGroupTooSmall, s.privacy.MinGroupSize))
+	}
+	simulation.Before = s.protectSimulationStats(ctx, simulationStats(salaries, before))
+	simulation.After = s.protectSimulationStats(ctx, simulationStats(salaries, after))
+	logger.Info("raises simulated for %d of %d salaries", simulation.Raised, len(salaries))
+	return simulation, nil
+}
+
+// raise applies the matching rules to the salary in order, amounts in another currency are converted to the salary's
+func (s salaryServiceImpl) raise(salary *domain.Salary, rules []api.SimulationRule) (api.SimulatedSalary, error) {
+	simulated := api.SimulatedSalary{
+		SalaryID:      salary.ID,
+		Name:          salary.Name,
+		Department:    salary.Department,
+		SubDepartment: salary.SubDepartment,
+		OnContract:    salary.OnContract,
+		Currency:      salary.Currency,
+		Before:        salary.Salary,
+		After:         salary.Salary,
+		Rules:         []int{},
+	}
+	for i, rule := range rules {
+		if !rule.Filter.Matches(salary) {
+			continue
+		}
+		convert := func(amount float64) (float64, error) {
+			if rule.Currency == "" || amount == 0 {
+				return amount, nil
+			}
+			return s.rates.Convert(amount, rule.Currency, salary.Currency)
+		}
+		increase := simulated.After * rule.Percentage / 100
+		if rule.Amount > 0 {
+			amount, err := convert(rule.Amount)
+			if err != nil {
+				return simulated, err
+			}
+			increase = amount
+		}
+		maxIncrease, err := convert(rule.MaxIncrease)
+		if err != nil {
+			return simulated, err
+		}
+		if maxIncrease > 0 {
+			increase = math.Min(increase, maxIncrease)
+		}
+		maxSalary, err := convert(rule.MaxSalary)
+		if err != nil {
+			return simulated, err
+		}
+		if maxSalary > 0 {
+			increase = math.Max(0, math.Min(increase, maxSalary-simulated.After))
+		}
+		simulated.After += increase
+		simulated.Rules = append(simulated.Rules, i)
+	}
+	simulated.Increase = simulated.After - simulated.Before
+	return simulated, nil
+}
+
+// simulationStats computes the stats of the amounts, amounts[i] being the amount of salaries[i]
+func simulationStats(salaries []domain.Salary, amounts []float64) api.SimulationStats {
+	type subDepartmentKey struct{ department, subDepartment string }
+	departments := map[string][]float64{}
+	subDepartments := map[subDepartmentKey][]float64{}
+	for i := range salaries {
+		departments[salaries[i].Department] = append(departments[salaries[i].Department], amounts[i])
+		key := subDepartmentKey{salaries[i].Department, salaries[i].SubDepartment}
+		subDepartments[key] = append(subDepartments[key], amounts[i])
+	}
+
+	stats := api.SimulationStats{
+		Overall:        statsOf(amounts),
+		Departments:    []api.DepartmentStats{},
+		SubDepartments: []api.SubDepartmentStats{},
+	}
+	for department, values := range departments {
+		stats.Departments = append(stats.Departments, api.DepartmentStats{Department: department, Stats: statsOf(values)})
+	}
+	sort.Slice(stats.Departments, func(i, j int) bool { return stats.Departments[i].Department < stats.Departments[j].Department })
+	for key, values := range subDepartments {
+		stats.SubDepartments = append(stats.SubDepartments, api.SubDepartmentStats{
+			SubDepartment:   key.subDepartment,
+			DepartmentStats: api.DepartmentStats{Department: key.department, Stats: statsOf(values)},
+		})
+	}
+	sort.Slice(stats.SubDepartments, func(i, j int) bool {
+		a, b := stats.SubDepartments[i], stats.SubDepartments[j]
+		if a.DepartmentStats.Department != b.DepartmentStats.Department {
+			return a.DepartmentStats.Department < b.DepartmentStats.Department
+		}
+		return a.SubDepartment < b.SubDepartment
+	})
+	return stats
+}
+
+// protectSimulationStats merges the small departments and sub-departments and adds noise like the stats endpoints
+func (s salaryServiceImpl) protectSimulationStats(ctx context.Context, stats api.SimulationStats) api.SimulationStats {
+	return api.SimulationStats{
+		Overall:        s.privacy.perturb(ctx, stats.Overall),
+		Departments:    s.privacy.perturbDepartments(ctx, s.privacy.departments(stats.Departments)),
+		SubDepartments: s.privacy.perturbSubDepartments(ctx, s.privacy.subDepartments(stats.SubDepartments)),
+	}
+}
+
+// statsOf computes the stats of the values like the database does, all zero when there are none
+func statsOf(values []float64) api.Stats {
+	if len(values) == 0 {
+		return api.Stats{}
+	}
+	stats := api.Stats{Min: values[0], Max: values[0], Count: int64(len(values))}
+	var sum float64
+	for _, value := range values {
+		stats.Min = math.Min(stats.Min, value)
+		stats.Max = math.Max(stats.Max, value)
+		sum += value
+	}
+	stats.Mean = sum / float64(len(values))
+	return stats
+}
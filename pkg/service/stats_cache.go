@@ -0,0 +1,476 @@
+package service
+
+import (
+	"context"
+	"math"
+	"salaries/pkg/api"
+	"salaries/pkg/domain"
+	"salaries/pkg/logger"
+	"salaries/pkg/repository"
+	"salaries/pkg/requestctx"
+	"sort"
+	"sync"
+	"time"
+)
+
+// statsTolerance is the relative difference of a mean, min or max the consistency check ignores, running sums drift
+// from the averages of the database in the last digits
+const statsTolerance = 1e-9
+
+// StatsCache keeps running aggregates of the salaries of every stats group so the stats endpoints don't scan the table.
+// It is built from the database on the first read, updated by the creations, updates and deletions of the SalaryService and
+// rebuilt after any other write. Requests with requestctx.CacheBypass and any read while the cache can not be trusted
+// are answered from the database
+type StatsCache struct {
+	salaryRepository repository.SalaryRepository
+	logger           logger.Logger
+
+	mu      sync.Mutex
+	loaded  bool
+	loading bool
+	// inflight counts the writes between their database change and their cache update and epoch the finished ones, the
+	// result of a scan is only kept when neither changed while it read the database
+	inflight int
+	epoch    int64
+	table    statsTable
+}
+
+// statsTable has the aggregates of the groups of the stats endpoints
+type statsTable struct {
+	all            aggregate
+	contract       aggregate
+	departments    map[string]*aggregate
+	subDepartments map[[2]string]*aggregate
+}
+
+// aggregate is the running count, sum, sum of squares, min and max of a group, min and max are stale after the deletion
+// of one of them until the group is read again from the database
+type aggregate struct {
+	count        int64
+	sum          float64
+	sumOfSquares float64
+	min          float64
+	max          float64
+	stale        bool
+}
+
+func NewStatsCache(salaryRepository repository.SalaryRepository, logger logger.Logger) *StatsCache {
+	return &StatsCache{
+		salaryRepository: salaryRepository,
+		logger:           logger,
+	}
+}
+
+// begin marks a write of the salaries as in flight, it must be followed by end or invalidate once the database has
+// been changed or the write failed
+func (c *StatsCache) begin() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.inflight++
+}
+
+// end applies the outcome of a write, removed and added are nil when the write did not remove or add a salary
+func (c *StatsCache) end(removed, added *domain.Salary) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.inflight--
+	c.epoch++
+	if !c.loaded {
+		return
+	}
+	if removed != nil {
+		c.table.remove(removed)
+	}
+	if added != nil {
+		c.table.add(added)
+	}
+}
+
+// invalidate ends a write whose changes are not known, the cache is rebuilt on the next read
+func (c *StatsCache) invalidate() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.inflight--
+	c.epoch++
+	c.loaded = false
+}
+
+func (c *StatsCache) overall(ctx context.Context) (*api.Stats, bool) {
+	var stats api.Stats
+	ok := c.read(ctx, func(table *statsTable) {
+		stats = table.all.stats()
+	})
+	return &stats, ok
+}
+
+func (c *StatsCache) contracts(ctx context.Context) (*api.Stats, bool) {
+	var stats api.Stats
+	ok := c.read(ctx, func(table *statsTable) {
+		stats = table.contract.stats()
+	})
+	return &stats, ok
+}
+
+// departments returns the stats ordered by department, nil without salaries like the database
+func (c *StatsCache) departments(ctx context.Context) ([]api.DepartmentStats, bool) {
+	var stats []api.DepartmentStats
+	ok := c.read(ctx, func(table *statsTable) {
+		for department, aggregate := range table.departments {
+			stats = append(stats, api.DepartmentStats{Department: department, Stats: aggregate.stats()})
+		}
+	})
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Department < stats[j].Department
+	})
+	return stats, ok
+}
+
+func (c *StatsCache) subDepartments(ctx context.Context) ([]api.SubDepartmentStats, bool) {
+	var stats []api.SubDepartmentStats
+	ok := c.read(ctx, func(table *statsTable) {
+		for key, aggregate := range table.subDepartments {
+			stats = append(stats, api.SubDepartmentStats{
+				SubDepartment:   key[1],
+				DepartmentStats: api.DepartmentStats{Department: key[0], Stats: aggregate.stats()},
+			})
+		}
+	})
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].DepartmentStats.Department != stats[j].DepartmentStats.Department {
+			return stats[i].DepartmentStats.Department < stats[j].DepartmentStats.Department
+		}
+		return stats[i].SubDepartment < stats[j].SubDepartment
+	})
+	return stats, ok
+}
+
+// read calls view with the aggregates once they are loaded and none is stale, it returns false when the request must be
+// answered from the database
+func (c *StatsCache) read(ctx context.Context, view func(table *statsTable)) bool {
+	if c == nil || requestctx.CacheBypass(ctx) {
+		return false
+	}
+	if !c.refresh(ctx) {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.loaded || c.table.hasStale() {
+		return false
+	}
+	view(&c.table)
+	return true
+}
+
+// refresh loads the aggregates when the cache is empty and reads the stale groups again, a single goroutine refreshes
+// at a time and the others use the database meanwhile
+func (c *StatsCache) refresh(ctx context.Context) bool {
+	c.mu.Lock()
+	if c.loaded && !c.table.hasStale() {
+		c.mu.Unlock()
+		return true
+	}
+	if c.loading || c.inflight > 0 {
+		c.mu.Unlock()
+		return false
+	}
+	c.loading = true
+	loaded, epoch := c.loaded, c.epoch
+	var stale []api.SalaryFilter
+	if loaded {
+		stale = c.table.staleFilters()
+	}
+	c.mu.Unlock()
+
+	logger := c.logger.WithContext(ctx)
+	if !loaded {
+		logger.Info("loading the stats cache")
+		stale = []api.SalaryFilter{{}}
+	}
+	tables := make([]*statsTable, len(stale))
+	var err error
+	for i, filter := range stale {
+		if tables[i], err = c.scan(ctx, filter); err != nil {
+			break
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.loading = false
+	if err != nil {
+		logger.Error("error loading the stats cache: %s", err.Error())
+		return false
+	}
+	if c.epoch != epoch || c.inflight > 0 || c.loaded != loaded {
+		return false
+	}
+	if !loaded {
+		c.table = *tables[0]
+		c.loaded = true
+		return true
+	}
+	for i, filter := range stale {
+		c.table.replace(filter, tables[i])
+	}
+	return true
+}
+
+func (c *StatsCache) scan(ctx context.Context, filter api.SalaryFilter) (*statsTable, error) {
+	table := newStatsTable()
+	err := c.salaryRepository.ReadEach(ctx, filter, func(salary *domain.Salary) error {
+		table.add(salary)
+		return nil
+	})
+	return table, err
+}
+
+// Check compares the aggregates with the stats of the database and drops the cache when they differ, a write made
+// outside of the service like the salariesctl tools leaves the cache wrong until then. Checks overlapping a write of the
+// service are skipped and reported consistent
+func (c *StatsCache) Check(ctx context.Context) (bool, error) {
+	c.mu.Lock()
+	loaded, epoch := c.loaded, c.epoch
+	skip := !loaded || c.inflight > 0
+	c.mu.Unlock()
+	if skip {
+		return true, nil
+	}
+
+	expected := newStatsTable()
+	all, err := c.salaryRepository.GetStatsForAllSalaries(ctx)
+	if err != nil {
+		return false, err
+	}
+	expected.all = aggregateOf(*all)
+	contract, err := c.salaryRepository.GetContractsStats(ctx)
+	if err != nil {
+		return false, err
+	}
+	expected.contract = aggregateOf(*contract)
+	departments, err := c.salaryRepository.GetDepartmentsStats(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, department := range departments {
+		aggregate := aggregateOf(department.Stats)
+		expected.departments[department.Department] = &aggregate
+	}
+	subDepartments, err := c.salaryRepository.GetSubDepartmentsStats(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, subDepartment := range subDepartments {
+		aggregate := aggregateOf(subDepartment.DepartmentStats.Stats)
+		expected.subDepartments[[2]string{subDepartment.DepartmentStats.Department, subDepartment.SubDepartment}] = &aggregate
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.loaded || c.epoch != epoch || c.inflight > 0 {
+		return true, nil
+	}
+	if c.table.matches(expected) {
+		return true, nil
+	}
+	c.logger.WithContext(ctx).Warn("the stats cache differs from the database, it is loaded again on the next read")
+	c.loaded = false
+	return false, nil
+}
+
+// Run checks the cache every interval until the context is done
+func (c *StatsCache) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := c.Check(ctx); err != nil {
+				c.logger.WithContext(ctx).Error("error checking the stats cache: %s", err.Error())
+			}
+		}
+	}
+}
+
+func newStatsTable() *statsTable {
+	return &statsTable{
+		departments:    map[string]*aggregate{},
+		subDepartments: map[[2]string]*aggregate{},
+	}
+}
+
+func (t *statsTable) add(salary *domain.Salary) {
+	t.all.add(salary.Salary)
+	if salary.OnContract {
+		t.contract.add(salary.Salary)
+	}
+	department := t.departments[salary.Department]
+	if department == nil {
+		department = &aggregate{}
+		t.departments[salary.Department] = department
+	}
+	department.add(salary.Salary)
+	key := [2]string{salary.Department, salary.SubDepartment}
+	subDepartment := t.subDepartments[key]
+	if subDepartment == nil {
+		subDepartment = &aggregate{}
+		t.subDepartments[key] = subDepartment
+	}
+	subDepartment.add(salary.Salary)
+}
+
+// remove takes the salary out of its groups, the groups left empty are dropped like they are missing from the database
+func (t *statsTable) remove(salary *domain.Salary) {
+	t.all.remove(salary.Salary)
+	if salary.OnContract {
+		t.contract.remove(salary.Salary)
+	}
+	if department := t.departments[salary.Department]; department != nil {
+		if department.remove(salary.Salary); department.count == 0 {
+			delete(t.departments, salary.Department)
+		}
+	}
+	key := [2]string{salary.Department, salary.SubDepartment}
+	if subDepartment := t.subDepartments[key]; subDepartment != nil {
+		if subDepartment.remove(salary.Salary); subDepartment.count == 0 {
+			delete(t.subDepartments, key)
+		}
+	}
+}
+
+func (t *statsTable) hasStale() bool {
+	return len(t.staleFilters()) > 0
+}
+
+// staleFilters returns the filters reading the salaries of the stale groups
+func (t *statsTable) staleFilters() []api.SalaryFilter {
+	var filters []api.SalaryFilter
+	if t.all.stale {
+		// the whole dataset is read again, which refreshes every group
+		return []api.SalaryFilter{{}}
+	}
+	if t.contract.stale {
+		onContract := true
+		filters = append(filters, api.SalaryFilter{OnContract: &onContract})
+	}
+	for department, aggregate := range t.departments {
+		if aggregate.stale {
+			filters = append(filters, api.SalaryFilter{Department: department})
+		}
+	}
+	for key, aggregate := range t.subDepartments {
+		if department := t.departments[key[0]]; aggregate.stale && (department == nil || !department.stale) {
+			filters = append(filters, api.SalaryFilter{Department: key[0], SubDepartment: key[1]})
+		}
+	}
+	return filters
+}
+
+// replace sets the groups read with the filter to the ones of the table, the groups missing from it are dropped
+func (t *statsTable) replace(filter api.SalaryFilter, table *statsTable) {
+	switch {
+	case filter.OnContract != nil:
+		t.contract = table.contract
+	case filter.Department == "" && filter.SubDepartment == "":
+		*t = *table
+	case filter.SubDepartment == "":
+		delete(t.departments, filter.Department)
+		if department := table.departments[filter.Department]; department != nil {
+			t.departments[filter.Department] = department
+		}
+		for key := range t.subDepartments {
+			if key[0] == filter.Department {
+				delete(t.subDepartments, key)
+			}
+		}
+		for key, subDepartment := range table.subDepartments {
+			t.subDepartments[key] = subDepartment
+		}
+	default:
+		key := [2]string{filter.Department, filter.SubDepartment}
+		delete(t.subDepartments, key)
+		if subDepartment := table.subDepartments[key]; subDepartment != nil {
+			t.subDepartments[key] = subDepartment
+		}
+	}
+}
+
+// matches compares the groups of the tables, the min and max of stale groups are not compared
+func (t *statsTable) matches(expected *statsTable) bool {
+	if !t.all.matches(expected.all) || !t.contract.matches(expected.contract) {
+		return false
+	}
+	if len(t.departments) != len(expected.departments) || len(t.subDepartments) != len(expected.subDepartments) {
+		return false
+	}
+	for department, aggregate := range t.departments {
+		if other, ok := expected.departments[department]; !ok || !aggregate.matches(*other) {
+			return false
+		}
+	}
+	for key, aggregate := range t.subDepartments {
+		if other, ok := expected.subDepartments[key]; !ok || !aggregate.matches(*other) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *aggregate) add(value float64) {
+	if a.count == 0 || value < a.min {
+		a.min = value
+	}
+	if a.count == 0 || value > a.max {
+		a.max = value
+	}
+	a.count++
+	a.sum += value
+	a.sumOfSquares += value * value
+}
+
+func (a *aggregate) remove(value float64) {
+	a.count--
+	a.sum -= value
+	a.sumOfSquares -= value * value
+	if a.count <= 0 {
+		*a = aggregate{}
+		return
+	}
+	if value <= a.min || value >= a.max {
+		a.stale = true
+	}
+}
+
+// stats has the fields of the stats of the database, which are all 0 for an empty group
+func (a aggregate) stats() api.Stats {
+	if a.count == 0 {
+		return api.Stats{}
+	}
+	return api.Stats{Mean: a.sum / float64(a.count), Max: a.max, Min: a.min, Count: a.count, SumOfSquares: a.sumOfSquares}
+}
+
+func (a aggregate) matches(expected aggregate) bool {
+	if a.count != expected.count || !nearlyEqual(a.stats().Mean, expected.stats().Mean) || !nearlyEqual(a.sumOfSquares, expected.sumOfSquares) {
+		return false
+	}
+	return a.stale || (nearlyEqual(a.min, expected.min) && nearlyEqual(a.max, expected.max))
+}
+
+func aggregateOf(stats api.Stats) aggregate {
+	return aggregate{count: stats.Count, sum: stats.Mean * float64(stats.Count), sumOfSquares: stats.SumOfSquares, min: stats.Min, max: stats.Max}
+}
+
+func nearlyEqual(a, b float64) bool {
+	return math.Abs(a-b) <= statsTolerance*math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+}
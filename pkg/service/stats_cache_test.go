@@ -0,0 +1,218 @@
+package service_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"salaries/pkg/api"
+	"salaries/pkg/domain"
+	"salaries/pkg/repository"
+	"salaries/pkg/requestctx"
+	"salaries/pkg/service"
+	"testing"
+)
+
+// newStatsRepository keeps the salaries in memory and computes the stats of the database from them
+func newStatsRepository(salaries *[]domain.Salary) *repository.SalaryRepositoryMock {
+	statsOf := func(keep func(salary *domain.Salary) bool) api.Stats {
+		var stats api.Stats
+		var sum float64
+		for i := range *salaries {
+			salary := (*salaries)[i]
+			if !keep(&salary) {
+				continue
+			}
+			if stats.Count == 0 || salary.Salary < stats.Min {
+				stats.Min = salary.Salary
+			}
+			if stats.Count == 0 || salary.Salary > stats.Max {
+				stats.Max = salary.Salary
+			}
+			stats.Count++
+			sum += salary.Salary
+			stats.SumOfSquares += salary.Salary * salary.Salary
+		}
+		if stats.Count > 0 {
+			stats.Mean = sum / float64(stats.Count)
+		}
+		return stats
+	}
+	lastID := int64(len(*salaries))
+	return &repository.SalaryRepositoryMock{
+		CreateFunc: func(ctx context.Context, salary *domain.Salary) (*domain.Salary, error) {
+			lastID++
+			salary.ID = lastID
+			*salaries = append(*salaries, *salary)
+			return salary, nil
+		},
+		DeleteByIDFunc: func(ctx context.Context, salaryID int64, version int64) (*domain.Salary, error) {
+			for i, salary := range *salaries {
+				if salary.ID == salaryID {
+					*salaries = append((*salaries)[:i], (*salaries)[i+1:]...)
+					return &salary, nil
+				}
+			}
+			return nil, api.ErrNotFound
+		},
+		UpdateFunc: func(ctx context.Context, salary *domain.Salary, version int64) (*domain.Salary, error) {
+			for i := range *salaries {
+				if (*salaries)[i].ID == salary.ID {
+					before := (*salaries)[i]
+					(*salaries)[i] = *salary
+					return &before, nil
+				}
+			}
+			return nil, api.ErrNotFound
+		},
+		ReadEachFunc: func(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary) error) error {
+			for i := range *salaries {
+				salary := (*salaries)[i]
+				if filter.Matches(&salary) {
+					if err := each(&salary); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+		GetStatsForAllSalariesFunc: func(ctx context.Context) (*api.Stats, error) {
+			stats := statsOf(func(salary *domain.Salary) bool { return true })
+			return &stats, nil
+		},
+		GetContractsStatsFunc: func(ctx context.Context) (*api.Stats, error) {
+			stats := statsOf(func(salary *domain.Salary) bool { return salary.OnContract })
+			return &stats, nil
+		},
+		GetDepartmentsStatsFunc: func(ctx context.Context) ([]api.DepartmentStats, error) {
+			var departmentsStats []api.DepartmentStats
+			for _, department := range []string{"Banking", "Engineering"} {
+				stats := statsOf(func(salary *domain.Salary) bool { return salary.Department == department })
+				if stats.Count > 0 {
+					departmentsStats = append(departmentsStats, api.DepartmentStats{Department: department, Stats: stats})
+				}
+			}
+			return departmentsStats, nil
+		},
+		GetSubDepartmentsStatsFunc: func(ctx context.Context) ([]api.SubDepartmentStats, error) {
+			var subDepartmentsStats []api.SubDepartmentStats
+			for _, key := range [][2]string{{"Banking", "Loan"}, {"Engineering", "Platform"}} {
+				stats := statsOf(func(salary *domain.Salary) bool { return salary.Department == key[0] && salary.SubDepartment == key[1] })
+				if stats.Count > 0 {
+					subDepartmentsStats = append(subDepartmentsStats, api.SubDepartmentStats{SubDepartment: key[1], DepartmentStats: api.DepartmentStats{Department: key[0], Stats: stats}})
+				}
+			}
+			return subDepartmentsStats, nil
+		},
+	}
+}
+
+func TestStatsCache(t *testing.T) {
+	salaries := []domain.Salary{
+		{ID: 1, Salary: 90000, Currency: "USD", OnContract: true, Department: "Banking", SubDepartment: "Loan"},
+		{ID: 2, Salary: 240000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"},
+		{ID: 3, Salary: 150000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"},
+		{ID: 4, Salary: 120000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"},
+	}
+	salaryRepository := newStatsRepository(&salaries)
+	cache := service.NewStatsCache(salaryRepository, getTestLogger())
+	salaryService := service.NewSalaryService(salaryRepository, getTestLogger(), service.WithStatsCache(cache))
+	ctx := context.Background()
+
+	// the cached stats are the ones of the database, which are only read to compare
+	assertStats := func() {
+		t.Helper()
+		stats, err := salaryService.GetStatsForAllSalaries(ctx)
+		require.NoError(t, err)
+		expected, _ := salaryRepository.GetStatsForAllSalaries(ctx)
+		assert.InDelta(t, expected.Mean, stats.Mean, 1e-6)
+		assert.Equal(t, expected.Count, stats.Count)
+		assert.Equal(t, expected.Min, stats.Min)
+		assert.Equal(t, expected.Max, stats.Max)
+		contractsStats, err := salaryService.GetContractsStats(ctx)
+		require.NoError(t, err)
+		expected, _ = salaryRepository.GetContractsStats(ctx)
+		assert.Equal(t, *expected, *contractsStats)
+		departmentsStats, err := salaryService.GetDepartmentsStats(ctx)
+		require.NoError(t, err)
+		expectedDepartments, _ := salaryRepository.GetDepartmentsStats(ctx)
+		assert.Equal(t, expectedDepartments, departmentsStats)
+		subDepartmentsStats, err := salaryService.GetSubDepartmentsStats(ctx)
+		require.NoError(t, err)
+		expectedSubDepartments, _ := salaryRepository.GetSubDepartmentsStats(ctx)
+		assert.Equal(t, expectedSubDepartments, subDepartmentsStats)
+	}
+	scans := func() int {
+		return len(salaryRepository.ReadEachCalls())
+	}
+
+	assertStats()
+	assert.Equal(t, 1, scans(), "loaded on the first read")
+
+	_, err := salaryService.Create(ctx, &domain.Salary{Salary: 200000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"})
+	require.NoError(t, err)
+	require.NoError(t, salaryService.DeleteByID(ctx, 3, 0))
+	assertStats()
+	assert.Equal(t, 1, scans(), "creations and deletions are applied to the cache")
+
+	require.NoError(t, salaryService.DeleteByID(ctx, 2, 0))
+	assertStats()
+	require.Equal(t, 2, scans(), "the max of the whole dataset was deleted")
+	assert.Equal(t, api.SalaryFilter{}, salaryRepository.ReadEachCalls()[1].Filter)
+
+	_, err = salaryService.Create(ctx, &domain.Salary{Salary: 95000, Currency: "USD", Department: "Banking", SubDepartment: "Loan"})
+	require.NoError(t, err)
+	require.NoError(t, salaryService.DeleteByID(ctx, 6, 0))
+	assertStats()
+	require.Equal(t, 3, scans(), "the max of Banking was deleted")
+	assert.Equal(t, api.SalaryFilter{Department: "Banking"}, salaryRepository.ReadEachCalls()[2].Filter)
+
+	require.NoError(t, salaryService.Update(ctx, &domain.Salary{ID: 5, Salary: 210000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"}, 0))
+	assertStats()
+	require.Equal(t, 4, scans(), "the max of the whole dataset was updated")
+
+	_, err = salaryService.Create(ctx, &domain.Salary{Salary: 150000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"})
+	require.NoError(t, err)
+	require.NoError(t, salaryService.Update(ctx, &domain.Salary{ID: 7, Salary: 160000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"}, 0))
+	assertStats()
+	assert.Equal(t, 4, scans(), "updates are applied to the cache")
+
+	before := len(salaryRepository.GetStatsForAllSalariesCalls())
+	_, err = salaryService.GetStatsForAllSalaries(requestctx.WithCacheBypass(ctx))
+	require.NoError(t, err)
+	assert.Len(t, salaryRepository.GetStatsForAllSalariesCalls(), before+1, "bypassed")
+
+	consistent, err := cache.Check(ctx)
+	require.NoError(t, err)
+	assert.True(t, consistent)
+	salaries = append(salaries, domain.Salary{ID: 9, Salary: 50000, Currency: "USD", Department: "Banking", SubDepartment: "Loan"})
+	consistent, err = cache.Check(ctx)
+	require.NoError(t, err)
+	assert.False(t, consistent, "written outside of the service")
+	assertStats()
+	assert.Equal(t, 5, scans())
+}
+
+func TestStatsCache_CheckComparesSumOfSquares(t *testing.T) {
+	salaries := []domain.Salary{
+		{ID: 1, Salary: 100000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"},
+		{ID: 2, Salary: 200000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"},
+		{ID: 3, Salary: 200000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"},
+		{ID: 4, Salary: 300000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"},
+	}
+	salaryRepository := newStatsRepository(&salaries)
+	cache := service.NewStatsCache(salaryRepository, getTestLogger())
+	salaryService := service.NewSalaryService(salaryRepository, getTestLogger(), service.WithStatsCache(cache))
+	ctx := context.Background()
+
+	_, err := salaryService.GetStatsForAllSalaries(ctx)
+	require.NoError(t, err)
+	consistent, err := cache.Check(ctx)
+	require.NoError(t, err)
+	assert.True(t, consistent)
+
+	// the same count, mean, min and max with another spread
+	salaries[1].Salary, salaries[2].Salary = 150000, 250000
+	consistent, err = cache.Check(ctx)
+	require.NoError(t, err)
+	assert.False(t, consistent, "the sum of squares differs")
+}
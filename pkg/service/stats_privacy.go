@@ -0,0 +1,359 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"salaries/pkg/api"
+	"salaries/pkg/distribution"
+	"salaries/pkg/domain"
+	"salaries/pkg/requestctx"
+	"sort"
+)
+
+// StatsPrivacy keeps the stats from telling the salaries of single people. Groups with fewer than MinGroupSize salaries
+// are refused or merged into an api.OtherGroup group, and with an Epsilon the users that are neither HR nor admins get
+// the stats with Laplace noise. The zero value reports the stats as they are
+type StatsPrivacy struct {
+	MinGroupSize int
+	// Epsilon is spent by every group of a response, evenly between its figures. The smaller the noisier, 0 disables
+	// the noise
+	Epsilon float64
+	// MinSalary and MaxSalary bound the amounts the noise is scaled to, amounts outside them are clamped to them before
+	// the noise is added. The noise never depends on the salaries themselves
+	MinSalary float64
+	MaxSalary float64
+}
+
+// check refuses the stats of the whole dataset or of the salaries on contract when they are about too few people. The
+// rest of the dataset, the complement, can be told from the total and must not be too small either
+func (p StatsPrivacy) check(stats *api.Stats, complement int64) error {
+	if p.tooSmall(stats.Count) || p.tooSmall(complement) {
+		return fmt.Errorf("%w: fewer than %d salaries", api.ErrGroupTooSmall, p.MinGroupSize)
+	}
+	return nil
+}
+
+// departments merges the small departments into an api.OtherGroup department, which is left out when it is still too
+// small. The departments are ordered by name with the merged one last
+func (p StatsPrivacy) departments(stats []api.DepartmentStats) []api.DepartmentStats {
+	if p.MinGroupSize <= 1 {
+		return stats
+	}
+	groups := map[string]api.Stats{}
+	for _, departmentStats := range stats {
+		groups[departmentStats.Department] = departmentStats.Stats
+	}
+	result := []api.DepartmentStats{}
+	for department, stats := range p.merge(groups) {
+		result = append(result, api.DepartmentStats{Department: department, Stats: stats})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return otherLast(result[i].Department, result[j].Department)
+	})
+	return result
+}
+
+// subDepartments merges the sub-departments of the departments merged by departments, then the small sub-departments
+// of every department into an api.OtherGroup sub-department, so both levels add up to the same groups
+func (p StatsPrivacy) subDepartments(stats []api.SubDepartmentStats) []api.SubDepartmentStats {
+	if p.MinGroupSize <= 1 {
+		return stats
+	}
+	totals := map[string]api.Stats{}
+	for _, subDepartmentStats := range stats {
+		department := subDepartmentStats.DepartmentStats.Department
+		totals[department] = mergeStats(totals[department], subDepartmentStats.DepartmentStats.Stats)
+	}
+	departments := p.merge(totals)
+	groups := map[string]map[string]api.Stats{}
+	for _, subDepartmentStats := range stats {
+		department := subDepartmentStats.DepartmentStats.Department
+		if _, ok := departments[department]; !ok {
+			department = api.OtherGroup
+		}
+		if _, ok := departments[department]; !ok {
+			continue
+		}
+		if groups[department] == nil {
+			groups[department] = map[string]api.Stats{}
+		}
+		subDepartment := subDepartmentStats.SubDepartment
+		groups[department][subDepartment] = mergeStats(groups[department][subDepartment], subDepartmentStats.DepartmentStats.Stats)
+	}
+	result := []api.SubDepartmentStats{}
+	for department, subDepartments := range groups {
+		for subDepartment, stats := range p.merge(subDepartments) {
+			result = append(result, api.SubDepartmentStats{
+				SubDepartment:   subDepartment,
+				DepartmentStats: api.DepartmentStats{Department: department, Stats: stats},
+			})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].DepartmentStats.Department != result[j].DepartmentStats.Department {
+			return otherLast(result[i].DepartmentStats.Department, result[j].DepartmentStats.Department)
+		}
+		return otherLast(result[i].SubDepartment, result[j].SubDepartment)
+	})
+	return result
+}
+
+// merge returns the stats of the groups reported by assign
+func (p StatsPrivacy) merge(groups map[string]api.Stats) map[string]api.Stats {
+	counts := make(map[string]int64, len(groups))
+	for name, stats := range groups {
+		counts[name] = stats.Count
+	}
+	merged := map[string]api.Stats{}
+	for name, reported := range p.assign(counts) {
+		merged[reported] = mergeStats(merged[reported], groups[name])
+	}
+	return merged
+}
+
+// assign returns the group every group of the counts is reported in: itself when it has at least MinGroupSize people,
+// api.OtherGroup for the smaller ones and a group already named api.OtherGroup. The merged group can be told from the
+// total of the others, so while it is too small it takes in the smallest of them. Groups that are still too small are
+// left out
+func (p StatsPrivacy) assign(counts map[string]int64) map[string]string {
+	assigned := make(map[string]string, len(counts))
+	kept := map[string]int64{}
+	var other int64
+	for name, count := range counts {
+		if name == api.OtherGroup || p.tooSmall(count) {
+			assigned[name] = api.OtherGroup
+			other += count
+		} else {
+			kept[name] = count
+			assigned[name] = name
+		}
+	}
+	for p.tooSmall(other) && len(kept) > 0 {
+		smallest := ""
+		for name, count := range kept {
+			if smallest == "" || count < kept[smallest] || count == kept[smallest] && name < smallest {
+				smallest = name
+			}
+		}
+		assigned[smallest] = api.OtherGroup
+		other += kept[smallest]
+		delete(kept, smallest)
+	}
+	if p.tooSmall(other) {
+		for name, reported := range assigned {
+			if reported == api.OtherGroup {
+				delete(assigned, name)
+			}
+		}
+	}
+	return assigned
+}
+
+// mergeValues merges the amounts of the groups like assign
+func (p StatsPrivacy) mergeValues(groups map[string][]float64) map[string][]float64 {
+	if p.MinGroupSize <= 1 {
+		return groups
+	}
+	counts := make(map[string]int64, len(groups))
+	for name, values := range groups {
+		counts[name] = int64(len(values))
+	}
+	merged := map[string][]float64{}
+	for name, reported := range p.assign(counts) {
+		merged[reported] = append(merged[reported], groups[name]...)
+	}
+	return merged
+}
+
+// mergeBuckets merges adjacent buckets of a histogram until no group has a bucket with fewer than MinGroupSize salaries,
+// the outer edges are moved to the lowest and highest salaries when too few of them fall outside the edges
+func (p StatsPrivacy) mergeBuckets(edges []float64, groups map[string][]float64) []float64 {
+	if p.MinGroupSize <= 1 || len(edges) < 2 {
+		return edges
+	}
+	edges = append([]float64(nil), edges...)
+	low, high := math.Inf(1), math.Inf(-1)
+	var underflow, overflow bool
+	for _, values := range groups {
+		counts := distribution.Count(edges, values)
+		underflow = underflow || p.tooSmall(counts.Underflow)
+		overflow = overflow || p.tooSmall(counts.Overflow)
+		for _, value := range values {
+			low, high = math.Min(low, value), math.Max(high, value)
+		}
+	}
+	if underflow {
+		edges[0] = low
+	}
+	if overflow {
+		edges[len(edges)-1] = high
+	}
+
+	buckets := make([][]int64, 0, len(groups))
+	for _, values := range groups {
+		buckets = append(buckets, distribution.Count(edges, values).Buckets)
+	}
+	for i := 0; i < len(edges)-1 && len(edges) > 2; {
+		small := false
+		for _, counts := range buckets {
+			small = small || p.tooSmall(counts[i])
+		}
+		if !small {
+			i++
+			continue
+		}
+		// a small bucket is merged with the next one, the last one with the one before
+		if i == len(edges)-2 {
+			i--
+		}
+		for g, counts := range buckets {
+			counts[i] += counts[i+1]
+			buckets[g] = append(counts[:i+1], counts[i+2:]...)
+		}
+		edges = append(edges[:i+1], edges[i+2:]...)
+	}
+	return edges
+}
+
+func (p StatsPrivacy) tooSmall(count int64) bool {
+	return count > 0 && count < int64(p.MinGroupSize)
+}
+
+// noisy reports whether the user of the request gets the stats with noise
+func (p StatsPrivacy) noisy(ctx context.Context) bool {
+	if p.Epsilon <= 0 {
+		return false
+	}
+	user := requestctx.User(ctx)
+	return user == nil || user.Role != domain.RoleHR && user.Role != domain.RoleAdmin
+}
+
+// perturb adds noise to the stats for the users that do not see them exactly. The mean is a noisy sum of the clamped
+// salaries over a noisy count, a single salary moves the sum by the largest bound and the max and min by the range of
+// the bounds
+func (p StatsPrivacy) perturb(ctx context.Context, stats api.Stats) api.Stats {
+	if !p.noisy(ctx) || stats.Count == 0 {
+		return stats
+	}
+	epsilon := p.Epsilon / 4
+	count := float64(stats.Count) + laplace(1/epsilon)
+	sum := p.clamp(stats.Mean)*float64(stats.Count) + laplace(p.bound()/epsilon)
+	noisy := api.Stats{
+		Mean:  p.clamp(sum / math.Max(1, count)),
+		Max:   p.perturbQuantile(ctx, stats.Max),
+		Min:   p.perturbQuantile(ctx, stats.Min),
+		Count: int64(math.Max(0, math.Round(count))),
+	}
+	noisy.Max = math.Max(noisy.Max, noisy.Mean)
+	noisy.Min = math.Min(noisy.Min, noisy.Mean)
+	return noisy
+}
+
+// perturbDepartments adds noise to the stats of every department
+func (p StatsPrivacy) perturbDepartments(ctx context.Context, stats []api.DepartmentStats) []api.DepartmentStats {
+	if !p.noisy(ctx) {
+		return stats
+	}
+	noisy := make([]api.DepartmentStats, len(stats))
+	for i, departmentStats := range stats {
+		noisy[i] = api.DepartmentStats{Department: departmentStats.Department, Stats: p.perturb(ctx, departmentStats.Stats)}
+	}
+	return noisy
+}
+
+// perturbSubDepartments adds noise to the stats of every sub-department
+func (p StatsPrivacy) perturbSubDepartments(ctx context.Context, stats []api.SubDepartmentStats) []api.SubDepartmentStats {
+	if !p.noisy(ctx) {
+		return stats
+	}
+	noisy := make([]api.SubDepartmentStats, len(stats))
+	for i, subDepartmentStats := range stats {
+		departmentStats := subDepartmentStats.DepartmentStats
+		noisy[i] = api.SubDepartmentStats{
+			SubDepartment:   subDepartmentStats.SubDepartment,
+			DepartmentStats: api.DepartmentStats{Department: departmentStats.Department, Stats: p.perturb(ctx, departmentStats.Stats)},
+		}
+	}
+	return noisy
+}
+
+// perturbCount adds noise to a count that a single salary changes by at most sensitivity
+func (p StatsPrivacy) perturbCount(ctx context.Context, count int64, sensitivity float64) int64 {
+	if !p.noisy(ctx) {
+		return count
+	}
+	return int64(math.Max(0, math.Round(float64(count)+laplace(sensitivity/(p.Epsilon/4)))))
+}
+
+// perturbAmount adds noise to an amount of a group that a single salary changes by at most its share of the bounds, 1
+// for an amount within the bounds and a twelfth for a monthly cost
+func (p StatsPrivacy) perturbAmount(ctx context.Context, amount, share float64) float64 {
+	if !p.noisy(ctx) {
+		return amount
+	}
+	return amount + laplace(share*p.bound()/(p.Epsilon/4))
+}
+
+// perturbQuantile adds noise to a quantile of the salaries of a group, like their max or min, that a single salary moves
+// by at most the range of the bounds
+func (p StatsPrivacy) perturbQuantile(ctx context.Context, amount float64) float64 {
+	if !p.noisy(ctx) {
+		return amount
+	}
+	return p.clamp(p.clamp(amount) + laplace((p.MaxSalary-p.MinSalary)/(p.Epsilon/4)))
+}
+
+// perturbRank adds noise to the percentage of a group of at least minGroupSize salaries, which a single salary changes
+// by at most 100/minGroupSize
+func (p StatsPrivacy) perturbRank(ctx context.Context, rank float64, minGroupSize int) float64 {
+	if !p.noisy(ctx) {
+		return rank
+	}
+	return math.Max(0, math.Min(100, rank+laplace(100/float64(minGroupSize)/(p.Epsilon/4))))
+}
+
+// bound is the most a single salary adds to a sum of clamped salaries
+func (p StatsPrivacy) bound() float64 {
+	return math.Max(math.Abs(p.MinSalary), math.Abs(p.MaxSalary))
+}
+
+func (p StatsPrivacy) clamp(amount float64) float64 {
+	return math.Max(p.MinSalary, math.Min(p.MaxSalary, amount))
+}
+
+func laplace(scale float64) float64 {
+	p := rand.Float64()
+	for p == 0 {
+		p = rand.Float64()
+	}
+	return distribution.LaplaceQuantile(p, scale)
+}
+
+// mergeStats returns the stats of the salaries of both groups
+func mergeStats(a, b api.Stats) api.Stats {
+	if a.Count == 0 {
+		return b
+	}
+	if b.Count == 0 {
+		return a
+	}
+	count := a.Count + b.Count
+	return api.Stats{
+		Mean:  (a.Mean*float64(a.Count) + b.Mean*float64(b.Count)) / float64(count),
+		Max:   math.Max(a.Max, b.Max),
+		Min:   math.Min(a.Min, b.Min),
+		Count: count,
+		// kept so merged groups still add up like the ones of the database
+		SumOfSquares: a.SumOfSquares + b.SumOfSquares,
+	}
+}
+
+// otherLast orders names alphabetically with api.OtherGroup last
+func otherLast(a, b string) bool {
+	if (a == api.OtherGroup) != (b == api.OtherGroup) {
+		return b == api.OtherGroup
+	}
+	return a < b
+}
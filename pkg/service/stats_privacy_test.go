@@ -0,0 +1,170 @@
+package service_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"salaries/pkg/api"
+	"salaries/pkg/domain"
+	"salaries/pkg/repository"
+	"salaries/pkg/requestctx"
+	"salaries/pkg/service"
+	"testing"
+)
+
+func TestStatsPrivacy(t *testing.T) {
+	salaryRepository := &repository.SalaryRepositoryMock{
+		GetStatsForAllSalariesFunc: func(ctx context.Context) (*api.Stats, error) {
+			return &api.Stats{Mean: 85, Max: 200, Min: 30, Count: 20}, nil
+		},
+		GetContractsStatsFunc: func(ctx context.Context) (*api.Stats, error) {
+			return &api.Stats{Mean: 60, Max: 80, Min: 40, Count: 17}, nil
+		},
+		GetDepartmentsStatsFunc: func(ctx context.Context) ([]api.DepartmentStats, error) {
+			return []api.DepartmentStats{
+				{Department: "Banking", Stats: api.Stats{Mean: 50, Max: 70, Min: 30, Count: 3}},
+				{Department: "Engineering", Stats: api.Stats{Mean: 98, Max: 150, Min: 50, Count: 10}},
+				{Department: "Legal", Stats: api.Stats{Mean: 200, Max: 200, Min: 200, Count: 1}},
+				{Department: "Sales", Stats: api.Stats{Mean: 60, Max: 80, Min: 40, Count: 6}},
+			}, nil
+		},
+		GetSubDepartmentsStatsFunc: func(ctx context.Context) ([]api.SubDepartmentStats, error) {
+			return []api.SubDepartmentStats{
+				{SubDepartment: "Loan", DepartmentStats: api.DepartmentStats{Department: "Banking", Stats: api.Stats{Mean: 50, Max: 70, Min: 30, Count: 3}}},
+				{SubDepartment: "Data", DepartmentStats: api.DepartmentStats{Department: "Engineering", Stats: api.Stats{Mean: 70, Max: 80, Min: 60, Count: 2}}},
+				{SubDepartment: "Platform", DepartmentStats: api.DepartmentStats{Department: "Engineering", Stats: api.Stats{Mean: 120, Max: 150, Min: 100, Count: 5}}},
+				{SubDepartment: "Web", DepartmentStats: api.DepartmentStats{Department: "Engineering", Stats: api.Stats{Mean: 80, Max: 90, Min: 50, Count: 3}}},
+				{SubDepartment: "", DepartmentStats: api.DepartmentStats{Department: "Legal", Stats: api.Stats{Mean: 200, Max: 200, Min: 200, Count: 1}}},
+				{SubDepartment: "Direct", DepartmentStats: api.DepartmentStats{Department: "Sales", Stats: api.Stats{Mean: 60, Max: 80, Min: 40, Count: 6}}},
+			}, nil
+		},
+	}
+	salaryService := service.NewSalaryService(salaryRepository, getTestLogger(), service.WithStatsPrivacy(service.StatsPrivacy{MinGroupSize: 5}))
+	ctx := context.Background()
+
+	stats, err := salaryService.GetStatsForAllSalaries(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &api.Stats{Mean: 85, Max: 200, Min: 30, Count: 20}, stats)
+
+	_, err = salaryService.GetContractsStats(ctx)
+	assert.ErrorIs(t, err, api.ErrGroupTooSmall, "the 3 salaries not on contract can be told from the total")
+
+	departments, err := salaryService.GetDepartmentsStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []api.DepartmentStats{
+		{Department: "Engineering", Stats: api.Stats{Mean: 98, Max: 150, Min: 50, Count: 10}},
+		{Department: api.OtherGroup, Stats: api.Stats{Mean: 71, Max: 200, Min: 30, Count: 10}},
+	}, departments, "Banking and Legal are too small and Sales joins them so that they can not be told from the total")
+
+	subDepartments, err := salaryService.GetSubDepartmentsStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []api.SubDepartmentStats{
+		{SubDepartment: "Platform", DepartmentStats: api.DepartmentStats{Department: "Engineering", Stats: api.Stats{Mean: 120, Max: 150, Min: 100, Count: 5}}},
+		{SubDepartment: api.OtherGroup, DepartmentStats: api.DepartmentStats{Department: "Engineering", Stats: api.Stats{Mean: 76, Max: 90, Min: 50, Count: 5}}},
+		{SubDepartment: api.OtherGroup, DepartmentStats: api.DepartmentStats{Department: api.OtherGroup, Stats: api.Stats{Mean: 71, Max: 200, Min: 30, Count: 10}}},
+	}, subDepartments)
+
+	salaryService = service.NewSalaryService(salaryRepository, getTestLogger(), service.WithStatsPrivacy(service.StatsPrivacy{MinGroupSize: 25}))
+	_, err = salaryService.GetStatsForAllSalaries(ctx)
+	assert.ErrorIs(t, err, api.ErrGroupTooSmall)
+	departments, err = salaryService.GetDepartmentsStats(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, departments)
+}
+
+func TestStatsPrivacy_Noise(t *testing.T) {
+	salaryRepository := &repository.SalaryRepositoryMock{
+		GetStatsForAllSalariesFunc: func(ctx context.Context) (*api.Stats, error) {
+			return &api.Stats{Mean: 85, Max: 200, Min: 30, Count: 20}, nil
+		},
+		GetDepartmentsStatsFunc: func(ctx context.Context) ([]api.DepartmentStats, error) {
+			return []api.DepartmentStats{{Department: "Engineering", Stats: api.Stats{Mean: 85, Max: 200, Min: 30, Count: 20}}}, nil
+		},
+	}
+	salaryService := service.NewSalaryService(salaryRepository, getTestLogger(), service.WithStatsPrivacy(service.StatsPrivacy{MinGroupSize: 5, Epsilon: 1, MaxSalary: 150}))
+
+	for _, role := range []string{domain.RoleHR, domain.RoleAdmin} {
+		ctx := requestctx.WithUser(context.Background(), &domain.User{ID: 1, Role: role})
+		stats, err := salaryService.GetStatsForAllSalaries(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, &api.Stats{Mean: 85, Max: 200, Min: 30, Count: 20}, stats, role)
+	}
+
+	ctx := requestctx.WithUser(context.Background(), &domain.User{ID: 2, Role: domain.RoleUser})
+	stats, err := salaryService.GetStatsForAllSalaries(ctx)
+	require.NoError(t, err)
+	assert.NotEqual(t, 85.0, stats.Mean)
+	assert.LessOrEqual(t, stats.Min, stats.Mean)
+	assert.GreaterOrEqual(t, stats.Max, stats.Mean)
+	assert.GreaterOrEqual(t, stats.Min, 0.0)
+	assert.LessOrEqual(t, stats.Max, 150.0, "amounts are clamped to the bounds")
+	assert.GreaterOrEqual(t, stats.Count, int64(0))
+
+	departments, err := salaryService.GetDepartmentsStats(ctx)
+	require.NoError(t, err)
+	require.Len(t, departments, 1)
+	assert.Equal(t, "Engineering", departments[0].Department)
+	assert.NotEqual(t, 85.0, departments[0].Stats.Mean)
+}
+
+func TestStatsPrivacy_Responses(t *testing.T) {
+	salaries := []domain.Salary{
+		{ID: 1, Salary: 100, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"},
+		{ID: 2, Salary: 110, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"},
+		{ID: 3, Salary: 120, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"},
+		{ID: 4, Salary: 130, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"},
+		{ID: 5, Salary: 1000, Currency: "USD", Department: "Engineering", SubDepartment: "Platform"},
+		{ID: 6, Salary: 50, Currency: "USD", Department: "Sales", SubDepartment: "Direct"},
+		{ID: 7, Salary: 60, Currency: "USD", Department: "Sales", SubDepartment: "Direct"},
+		{ID: 8, Salary: 70, Currency: "USD", Department: "Sales", SubDepartment: "Direct"},
+		{ID: 9, Salary: 80, Currency: "USD", Department: "Banking", SubDepartment: "Loan"},
+		{ID: 10, Salary: 200, Currency: "USD", Department: "Legal", SubDepartment: "Contracts"},
+	}
+	salaryRepository := &repository.SalaryRepositoryMock{
+		ReadEachFunc: func(ctx context.Context, filter api.SalaryFilter, each func(salary *domain.Salary) error) error {
+			for i := range salaries {
+				if !filter.Matches(&salaries[i]) {
+					continue
+				}
+				if err := each(&salaries[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	salaryService := service.NewSalaryService(salaryRepository, getTestLogger(), service.WithStatsPrivacy(service.StatsPrivacy{MinGroupSize: 3}))
+	ctx := context.Background()
+
+	histogram, err := salaryService.GetHistogram(ctx, api.HistogramQuery{Strategy: "edges", Edges: "0,500,1000", GroupBy: api.GroupByDepartment})
+	require.NoError(t, err)
+	assert.Equal(t, []float64{0, 1000}, histogram.Edges, "the bucket of the 1000 salary of Engineering is too small")
+	assert.Equal(t, []api.HistogramGroup{
+		{Group: "Engineering", Count: 5, Counts: []int64{5}},
+		{Group: api.OtherGroup, Count: 5, Counts: []int64{5}},
+	}, histogram.Groups, "Banking and Legal are too small and Sales joins them")
+
+	histogram, err = salaryService.GetHistogram(ctx, api.HistogramQuery{Strategy: "edges", Edges: "60,100,120,500"})
+	require.NoError(t, err)
+	assert.Equal(t, []float64{50, 100, 1000}, histogram.Edges, "the outer edges move to the salaries left out")
+	assert.Equal(t, []api.HistogramGroup{{Count: 10, Counts: []int64{4, 6}}}, histogram.Groups)
+
+	_, err = salaryService.GetHistogram(ctx, api.HistogramQuery{SalaryFilter: api.SalaryFilter{Department: "Legal"}})
+	assert.ErrorIs(t, err, api.ErrGroupTooSmall)
+
+	simulation, err := salaryService.Simulate(ctx, api.SimulationRequest{Rules: []api.SimulationRule{{Percentage: 10}}})
+	require.NoError(t, err)
+	require.Len(t, simulation.Before.Departments, 2)
+	assert.Equal(t, "Engineering", simulation.Before.Departments[0].Department)
+	assert.Equal(t, api.OtherGroup, simulation.After.Departments[1].Department)
+	assert.Equal(t, int64(5), simulation.After.Departments[1].Stats.Count)
+	for _, subDepartment := range simulation.After.SubDepartments {
+		assert.GreaterOrEqual(t, subDepartment.DepartmentStats.Stats.Count, int64(3))
+	}
+
+	salaryService = service.NewSalaryService(salaryRepository, getTestLogger(), service.WithStatsPrivacy(service.StatsPrivacy{MinGroupSize: 6}))
+	report, err := salaryService.GetAnomalies(ctx, api.AnomalyQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, 6, report.MinGroupSize)
+	assert.Empty(t, report.Anomalies, "the 5 salaries of Platform are too few")
+}
@@ -0,0 +1,332 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"salaries/pkg/api"
+	"salaries/pkg/domain"
+	"salaries/pkg/logger"
+	"salaries/pkg/repository"
+	"salaries/pkg/tracing"
+	"sort"
+	"time"
+)
+
+// statsGroups orders the groups of a snapshot like the stats endpoints
+var statsGroups = []string{domain.StatsGroupAll, domain.StatsGroupContract, domain.StatsGroupDepartment, domain.StatsGroupSubDepartment}
+
+type SnapshotService interface {
+	TakeSnapshot(ctx context.Context, source string) (*domain.StatsSnapshot, error)
+	GetSnapshots(ctx context.Context, filter api.SnapshotFilter) ([]domain.StatsSnapshot, error)
+	GetSnapshot(ctx context.Context, id int64) (*domain.StatsSnapshot, error)
+	GetHistory(ctx context.Context, query api.StatsHistoryQuery) (*api.StatsHistory, error)
+	GetDiff(ctx context.Context, query api.SnapshotDiffQuery) (*api.SnapshotDiff, error)
+	Schedule(ctx context.Context, interval time.Duration)
+}
+
+type snapshotServiceImpl struct {
+	snapshotRepository repository.SnapshotRepository
+	salaryRepository   repository.SalaryRepository
+	privacy            StatsPrivacy
+	logger             logger.Logger
+}
+
+// NewSnapshotService persists the output of the stats endpoints over time and serves it as time series. The snapshots
+// keep the groups of the stats endpoints with the privacy and are read with its noise
+func NewSnapshotService(snapshotRepository repository.SnapshotRepository, salaryRepository repository.SalaryRepository, privacy StatsPrivacy, logger logger.Logger) SnapshotService {
+	return &snapshotServiceImpl{
+		snapshotRepository: snapshotRepository,
+		salaryRepository:   salaryRepository,
+		privacy:            privacy,
+		logger:             logger,
+	}
+}
+
+// TakeSnapshot stores the stats of the whole dataset, the salaries on contract, the departments and the sub-departments
+func (s snapshotServiceImpl) TakeSnapshot(ctx context.Context, source string) (*domain.StatsSnapshot, error) {
+	ctx, span := tracer.Start(ctx, "SnapshotService.TakeSnapshot")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("taking %s stats snapshot", source)
+	snapshot, err := s.snapshot(ctx, source)
+	if err == nil {
+		snapshot, err = s.snapshotRepository.CreateSnapshot(ctx, snapshot)
+	}
+	if err != nil {
+		logger.Error("error taking stats snapshot: %s", err.Error())
+		return nil, tracing.RecordError(span, err)
+	}
+	logger.Info("stats snapshot taken with id %d", snapshot.ID)
+	return snapshot, nil
+}
+
+func (s snapshotServiceImpl) snapshot(ctx context.Context, source string) (*domain.StatsSnapshot, error) {
+	snapshot := &domain.StatsSnapshot{TakenAt: time.Now().UTC(), Source: source}
+	overall, err := s.salaryRepository.GetStatsForAllSalaries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// groups too small to be reported are left out of the snapshot
+	if s.privacy.check(overall, 0) == nil {
+		snapshot.Stats = append(snapshot.Stats, snapshotStats(domain.StatsGroupAll, "", "", *overall))
+	}
+	stats, err := s.salaryRepository.GetContractsStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s.privacy.check(stats, overall.Count-stats.Count) == nil {
+		snapshot.Stats = append(snapshot.Stats, snapshotStats(domain.StatsGroupContract, "", "", *stats))
+	}
+	departmentsStats, err := s.salaryRepository.GetDepartmentsStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, departmentStats := range s.privacy.departments(departmentsStats) {
+		snapshot.Stats = append(snapshot.Stats, snapshotStats(domain.StatsGroupDepartment, departmentStats.Department, "", departmentStats.Stats))
+	}
+	subDepartmentsStats, err := s.salaryRepository.GetSubDepartmentsStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, subDepartmentStats := range s.privacy.subDepartments(subDepartmentsStats) {
+		departmentStats := subDepartmentStats.DepartmentStats
+		snapshot.Stats = append(snapshot.Stats, snapshotStats(domain.StatsGroupSubDepartment, departmentStats.Department, subDepartmentStats.SubDepartment, departmentStats.Stats))
+	}
+	return snapshot, nil
+}
+
+func (s snapshotServiceImpl) GetSnapshots(ctx context.Context, filter api.SnapshotFilter) ([]domain.StatsSnapshot, error) {
+	ctx, span := tracer.Start(ctx, "SnapshotService.GetSnapshots")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("getting stats snapshots")
+	snapshots, err := s.snapshotRepository.ReadSnapshots(ctx, filter)
+	if err != nil {
+		logger.Error("error getting stats snapshots: %s", err.Error())
+		return nil, tracing.RecordError(span, err)
+	}
+	logger.Info("%d stats snapshots retrieved", len(snapshots))
+	return snapshots, nil
+}
+
+func (s snapshotServiceImpl) GetSnapshot(ctx context.Context, id int64) (*domain.StatsSnapshot, error) {
+	ctx, span := tracer.Start(ctx, "SnapshotService.GetSnapshot")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("getting stats snapshot with id %d", id)
+	snapshot, err := s.snapshotRepository.ReadSnapshot(ctx, id)
+	if err != nil {
+		logger.Error("error getting stats snapshot with id %d: %s", id, err.Error())
+		return nil, tracing.RecordError(span, err)
+	}
+	for i := range snapshot.Stats {
+		snapshot.Stats[i] = s.perturb(ctx, snapshot.Stats[i])
+	}
+	logger.Info("stats snapshot with id %d retrieved", id)
+	return snapshot, nil
+}
+
+// GetHistory returns a time series of the metric per department or sub-department of the group, or a single series for
+// the whole dataset and the salaries on contract
+func (s snapshotServiceImpl) GetHistory(ctx context.Context, query api.StatsHistoryQuery) (*api.StatsHistory, error) {
+	ctx, span := tracer.Start(ctx, "SnapshotService.GetHistory")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	if query.Metric == "" {
+		query.Metric = api.StatsMetricMean
+	}
+	if query.Group == "" {
+		query.Group = domain.StatsGroupAll
+	}
+	logger.Info("getting %s history of %s stats", query.Metric, query.Group)
+	if err := validateHistoryQuery(query); err != nil {
+		return nil, tracing.RecordError(span, err)
+	}
+	snapshots, err := s.snapshotRepository.ReadHistory(ctx, query)
+	if err != nil {
+		logger.Error("error getting stats history: %s", err.Error())
+		return nil, tracing.RecordError(span, err)
+	}
+
+	history := &api.StatsHistory{Metric: query.Metric, Group: query.Group, Series: []api.StatsSeries{}}
+	series := map[[2]string]int{}
+	for _, snapshot := range snapshots {
+		for _, stats := range snapshot.Stats {
+			stats = s.perturb(ctx, stats)
+			key := [2]string{stats.Department, stats.SubDepartment}
+			i, ok := series[key]
+			if !ok {
+				i = len(history.Series)
+				series[key] = i
+				history.Series = append(history.Series, api.StatsSeries{Department: stats.Department, SubDepartment: stats.SubDepartment})
+			}
+			point := api.StatsPoint{SnapshotID: snapshot.ID, TakenAt: snapshot.TakenAt, Value: metric(stats, query.Metric)}
+			history.Series[i].Points = append(history.Series[i].Points, point)
+		}
+	}
+	sort.Slice(history.Series, func(i, j int) bool {
+		if history.Series[i].Department != history.Series[j].Department {
+			return history.Series[i].Department < history.Series[j].Department
+		}
+		return history.Series[i].SubDepartment < history.Series[j].SubDepartment
+	})
+	logger.Info("%d series of stats history retrieved", len(history.Series))
+	return history, nil
+}
+
+func validateHistoryQuery(query api.StatsHistoryQuery) error {
+	switch query.Metric {
+	case api.StatsMetricMean, api.StatsMetricMax, api.StatsMetricMin, api.StatsMetricCount:
+	default:
+		return fmt.Errorf("%w: metric must be %s, %s, %s or %s", api.ErrInvalidQuery, api.StatsMetricMean, api.StatsMetricMax, api.StatsMetricMin, api.StatsMetricCount)
+	}
+	switch query.Group {
+	case domain.StatsGroupAll, domain.StatsGroupContract:
+		if query.Department != "" || query.SubDepartment != "" {
+			return fmt.Errorf("%w: the %s group has no departments", api.ErrInvalidQuery, query.Group)
+		}
+	case domain.StatsGroupDepartment:
+		if query.SubDepartment != "" {
+			return fmt.Errorf("%w: the %s group has no sub-departments", api.ErrInvalidQuery, query.Group)
+		}
+	case domain.StatsGroupSubDepartment:
+	default:
+		return fmt.Errorf("%w: group must be %s, %s, %s or %s", api.ErrInvalidQuery, domain.StatsGroupAll, domain.StatsGroupContract, domain.StatsGroupDepartment, domain.StatsGroupSubDepartment)
+	}
+	return nil
+}
+
+// GetDiff compares every group of two snapshots, groups are ordered like the stats endpoints and by name
+func (s snapshotServiceImpl) GetDiff(ctx context.Context, query api.SnapshotDiffQuery) (*api.SnapshotDiff, error) {
+	ctx, span := tracer.Start(ctx, "SnapshotService.GetDiff")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	logger.Info("comparing stats snapshots with ids %d and %d", query.From, query.To)
+	from, err := s.snapshotRepository.ReadSnapshot(ctx, query.From)
+	if err != nil {
+		logger.Error("error getting stats snapshot with id %d: %s", query.From, err.Error())
+		return nil, tracing.RecordError(span, err)
+	}
+	to, err := s.snapshotRepository.ReadSnapshot(ctx, query.To)
+	if err != nil {
+		logger.Error("error getting stats snapshot with id %d: %s", query.To, err.Error())
+		return nil, tracing.RecordError(span, err)
+	}
+
+	changes := map[[3]string]*api.StatsDelta{}
+	delta := func(stats domain.SnapshotStats) *api.StatsDelta {
+		key := [3]string{stats.Group, stats.Department, stats.SubDepartment}
+		if changes[key] == nil {
+			changes[key] = &api.StatsDelta{Group: stats.Group, Department: stats.Department, SubDepartment: stats.SubDepartment}
+		}
+		return changes[key]
+	}
+	for _, stats := range from.Stats {
+		delta(stats).Before = statsOfSnapshot(s.perturb(ctx, stats))
+	}
+	for _, stats := range to.Stats {
+		delta(stats).After = statsOfSnapshot(s.perturb(ctx, stats))
+	}
+
+	diff := &api.SnapshotDiff{From: *from, To: *to, Changes: make([]api.StatsDelta, 0, len(changes))}
+	diff.From.Stats, diff.To.Stats = nil, nil
+	for _, change := range changes {
+		if change.Before != nil && change.After != nil {
+			change.Change = &api.Stats{
+				Mean:  change.After.Mean - change.Before.Mean,
+				Max:   change.After.Max - change.Before.Max,
+				Min:   change.After.Min - change.Before.Min,
+				Count: change.After.Count - change.Before.Count,
+			}
+		}
+		diff.Changes = append(diff.Changes, *change)
+	}
+	sort.Slice(diff.Changes, func(i, j int) bool {
+		a, b := diff.Changes[i], diff.Changes[j]
+		if a.Group != b.Group {
+			return groupOrder(a.Group) < groupOrder(b.Group)
+		}
+		if a.Department != b.Department {
+			return a.Department < b.Department
+		}
+		return a.SubDepartment < b.SubDepartment
+	})
+	logger.Info("%d groups of stats snapshots compared", len(diff.Changes))
+	return diff, nil
+}
+
+// Schedule takes a snapshot every interval until the context is done. The first one is due an interval after the latest
+// snapshot so restarts neither skip nor repeat snapshots
+func (s snapshotServiceImpl) Schedule(ctx context.Context, interval time.Duration) {
+	wait := time.Duration(0)
+	latest, err := s.snapshotRepository.ReadLatestSnapshot(ctx)
+	switch {
+	case err == nil:
+		if wait = interval - time.Since(latest.TakenAt); wait < 0 {
+			wait = 0
+		}
+	case !errors.Is(err, api.ErrNotFound):
+		s.logger.WithContext(ctx).Error("error getting the latest stats snapshot: %s", err.Error())
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			// errors are logged and the next snapshot is taken on schedule
+			s.TakeSnapshot(ctx, domain.SnapshotSourceScheduled)
+			timer.Reset(interval)
+		}
+	}
+}
+
+// perturb adds the noise of the privacy to the stats of a snapshot for the users that do not see them exactly
+func (s snapshotServiceImpl) perturb(ctx context.Context, stats domain.SnapshotStats) domain.SnapshotStats {
+	return snapshotStats(stats.Group, stats.Department, stats.SubDepartment, s.privacy.perturb(ctx, *statsOfSnapshot(stats)))
+}
+
+func snapshotStats(group, department, subDepartment string, stats api.Stats) domain.SnapshotStats {
+	return domain.SnapshotStats{
+		Group:         group,
+		Department:    department,
+		SubDepartment: subDepartment,
+		Mean:          stats.Mean,
+		Max:           stats.Max,
+		Min:           stats.Min,
+		Count:         stats.Count,
+	}
+}
+
+func statsOfSnapshot(stats domain.SnapshotStats) *api.Stats {
+	return &api.Stats{Mean: stats.Mean, Max: stats.Max, Min: stats.Min, Count: stats.Count}
+}
+
+func metric(stats domain.SnapshotStats, name string) float64 {
+	switch name {
+	case api.StatsMetricMax:
+		return stats.Max
+	case api.StatsMetricMin:
+		return stats.Min
+	case api.StatsMetricCount:
+		return float64(stats.Count)
+	}
+	return stats.Mean
+}
+
+func groupOrder(group string) int {
+	for i, g := range statsGroups {
+		if g == group {
+			return i
+		}
+	}
+	return len(statsGroups)
+}
@@ -0,0 +1,162 @@
+package service_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"salaries/pkg/api"
+	"salaries/pkg/domain"
+	"salaries/pkg/repository"
+	"salaries/pkg/service"
+	"testing"
+	"time"
+)
+
+func TestSnapshotService_TakeSnapshot(t *testing.T) {
+	salaryRepository := &repository.SalaryRepositoryMock{
+		GetStatsForAllSalariesFunc: func(ctx context.Context) (*api.Stats, error) {
+			return &api.Stats{Mean: 100, Max: 150, Min: 50, Count: 3}, nil
+		},
+		GetContractsStatsFunc: func(ctx context.Context) (*api.Stats, error) {
+			return &api.Stats{Mean: 50, Max: 50, Min: 50, Count: 1}, nil
+		},
+		GetDepartmentsStatsFunc: func(ctx context.Context) ([]api.DepartmentStats, error) {
+			return []api.DepartmentStats{{Department: "Engineering", Stats: api.Stats{Mean: 100, Max: 150, Min: 50, Count: 3}}}, nil
+		},
+		GetSubDepartmentsStatsFunc: func(ctx context.Context) ([]api.SubDepartmentStats, error) {
+			return []api.SubDepartmentStats{{SubDepartment: "Platform", DepartmentStats: api.DepartmentStats{Department: "Engineering", Stats: api.Stats{Mean: 100, Max: 150, Min: 50, Count: 3}}}}, nil
+		},
+	}
+	snapshotRepository := &repository.SnapshotRepositoryMock{
+		CreateSnapshotFunc: func(ctx context.Context, snapshot *domain.StatsSnapshot) (*domain.StatsSnapshot, error) {
+			snapshot.ID = 1
+			return snapshot, nil
+		},
+	}
+	snapshotService := service.NewSnapshotService(snapshotRepository, salaryRepository, service.StatsPrivacy{}, getTestLogger())
+
+	snapshot, err := snapshotService.TakeSnapshot(context.Background(), domain.SnapshotSourceManual)
+	require.NoError(t, err)
+	assert.Equal(t, domain.SnapshotSourceManual, snapshot.Source)
+	assert.False(t, snapshot.TakenAt.IsZero())
+	assert.Equal(t, []domain.SnapshotStats{
+		{Group: domain.StatsGroupAll, Mean: 100, Max: 150, Min: 50, Count: 3},
+		{Group: domain.StatsGroupContract, Mean: 50, Max: 50, Min: 50, Count: 1},
+		{Group: domain.StatsGroupDepartment, Department: "Engineering", Mean: 100, Max: 150, Min: 50, Count: 3},
+		{Group: domain.StatsGroupSubDepartment, Department: "Engineering", SubDepartment: "Platform", Mean: 100, Max: 150, Min: 50, Count: 3},
+	}, snapshot.Stats)
+
+	snapshotService = service.NewSnapshotService(snapshotRepository, salaryRepository, service.StatsPrivacy{MinGroupSize: 3}, getTestLogger())
+	snapshot, err = snapshotService.TakeSnapshot(context.Background(), domain.SnapshotSourceManual)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.SnapshotStats{
+		{Group: domain.StatsGroupAll, Mean: 100, Max: 150, Min: 50, Count: 3},
+		{Group: domain.StatsGroupDepartment, Department: "Engineering", Mean: 100, Max: 150, Min: 50, Count: 3},
+		{Group: domain.StatsGroupSubDepartment, Department: "Engineering", SubDepartment: "Platform", Mean: 100, Max: 150, Min: 50, Count: 3},
+	}, snapshot.Stats, "a single salary on contract is left out")
+}
+
+func TestSnapshotService_History(t *testing.T) {
+	january := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	february := january.AddDate(0, 1, 0)
+	snapshots := map[int64]*domain.StatsSnapshot{
+		1: {ID: 1, TakenAt: january, Source: domain.SnapshotSourceScheduled, Stats: []domain.SnapshotStats{
+			{Group: domain.StatsGroupAll, Mean: 100, Max: 150, Min: 50, Count: 3},
+			{Group: domain.StatsGroupDepartment, Department: "Engineering", Mean: 100, Max: 150, Min: 50, Count: 3},
+		}},
+		2: {ID: 2, TakenAt: february, Source: domain.SnapshotSourceManual, Stats: []domain.SnapshotStats{
+			{Group: domain.StatsGroupAll, Mean: 90, Max: 150, Min: 30, Count: 5},
+			{Group: domain.StatsGroupDepartment, Department: "Banking", Mean: 75, Max: 120, Min: 30, Count: 2},
+			{Group: domain.StatsGroupDepartment, Department: "Engineering", Mean: 100, Max: 150, Min: 50, Count: 3},
+		}},
+	}
+	snapshotRepository := &repository.SnapshotRepositoryMock{
+		ReadSnapshotFunc: func(ctx context.Context, snapshotID int64) (*domain.StatsSnapshot, error) {
+			if snapshot, ok := snapshots[snapshotID]; ok {
+				return snapshot, nil
+			}
+			return nil, api.ErrNotFound
+		},
+		ReadHistoryFunc: func(ctx context.Context, query api.StatsHistoryQuery) ([]domain.StatsSnapshot, error) {
+			assert.Equal(t, domain.StatsGroupDepartment, query.Group)
+			history := []domain.StatsSnapshot{*snapshots[1], *snapshots[2]}
+			history[0].Stats = history[0].Stats[1:]
+			history[1].Stats = history[1].Stats[1:]
+			return history, nil
+		},
+	}
+	snapshotService := service.NewSnapshotService(snapshotRepository, &repository.SalaryRepositoryMock{}, service.StatsPrivacy{}, getTestLogger())
+
+	history, err := snapshotService.GetHistory(context.Background(), api.StatsHistoryQuery{Metric: api.StatsMetricCount, Group: domain.StatsGroupDepartment})
+	require.NoError(t, err)
+	assert.Equal(t, []api.StatsSeries{
+		{Department: "Banking", Points: []api.StatsPoint{{SnapshotID: 2, TakenAt: february, Value: 2}}},
+		{Department: "Engineering", Points: []api.StatsPoint{{SnapshotID: 1, TakenAt: january, Value: 3}, {SnapshotID: 2, TakenAt: february, Value: 3}}},
+	}, history.Series)
+
+	_, err = snapshotService.GetHistory(context.Background(), api.StatsHistoryQuery{Metric: "median"})
+	assert.ErrorIs(t, err, api.ErrInvalidQuery)
+	_, err = snapshotService.GetHistory(context.Background(), api.StatsHistoryQuery{Department: "Engineering"})
+	assert.ErrorIs(t, err, api.ErrInvalidQuery, "the whole dataset has no departments")
+
+	diff, err := snapshotService.GetDiff(context.Background(), api.SnapshotDiffQuery{From: 1, To: 2})
+	require.NoError(t, err)
+	assert.Empty(t, diff.From.Stats)
+	assert.Equal(t, []api.StatsDelta{
+		{
+			Group:  domain.StatsGroupAll,
+			Before: &api.Stats{Mean: 100, Max: 150, Min: 50, Count: 3},
+			After:  &api.Stats{Mean: 90, Max: 150, Min: 30, Count: 5},
+			Change: &api.Stats{Mean: -10, Max: 0, Min: -20, Count: 2},
+		},
+		{
+			Group:      domain.StatsGroupDepartment,
+			Department: "Banking",
+			After:      &api.Stats{Mean: 75, Max: 120, Min: 30, Count: 2},
+		},
+		{
+			Group:      domain.StatsGroupDepartment,
+			Department: "Engineering",
+			Before:     &api.Stats{Mean: 100, Max: 150, Min: 50, Count: 3},
+			After:      &api.Stats{Mean: 100, Max: 150, Min: 50, Count: 3},
+			Change:     &api.Stats{},
+		},
+	}, diff.Changes)
+
+	_, err = snapshotService.GetDiff(context.Background(), api.SnapshotDiffQuery{From: 1, To: 404})
+	assert.ErrorIs(t, err, api.ErrNotFound)
+}
+
+func TestSnapshotService_Schedule(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	salaryRepository := &repository.SalaryRepositoryMock{
+		GetStatsForAllSalariesFunc: func(ctx context.Context) (*api.Stats, error) {
+			return &api.Stats{}, nil
+		},
+		GetContractsStatsFunc: func(ctx context.Context) (*api.Stats, error) {
+			return &api.Stats{}, nil
+		},
+		GetDepartmentsStatsFunc: func(ctx context.Context) ([]api.DepartmentStats, error) {
+			return nil, nil
+		},
+		GetSubDepartmentsStatsFunc: func(ctx context.Context) ([]api.SubDepartmentStats, error) {
+			return nil, nil
+		},
+	}
+	snapshotRepository := &repository.SnapshotRepositoryMock{
+		ReadLatestSnapshotFunc: func(ctx context.Context) (*domain.StatsSnapshot, error) {
+			return nil, api.ErrNotFound
+		},
+		CreateSnapshotFunc: func(ctx context.Context, snapshot *domain.StatsSnapshot) (*domain.StatsSnapshot, error) {
+			assert.Equal(t, domain.SnapshotSourceScheduled, snapshot.Source)
+			cancel()
+			return snapshot, nil
+		},
+	}
+	snapshotService := service.NewSnapshotService(snapshotRepository, salaryRepository, service.StatsPrivacy{}, getTestLogger())
+
+	// without any snapshot the first one is taken right away, Schedule returns once the context is cancelled
+	snapshotService.Schedule(ctx, time.Hour)
+	assert.Len(t, snapshotRepository.CreateSnapshotCalls(), 1)
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"salaries/pkg/auth"
+	"salaries/pkg/domain"
+	"salaries/pkg/logger"
+	"salaries/pkg/repository"
+	"salaries/pkg/tracing"
+	"strings"
+)
+
+var ErrInvalidUser = errors.New("invalid user")
+
+type UserService interface {
+	Create(ctx context.Context, username, password, role string) (*domain.User, error)
+	ResetPassword(ctx context.Context, username, password string) error
+}
+
+type userServiceImpl struct {
+	userRepository repository.UserRepository
+	logger         logger.Logger
+}
+
+func NewUserService(userRepository repository.UserRepository, logger logger.Logger) UserService {
+	return &userServiceImpl{
+		userRepository: userRepository,
+		logger:         logger,
+	}
+}
+
+func (s userServiceImpl) Create(ctx context.Context, username, password, role string) (*domain.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.Create")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return nil, tracing.RecordError(span, fmt.Errorf("%w: username is required", ErrInvalidUser))
+	}
+	if role != domain.RoleAdmin && role != domain.RoleHR && role != domain.RoleUser {
+		return nil, tracing.RecordError(span, fmt.Errorf("%w: role must be %s, %s or %s", ErrInvalidUser, domain.RoleAdmin, domain.RoleHR, domain.RoleUser))
+	}
+	passwordHash, err := auth.HashPassword(password)
+	if err != nil {
+		return nil, tracing.RecordError(span, fmt.Errorf("%w: %s", ErrInvalidUser, err.Error()))
+	}
+
+	logger.Info("creating user %s", username)
+	user, err := s.userRepository.Create(ctx, &domain.User{Username: username, PasswordHash: passwordHash, Role: role})
+	if err != nil {
+		logger.Error("error creating user %s: %s", username, err.Error())
+		return nil, tracing.RecordError(span, err)
+	}
+	logger.Info("user created with id %d", user.ID)
+	return user, nil
+}
+
+func (s userServiceImpl) ResetPassword(ctx context.Context, username, password string) error {
+	ctx, span := tracer.Start(ctx, "UserService.ResetPassword")
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx)
+	passwordHash, err := auth.HashPassword(password)
+	if err != nil {
+		return tracing.RecordError(span, fmt.Errorf("%w: %s", ErrInvalidUser, err.Error()))
+	}
+
+	logger.Info("resetting password of user %s", username)
+	if err := s.userRepository.UpdatePasswordHash(ctx, username, passwordHash); err != nil {
+		logger.Error("error resetting password of user %s: %s", username, err.Error())
+		return tracing.RecordError(span, err)
+	}
+	logger.Info("password reset for user %s", username)
+	return nil
+}
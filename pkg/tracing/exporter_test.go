@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewExporter(t *testing.T) {
+	ctx := context.Background()
+
+	exporter, output, err := newExporter(ctx, Config{Exporter: NoneExporter})
+	require.NoError(t, err)
+	assert.Nil(t, exporter)
+	assert.Nil(t, output)
+
+	for _, name := range []string{StdoutExporter, ""} {
+		exporter, output, err = newExporter(ctx, Config{Exporter: name})
+		require.NoError(t, err)
+		assert.IsType(t, &stdouttrace.Exporter{}, exporter, "stdout is the default exporter")
+		assert.Nil(t, output)
+	}
+
+	path := filepath.Join(t.TempDir(), "traces.json")
+	exporter, output, err = newExporter(ctx, Config{Exporter: FileExporter, FilePath: path})
+	require.NoError(t, err)
+	assert.IsType(t, &stdouttrace.Exporter{}, exporter)
+	require.NotNil(t, output)
+	assert.NoError(t, output.Close())
+	_, err = os.Stat(path)
+	assert.NoError(t, err, "the file is created")
+
+	_, _, err = newExporter(ctx, Config{Exporter: FileExporter})
+	assert.Error(t, err, "the file exporter requires a path")
+	_, _, err = newExporter(ctx, Config{Exporter: FileExporter, FilePath: filepath.Join(t.TempDir(), "missing", "traces.json")})
+	assert.Error(t, err)
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4318")
+	exporter, output, err = newExporter(ctx, Config{Exporter: OTLPExporter})
+	require.NoError(t, err)
+	require.NotNil(t, exporter)
+	_, isStdout := exporter.(*stdouttrace.Exporter)
+	assert.False(t, isStdout)
+	assert.Nil(t, output)
+	assert.NoError(t, exporter.Shutdown(ctx))
+
+	_, _, err = newExporter(ctx, Config{Exporter: "jaeger"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,101 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"os"
+)
+
+const (
+	NoneExporter   = "none"
+	StdoutExporter = "stdout"
+	FileExporter   = "file"
+	OTLPExporter   = "otlp"
+)
+
+type Config struct {
+	ServiceName string
+	// Exporter is one of none, stdout, file or otlp, the otlp exporter is configured with the standard OTEL_EXPORTER_OTLP_* variables
+	Exporter string
+	FilePath string
+}
+
+// Init installs the global tracer provider and propagator, the returned function flushes and stops the exporter
+func Init(ctx context.Context, config Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	exporter, output, err := newExporter(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	if exporter == nil {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	provider := NewProvider(config.ServiceName, sdktrace.WithBatcher(exporter))
+	otel.SetTracerProvider(provider)
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if output != nil {
+			if closeErr := output.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		return err
+	}, nil
+}
+
+// NewProvider builds a tracer provider for the service, tests pass a syncer with an in-memory exporter
+func NewProvider(serviceName string, options ...sdktrace.TracerProviderOption) *sdktrace.TracerProvider {
+	options = append([]sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+	}, options...)
+	return sdktrace.NewTracerProvider(options...)
+}
+
+// RecordError marks the span as failed and returns the error to keep the call sites short
+func RecordError(span trace.Span, err error) error {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	return err
+}
+
+// newExporter returns the span exporter of the config and the file it writes to, if any
+func newExporter(ctx context.Context, config Config) (sdktrace.SpanExporter, io.Closer, error) {
+	switch config.Exporter {
+	case NoneExporter:
+		return nil, nil, nil
+	case StdoutExporter, "":
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		return exporter, nil, err
+	case FileExporter:
+		if config.FilePath == "" {
+			return nil, nil, errors.New("a file path is required for the file trace exporter")
+		}
+		file, err := os.OpenFile(config.FilePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, nil, err
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			file.Close()
+			return nil, nil, err
+		}
+		return exporter, file, nil
+	case OTLPExporter:
+		exporter, err := otlptracehttp.New(ctx)
+		return exporter, nil, err
+	}
+	return nil, nil, fmt.Errorf("unknown trace exporter %q", config.Exporter)
+}
@@ -0,0 +1,117 @@
+package tracing_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/gin-gonic/gin"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"salaries/pkg/controller"
+	"salaries/pkg/db"
+	"salaries/pkg/logger"
+	"salaries/pkg/middleware"
+	"salaries/pkg/repository"
+	"salaries/pkg/service"
+	"salaries/pkg/tracing"
+	"strings"
+	"testing"
+)
+
+func TestTracing_SpansAcrossLayers(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := tracing.NewProvider("salaries-test", sdktrace.WithSyncer(exporter))
+	previousProvider := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previousProvider)
+
+	database, err := sql.Open("sqlite3", ":memory:")
+	assert.NoError(t, err)
+	defer database.Close()
+	database.SetMaxOpenConns(1)
+	assert.NoError(t, db.Migrate(context.Background(), database))
+
+	salaryService := service.NewSalaryService(repository.NewSalaryRepositoryWithClient(db.NewSqlite(database)), logger.NewLogger())
+	salaryController := controller.NewSalaryController(salaryService, 100)
+
+	w := httptest.NewRecorder()
+	_, r := gin.CreateTestContext(w)
+	r.Use(middleware.NewTracingMiddleware())
+	r.POST("/api/salaries", salaryController.Create)
+
+	body := `{"name": "Anurag", "salary": "90000", "currency": "USD", "department": "Banking", "on_contract": "true", "sub_department": "Loan"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/salaries", strings.NewReader(body))
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	spans := exporter.GetSpans()
+	byName := map[string]tracetest.SpanStub{}
+	for _, span := range spans {
+		byName[span.Name] = span
+	}
+	httpSpan, ok := byName["POST /api/salaries"]
+	assert.True(t, ok)
+	serviceSpan, ok := byName["SalaryService.Create"]
+	assert.True(t, ok)
+	dbSpan, ok := byName["db.insert_salary"]
+	assert.True(t, ok)
+
+	assert.Equal(t, httpSpan.SpanContext.TraceID(), dbSpan.SpanContext.TraceID())
+	assert.Equal(t, httpSpan.SpanContext.SpanID(), serviceSpan.Parent.SpanID())
+	assert.Equal(t, serviceSpan.SpanContext.SpanID(), dbSpan.Parent.SpanID())
+
+	for _, attribute := range dbSpan.Attributes {
+		assert.NotContains(t, attribute.Value.Emit(), "Anurag")
+		assert.NotContains(t, attribute.Value.Emit(), "90000")
+	}
+}
+
+func TestInit(t *testing.T) {
+	previousProvider := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(previousProvider)
+
+	shutdown, err := tracing.Init(context.Background(), tracing.Config{ServiceName: "salaries-test", Exporter: tracing.NoneExporter})
+	require.NoError(t, err)
+	assert.Same(t, previousProvider, otel.GetTracerProvider(), "no provider is installed without an exporter")
+	assert.NoError(t, shutdown(context.Background()))
+
+	path := filepath.Join(t.TempDir(), "traces.json")
+	shutdown, err = tracing.Init(context.Background(), tracing.Config{ServiceName: "salaries-test", Exporter: tracing.FileExporter, FilePath: path})
+	require.NoError(t, err)
+	_, span := otel.Tracer("salaries-test").Start(context.Background(), "SalaryService.Create")
+	span.End()
+	require.NoError(t, shutdown(context.Background()), "the spans are flushed and the file is closed")
+	traces, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(traces), "SalaryService.Create")
+	assert.Contains(t, string(traces), "salaries-test")
+
+	_, err = tracing.Init(context.Background(), tracing.Config{ServiceName: "salaries-test", Exporter: "jaeger"})
+	assert.Error(t, err)
+}
+
+func TestRecordError(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := tracing.NewProvider("salaries-test", sdktrace.WithSyncer(exporter))
+
+	_, span := provider.Tracer("salaries-test").Start(context.Background(), "SalaryService.Create")
+	err := errors.New("database is locked")
+	assert.Same(t, err, tracing.RecordError(span, err))
+	span.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	assert.Equal(t, "database is locked", spans[0].Status.Description)
+	require.Len(t, spans[0].Events, 1)
+	assert.Equal(t, "exception", spans[0].Events[0].Name)
+}